@@ -0,0 +1,172 @@
+// Package analytics summarizes recipe collections: the most used
+// ingredients, the ingredients used together and the tags and cuisines of
+// the recipes
+package analytics
+
+import (
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/ingredients"
+)
+
+// Metadata keys of the breakdowns
+const (
+	MetadataTags    = "tags"
+	MetadataCuisine = "cuisine"
+)
+
+// Count is the number of recipes with an ingredient, tag or cuisine
+type Count struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// Pair is the number of recipes using both ingredients, A < B
+type Pair struct {
+	A     string `json:"a"`
+	B     string `json:"b"`
+	Count int    `json:"count"`
+}
+
+// Summary is the analysis of a recipe collection. The counts are sorted by
+// count, the largest first, and by name. The ingredient names are lower case
+// and singular, so "Eggs" and "egg" are counted together.
+type Summary struct {
+	Recipes            int     `json:"recipes"`
+	AverageSteps       float64 `json:"averageSteps"`       // steps with directions per recipe
+	AverageIngredients float64 `json:"averageIngredients"` // distinct ingredients per recipe
+	Ingredients        []Count `json:"ingredients"`
+	Pairs              []Pair  `json:"pairs"`
+	Tags               []Count `json:"tags"`
+	Cuisines           []Count `json:"cuisines"`
+}
+
+// metadataList returns the list value of the metadata key, the comma
+// separated value is split for the recipes parsed without the list key
+func metadataList(r *cooklang.Recipe, key string) []string {
+	if list := r.MetadataList(key); list != nil {
+		return list
+	}
+	return cooklang.SplitMetadataList(r.Metadata[key])
+}
+
+// counts returns the counts of the map sorted by count and name
+func counts(m map[string]int) []Count {
+	result := make([]Count, 0, len(m))
+	for name, n := range m {
+		result = append(result, Count{name, n})
+	}
+	slices.SortFunc(result, func(a, b Count) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Name, b.Name))
+	})
+	return result
+}
+
+// Analyze returns the summary of the recipes of the collection (see
+// cooklang.ParseDir)
+func Analyze(collection map[string]*cooklang.Recipe) *Summary {
+	summary := &Summary{Recipes: len(collection)}
+	used := make(map[string]int)
+	pairs := make(map[[2]string]int)
+	tags := make(map[string]int)
+	cuisines := make(map[string]int)
+	steps, total := 0, 0
+	for _, r := range collection {
+		names := make(map[string]bool)
+		for _, step := range r.Steps {
+			if strings.TrimSpace(step.Directions) != "" {
+				steps++
+			}
+			for _, i := range step.Ingredients {
+				names[ingredients.Singular(cooklang.NormalizeName(i.Name))] = true
+			}
+		}
+		sorted := make([]string, 0, len(names))
+		for name := range names {
+			sorted = append(sorted, name)
+			used[name]++
+		}
+		slices.Sort(sorted)
+		for i, a := range sorted {
+			for _, b := range sorted[i+1:] {
+				pairs[[2]string{a, b}]++
+			}
+		}
+		total += len(sorted)
+		for _, tag := range metadataList(r, MetadataTags) {
+			tags[strings.ToLower(tag)]++
+		}
+		for _, cuisine := range metadataList(r, MetadataCuisine) {
+			cuisines[strings.ToLower(cuisine)]++
+		}
+	}
+	if summary.Recipes > 0 {
+		summary.AverageSteps = float64(steps) / float64(summary.Recipes)
+		summary.AverageIngredients = float64(total) / float64(summary.Recipes)
+	}
+	summary.Ingredients = counts(used)
+	summary.Tags = counts(tags)
+	summary.Cuisines = counts(cuisines)
+	summary.Pairs = make([]Pair, 0, len(pairs))
+	for k, n := range pairs {
+		summary.Pairs = append(summary.Pairs, Pair{k[0], k[1], n})
+	}
+	slices.SortFunc(summary.Pairs, func(a, b Pair) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.A, b.A), cmp.Compare(a.B, b.B))
+	})
+	return summary
+}
+
+// Top returns the summary with at most n ingredients, pairs, tags and
+// cuisines
+func (s *Summary) Top(n int) *Summary {
+	top := *s
+	top.Ingredients = s.Ingredients[:min(n, len(s.Ingredients))]
+	top.Pairs = s.Pairs[:min(n, len(s.Pairs))]
+	top.Tags = s.Tags[:min(n, len(s.Tags))]
+	top.Cuisines = s.Cuisines[:min(n, len(s.Cuisines))]
+	return &top
+}
+
+// WriteJSON writes the summary as JSON
+func (s *Summary) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// WriteCSV writes the summary as CSV with the columns type, name and value,
+// one row per statistic, ingredient, pair ("flour + milk"), tag and cuisine,
+// for spreadsheets
+func (s *Summary) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	rows := [][]string{
+		{"type", "name", "value"},
+		{"recipes", "", strconv.Itoa(s.Recipes)},
+		{"average steps", "", strconv.FormatFloat(s.AverageSteps, 'f', 2, 64)},
+		{"average ingredients", "", strconv.FormatFloat(s.AverageIngredients, 'f', 2, 64)},
+	}
+	for _, c := range s.Ingredients {
+		rows = append(rows, []string{"ingredient", c.Name, strconv.Itoa(c.Count)})
+	}
+	for _, p := range s.Pairs {
+		rows = append(rows, []string{"pair", p.A + " + " + p.B, strconv.Itoa(p.Count)})
+	}
+	for _, c := range s.Tags {
+		rows = append(rows, []string{"tag", c.Name, strconv.Itoa(c.Count)})
+	}
+	for _, c := range s.Cuisines {
+		rows = append(rows, []string{"cuisine", c.Name, strconv.Itoa(c.Count)})
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
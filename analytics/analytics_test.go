@@ -0,0 +1,66 @@
+package analytics
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+func testCollection(t *testing.T) map[string]*cooklang.Recipe {
+	t.Helper()
+	collection := make(map[string]*cooklang.Recipe)
+	for name, source := range map[string]string{
+		"pancakes.cook": ">> tags: breakfast, sweet\nMix @flour{200%g}, @milk{300%ml} and @eggs{2}.\n\nFry in a #pan.",
+		"bread.cook":    ">> cuisine: French\n>> tags: [baking]\nKnead @Flour{500%g} with @water{300%ml}.\n\n-- rest overnight\n\nBake.",
+		"omelette.cook": ">> cuisine: french\n>> tags: Breakfast\nBeat @egg{3} with @milk{50%ml}.",
+	} {
+		r, err := cooklang.ParseString(source)
+		if err != nil {
+			t.Fatal(err)
+		}
+		collection[name] = r
+	}
+	return collection
+}
+
+func TestAnalyze(t *testing.T) {
+	got := Analyze(testCollection(t))
+	want := &Summary{
+		Recipes:            3,
+		AverageSteps:       5.0 / 3,
+		AverageIngredients: 7.0 / 3,
+		Ingredients:        []Count{{"egg", 2}, {"flour", 2}, {"milk", 2}, {"water", 1}},
+		Pairs: []Pair{
+			{"egg", "milk", 2},
+			{"egg", "flour", 1},
+			{"flour", "milk", 1},
+			{"flour", "water", 1},
+		},
+		Tags:     []Count{{"breakfast", 2}, {"baking", 1}, {"sweet", 1}},
+		Cuisines: []Count{{"french", 2}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Analyze() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSummaryWriteCSV(t *testing.T) {
+	var b bytes.Buffer
+	if err := Analyze(testCollection(t)).Top(1).WriteCSV(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `type,name,value
+recipes,,3
+average steps,,1.67
+average ingredients,,2.33
+ingredient,egg,2
+pair,egg + milk,2
+tag,breakfast,2
+cuisine,french,2
+`
+	if got := b.String(); got != want {
+		t.Errorf("WriteCSV() = %q, want %q", got, want)
+	}
+}
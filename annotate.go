@@ -0,0 +1,73 @@
+package cooklang
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var annotateDuration = regexp.MustCompile(`(?i)\b(\d+(?:[.,]\d+)?|\d+/\d+)\s*(seconds?|secs?|minutes?|mins?|hours?|hrs?|days?)\b`)
+
+type annotation struct {
+	start, end int
+	markup     string
+}
+
+// Annotate inserts cooklang markup in free text: every mention of a known
+// ingredient name (case insensitive, whole words, longest names first)
+// becomes an ingredient (@olive oil{}) and every duration ("10 minutes")
+// becomes a timer (~{10%minutes}). The text is otherwise kept as is.
+func Annotate(text string, known []string) string {
+	var annotations []annotation
+	overlaps := func(start, end int) bool {
+		for _, a := range annotations {
+			if start < a.end && a.start < end {
+				return true
+			}
+		}
+		return false
+	}
+	for _, m := range annotateDuration.FindAllStringSubmatchIndex(text, -1) {
+		quantity := strings.ReplaceAll(text[m[2]:m[3]], ",", ".")
+		annotations = append(annotations, annotation{m[0], m[1], string(prefixTimer) + "{" + quantity + "%" + text[m[4]:m[5]] + "}"})
+	}
+
+	names := slices.Clone(known)
+	slices.SortStableFunc(names, func(a, b string) int { return len(b) - len(a) })
+	lower := strings.ToLower(text)
+	folded := len(lower) == len(text)
+	if !folded {
+		// the case mapping changed the byte offsets
+		lower = text
+	}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		word := name
+		if folded && len(strings.ToLower(name)) == len(name) {
+			word = strings.ToLower(name)
+		}
+		offset := 0
+		for {
+			index := indexWord(lower[offset:], word)
+			if index == -1 {
+				break
+			}
+			start, end := offset+index, offset+index+len(word)
+			if !overlaps(start, end) {
+				annotations = append(annotations, annotation{start, end, string(prefixIngredient) + text[start:end] + "{}"})
+			}
+			offset = end
+		}
+	}
+
+	slices.SortFunc(annotations, func(a, b annotation) int { return a.start - b.start })
+	var b strings.Builder
+	last := 0
+	for _, a := range annotations {
+		b.WriteString(text[last:a.start])
+		b.WriteString(a.markup)
+		last = a.end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
@@ -0,0 +1,27 @@
+package cooklang
+
+import "testing"
+
+func TestAnnotate(t *testing.T) {
+	known := []string{"oil", "olive oil", "salt", "Eggs", ""}
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"Empty", "", ""},
+		{"Nothing known", "Serve warm.", "Serve warm."},
+		{"Ingredients", "Heat the olive oil, add salt and eggs.", "Heat the @olive oil{}, add @salt{} and @eggs{}."},
+		{"Whole words", "Season with salty butter and oilseed.", "Season with salty butter and oilseed."},
+		{"Every mention", "Salt the water, then salt the pasta.", "@Salt{} the water, then @salt{} the pasta."},
+		{"Durations", "Boil for 10 minutes, rest 1,5 hours or 30 secs.", "Boil for ~{10%minutes}, rest ~{1.5%hours} or ~{30%secs}."},
+		{"Durations and ingredients", "Fry eggs in oil for 3 min", "Fry @eggs{} in @oil{} for ~{3%min}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Annotate(tt.text, known); got != tt.want {
+				t.Errorf("Annotate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
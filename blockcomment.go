@@ -0,0 +1,63 @@
+package cooklang
+
+import "strings"
+
+// openBlockComment reports whether the step line ends inside a block comment
+// ("[-" without "-]"), which continues on the next lines
+func openBlockComment(line string) bool {
+	for i := 0; i+1 < len(line); i++ {
+		switch {
+		case line[i] == prefixBlockComment && line[i+1] == '-':
+			end := strings.Index(line[i+2:], "-]")
+			if end == -1 {
+				return true
+			}
+			i += end + 3
+		case line[i] == prefixInlineComment && line[i+1] == prefixInlineComment:
+			return false
+		}
+	}
+	return false
+}
+
+// isStepLine returns false for the line comments and the metadata lines
+func isStepLine(line string) bool {
+	return !strings.HasPrefix(line, commentsLinePrefix) && !strings.HasPrefix(line, metadataLinePrefix)
+}
+
+// commentLines joins the lines of the block comments spanning several lines,
+// so the step is tokenized as a single line with the line breaks in the
+// comment
+type commentLines struct {
+	multiline bool // the block comments can span several lines (specFeatures.multilineComments)
+	text      strings.Builder
+	line      int // line number of the opening line, zero when no comment is open
+}
+
+// add adds the source line. It returns the line to parse with its line
+// number, or false while the line is part of an open block comment.
+func (c *commentLines) add(line string, lineNumber int) (string, int, bool) {
+	if c.line == 0 {
+		if !c.multiline || !isStepLine(line) || !openBlockComment(line) {
+			return line, lineNumber, true
+		}
+		c.line = lineNumber
+		c.text.Reset()
+		c.text.WriteString(line)
+		return "", 0, false
+	}
+	c.text.WriteString("\n")
+	c.text.WriteString(line)
+	if end := strings.Index(line, "-]"); end == -1 || openBlockComment(line[end+2:]) {
+		return "", 0, false
+	}
+	first := c.line
+	c.line = 0
+	return c.text.String(), first, true
+}
+
+// open returns the line number of the open block comment, zero when all the
+// comments are closed
+func (c *commentLines) open() int {
+	return c.line
+}
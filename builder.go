@@ -0,0 +1,161 @@
+package cooklang
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidText is returned by the Builder for text which would be read as
+// markup
+var ErrInvalidText = errors.New("invalid text")
+
+// Builder constructs recipes in code, for programs which generate recipes
+// (from scraped data, templates, ...) rather than parse them:
+//
+//	r, src, err := cooklang.NewBuilder().
+//		Meta("title", "Pancakes").
+//		Step().Text("Mix ").Ingredient("flour", 200, "g").Text(" with ").Ingredient("milk", 300, "ml").Text(".").
+//		Step().Text("Fry in a ").Cookware("pan", 0).Text(" for ").Timer("", 2, "minutes").Text(".").
+//		Build()
+//
+// The first error is kept and returned by Build, the calls after it are
+// ignored.
+type Builder struct {
+	metadata []string // metadata lines
+	steps    []*strings.Builder
+	err      error
+}
+
+// NewBuilder creates an empty recipe builder
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+func (b *Builder) fail(err error) *Builder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
+
+// step returns the current step, starting the first one when needed
+func (b *Builder) step() *strings.Builder {
+	if len(b.steps) == 0 {
+		b.Step()
+	}
+	return b.steps[len(b.steps)-1]
+}
+
+// Meta adds the metadata entry
+func (b *Builder) Meta(key, value string) *Builder {
+	key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+	if key == "" || strings.ContainsAny(key, metadataValueSeparator+"\r\n") {
+		return b.fail(fmt.Errorf("%w: metadata key %q", ErrInvalidText, key))
+	}
+	if strings.ContainsAny(value, "\r\n") {
+		return b.fail(fmt.Errorf("%w: metadata value %q", ErrInvalidText, value))
+	}
+	b.metadata = append(b.metadata, metadataLinePrefix+" "+key+metadataValueSeparator+" "+value)
+	return b
+}
+
+// Step starts a new step
+func (b *Builder) Step() *Builder {
+	b.steps = append(b.steps, &strings.Builder{})
+	return b
+}
+
+// Text adds plain text to the step. Text with item prefixes, comments or
+// line breaks is an error, as it can not be written as cooklang markup.
+func (b *Builder) Text(s string) *Builder {
+	if strings.ContainsAny(s, "@#~\r\n") || strings.Contains(s, commentsLinePrefix) || strings.Contains(s, "[-") {
+		return b.fail(fmt.Errorf("%w: %q", ErrInvalidText, s))
+	}
+	b.step().WriteString(s)
+	return b
+}
+
+// item adds the item markup to the step
+func (b *Builder) item(prefix byte, name, quantity, unit string) *Builder {
+	if name != "" {
+		if err := checkItemName(name); err != nil {
+			return b.fail(err)
+		}
+	}
+	if strings.ContainsAny(quantity+unit, "{}%\r\n") {
+		return b.fail(fmt.Errorf("%w: amount %q", ErrInvalidText, quantity+unit))
+	}
+	if unit != "" {
+		if quantity == "" {
+			return b.fail(fmt.Errorf("%w: unit %q without quantity", ErrInvalidQuantity, unit))
+		}
+		quantity += "%" + unit
+	}
+	b.step().WriteString(string(prefix) + name + "{" + quantity + "}")
+	return b
+}
+
+// formatQuantity returns the quantity markup, zero is no quantity
+func formatQuantity(quantity float64) string {
+	if quantity == 0 {
+		return ""
+	}
+	return formatScaled(quantity)
+}
+
+// Ingredient adds an ingredient with a numeric quantity to the step. A zero
+// quantity adds the ingredient without amount.
+func (b *Builder) Ingredient(name string, quantity float64, unit string) *Builder {
+	return b.item(prefixIngredient, name, formatQuantity(quantity), unit)
+}
+
+// IngredientText adds an ingredient with a textual quantity ("a pinch")
+func (b *Builder) IngredientText(name, quantity, unit string) *Builder {
+	return b.item(prefixIngredient, name, strings.TrimSpace(quantity), unit)
+}
+
+// Cookware adds cookware to the step. A zero quantity adds it without
+// quantity.
+func (b *Builder) Cookware(name string, quantity float64) *Builder {
+	return b.item(prefixCookware, name, formatQuantity(quantity), "")
+}
+
+// Timer adds a timer to the step. The name is optional.
+func (b *Builder) Timer(name string, duration float64, unit string) *Builder {
+	if duration <= 0 {
+		return b.fail(fmt.Errorf("%w: timer duration %v", ErrInvalidQuantity, duration))
+	}
+	return b.item(prefixTimer, name, formatScaled(duration), unit)
+}
+
+// Source returns the recipe as canonical cooklang markup (see RoundTrip)
+func (b *Builder) Source() (string, error) {
+	if b.err != nil {
+		return "", b.err
+	}
+	lines := append([]string(nil), b.metadata...)
+	for _, step := range b.steps {
+		if strings.TrimSpace(step.String()) != "" {
+			lines = append(lines, step.String())
+		}
+	}
+	if len(lines) == 0 {
+		return "", fmt.Errorf("empty recipe")
+	}
+	return RoundTrip(strings.Join(lines, "\n\n"))
+}
+
+// Build returns the recipe and its canonical cooklang markup. The recipe is
+// parsed from the markup, so both are consistent.
+func (b *Builder) Build() (*Recipe, string, error) {
+	src, err := b.Source()
+	if err != nil {
+		return nil, "", err
+	}
+	r, err := ParseString(src)
+	if err != nil {
+		return nil, "", err
+	}
+	return r, src, nil
+}
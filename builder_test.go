@@ -0,0 +1,74 @@
+package cooklang
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	r, src, err := NewBuilder().
+		Meta("title", "Pancakes").
+		Meta("servings", "4").
+		Step().Text("Mix ").Ingredient("flour", 200, "g").Text(" with ").Ingredient("whole milk", 0.3, "l").Text(" and ").IngredientText("salt", "a pinch", "").Text(".").
+		Step().Text("Fry in a ").Cookware("pan", 0).Text(" for ").Timer("", 2, "minutes").Text(".").
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	wantSrc := ">> title: Pancakes\n>> servings: 4\n\nMix @flour{200%g} with @whole milk{0.3%l} and @salt{a pinch}.\n\nFry in a #pan for ~{2%minutes}.\n"
+	if src != wantSrc {
+		t.Errorf("Build() source = %q, want %q", src, wantSrc)
+	}
+	want := &Recipe{
+		Steps: []Step{
+			{
+				Directions: "Mix flour with whole milk and salt.",
+				Timers:     []Timer{},
+				Ingredients: []Ingredient{
+					{Name: "flour", Amount: IngredientAmount{IsNumeric: true, Quantity: 200, QuantityRaw: "200", Unit: "g"}},
+					{Name: "whole milk", Amount: IngredientAmount{IsNumeric: true, Quantity: 0.3, QuantityRaw: "0.3", Unit: "l"}},
+					{Name: "salt", Amount: IngredientAmount{QuantityRaw: "a pinch"}},
+				},
+				Cookware: []Cookware{},
+			},
+			{
+				Directions:  "Fry in a pan for 2 minutes.",
+				Timers:      []Timer{{Duration: 2, Unit: "minutes"}},
+				Ingredients: []Ingredient{},
+				Cookware:    []Cookware{{Name: "pan", Quantity: 1}},
+			},
+		},
+		Metadata:      Metadata{"title": "Pancakes", "servings": "4"},
+		MetadataOrder: []string{"title", "servings"},
+	}
+	if !reflect.DeepEqual(r, want) {
+		t.Errorf("Build() = %#v, want %#v", r, want)
+	}
+}
+
+func TestBuilderErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *Builder
+		want    error
+	}{
+		{"Markup in text", NewBuilder().Text("Use @home"), ErrInvalidText},
+		{"Comment in text", NewBuilder().Text("Mix -- well"), ErrInvalidText},
+		{"Metadata key", NewBuilder().Meta("a:b", "c"), ErrInvalidText},
+		{"Ingredient name", NewBuilder().Ingredient("salt{}", 1, ""), ErrInvalidName},
+		{"Unit without quantity", NewBuilder().Ingredient("salt", 0, "g"), ErrInvalidQuantity},
+		{"Timer duration", NewBuilder().Timer("rest", 0, "minutes"), ErrInvalidQuantity},
+		{"First error", NewBuilder().Text("#1").Ingredient("salt{}", 1, ""), ErrInvalidText},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := tt.builder.Build(); !errors.Is(err, tt.want) {
+				t.Errorf("Build() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+	if _, _, err := NewBuilder().Build(); err == nil {
+		t.Error("Build() of an empty recipe returned no error")
+	}
+}
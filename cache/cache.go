@@ -0,0 +1,239 @@
+// Package cache memoizes parsed recipes by the hash of their content, so
+// unchanged recipe files are not parsed again
+package cache
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/encoding"
+)
+
+// ErrUnsupportedConfig is returned by New for the parser configurations
+// which read other files than the parsed recipe, as their content is not part
+// of the cache keys
+var ErrUnsupportedConfig = errors.New("unsupported parser configuration")
+
+// DefaultSize is the number of recipes kept in memory when Options.Size is
+// not set
+const DefaultSize = 256
+
+// Options contains the cache options
+type Options struct {
+	Size  int                   // maximum number of recipes kept in memory (default: DefaultSize)
+	Dir   string                // optional directory of the on-disk cache
+	Parse *cooklang.ParseConfig // parser configuration, nil uses the defaults
+	// ConfigKey identifies the parts of the parser configuration which are
+	// not values, such as a custom Parse.Numbers implementation other than
+	// cooklang.NumberWords, in the cache keys. The keys are otherwise built
+	// from the value fields only, so the on-disk cache is shared between
+	// processes.
+	ConfigKey string
+}
+
+// Stats contains the cache counters
+type Stats struct {
+	Hits     int // recipes found in memory
+	DiskHits int // recipes found in the on-disk cache
+	Misses   int // recipes parsed
+}
+
+type entry struct {
+	key    string
+	recipe *cooklang.Recipe
+}
+
+// Cache is a least recently used cache of parsed recipes keyed by the hash
+// of the recipe source and the parser configuration. It is safe for
+// concurrent use.
+type Cache struct {
+	parser *cooklang.Parser
+	config string // parser configuration part of the keys
+	size   int
+	dir    string
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List // most recently used first
+	stats   Stats
+}
+
+// New creates a new cache. Nil options use the defaults. The parser
+// configurations with Includes or InheritDirMetadata are rejected with
+// ErrUnsupportedConfig: the recipes are cached by their own content only, so
+// the entries would not change with the included or inherited files.
+func New(opts *Options) (*Cache, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	config := cooklang.ParseConfig{}
+	if opts.Parse != nil {
+		config = *opts.Parse
+	}
+	if config.Includes != cooklang.IncludesOff {
+		return nil, fmt.Errorf("%w: Includes", ErrUnsupportedConfig)
+	}
+	if config.InheritDirMetadata {
+		return nil, fmt.Errorf("%w: InheritDirMetadata", ErrUnsupportedConfig)
+	}
+	size := opts.Size
+	if size <= 0 {
+		size = DefaultSize
+	}
+	return &Cache{
+		parser:  cooklang.NewParser(&config),
+		config:  configKey(config, opts.ConfigKey),
+		size:    size,
+		dir:     opts.Dir,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}, nil
+}
+
+// configKey returns the parser configuration part of the cache keys. The
+// logger does not change the parsed recipes and the custom number parsers
+// are identified by their type and the key, as they may print as pointers.
+func configKey(config cooklang.ParseConfig, key string) string {
+	config.Logger = nil
+	numbers := ""
+	if _, ok := config.Numbers.(cooklang.NumberWords); !ok && config.Numbers != nil {
+		numbers = fmt.Sprintf("%T", config.Numbers)
+		config.Numbers = nil
+	}
+	return fmt.Sprintf("%+v %s %s", config, numbers, key)
+}
+
+// Hash returns the hex encoded SHA-256 hash of the recipe source
+func Hash(src []byte) string {
+	sum := sha256.Sum256(src)
+	return hex.EncodeToString(sum[:])
+}
+
+// Parse returns the parsed recipe of the source, parsing it only when it is
+// not cached. The returned recipes are shared and must not be modified.
+func (c *Cache) Parse(src []byte) (*cooklang.Recipe, error) {
+	key := Hash(append([]byte(c.config+"\n"), src...))
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(e)
+		c.stats.Hits++
+		c.mu.Unlock()
+		return e.Value.(*entry).recipe, nil
+	}
+	c.mu.Unlock()
+
+	r, fromDisk := c.load(key)
+	if !fromDisk {
+		var err error
+		if r, err = c.parser.ParseString(string(src)); err != nil {
+			return nil, err
+		}
+		c.store(key, r)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if fromDisk {
+		c.stats.DiskHits++
+	} else {
+		c.stats.Misses++
+	}
+	if e, ok := c.entries[key]; ok {
+		// added concurrently
+		c.lru.MoveToFront(e)
+		return e.Value.(*entry).recipe, nil
+	}
+	c.entries[key] = c.lru.PushFront(&entry{key, r})
+	for c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*entry).key)
+	}
+	return r, nil
+}
+
+// ParseFile returns the parsed recipe of the file (see Parse)
+func (c *Cache) ParseFile(name string) (*cooklang.Recipe, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return c.Parse(b)
+}
+
+// ParseFileFS returns the parsed recipe of the file from the fsys file
+// system (see Parse)
+func (c *Cache) ParseFileFS(fsys fs.FS, name string) (*cooklang.Recipe, error) {
+	b, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	return c.Parse(b)
+}
+
+// Stats returns the cache counters
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key+".bin")
+}
+
+// load reads the recipe from the on-disk cache
+func (c *Cache) load(key string) (*cooklang.Recipe, bool) {
+	if c.dir == "" {
+		return nil, false
+	}
+	b, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	// files written by other versions of the encoding are parsed again
+	r, err := encoding.Unmarshal(b)
+	if err != nil {
+		return nil, false
+	}
+	return r, true
+}
+
+// store writes the recipe to the on-disk cache. The cache is best effort, so
+// the errors are ignored.
+func (c *Cache) store(key string, r *cooklang.Recipe) {
+	if c.dir == "" {
+		return
+	}
+	b, err := encoding.Marshal(r)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return
+	}
+	// write and rename so concurrent readers never see partial files
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(b)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
@@ -0,0 +1,165 @@
+package cache
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+const soup = ">> servings: 2\nBoil @water{1%l} for ~{10%minutes}."
+
+func newCache(t *testing.T, opts *Options) *Cache {
+	t.Helper()
+	c, err := New(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestCache(t *testing.T) {
+	c := newCache(t, &Options{Size: 2})
+	for _, src := range []string{soup, soup, "Add @salt.", soup, "Add @pepper.", "Add @salt.", soup} {
+		want, err := cooklang.ParseString(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := c.Parse([]byte(src))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Parse(%q) = %+v, want %+v", src, got, want)
+		}
+	}
+	// salt is evicted by pepper, soup by salt
+	if got, want := c.Stats(), (Stats{Hits: 2, Misses: 5}); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+	if _, err := c.Parse([]byte(">> no separator")); err == nil {
+		t.Error("Parse() error = nil, want error")
+	}
+}
+
+func TestCacheParseConfig(t *testing.T) {
+	dir := t.TempDir()
+	src := []byte("Add @x{.")
+	lenient, err := newCache(t, &Options{Dir: dir}).Parse(src)
+	if err != nil || len(lenient.Steps) != 1 {
+		t.Fatalf("Parse() = %+v, %v", lenient, err)
+	}
+	// the strict parser does not use the cached lenient result
+	if _, err := newCache(t, &Options{Dir: dir, Parse: &cooklang.ParseConfig{Strict: true}}).Parse(src); err == nil {
+		t.Error("Parse() error = nil, want error")
+	}
+}
+
+func TestCacheDisk(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "soup.cook")
+	if err := os.WriteFile(name, []byte(soup), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cacheDir := filepath.Join(dir, "cache")
+	want, err := newCache(t, &Options{Dir: cacheDir}).ParseFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a new cache reads the recipe parsed by the previous one from the disk
+	c := newCache(t, &Options{Dir: cacheDir})
+	got, err := c.ParseFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseFile() = %+v, want %+v", got, want)
+	}
+	if got, want := c.Stats(), (Stats{DiskHits: 1}); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+	if _, err := c.ParseFile(filepath.Join(dir, "missing.cook")); !os.IsNotExist(err) {
+		t.Errorf("ParseFile() error = %v, want not exist", err)
+	}
+}
+
+func TestCacheIncludes(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.cook": {Data: []byte(">> include: ./b.cook\nAdd @salt.")},
+		"b.cook": {Data: []byte(">> servings: 2\nBoil @water.")},
+	}
+	for _, config := range []*cooklang.ParseConfig{
+		{Includes: cooklang.IncludeSteps},
+		{Includes: cooklang.IncludeMetadata},
+		{InheritDirMetadata: true},
+	} {
+		if _, err := New(&Options{Parse: config}); !errors.Is(err, ErrUnsupportedConfig) {
+			t.Errorf("New(%+v) error = %v, want %v", *config, err, ErrUnsupportedConfig)
+		}
+	}
+	// without includes the include metadata is kept and not read
+	want, err := cooklang.NewParser(nil).ParseFileFS(fsys, "a.cook")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := newCache(t, nil).ParseFileFS(fsys, "a.cook")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseFileFS() = %+v, want %+v", got, want)
+	}
+}
+
+func TestHash(t *testing.T) {
+	if got, want := Hash([]byte("")), "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"; got != want {
+		t.Errorf("Hash() = %q, want %q", got, want)
+	}
+}
+
+// numberParser is a number parser printed as a pointer
+type numberParser struct{ value float64 }
+
+func (p *numberParser) ParseNumber(s string) (float64, bool) {
+	return p.value, s == "some"
+}
+
+func TestCacheConfigKey(t *testing.T) {
+	dir := t.TempDir()
+	src := []byte("Add @salt{some}.")
+	config := func() *cooklang.ParseConfig {
+		return &cooklang.ParseConfig{
+			Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+			Numbers: &numberParser{2},
+		}
+	}
+	if _, err := newCache(t, &Options{Dir: dir, Parse: config()}).Parse(src); err != nil {
+		t.Fatal(err)
+	}
+	// the loggers and number parsers of another process have other addresses
+	c := newCache(t, &Options{Dir: dir, Parse: config()})
+	if _, err := c.Parse(src); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := c.Stats(), (Stats{DiskHits: 1}); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+	c = newCache(t, &Options{Dir: dir, Parse: config(), ConfigKey: "v2"})
+	if _, err := c.Parse(src); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := c.Stats(), (Stats{Misses: 1}); got != want {
+		t.Errorf("Stats() with ConfigKey = %+v, want %+v", got, want)
+	}
+	en, _ := cooklang.NumberLanguage("en")
+	de, _ := cooklang.NumberLanguage("de")
+	if configKey(cooklang.ParseConfig{Numbers: en}, "") == configKey(cooklang.ParseConfig{Numbers: de}, "") {
+		t.Error("configKey() is the same for the English and German numbers")
+	}
+}
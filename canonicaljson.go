@@ -0,0 +1,35 @@
+package cooklang
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// MarshalCanonicalJSON returns the JSON encoding of the recipe (Recipe,
+// RecipeV2 or any value encoded with encoding/json) in a canonical form for
+// the snapshots kept in version control: the keys of all the objects,
+// including the struct fields, are sorted, every value is on its own line
+// indented with two spaces, the numbers are kept as encoded, HTML characters
+// are not escaped and the output ends with a new line. The steps and their
+// items keep the source order.
+func MarshalCanonicalJSON(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var value any
+	if err := d.Decode(&value); err != nil {
+		return nil, err
+	}
+	// the maps are encoded with sorted keys
+	var b bytes.Buffer
+	e := json.NewEncoder(&b)
+	e.SetEscapeHTML(false)
+	e.SetIndent("", "  ")
+	if err := e.Encode(value); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
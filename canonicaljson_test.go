@@ -0,0 +1,58 @@
+package cooklang
+
+import "testing"
+
+func TestMarshalCanonicalJSON(t *testing.T) {
+	r, err := NewParserV2(nil).ParseString(">> title: Soup & bread\n>> author: Ann\nBoil @water{1.50%l} for ~{10%minutes}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := MarshalCanonicalJSON(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "metadata": {
+    "author": "Ann",
+    "title": "Soup & bread"
+  },
+  "steps": [
+    [
+      {
+        "type": "text",
+        "value": "Boil "
+      },
+      {
+        "name": "water",
+        "quantity": 1.5,
+        "type": "ingredient",
+        "units": "l"
+      },
+      {
+        "type": "text",
+        "value": " for "
+      },
+      {
+        "quantity": 10,
+        "type": "timer",
+        "units": "minutes"
+      },
+      {
+        "type": "text",
+        "value": "."
+      }
+    ]
+  ]
+}
+`
+	if string(got) != want {
+		t.Errorf("MarshalCanonicalJSON() = %s, want %s", got, want)
+	}
+	again, err := MarshalCanonicalJSON(r)
+	if err != nil || string(again) != string(got) {
+		t.Errorf("MarshalCanonicalJSON() is not stable: %s, %v", again, err)
+	}
+	if _, err := MarshalCanonicalJSON(func() {}); err == nil {
+		t.Error("MarshalCanonicalJSON() error = nil for a function")
+	}
+}
@@ -0,0 +1,17 @@
+# Example of calling the shared library from Python:
+#
+#   go build -tags capi -buildmode=c-shared -o libcooklang.so ./capi
+#   python3 capi/example.py
+import ctypes
+import json
+
+lib = ctypes.CDLL("./libcooklang.so")
+lib.CooklangParse.argtypes = [ctypes.c_char_p]
+lib.CooklangParse.restype = ctypes.c_void_p
+lib.CooklangFree.argtypes = [ctypes.c_void_p]
+
+ptr = lib.CooklangParse(b"Crack the @eggs{3} into a #bowl{}.")
+try:
+    print(json.dumps(json.loads(ctypes.string_at(ptr)), indent=2))
+finally:
+    lib.CooklangFree(ptr)
@@ -0,0 +1,58 @@
+//go:build capi
+
+// Command capi exports the parser as a C ABI so it can be embedded in other
+// languages using FFI. Build it as a shared library with:
+//
+//	go build -tags capi -buildmode=c-shared -o libcooklang.so ./capi
+//
+// CooklangParse returns the parse result as a JSON document
+// ({"recipe": ...} or {"error": "..."}) allocated with malloc; the caller
+// must release it with CooklangFree.
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"unsafe"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+type result struct {
+	Recipe *cooklang.Recipe `json:"recipe,omitempty"`
+	Error  string           `json:"error,omitempty"`
+}
+
+func parseJSON(source string) []byte {
+	var res result
+	r, err := cooklang.ParseString(source)
+	if err != nil {
+		res.Error = err.Error()
+	} else {
+		res.Recipe = r
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		b, _ = json.Marshal(result{Error: err.Error()})
+	}
+	return b
+}
+
+//export CooklangParse
+func CooklangParse(source *C.char) *C.char {
+	if source == nil {
+		return C.CString(`{"error":"source must not be NULL"}`)
+	}
+	return C.CString(string(parseJSON(C.GoString(source))))
+}
+
+//export CooklangFree
+func CooklangFree(p *C.char) {
+	C.free(unsafe.Pointer(p))
+}
+
+func main() {}
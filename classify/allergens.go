@@ -0,0 +1,138 @@
+package classify
+
+import (
+	_ "embed"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+//go:embed allergens.txt
+var defaultAllergens string
+
+// AllergenName is the name of an allergen
+type AllergenName string
+
+// Allergens of the EU (Regulation 1169/2011, annex II) and US (FALCPA and
+// the FASTER Act) labeling lists
+const (
+	AllergenGluten      AllergenName = "gluten" // cereals containing gluten
+	AllergenWheat       AllergenName = "wheat"
+	AllergenCrustaceans AllergenName = "crustaceans"
+	AllergenEggs        AllergenName = "eggs"
+	AllergenFish        AllergenName = "fish"
+	AllergenPeanuts     AllergenName = "peanuts"
+	AllergenSoybeans    AllergenName = "soybeans"
+	AllergenMilk        AllergenName = "milk"
+	AllergenTreeNuts    AllergenName = "tree nuts"
+	AllergenCelery      AllergenName = "celery"
+	AllergenMustard     AllergenName = "mustard"
+	AllergenSesame      AllergenName = "sesame"
+	AllergenSulphites   AllergenName = "sulphites"
+	AllergenLupin       AllergenName = "lupin"
+	AllergenMolluscs    AllergenName = "molluscs"
+)
+
+// EU14 are the allergens of the EU labeling list
+var EU14 = []AllergenName{
+	AllergenGluten, AllergenCrustaceans, AllergenEggs, AllergenFish,
+	AllergenPeanuts, AllergenSoybeans, AllergenMilk, AllergenTreeNuts,
+	AllergenCelery, AllergenMustard, AllergenSesame, AllergenSulphites,
+	AllergenLupin, AllergenMolluscs,
+}
+
+// US9 are the allergens of the US labeling list. Shellfish are the
+// crustaceans.
+var US9 = []AllergenName{
+	AllergenMilk, AllergenEggs, AllergenFish, AllergenCrustaceans,
+	AllergenTreeNuts, AllergenPeanuts, AllergenWheat, AllergenSoybeans,
+	AllergenSesame,
+}
+
+// allergenOrder is the order of the allergens returned by Allergens
+var allergenOrder = []AllergenName{
+	AllergenGluten, AllergenWheat, AllergenCrustaceans, AllergenEggs,
+	AllergenFish, AllergenPeanuts, AllergenSoybeans, AllergenMilk,
+	AllergenTreeNuts, AllergenCelery, AllergenMustard, AllergenSesame,
+	AllergenSulphites, AllergenLupin, AllergenMolluscs,
+}
+
+// Allergen is an allergen found in a recipe
+type Allergen struct {
+	Name        AllergenName
+	Ingredients []string // names of the ingredients containing the allergen
+}
+
+// AllergenDictionary maps the ingredient keywords to their allergens. The
+// keywords match like in IngredientDB.
+type AllergenDictionary map[string][]AllergenName
+
+// DefaultAllergens is the dictionary used by Allergens. It contains the
+// bundled keywords and can be extended.
+var DefaultAllergens = mustLoadAllergens(defaultAllergens)
+
+func mustLoadAllergens(s string) AllergenDictionary {
+	d, err := LoadAllergens(strings.NewReader(s))
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// LoadAllergens reads an allergen dictionary in the format of LoadDB:
+//
+//	tahini: sesame
+//	soy sauce: soybeans, gluten, wheat
+func LoadAllergens(r io.Reader) (AllergenDictionary, error) {
+	return load[AllergenName](r)
+}
+
+// Allergens returns the allergens of the recipe ingredients found in the
+// DefaultAllergens dictionary
+func Allergens(r *cooklang.Recipe) []Allergen {
+	return DefaultAllergens.Allergens(r)
+}
+
+// Allergens returns the allergens of the recipe ingredients found in the
+// dictionary. The known allergens are ordered like the EU list with wheat
+// after gluten, the others follow by name.
+func (d AllergenDictionary) Allergens(r *cooklang.Recipe) []Allergen {
+	m := newMatcher(d)
+	found := make(map[AllergenName][]string)
+	seen := make(map[string]bool)
+	for _, ingredient := range r.AllIngredients(nil) {
+		key := cooklang.NormalizeName(ingredient.Name)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		for _, name := range m.match(ingredient.Name) {
+			if !slices.Contains(found[name], ingredient.Name) {
+				found[name] = append(found[name], ingredient.Name)
+			}
+		}
+	}
+	result := make([]Allergen, 0, len(found))
+	for name, names := range found {
+		result = append(result, Allergen{name, names})
+	}
+	slices.SortFunc(result, func(a, b Allergen) int {
+		i, j := allergenIndex(a.Name), allergenIndex(b.Name)
+		if i != j {
+			return i - j
+		}
+		return strings.Compare(string(a.Name), string(b.Name))
+	})
+	return result
+}
+
+// allergenIndex returns the position of the allergen in allergenOrder,
+// the unknown allergens come last
+func allergenIndex(name AllergenName) int {
+	if i := slices.Index(allergenOrder, name); i >= 0 {
+		return i
+	}
+	return len(allergenOrder)
+}
@@ -0,0 +1,70 @@
+package classify
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+func TestAllergens(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []Allergen
+	}{
+		{
+			"None",
+			"Boil @rice{200%g} in @water{}.",
+			[]Allergen{},
+		},
+		{
+			"Ordered",
+			"Whisk @milk{} with @eggs{2}, @flour{} and @soy sauce{}. Top with @peanut butter{} and more @milk{}.",
+			[]Allergen{
+				{AllergenGluten, []string{"flour", "soy sauce"}},
+				{AllergenWheat, []string{"flour", "soy sauce"}},
+				{AllergenEggs, []string{"eggs"}},
+				{AllergenPeanuts, []string{"peanut butter"}},
+				{AllergenSoybeans, []string{"soy sauce"}},
+				{AllergenMilk, []string{"milk"}},
+			},
+		},
+		{
+			"Exceptions",
+			"Add @nutmeg{} to @butternut squash{} and @coconut milk{}.",
+			[]Allergen{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := cooklang.ParseString(tt.source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := Allergens(r); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Allergens() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllergenDictionary_Allergens(t *testing.T) {
+	d, err := LoadAllergens(strings.NewReader("quark: milk\nbee pollen: pollen\nflour: gluten"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := cooklang.ParseString("Mix @quark{} with @bee pollen{} and @flour{}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Allergen{
+		{AllergenGluten, []string{"flour"}},
+		{AllergenMilk, []string{"quark"}},
+		{"pollen", []string{"bee pollen"}},
+	}
+	if got := d.Allergens(r); !reflect.DeepEqual(got, want) {
+		t.Errorf("Allergens() = %v, want %v", got, want)
+	}
+}
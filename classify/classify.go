@@ -0,0 +1,208 @@
+// Package classify classifies recipes by their ingredients: the diets they
+// fit and the allergens they contain. Ingredients are recognized by keywords, so the results are a hint
+// rather than a guarantee.
+package classify
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/ingredients"
+)
+
+//go:embed ingredients.txt
+var defaultDB string
+
+// Property is a dietary property of an ingredient
+type Property string
+
+// Ingredient properties
+const (
+	Meat   Property = "meat" // meat, poultry and the products made of them
+	Fish   Property = "fish" // fish and seafood
+	Dairy  Property = "dairy"
+	Egg    Property = "egg"
+	Honey  Property = "honey"
+	Gluten Property = "gluten"
+)
+
+// IngredientDB maps the ingredient keywords to their properties. A keyword
+// matches whole words of the ingredient name, singular or plural, and the
+// longest matching keyword wins, so a keyword without properties ("peanut
+// butter") is an exception to a shorter one ("butter").
+type IngredientDB map[string][]Property
+
+// DefaultDB returns the bundled database of common ingredients. The result
+// is a new map, which can be extended.
+func DefaultDB() IngredientDB {
+	db, err := LoadDB(strings.NewReader(defaultDB))
+	if err != nil {
+		panic(err)
+	}
+	return db
+}
+
+// LoadDB reads an ingredient database. Every line has a keyword and its
+// comma separated properties:
+//
+//	chicken: meat
+//	puff pastry: gluten, dairy
+//	peanut butter:
+//
+// Empty lines and lines starting with # are skipped.
+func LoadDB(r io.Reader) (IngredientDB, error) {
+	return load[Property](r)
+}
+
+// load reads the keyword lines of LoadDB and LoadAllergens
+func load[T ~string](r io.Reader) (map[string][]T, error) {
+	result := make(map[string][]T)
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		keyword, list, found := strings.Cut(line, ":")
+		if !found {
+			return nil, fmt.Errorf("line %d: missing :", lineNumber)
+		}
+		keyword = strings.ToLower(strings.TrimSpace(keyword))
+		if keyword == "" {
+			return nil, fmt.Errorf("line %d: missing keyword", lineNumber)
+		}
+		values := make([]T, 0)
+		for _, v := range strings.Split(list, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, T(strings.ToLower(v)))
+			}
+		}
+		result[keyword] = values
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Lookup returns the properties of the ingredient name
+func (db IngredientDB) Lookup(name string) []Property {
+	return newMatcher(db).match(name)
+}
+
+// matcher finds the keywords in the ingredient names
+type matcher[T any] struct {
+	keywords map[string][]T // normalized keyword to values
+	longest  int            // number of words of the longest keyword
+}
+
+func newMatcher[T any](keywords map[string][]T) *matcher[T] {
+	m := &matcher[T]{keywords: make(map[string][]T, len(keywords))}
+	for keyword, values := range keywords {
+		words := normalize(keyword)
+		m.longest = max(m.longest, len(words))
+		key := strings.Join(words, " ")
+		m.keywords[key] = append(m.keywords[key], values...)
+	}
+	return m
+}
+
+// match returns the values of the keywords found in the name. At every
+// word the longest keyword is taken and the words it covers are skipped.
+func (m *matcher[T]) match(name string) []T {
+	var result []T
+	words := normalize(name)
+	for i := 0; i < len(words); {
+		n := min(m.longest, len(words)-i)
+		for ; n > 0; n-- {
+			if values, ok := m.keywords[strings.Join(words[i:i+n], " ")]; ok {
+				result = append(result, values...)
+				break
+			}
+		}
+		i += max(n, 1)
+	}
+	return result
+}
+
+// normalize splits the name into lower case, unaccented singular words
+func normalize(name string) []string {
+	words := strings.FieldsFunc(cooklang.NormalizeName(name), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	for i, word := range words {
+		words[i] = ingredients.Singular(word)
+	}
+	return words
+}
+
+// DietName is the name of a diet recipes are classified by
+type DietName string
+
+// Diets
+const (
+	Vegan      DietName = "vegan"
+	Vegetarian DietName = "vegetarian"
+	GlutenFree DietName = "gluten-free"
+	DairyFree  DietName = "dairy-free"
+)
+
+// excludedBy lists the diets excluding the ingredients with the property
+var excludedBy = map[Property][]DietName{
+	Meat:   {Vegan, Vegetarian},
+	Fish:   {Vegan, Vegetarian},
+	Dairy:  {Vegan, DairyFree},
+	Egg:    {Vegan},
+	Honey:  {Vegan},
+	Gluten: {GlutenFree},
+}
+
+// DietFlags contains the diets a recipe fits
+type DietFlags struct {
+	Vegan      bool
+	Vegetarian bool
+	GlutenFree bool
+	DairyFree  bool
+	// Offending lists the names of the ingredients which exclude the diet
+	Offending map[DietName][]string
+}
+
+// Diet classifies the recipe by the properties of its ingredients found in
+// db. Ingredients which are not in db don't exclude any diet. A nil db uses
+// DefaultDB.
+func Diet(r *cooklang.Recipe, db IngredientDB) DietFlags {
+	if db == nil {
+		db = DefaultDB()
+	}
+	m := newMatcher(db)
+	flags := DietFlags{Offending: make(map[DietName][]string)}
+	seen := make(map[string]bool)
+	for _, ingredient := range r.AllIngredients(nil) {
+		key := cooklang.NormalizeName(ingredient.Name)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		excluded := make(map[DietName]bool)
+		for _, property := range m.match(ingredient.Name) {
+			for _, diet := range excludedBy[property] {
+				if !excluded[diet] {
+					excluded[diet] = true
+					flags.Offending[diet] = append(flags.Offending[diet], ingredient.Name)
+				}
+			}
+		}
+	}
+	flags.Vegan = len(flags.Offending[Vegan]) == 0
+	flags.Vegetarian = len(flags.Offending[Vegetarian]) == 0
+	flags.GlutenFree = len(flags.Offending[GlutenFree]) == 0
+	flags.DairyFree = len(flags.Offending[DairyFree]) == 0
+	return flags
+}
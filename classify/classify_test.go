@@ -0,0 +1,112 @@
+package classify
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+func TestLoadDB(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    IngredientDB
+		wantErr bool
+	}{
+		{
+			"Database",
+			"# comment\n\nChicken: meat\npuff pastry: gluten, Dairy\npeanut butter:",
+			IngredientDB{
+				"chicken":       {Meat},
+				"puff pastry":   {Gluten, Dairy},
+				"peanut butter": {},
+			},
+			false,
+		},
+		{"Missing separator", "chicken", nil, true},
+		{"Missing keyword", ": meat", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadDB(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadDB() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadDB() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIngredientDB_Lookup(t *testing.T) {
+	db := DefaultDB()
+	tests := []struct {
+		name string
+		want []Property
+	}{
+		{"chicken breasts", []Property{Meat}},
+		{"Eggs", []Property{Egg}},
+		{"eggplant", nil},
+		{"butternut squash", nil},
+		{"peanut butter", nil},
+		{"butter", []Property{Dairy}},
+		{"gluten-free flour", nil},
+		{"anchovies", []Property{Fish}},
+		{"bacon and cheese", []Property{Meat, Dairy}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := db.Lookup(tt.name); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lookup() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiet(t *testing.T) {
+	db := DefaultDB()
+	db["tofu"] = []Property{}
+	db["fancy stock"] = []Property{Meat}
+	tests := []struct {
+		name   string
+		source string
+		want   DietFlags
+	}{
+		{
+			"Vegan",
+			"Fry @tofu{200%g} in @olive oil{}. Serve with @rice{}.",
+			DietFlags{true, true, true, true, map[DietName][]string{}},
+		},
+		{
+			"Vegetarian",
+			"Whisk @eggs{2} with @milk{100%ml} and @flour{}. Add more @milk{}.",
+			DietFlags{false, true, false, false, map[DietName][]string{
+				Vegan:      {"eggs", "milk"},
+				GlutenFree: {"flour"},
+				DairyFree:  {"milk"},
+			}},
+		},
+		{
+			"Extended database",
+			"Simmer @fancy stock{1%l} with @peanut butter{}.",
+			DietFlags{false, false, true, true, map[DietName][]string{
+				Vegan:      {"fancy stock"},
+				Vegetarian: {"fancy stock"},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := cooklang.ParseString(tt.source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := Diet(r, db); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diet() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+
+	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/analytics"
+)
+
+// analyzeCommand implements "cook analyze [flags] dir..." which prints the
+// ingredient statistics of the recipe collections
+func analyzeCommand(args []string, out, stderr io.Writer) error {
+	fs := flag.NewFlagSet("analyze", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String("format", "json", "output format: json or csv")
+	top := fs.Int("top", 0, "maximum number of ingredients, pairs, tags and cuisines (0 for all)")
+	dirs, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(dirs) == 0 {
+		return fmt.Errorf("%w: analyze: no recipe directories", errUsage)
+	}
+	collection := make(map[string]*cooklang.Recipe)
+	for _, dir := range dirs {
+		recipes, errs := cooklang.ParseDir(context.Background(), dir, 0)
+		for _, err := range errs {
+			fmt.Fprintln(stderr, "cook:", err)
+		}
+		maps.Copy(collection, recipes)
+	}
+	summary := analytics.Analyze(collection)
+	if *top > 0 {
+		summary = summary.Top(*top)
+	}
+	switch *format {
+	case "json":
+		return summary.WriteJSON(out)
+	case "csv":
+		return summary.WriteCSV(out)
+	default:
+		return fmt.Errorf("%w: analyze: unknown format %q", errUsage, *format)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+// annotateCommand implements "cook annotate [flags] [file]" which prints the
+// text of the file (or stdin) with cooklang markup for the known ingredients
+// and the durations
+func annotateCommand(args []string, stdin io.Reader, out, stderr io.Writer) error {
+	fs := flag.NewFlagSet("annotate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	known := fs.String("ingredients", "", "comma separated list of known ingredient names")
+	knownFile := fs.String("ingredients-file", "", "file with one known ingredient name per line")
+	if err := fs.Parse(args); err != nil {
+		return usageError(err)
+	}
+	var names []string
+	for _, name := range strings.Split(*known, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	if *knownFile != "" {
+		f, err := os.Open(*knownFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if name := strings.TrimSpace(scanner.Text()); name != "" {
+				names = append(names, name)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+	}
+	in := stdin
+	if fs.NArg() > 0 && fs.Arg(0) != "-" {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	text, err := io.ReadAll(in)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(out, cooklang.Annotate(string(text), names))
+	return err
+}
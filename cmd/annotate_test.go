@@ -0,0 +1,41 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnnotateCommand(t *testing.T) {
+	const text = "Boil the water for 10 minutes, add salt and pepper."
+	file := writeRecipe(t, "soup.txt", text)
+	known := writeRecipe(t, "ingredients.txt", "salt\n\n pepper \n")
+	tests := []struct {
+		name       string
+		stdin      string
+		args       []string
+		wantCode   int
+		wantStdout string
+		wantStderr string // substring of stderr
+	}{
+		{"Piped stdin", text, []string{"annotate", "-ingredients", "water, salt"}, exitOK, "Boil the @water{} for ~{10%minutes}, add @salt{} and pepper.", ""},
+		{"Stdin dash", text, []string{"annotate", "-ingredients-file", known, "-"}, exitOK, "Boil the water for ~{10%minutes}, add @salt{} and @pepper{}.", ""},
+		{"File", "", []string{"annotate", "-ingredients", "water", "-ingredients-file", known, file}, exitOK, "Boil the @water{} for ~{10%minutes}, add @salt{} and @pepper{}.", ""},
+		{"Bad flag", text, []string{"annotate", "-nope"}, exitUsage, "", "flag provided but not defined: -nope"},
+		{"Missing file", "", []string{"annotate", "missing.txt"}, exitError, "", "cook: open missing.txt: no such file or directory"},
+		{"Missing ingredients file", text, []string{"annotate", "-ingredients-file", "missing.txt"}, exitError, "", "cook: open missing.txt: no such file or directory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCook(t, tt.stdin, tt.args...)
+			if code != tt.wantCode {
+				t.Errorf("run() = %d, want %d (stderr %q)", code, tt.wantCode, stderr)
+			}
+			if stdout != tt.wantStdout {
+				t.Errorf("stdout = %q, want %q", stdout, tt.wantStdout)
+			}
+			if !strings.Contains(stderr, tt.wantStderr) || (tt.wantStderr == "" && stderr != "") {
+				t.Errorf("stderr = %q, want %q", stderr, tt.wantStderr)
+			}
+		})
+	}
+}
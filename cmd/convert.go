@@ -0,0 +1,330 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/cache"
+	"github.com/aquilax/cooklang-go/export"
+	"github.com/aquilax/cooklang-go/render"
+)
+
+// convertManifest is the file in the output directory which keeps the hashes
+// of the converted sources for the incremental mode
+const convertManifest = ".cook-convert.json"
+
+// convertInputs are the file extensions of the input formats
+var convertInputs = map[string][]string{
+	"cook":   {cooklang.RecipeFileExtension},
+	"md":     {".md", ".markdown"},
+	"jsonld": {".jsonld", ".json"},
+}
+
+// convertOutputs are the file extensions of the output formats
+var convertOutputs = map[string]string{
+	"json":   ".json",
+	"md":     ".md",
+	"html":   ".html",
+	"jsonld": ".jsonld",
+}
+
+// convertJob is a source file and the output file it is converted to
+type convertJob struct {
+	src string
+	out string
+	key string // output path relative to the output directory
+}
+
+// convertResult is the outcome of a job
+type convertResult struct {
+	job     convertJob
+	hash    string
+	skipped bool
+	err     error
+}
+
+// convertCommand implements "cook convert [flags] path..." which converts
+// the recipe files to another format. Directories are converted recursively
+// ("./recipes/..." is the same as "./recipes") keeping their structure in
+// the output directory. The failing files are reported on stderr and the
+// others are still converted.
+func convertCommand(args []string, out, stderr io.Writer, opts *render.Options) error {
+	fs := flag.NewFlagSet("convert", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	from := fs.String("from", "cook", "input format: cook, md or jsonld")
+	to := fs.String("to", "json", "output format: json, md, html or jsonld")
+	outDir := fs.String("out", ".", "output directory")
+	workers := fs.Int("j", runtime.GOMAXPROCS(0), "number of files converted in parallel")
+	incremental := fs.Bool("incremental", false, "skip the files which are older than their output or did not change since the last conversion")
+	baseURL := fs.String("base-url", "", "site mode: URL of the output directory for the OpenGraph tags and the sitemap.xml of the html pages")
+	paths, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	inputs, ok := convertInputs[*from]
+	if !ok {
+		return fmt.Errorf("%w: convert: unknown input format %q", errUsage, *from)
+	}
+	ext, ok := convertOutputs[*to]
+	if !ok {
+		return fmt.Errorf("%w: convert: unknown output format %q", errUsage, *to)
+	}
+	if *baseURL != "" && *to != "html" {
+		return fmt.Errorf("%w: convert: -base-url requires the html output format", errUsage)
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("%w: convert: no recipe files", errUsage)
+	}
+	jobs, err := convertJobs(paths, inputs, ext, *outDir)
+	if err != nil {
+		return err
+	}
+	// the workers read the hashes of the last conversion, the new ones
+	// are collected in manifest
+	previous := make(map[string]string)
+	if *incremental {
+		previous = loadConvertManifest(*outDir)
+	}
+	manifest := maps.Clone(previous)
+	results := make(chan convertResult)
+	queue := make(chan convertJob)
+	var wg sync.WaitGroup
+	for range max(*workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				jobOpts := opts
+				if *baseURL != "" {
+					jobOpts = pageOptions(opts, *baseURL, job.key)
+				}
+				results <- convertFile(job, *from, *to, *incremental, previous[job.key], jobOpts)
+			}
+		}()
+	}
+	go func() {
+		for _, job := range jobs {
+			queue <- job
+		}
+		close(queue)
+		wg.Wait()
+		close(results)
+	}()
+	var converted, skipped, failed int
+	var pages []render.SitemapURL
+	for result := range results {
+		if result.err == nil && *baseURL != "" {
+			pages = append(pages, sitemapURL(*baseURL, result.job))
+		}
+		switch {
+		case result.err != nil:
+			failed++
+			delete(manifest, result.job.key)
+			fmt.Fprintf(stderr, "cook: convert: %s: %v\n", result.job.src, result.err)
+		case result.skipped:
+			skipped++
+		default:
+			converted++
+			manifest[result.job.key] = result.hash
+		}
+	}
+	if *incremental {
+		if err := saveConvertManifest(*outDir, manifest); err != nil {
+			return err
+		}
+	}
+	if *baseURL != "" {
+		if err := writeSitemap(*outDir, pages); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(out, "%d converted, %d skipped, %d failed\n", converted, skipped, failed)
+	if failed > 0 {
+		return fmt.Errorf("convert: %d of %d files failed", failed, len(jobs))
+	}
+	return nil
+}
+
+// convertJobs lists the source files of the paths with the input extensions
+func convertJobs(paths, inputs []string, ext, outDir string) ([]convertJob, error) {
+	var jobs []convertJob
+	seen := make(map[string]bool)
+	add := func(src, rel string) {
+		if seen[src] {
+			return
+		}
+		seen[src] = true
+		key := strings.TrimSuffix(rel, filepath.Ext(rel)) + ext
+		jobs = append(jobs, convertJob{src, filepath.Join(outDir, key), filepath.ToSlash(key)})
+	}
+	for _, path := range paths {
+		root := filepath.Clean(strings.TrimSuffix(path, "..."))
+		info, err := os.Stat(root)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			add(root, filepath.Base(root))
+			continue
+		}
+		err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !slices.Contains(inputs, strings.ToLower(filepath.Ext(path))) {
+				return nil
+			}
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			add(path, rel)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].src < jobs[j].src })
+	return jobs, nil
+}
+
+// convertFile converts the source file. In the incremental mode the files
+// older than their output and the files with the hash of the last
+// conversion are skipped.
+func convertFile(job convertJob, from, to string, incremental bool, lastHash string, opts *render.Options) convertResult {
+	result := convertResult{job: job}
+	if incremental && isUpToDate(job.src, job.out) {
+		result.skipped = true
+		result.hash = lastHash
+		return result
+	}
+	src, err := os.ReadFile(job.src)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	result.hash = cache.Hash(src)
+	if incremental && result.hash == lastHash {
+		if _, err := os.Stat(job.out); err == nil {
+			result.skipped = true
+			return result
+		}
+	}
+	var r *cooklang.Recipe
+	switch from {
+	case "md":
+		r, err = cooklang.ImportMarkdown(bytes.NewReader(src))
+	case "jsonld":
+		r, err = cooklang.ImportJSONLD(bytes.NewReader(src))
+	default:
+		r, err = cooklang.ParseString(string(src))
+	}
+	if err != nil {
+		result.err = err
+		return result
+	}
+	var b bytes.Buffer
+	switch to {
+	case "md":
+		err = render.Markdown(&b, r, opts)
+	case "html":
+		err = render.HTML(&b, r, opts)
+	case "jsonld":
+		err = export.JSONLD(&b, r)
+	default:
+		err = json.NewEncoder(&b).Encode(r)
+	}
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(job.out), 0o755)
+	}
+	if err == nil {
+		err = os.WriteFile(job.out, b.Bytes(), 0o644)
+	}
+	result.err = err
+	return result
+}
+
+// pageURL returns the URL of the output file in the site
+func pageURL(baseURL, key string) string {
+	return strings.TrimSuffix(baseURL, "/") + "/" + (&url.URL{Path: key}).EscapedPath()
+}
+
+// pageOptions returns a copy of the options with the URL of the page
+func pageOptions(opts *render.Options, baseURL, key string) *render.Options {
+	page := render.Options{}
+	if opts != nil {
+		page = *opts
+	}
+	page.URL = pageURL(baseURL, key)
+	return &page
+}
+
+// sitemapURL returns the sitemap entry of the page, which was last modified
+// with its source
+func sitemapURL(baseURL string, job convertJob) render.SitemapURL {
+	u := render.SitemapURL{Loc: pageURL(baseURL, job.key)}
+	if info, err := os.Stat(job.src); err == nil {
+		u.LastMod = info.ModTime()
+	}
+	return u
+}
+
+// writeSitemap writes the sitemap.xml of the pages sorted by URL
+func writeSitemap(dir string, pages []render.SitemapURL) error {
+	sort.Slice(pages, func(i, j int) bool { return pages[i].Loc < pages[j].Loc })
+	var b bytes.Buffer
+	if err := render.Sitemap(&b, pages); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "sitemap.xml"), b.Bytes(), 0o644)
+}
+
+// isUpToDate returns true when the output file is newer than the source
+func isUpToDate(src, out string) bool {
+	srcInfo, err := os.Stat(src)
+	if err != nil {
+		return false
+	}
+	outInfo, err := os.Stat(out)
+	return err == nil && outInfo.ModTime().After(srcInfo.ModTime())
+}
+
+// loadConvertManifest reads the source hashes by output path. A missing or
+// invalid manifest is empty.
+func loadConvertManifest(dir string) map[string]string {
+	manifest := make(map[string]string)
+	data, err := os.ReadFile(filepath.Join(dir, convertManifest))
+	if err == nil {
+		_ = json.Unmarshal(data, &manifest)
+	}
+	return manifest
+}
+
+func saveConvertManifest(dir string, manifest map[string]string) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, convertManifest), data, 0o644)
+}
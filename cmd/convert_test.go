@@ -0,0 +1,64 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConvertCommand(t *testing.T) {
+	src := t.TempDir()
+	for name, data := range map[string]string{
+		"soup.cook":         "Boil @water{1%l}.",
+		"sauces/salsa.cook": "Chop @tomatoes{3}.",
+		"notes.txt":         "not a recipe",
+	} {
+		path := filepath.Join(src, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	invalid := writeRecipe(t, "invalid.cook", ">> no separator")
+	out := t.TempDir()
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStdout string
+		wantStderr string // substring of stderr
+	}{
+		{"Directory", []string{"convert", "-to", "md", "-out", out, src + "/..."}, exitOK, "2 converted, 0 skipped, 0 failed\n", ""},
+		{"Incremental", []string{"convert", "-incremental", "-out", out, src}, exitOK, "2 converted, 0 skipped, 0 failed\n", ""},
+		{"Incremental unchanged", []string{"convert", "-incremental", "-out", out, src}, exitOK, "0 converted, 2 skipped, 0 failed\n", ""},
+		{"Failed file", []string{"convert", "-out", out, filepath.Join(src, "soup.cook"), invalid}, exitError, "1 converted, 0 skipped, 1 failed\n", "cook: convert: 1 of 2 files failed"},
+		{"Unknown input format", []string{"convert", "-from", "pdf", src}, exitUsage, "", `cook: usage: convert: unknown input format "pdf"`},
+		{"Unknown output format", []string{"convert", "-to", "pdf", src}, exitUsage, "", `cook: usage: convert: unknown output format "pdf"`},
+		{"Base URL without html", []string{"convert", "-base-url", "https://example.com", src}, exitUsage, "", "cook: usage: convert: -base-url requires the html output format"},
+		{"No files", []string{"convert"}, exitUsage, "", "cook: usage: convert: no recipe files"},
+		{"Bad flag", []string{"convert", "-nope", src}, exitUsage, "", "flag provided but not defined: -nope"},
+		{"Missing path", []string{"convert", "-out", out, "missing"}, exitError, "", "cook: stat missing: no such file or directory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCook(t, "", tt.args...)
+			if code != tt.wantCode {
+				t.Errorf("run() = %d, want %d (stderr %q)", code, tt.wantCode, stderr)
+			}
+			if stdout != tt.wantStdout {
+				t.Errorf("stdout = %q, want %q", stdout, tt.wantStdout)
+			}
+			if !strings.Contains(stderr, tt.wantStderr) || (tt.wantStderr == "" && stderr != "") {
+				t.Errorf("stderr = %q, want %q", stderr, tt.wantStderr)
+			}
+		})
+	}
+	for _, name := range []string{"soup.md", "sauces/salsa.md", "soup.json", "sauces/salsa.json", convertManifest} {
+		if _, err := os.Stat(filepath.Join(out, name)); err != nil {
+			t.Errorf("convert output: %v", err)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+
+	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/similarity"
+)
+
+// duplicatesCommand implements "cook duplicates [flags] dir..." which lists
+// the similar recipes of the directory trees, to clean up merged recipe
+// folders
+func duplicatesCommand(args []string, out, stderr io.Writer) error {
+	fs := flag.NewFlagSet("duplicates", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	threshold := fs.Float64("threshold", similarity.DuplicateThreshold, "minimum similarity (0-1) of the listed recipes")
+	dirs, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(dirs) == 0 {
+		return fmt.Errorf("%w: duplicates: no recipe directories", errUsage)
+	}
+	collection := make(map[string]*cooklang.Recipe)
+	for _, dir := range dirs {
+		recipes, errs := cooklang.ParseDir(context.Background(), dir, 0)
+		for _, err := range errs {
+			fmt.Fprintln(stderr, "cook:", err)
+		}
+		maps.Copy(collection, recipes)
+	}
+	for _, d := range similarity.FindSimilar(collection, *threshold) {
+		fmt.Fprintf(out, "%.2f\t%s\t%s\n", d.Score, d.A, d.B)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/render"
+)
+
+// ingredientsCommand implements "cook ingredients [flags] file" which prints
+// the merged ingredients of the recipe. The textual quantities ("a pinch")
+// and the fixed quantities of a scaled recipe are kept as written and
+// reported on stderr.
+func ingredientsCommand(args []string, out, stderr io.Writer, opts *render.Options) error {
+	fs := flag.NewFlagSet("ingredients", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	servings := fs.String("servings", "", `scale the recipe: number of servings ("4") or multiplier ("2x")`)
+	fixed := fs.String("fixed", "", `comma separated ingredients which are not scaled ("salt, yeast")`)
+	files, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(files) != 1 {
+		return fmt.Errorf("%w: ingredients: expected a single recipe file", errUsage)
+	}
+	r, err := cooklang.ParseFile(files[0])
+	if err != nil {
+		return err
+	}
+	if *servings != "" {
+		factor, err := cooklang.ScaleFactor(r, *servings)
+		if err != nil {
+			return fmt.Errorf("%s: %w", files[0], err)
+		}
+		if factor != 1 {
+			for _, step := range r.Steps {
+				for _, i := range step.Ingredients {
+					if !i.Amount.IsNumeric && i.Amount.QuantityRaw != "" {
+						fmt.Fprintf(stderr, "cook: warning: %s: quantity %q of %s can not be scaled\n", files[0], i.Amount.QuantityRaw, i.Name)
+					}
+				}
+			}
+		}
+		var report *cooklang.ScaleReport
+		r, report = cooklang.ScaleWithReport(r, factor, cooklang.ScaleOptions{Rounding: cooklang.DefaultRoundingTable, Fixed: cooklang.SplitMetadataList(*fixed)})
+		if factor != 1 {
+			for _, i := range report.Fixed {
+				fmt.Fprintf(stderr, "cook: warning: %s: quantity of %s is fixed and not scaled\n", files[0], i.Name)
+			}
+		}
+	}
+	for _, i := range r.AllIngredients(mergeOptions) {
+		fmt.Fprintf(out, "%-30s%s\n", i.Name, render.FormatAmount(i.Amount, opts))
+	}
+	return nil
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"os"
@@ -8,38 +10,115 @@ import (
 	"strings"
 
 	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/ingredients"
+	"github.com/aquilax/cooklang-go/render"
 )
 
 const OFFSET_INDENT = 4
 
+// exit codes
+const (
+	exitOK    = 0
+	exitError = 1 // the recipe could not be read or parsed
+	exitUsage = 2 // invalid command line
+)
+
+// mergeOptions are the options of the merged ingredient lists
+var mergeOptions = ingredients.MergeOptions{ConvertUnits: true}
+
+var (
+	// errUsage marks the command line errors
+	errUsage = errors.New("usage")
+	// errFlags is returned for the invalid flags, which the flag sets
+	// report themselves
+	errFlags = fmt.Errorf("%w: invalid flags", errUsage)
+)
+
 func main() {
-	recipe, err := cooklang.ParseFile(os.Args[1])
-	if err != nil {
-		panic(err)
-	}
-	printRecipe(*recipe, os.Stdout)
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
 }
 
-func collectIngredients(steps []cooklang.Step) []cooklang.Ingredient {
-	var result []cooklang.Ingredient
-	for i := range steps {
-		result = append(result, steps[i].Ingredients...)
+// run executes the command line and returns the exit code. Errors are
+// written to stderr.
+func run(args []string, stdin *os.File, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("cook", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	locale := fs.String("locale", render.DefaultLocale, "locale of the output labels")
+	dualUnits := fs.Bool("dual-units", false, `add the amounts converted to the other measurement system ("820 g (1.8 lb)")`)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	opts := &render.Options{Locale: *locale, DualUnits: *dualUnits}
+	var err error
+	switch fs.Arg(0) {
+	case "annotate":
+		err = annotateCommand(fs.Args()[1:], stdin, stdout, stderr)
+	case "ingredients":
+		err = ingredientsCommand(fs.Args()[1:], stdout, stderr, opts)
+	case "shopping-list":
+		err = shoppingListCommand(fs.Args()[1:], stdout, stderr, opts)
+	case "run":
+		err = runCommand(fs.Args()[1:], stdin, stdout, stderr, opts)
+	case "convert":
+		err = convertCommand(fs.Args()[1:], stdout, stderr, opts)
+	case "analyze":
+		err = analyzeCommand(fs.Args()[1:], stdout, stderr)
+	case "duplicates":
+		err = duplicatesCommand(fs.Args()[1:], stdout, stderr)
+	case "spec-report":
+		err = specReportCommand(fs.Args()[1:], stdout, stderr)
+	case "parse":
+		err = parseCommand(fs.Args()[1:], stdin, stdout, opts)
+	default:
+		err = parseCommand(fs.Args(), stdin, stdout, opts)
+	}
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.Is(err, errFlags):
+		return exitUsage
+	case errors.Is(err, errUsage):
+		fmt.Fprintln(stderr, "cook:", err)
+		return exitUsage
+	default:
+		fmt.Fprintln(stderr, "cook:", err)
+		return exitError
 	}
-	sort.SliceStable(result, func(i, j int) bool {
-		return result[i].Name < result[j].Name
-	})
-	return result
 }
 
-func coollectCookware(steps []cooklang.Step) []string {
-	var result []string
-	for i := range steps {
-		for j := range steps[i].Cookware {
-			result = append(result, steps[i].Cookware[j].Name)
-		}
+// parseCommand implements "cook [parse] file" which prints the parsed
+// recipe. The recipe is read from stdin when the file is "-" or when no file
+// is given and stdin is not a terminal.
+func parseCommand(args []string, stdin *os.File, out io.Writer, opts *render.Options) error {
+	if len(args) > 1 {
+		return fmt.Errorf("%w: expected a single recipe file", errUsage)
 	}
-	sort.Strings(result)
-	return result
+	var recipe *cooklang.Recipe
+	var err error
+	switch {
+	case len(args) == 1 && args[0] != "-":
+		recipe, err = cooklang.ParseFile(args[0])
+	case len(args) == 1 || !isTerminal(stdin):
+		recipe, err = cooklang.ParseStream(stdin)
+	default:
+		return fmt.Errorf("%w: cook [parse] file (or - for stdin)", errUsage)
+	}
+	if err != nil {
+		return err
+	}
+	printRecipe(*recipe, out, opts)
+	return nil
+}
+
+// isTerminal returns true when f is a character device (a terminal)
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
 }
 
 func formatFloat(num float64, precision int) string {
@@ -48,51 +127,56 @@ func formatFloat(num float64, precision int) string {
 	return strings.TrimRight(strings.TrimRight(s, "0"), ".")
 }
 
-func getIngredients(ing []cooklang.Ingredient) []string {
+func getIngredients(ing []cooklang.Ingredient, opts *render.Options) []string {
 	var result []string
 	for i := range ing {
-		result = append(result, fmt.Sprintf("%s: %s %s", ing[i].Name, formatFloat(ing[i].Amount.Quantity, 2), ing[i].Amount.Unit))
+		result = append(result, strings.TrimSpace(fmt.Sprintf("%s: %s", ing[i].Name, render.FormatAmount(ing[i].Amount, opts))))
 	}
 	sort.Strings(result)
 	return result
 }
 
-func printRecipe(recipe cooklang.Recipe, out io.Writer) {
+func printRecipe(recipe cooklang.Recipe, out io.Writer, opts *render.Options) {
 	offset := strings.Repeat(" ", OFFSET_INDENT)
 	if len(recipe.Metadata) > 0 {
-		fmt.Fprintln(out, "Metadata:")
-		for k, v := range recipe.Metadata {
-			fmt.Fprintf(out, "%s%s: %s\n", offset, k, v)
+		fmt.Fprintf(out, "%s:\n", render.Message(opts.Locale, render.MsgMetadata, "Metadata"))
+		for _, k := range recipe.MetadataKeys() {
+			fmt.Fprintf(out, "%s%s: %s\n", offset, k, recipe.Metadata[k])
 		}
 		fmt.Fprintln(out, "")
 	}
-	allIngredients := collectIngredients(recipe.Steps)
+	allIngredients := recipe.AllIngredients(mergeOptions)
 	if len(allIngredients) > 0 {
-		fmt.Fprintln(out, "Ingredients:")
+		fmt.Fprintf(out, "%s:\n", render.Message(opts.Locale, render.MsgIngredients, "Ingredients"))
 		for i := range allIngredients {
-			fmt.Fprintf(out, "%s%-30s%s %s\n", offset, allIngredients[i].Name, formatFloat(allIngredients[i].Amount.Quantity, 2), allIngredients[i].Amount.Unit)
+			fmt.Fprintf(out, "%s%-30s%s\n", offset, allIngredients[i].Name, render.FormatAmount(allIngredients[i].Amount, opts))
 		}
 		fmt.Fprintln(out, "")
 	}
-	allCookware := coollectCookware(recipe.Steps)
+	allCookware := cooklang.CollectCookware(&recipe)
 	if len(allCookware) > 0 {
-		fmt.Fprintln(out, "Cookware:")
-		for i := range allCookware {
-			fmt.Fprintf(out, "%s%s\n", offset, allCookware[i])
+		fmt.Fprintf(out, "%s:\n", render.Message(opts.Locale, render.MsgCookware, "Cookware"))
+		for _, c := range allCookware {
+			fmt.Fprintf(out, "%s%s\n", offset, strings.TrimSpace(fmt.Sprintf("%-30s%s", c.Name, formatCookwareQuantity(c))))
 		}
 		fmt.Fprintln(out, "")
 	}
-	if len(recipe.Steps) > 0 {
-		fmt.Fprintln(out, "Steps:")
-		for i := range recipe.Steps {
-			fmt.Fprintf(out, "%s%2d. %s\n", offset, i+1, recipe.Steps[i].Directions)
-			ingredients := "–"
-			ing := getIngredients(recipe.Steps[i].Ingredients)
-			if len(ing) > 0 {
-				ingredients = strings.Join(ing, "; ")
-			}
-
-			fmt.Fprintf(out, "%s    [%s]\n", offset, ingredients)
+	number := 0
+	for _, step := range recipe.Steps {
+		if step.IsComment() {
+			continue
 		}
+		if number == 0 {
+			fmt.Fprintf(out, "%s:\n", render.Message(opts.Locale, render.MsgSteps, "Steps"))
+		}
+		number++
+		fmt.Fprintf(out, "%s%2d. %s\n", offset, number, render.LocalizeDirections(opts.Locale, step))
+		ingredients := "–"
+		ing := getIngredients(step.Ingredients, opts)
+		if len(ing) > 0 {
+			ingredients = strings.Join(ing, "; ")
+		}
+
+		fmt.Fprintf(out, "%s    [%s]\n", offset, ingredients)
 	}
 }
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/render"
+)
+
+func TestPrintRecipe(t *testing.T) {
+	recipe, err := cooklang.ParseString("-- prepare everything first\n\nBoil @water{1%l}.\n\n-- meanwhile\n\nServe.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	printRecipe(*recipe, &b, &render.Options{})
+	want := `Ingredients:
+    water                         1 l
+
+Steps:
+     1. Boil water.
+        [water: 1 l]
+     2. Serve.
+        [–]
+`
+	if got := b.String(); got != want {
+		t.Errorf("printRecipe() = %q, want %q", got, want)
+	}
+}
+
+// runCook runs the command line with stdin read from a file, which is not a
+// terminal like piped input, and returns the exit code and the output
+func runCook(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	name := filepath.Join(t.TempDir(), "stdin")
+	if err := os.WriteFile(name, []byte(stdin), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var stdout, stderr strings.Builder
+	code := run(args, f, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// writeRecipe writes the recipe file to a temporary directory and returns its
+// path
+func writeRecipe(t *testing.T, name, src string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRun(t *testing.T) {
+	const soup = "Boil @water{1%l}."
+	const parsed = "Ingredients:\n    water                         1 l\n\nSteps:\n     1. Boil water.\n        [water: 1 l]\n"
+	file := writeRecipe(t, "soup.cook", soup)
+	tests := []struct {
+		name       string
+		stdin      string
+		args       []string
+		wantCode   int
+		wantStdout string
+		wantStderr string // substring of stderr
+	}{
+		{"File", "", []string{file}, exitOK, parsed, ""},
+		{"Parse file", "", []string{"parse", file}, exitOK, parsed, ""},
+		{"Stdin dash", soup, []string{"-"}, exitOK, parsed, ""},
+		{"Parse stdin dash", soup, []string{"parse", "-"}, exitOK, parsed, ""},
+		{"Piped stdin", soup, nil, exitOK, parsed, ""},
+		{"Flags before the file", soup, []string{"-locale", "en", "-"}, exitOK, parsed, ""},
+		{"Help", "", []string{"-h"}, exitOK, "", "Usage of cook"},
+		{"Bad flag", "", []string{"-nope", file}, exitUsage, "", "flag provided but not defined: -nope"},
+		{"Several files", "", []string{file, file}, exitUsage, "", "cook: usage: expected a single recipe file"},
+		{"Bad subcommand flag", "", []string{"ingredients", "-nope", file}, exitUsage, "", "flag provided but not defined: -nope"},
+		{"Unknown subcommand", "", []string{"frobnicate"}, exitError, "", "cook: open frobnicate: no such file or directory"},
+		{"Missing file", "", []string{"parse", "missing.cook"}, exitError, "", "cook: open missing.cook: no such file or directory"},
+		{"Invalid recipe", ">> no separator", []string{"-"}, exitError, "", "cook: "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCook(t, tt.stdin, tt.args...)
+			if code != tt.wantCode {
+				t.Errorf("run() = %d, want %d (stderr %q)", code, tt.wantCode, stderr)
+			}
+			if stdout != tt.wantStdout {
+				t.Errorf("stdout = %q, want %q", stdout, tt.wantStdout)
+			}
+			if !strings.Contains(stderr, tt.wantStderr) || (tt.wantStderr == "" && stderr != "") {
+				t.Errorf("stderr = %q, want %q", stderr, tt.wantStderr)
+			}
+		})
+	}
+}
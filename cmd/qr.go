@@ -0,0 +1,13 @@
+//go:build qr
+
+package main
+
+import qrcode "github.com/skip2/go-qrcode"
+
+// qrCodeSize is the width and height of the QR code images in pixels
+const qrCodeSize = 512
+
+// writeQRCode writes the content as QR code to the PNG file
+func writeQRCode(file, content string) error {
+	return qrcode.WriteFile(content, qrcode.Medium, qrCodeSize, file)
+}
@@ -0,0 +1,11 @@
+//go:build !qr
+
+package main
+
+import "errors"
+
+// writeQRCode is not available without the qr build tag, which adds the QR
+// code dependency
+func writeQRCode(file, content string) error {
+	return errors.New("QR codes are not supported, build with -tags qr")
+}
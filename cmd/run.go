@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/render"
+)
+
+const runHelp = `Commands:
+  <enter>, n      next step
+  p               previous step
+  t               start the timers of the step
+  l               list the running timers
+  i               list all ingredients
+  s <scale>       scale the recipe ("2x" or number of servings)
+  h, ?            show this help
+  q               quit
+`
+
+// runningTimer is a started countdown timer
+type runningTimer struct {
+	label string
+	end   time.Time
+	timer *time.Timer
+}
+
+// cookSession is an interactive walk through the recipe steps
+type cookSession struct {
+	original *cooklang.Recipe
+	recipe   *cooklang.Recipe // scaled recipe
+	steps    []int            // indexes of the steps with directions
+	current  int              // index in steps
+	opts     *render.Options
+
+	mu     sync.Mutex // guards out and timers, the timers finish concurrently
+	out    io.Writer
+	timers []*runningTimer
+}
+
+// runCommand implements "cook run [flags] file" which walks through the
+// recipe steps one at a time with countdown timers
+func runCommand(args []string, in io.Reader, out, stderr io.Writer, opts *render.Options) error {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	scale := fs.String("servings", "", `scale the recipe: multiplier ("2x") or number of servings ("4")`)
+	files, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(files) != 1 {
+		return fmt.Errorf("%w: run: expected a single recipe file", errUsage)
+	}
+	r, err := cooklang.ParseFile(files[0])
+	if err != nil {
+		return err
+	}
+	s := &cookSession{original: r, recipe: r, opts: opts, out: out}
+	for i, step := range r.Steps {
+		if step.Directions != "" {
+			s.steps = append(s.steps, i)
+		}
+	}
+	if len(s.steps) == 0 {
+		return fmt.Errorf("%s: recipe has no steps", files[0])
+	}
+	if *scale != "" {
+		if err := s.scale(*scale); err != nil {
+			return err
+		}
+	}
+	defer s.stopTimers()
+	s.printf("%s", runHelp)
+	s.showStep()
+	scanner := bufio.NewScanner(in)
+	for s.prompt(); scanner.Scan(); s.prompt() {
+		command, arg, _ := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		switch command {
+		case "", "n":
+			if s.current == len(s.steps)-1 {
+				s.printf("Done, enjoy your meal!\n")
+				return nil
+			}
+			s.current++
+			s.showStep()
+		case "p":
+			if s.current > 0 {
+				s.current--
+			}
+			s.showStep()
+		case "t":
+			s.startTimers()
+		case "l":
+			s.listTimers()
+		case "i":
+			s.listIngredients()
+		case "s":
+			if err := s.scale(arg); err != nil {
+				s.printf("%v\n", err)
+				continue
+			}
+			s.showStep()
+		case "h", "?":
+			s.printf("%s", runHelp)
+		case "q":
+			return nil
+		default:
+			s.printf("unknown command %q, type h for help\n", command)
+		}
+	}
+	return scanner.Err()
+}
+
+func (s *cookSession) printf(format string, a ...any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Fprintf(s.out, format, a...)
+}
+
+func (s *cookSession) prompt() {
+	s.printf("> ")
+}
+
+func (s *cookSession) step() cooklang.Step {
+	return s.recipe.Steps[s.steps[s.current]]
+}
+
+func (s *cookSession) scale(spec string) error {
+	factor, err := cooklang.ScaleFactor(s.original, spec)
+	if err != nil {
+		return err
+	}
+	s.recipe = cooklang.Scale(s.original, factor)
+	return nil
+}
+
+func (s *cookSession) showStep() {
+	step := s.step()
+	var b strings.Builder
+	fmt.Fprintf(&b, "\nStep %d/%d\n%s\n", s.current+1, len(s.steps), render.LocalizeDirections(s.opts.Locale, step))
+	var ingredients []string
+	for _, i := range step.Ingredients {
+		ingredients = append(ingredients, strings.TrimSpace(render.FormatAmount(i.Amount, s.opts)+" "+i.Name))
+	}
+	if len(ingredients) > 0 {
+		fmt.Fprintf(&b, "%s: %s\n", render.Message(s.opts.Locale, render.MsgIngredients, "Ingredients"), strings.Join(ingredients, ", "))
+	}
+	if len(step.Timers) > 0 {
+		b.WriteString("Timers: type t to start\n")
+	}
+	s.printf("%s", b.String())
+}
+
+func timerLabel(t cooklang.Timer, d time.Duration) string {
+	if t.Name != "" {
+		return fmt.Sprintf("%s (%s)", t.Name, d)
+	}
+	return d.String()
+}
+
+func (s *cookSession) startTimers() {
+	step := s.step()
+	started := 0
+	for _, t := range step.Timers {
+		d, ok := t.ToDuration()
+		if !ok {
+			continue
+		}
+		rt := &runningTimer{label: fmt.Sprintf("step %d: %s", s.current+1, timerLabel(t, d)), end: time.Now().Add(d)}
+		rt.timer = time.AfterFunc(d, func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			for i, other := range s.timers {
+				if other == rt {
+					s.timers = append(s.timers[:i], s.timers[i+1:]...)
+					break
+				}
+			}
+			fmt.Fprintf(s.out, "\a\nTimer done: %s\n> ", rt.label)
+		})
+		s.mu.Lock()
+		s.timers = append(s.timers, rt)
+		s.mu.Unlock()
+		started++
+	}
+	if started == 0 {
+		s.printf("no timers with duration in this step\n")
+		return
+	}
+	s.listTimers()
+}
+
+func (s *cookSession) listTimers() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.timers) == 0 {
+		fmt.Fprintln(s.out, "no running timers")
+		return
+	}
+	for _, t := range s.timers {
+		fmt.Fprintf(s.out, "%s: %s left\n", t.label, time.Until(t.end).Round(time.Second))
+	}
+}
+
+func (s *cookSession) listIngredients() {
+	for _, i := range s.recipe.AllIngredients(mergeOptions) {
+		s.printf("%-30s%s\n", i.Name, render.FormatAmount(i.Amount, s.opts))
+	}
+}
+
+func (s *cookSession) stopTimers() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range s.timers {
+		t.timer.Stop()
+	}
+	s.timers = nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRunCommand(t *testing.T) {
+	recipe := writeRecipe(t, "soup.cook", ">> servings: 2\nBoil @water{1%l} for ~{10%minutes}.\n\n-- meanwhile\n\nAdd @salt{1%tsp}.")
+	empty := writeRecipe(t, "empty.cook", "-- nothing to do")
+	tests := []struct {
+		name       string
+		stdin      string
+		args       []string
+		wantCode   int
+		wantStdout []string // substrings of stdout
+		wantStderr string   // substring of stderr
+	}{
+		{
+			"Session",
+			"n\np\ni\ns 2x\ns 0\nx\nn\nn\n",
+			[]string{"run", recipe},
+			exitOK,
+			[]string{
+				"Step 1/2\nBoil water for 10 minutes.\nIngredients: 1 l water\nTimers: type t to start\n",
+				"Step 2/2\nAdd salt.\nIngredients: 1 tsp salt\n",
+				"> salt                          1 tsp\nwater                         1 l\n",
+				"Ingredients: 2 l water\n",
+				`invalid servings "0"`,
+				`unknown command "x", type h for help`,
+				"Ingredients: 2 tsp salt\n> Done, enjoy your meal!\n",
+			},
+			"",
+		},
+		{"Timers", "t\nl\nq\n", []string{"run", recipe}, exitOK, []string{"> step 1: 10m0s: ", " left\n"}, ""},
+		{"Scaled", "q\n", []string{"run", "-servings", "4", recipe}, exitOK, []string{"Ingredients: 2 l water\n"}, ""},
+		{"No file", "", []string{"run"}, exitUsage, nil, "cook: usage: run: expected a single recipe file"},
+		{"Bad flag", "", []string{"run", "-nope", recipe}, exitUsage, nil, "flag provided but not defined: -nope"},
+		{"Invalid servings", "", []string{"run", "-servings", "0", recipe}, exitError, nil, `cook: invalid servings "0"`},
+		{"No steps", "", []string{"run", empty}, exitError, nil, "empty.cook: recipe has no steps"},
+		{"Missing file", "", []string{"run", "missing.cook"}, exitError, nil, "cook: open missing.cook: no such file or directory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCook(t, tt.stdin, tt.args...)
+			if code != tt.wantCode {
+				t.Errorf("run() = %d, want %d (stderr %q)", code, tt.wantCode, stderr)
+			}
+			for _, want := range tt.wantStdout {
+				if !strings.Contains(stdout, want) {
+					t.Errorf("stdout = %q, want %q in it", stdout, want)
+				}
+			}
+			if !strings.Contains(stderr, tt.wantStderr) || (tt.wantStderr == "" && stderr != "") {
+				t.Errorf("stderr = %q, want %q", stderr, tt.wantStderr)
+			}
+		})
+	}
+}
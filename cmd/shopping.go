@@ -0,0 +1,184 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/render"
+	"github.com/aquilax/cooklang-go/shoppinglist"
+)
+
+// usageError converts the flag parsing errors, which the flag set already
+// reported, to errFlags. The help request is kept as is.
+func usageError(err error) error {
+	if errors.Is(err, flag.ErrHelp) {
+		return err
+	}
+	return errFlags
+}
+
+// parseInterspersed parses the flags which can appear before, between or
+// after the positional arguments and returns the positional arguments
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, usageError(err)
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// shoppingListCommand implements "cook shopping-list [flags] file..." which
+// prints the merged ingredients of all recipe files
+func shoppingListCommand(args []string, out, stderr io.Writer, opts *render.Options) error {
+	fs := flag.NewFlagSet("shopping-list", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	servings := fs.String("servings", "", `scale the recipes: multiplier ("2x") or number of servings ("4")`)
+	format := fs.String("format", "text", "output format: text, markdown, json, url, todoist (CSV), anydo or reminders (JSON)")
+	aislesFile := fs.String("aisles", "", "aisle configuration (aisle.conf) grouping the items of the task app formats")
+	qr := fs.String("qr", "", "write the list as URL in a QR code to the PNG file (requires the qr build tag)")
+	group := fs.String("group", "", "group the items by recipe or section (text, markdown and json formats)")
+	files, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("%w: shopping-list: no recipe files", errUsage)
+	}
+	var recipes []*cooklang.Recipe
+	var sources []shoppinglist.Source
+	for _, file := range files {
+		r, err := cooklang.ParseFile(file)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		if *servings != "" {
+			factor, err := cooklang.ScaleFactor(r, *servings)
+			if err != nil {
+				return fmt.Errorf("%s: %w", file, err)
+			}
+			r = cooklang.Scale(r, factor)
+		}
+		recipes = append(recipes, r)
+		sources = append(sources, shoppinglist.Source{Name: strings.TrimSuffix(filepath.Base(file), filepath.Ext(file)), Recipe: r})
+	}
+	if *group != "" {
+		return writeShoppingGroups(out, shoppinglist.Grouping(*group), *format, sources, opts)
+	}
+	merged := shoppinglist.New(mergeOptions, recipes...)
+	var aisles shoppinglist.Aisles
+	if *aislesFile != "" {
+		f, err := os.Open(*aislesFile)
+		if err != nil {
+			return err
+		}
+		aisles, err = shoppinglist.LoadAisles(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", *aislesFile, err)
+		}
+	}
+	if *qr != "" {
+		content, err := merged.Export(shoppinglist.URL)
+		if err != nil {
+			return err
+		}
+		if err := writeQRCode(*qr, content); err != nil {
+			return err
+		}
+	}
+	switch *format {
+	case "text":
+		for _, i := range merged {
+			fmt.Fprintf(out, "%-30s%s\n", i.Name, render.FormatAmount(i.Amount, opts))
+		}
+	case "markdown":
+		fmt.Fprint(out, "# Shopping list\n\n")
+		for _, i := range merged {
+			if amount := render.FormatAmount(i.Amount, opts); amount != "" {
+				fmt.Fprintf(out, "- [ ] %s %s\n", amount, i.Name)
+			} else {
+				fmt.Fprintf(out, "- [ ] %s\n", i.Name)
+			}
+		}
+	case "url":
+		content, err := merged.Export(shoppinglist.URL)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, content)
+	case "todoist":
+		return merged.WriteTodoistCSV(out, aisles)
+	case "anydo":
+		return merged.WriteAnyDoJSON(out, aisles)
+	case "reminders":
+		return merged.WriteRemindersJSON(out, "Shopping list", aisles)
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(merged)
+	default:
+		return fmt.Errorf("%w: shopping-list: unknown format %q", errUsage, *format)
+	}
+	return nil
+}
+
+// writeShoppingGroups writes the shopping list of the sources grouped by
+// recipe or section
+func writeShoppingGroups(out io.Writer, grouping shoppinglist.Grouping, format string, sources []shoppinglist.Source, opts *render.Options) error {
+	groups, err := shoppinglist.NewGroups(mergeOptions, grouping, sources...)
+	if err != nil {
+		return fmt.Errorf("%w: shopping-list: %w", errUsage, err)
+	}
+	switch format {
+	case "text":
+		for i, g := range groups {
+			if i > 0 {
+				fmt.Fprintln(out)
+			}
+			if g.Name != "" {
+				fmt.Fprintf(out, "%s:\n", g.Name)
+			}
+			for _, item := range g.Items {
+				fmt.Fprintf(out, "%-30s%s\n", item.Name, render.FormatAmount(item.Amount, opts))
+			}
+		}
+	case "markdown":
+		content, err := shoppinglist.ExportGroups(groups, shoppinglist.Markdown)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(out, content)
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(groups)
+	default:
+		return fmt.Errorf("%w: shopping-list: format %q can not be grouped", errUsage, format)
+	}
+	return nil
+}
+
+// formatCookwareQuantity returns the quantity of the cookware or an empty
+// string for a single item
+func formatCookwareQuantity(c cooklang.Cookware) string {
+	if c.IsNumeric && c.Quantity != 1 {
+		return formatFloat(c.Quantity, 2)
+	}
+	if !c.IsNumeric {
+		return c.QuantityRaw
+	}
+	return ""
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShoppingListCommand(t *testing.T) {
+	soup := writeRecipe(t, "soup.cook", ">> servings: 2\nBoil @water{1%l} with @salt{1%tsp}.")
+	bread := writeRecipe(t, "bread.cook", "Mix @flour{500%g}, @water{300%ml} and @salt{1%tsp}.")
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStdout string
+		wantStderr string // substring of stderr
+	}{
+		{
+			"Text",
+			[]string{"shopping-list", soup, bread},
+			exitOK,
+			"flour                         500 g\nsalt                          2 tsp\nwater                         1.3 l\n",
+			"",
+		},
+		{
+			"Markdown scaled",
+			[]string{"shopping-list", "-format", "markdown", soup, "-servings", "4"},
+			exitOK,
+			"# Shopping list\n\n- [ ] 2 tsp salt\n- [ ] 2 l water\n",
+			"",
+		},
+		{"No files", []string{"shopping-list"}, exitUsage, "", "cook: usage: shopping-list: no recipe files"},
+		{"Unknown format", []string{"shopping-list", "-format", "pdf", soup}, exitUsage, "", `cook: usage: shopping-list: unknown format "pdf"`},
+		{"Format not grouped", []string{"shopping-list", "-group", "recipe", "-format", "url", soup}, exitUsage, "", `format "url" can not be grouped`},
+		{"Bad flag", []string{"shopping-list", "-nope", soup}, exitUsage, "", "flag provided but not defined: -nope"},
+		{"Invalid servings", []string{"shopping-list", "-servings", "NaN", soup}, exitError, "", "soup.cook: "},
+		{"Missing file", []string{"shopping-list", "missing.cook"}, exitError, "", "cook: missing.cook: open missing.cook: no such file or directory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCook(t, "", tt.args...)
+			if code != tt.wantCode {
+				t.Errorf("run() = %d, want %d (stderr %q)", code, tt.wantCode, stderr)
+			}
+			if stdout != tt.wantStdout {
+				t.Errorf("stdout = %q, want %q", stdout, tt.wantStdout)
+			}
+			if !strings.Contains(stderr, tt.wantStderr) || (tt.wantStderr == "" && stderr != "") {
+				t.Errorf("stderr = %q, want %q", stderr, tt.wantStderr)
+			}
+		})
+	}
+}
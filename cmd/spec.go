@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aquilax/cooklang-go/conformance"
+	"github.com/aquilax/cooklang-go/spec"
+)
+
+// specReportCommand implements "cook spec-report [flags] [canonical.json]"
+// which runs the canonical tests of the cooklang specification (the bundled
+// copy or the given file) and lists the passing and failing cases
+func specReportCommand(args []string, out, stderr io.Writer) error {
+	fs := flag.NewFlagSet("spec-report", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	failed := fs.Bool("failed", false, "list only the failing cases")
+	verbose := fs.Bool("v", false, "print the differences of the expected (-) and the parsed (+) result of the failing cases")
+	files, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	var tests *spec.Tests
+	switch len(files) {
+	case 0:
+		tests, err = spec.Canonical()
+	case 1:
+		tests, err = spec.LoadFile(files[0])
+	default:
+		return fmt.Errorf("%w: spec-report: expected at most one canonical tests file", errUsage)
+	}
+	if err != nil {
+		return err
+	}
+	report := conformance.RunTests(tests, nil)
+	for _, c := range report.Cases {
+		if c.Passed {
+			if !*failed {
+				fmt.Fprintf(out, "pass  %s\n", c.Name)
+			}
+			continue
+		}
+		fmt.Fprintf(out, "FAIL  %s\n", c.Name)
+		switch {
+		case c.Err != nil:
+			fmt.Fprintf(out, "      error: %v\n", c.Err)
+		case *verbose:
+			for _, line := range strings.Split(strings.TrimSuffix(c.Diff, "\n"), "\n") {
+				fmt.Fprintf(out, "      %s\n", line)
+			}
+		}
+	}
+	fmt.Fprintf(out, "%d of %d canonical cases pass (spec version %d)\n", report.Passed(), len(report.Cases), report.SpecVersion)
+	return nil
+}
@@ -0,0 +1,147 @@
+package cooklang
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseStringKeepCommentPositions(t *testing.T) {
+	recipe := `-- line comment
+  Mix @flour{200%g} [- not too long -] with @water{} -- end of line`
+	got, err := NewParser(&ParseConfig{KeepCommentPositions: true}).ParseString(recipe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]StepComment{
+		{{CommentTypeLine, "line comment", 0}},
+		{{CommentTypeBlock, "not too long", 10}, {CommentTypeEndLine, "end of line", 21}},
+	}
+	for i, step := range got.Steps {
+		if !reflect.DeepEqual(step.TypedComments, want[i]) {
+			t.Errorf("step %d TypedComments = %v, want %v", i, step.TypedComments, want[i])
+		}
+	}
+	if d := got.Steps[1].Directions; d != "Mix flour  with water" {
+		t.Errorf("Directions = %q", d)
+	}
+
+	got, err = ParseString(recipe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Steps[1].TypedComments != nil {
+		t.Errorf("TypedComments = %v, want nil without KeepCommentPositions", got.Steps[1].TypedComments)
+	}
+}
+
+func TestParseStringCommentModes(t *testing.T) {
+	recipe := "Mix @flour{200%g} [- not too long -] with @water{} -- end of line  "
+	tests := []struct {
+		name   string
+		mode   CommentMode
+		holder string
+		want   string
+		wantV2 StepV2
+	}{
+		{
+			"Remove", CommentsRemove, "", "Mix flour  with water",
+			StepV2{TextV2{"text", "Mix "}, IngredientV2{"ingredient", "flour", 200, "g", false, false, false, false}, TextV2{"text", " "},
+				Comment{CommentTypeBlock, "not too long"}, TextV2{"text", " with "}, IngredientV2{"ingredient", "water", 0, "", false, false, false, false},
+				TextV2{"text", " "}, Comment{CommentTypeEndLine, "end of line"}},
+		},
+		{
+			"Default placeholder", CommentsPlaceholder, "", "Mix flour … with water …",
+			StepV2{TextV2{"text", "Mix "}, IngredientV2{"ingredient", "flour", 200, "g", false, false, false, false}, TextV2{"text", " "},
+				TextV2{"text", "…"}, Comment{CommentTypeBlock, "not too long"}, TextV2{"text", " with "}, IngredientV2{"ingredient", "water", 0, "", false, false, false, false},
+				TextV2{"text", " "}, TextV2{"text", "…"}, Comment{CommentTypeEndLine, "end of line"}},
+		},
+		{
+			"Placeholder", CommentsPlaceholder, "(see note)", "Mix flour (see note) with water (see note)",
+			nil,
+		},
+		{
+			"Preserve", CommentsPreserve, "", "Mix flour [- not too long -] with water -- end of line",
+			StepV2{TextV2{"text", "Mix "}, IngredientV2{"ingredient", "flour", 200, "g", false, false, false, false}, TextV2{"text", " "},
+				TextV2{"text", "[- not too long -]"}, Comment{CommentTypeBlock, "not too long"}, TextV2{"text", " with "}, IngredientV2{"ingredient", "water", 0, "", false, false, false, false},
+				TextV2{"text", " "}, TextV2{"text", "-- end of line"}, Comment{CommentTypeEndLine, "end of line"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewParser(&ParseConfig{Comments: tt.mode, CommentPlaceholder: tt.holder}).ParseString(recipe)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := r.Steps[0].Directions; got != tt.want {
+				t.Errorf("Directions = %q, want %q", got, tt.want)
+			}
+			if want := []string{"not too long", "end of line"}; !reflect.DeepEqual(r.Steps[0].Comments, want) {
+				t.Errorf("Comments = %q, want %q", r.Steps[0].Comments, want)
+			}
+			v2, err := NewParserV2(&ParseV2Config{Comments: tt.mode, CommentPlaceholder: tt.holder}).ParseString(recipe)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantV2 != nil && !reflect.DeepEqual(v2.Steps[0], tt.wantV2) {
+				t.Errorf("v2 step = %#v, want %#v", v2.Steps[0], tt.wantV2)
+			}
+		})
+	}
+}
+
+func TestParseMultiLineBlockComment(t *testing.T) {
+	recipe := "Mix @flour{200%g} [- sifted,\n\nor not -] with @water{}.\n[- a note\n-- still the note -]\nBake."
+	got, err := ParseString(recipe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Step{
+		{Directions: "Mix flour  with water.", Timers: []Timer{}, Cookware: []Cookware{},
+			Ingredients: []Ingredient{{Name: "flour", Amount: IngredientAmount{true, 200, "200", "g"}}, {Name: "water"}},
+			Comments:    []string{"sifted,\n\nor not"}},
+		{Timers: []Timer{}, Cookware: []Cookware{}, Ingredients: []Ingredient{}, Comments: []string{"a note\n-- still the note"}},
+		{Directions: "Bake.", Timers: []Timer{}, Cookware: []Cookware{}, Ingredients: []Ingredient{}},
+	}
+	if !reflect.DeepEqual(got.Steps, want) {
+		t.Errorf("Steps = %#v, want %#v", got.Steps, want)
+	}
+
+	if _, err := ParseString("Mix [- open\ncomment"); !errors.Is(err, ErrUnterminatedComment) || err.Error() != "line 1: unterminated block comment" {
+		t.Errorf("ParseString() error = %v, want ErrUnterminatedComment", err)
+	}
+	if _, err := ParseCST("Mix [- open\ncomment"); !errors.Is(err, ErrUnterminatedComment) {
+		t.Errorf("ParseCST() error = %v, want ErrUnterminatedComment", err)
+	}
+
+	cst, err := ParseCST(recipe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cst.String() != recipe {
+		t.Errorf("CST.String() = %q, want %q", cst.String(), recipe)
+	}
+	for _, n := range cst.Nodes {
+		if n.Type == ItemTypeIngredient && n.Item.(Ingredient).Name == "water" && n.Line != 3 {
+			t.Errorf("water node line = %d, want 3", n.Line)
+		}
+	}
+
+	for e, err := range Tokenize(strings.NewReader(recipe)) {
+		if err != nil {
+			t.Fatalf("Tokenize() error = %v", err)
+		}
+		if c, ok := e.Item.(Comment); ok && c.Value == "a note\n-- still the note" && e.Line != 4 {
+			t.Errorf("Tokenize() comment line = %d, want 4", e.Line)
+		}
+	}
+
+	out, err := RoundTrip(recipe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, err := ParseString(out); err != nil || !reflect.DeepEqual(again.Steps, want) {
+		t.Errorf("RoundTrip() = %q, parsed %v, %v", out, again, err)
+	}
+}
@@ -0,0 +1,123 @@
+// Package conformance checks a parser against the canonical tests of the
+// cooklang specification, so the embedders can verify the spec level in
+// their CI
+package conformance
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/spec"
+)
+
+// Case is the outcome of a canonical test case
+type Case struct {
+	Name   string
+	Passed bool
+	Err    error // parse error
+	// Diff lists the lines of the expected (-) and the parsed (+) result
+	// as indented JSON which differ. Empty for the passing cases.
+	Diff string
+}
+
+// Report is the outcome of the canonical tests
+type Report struct {
+	SpecVersion int    // version of the canonical tests
+	Cases       []Case // cases sorted by name
+}
+
+// Passed returns the number of passing cases
+func (r Report) Passed() int {
+	passed := 0
+	for _, c := range r.Cases {
+		if c.Passed {
+			passed++
+		}
+	}
+	return passed
+}
+
+// Failed returns the failing cases
+func (r Report) Failed() []Case {
+	var result []Case
+	for _, c := range r.Cases {
+		if !c.Passed {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// Run runs the bundled canonical tests (see the spec package) with the
+// parser. The comments are not part of the canonical results, nil uses a
+// parser ignoring them.
+func Run(parser *cooklang.ParserV2) (Report, error) {
+	tests, err := spec.Canonical()
+	if err != nil {
+		return Report{}, err
+	}
+	return RunTests(tests, parser), nil
+}
+
+// RunTests runs the tests with the parser (see Run)
+func RunTests(tests *spec.Tests, parser *cooklang.ParserV2) Report {
+	if parser == nil {
+		parser = cooklang.NewParserV2(&cooklang.ParseV2Config{IgnoreTypes: []cooklang.ItemType{cooklang.ItemTypeComment}})
+	}
+	report := Report{SpecVersion: tests.Version}
+	for _, r := range spec.Run(tests, parser) {
+		c := Case{Name: r.Name, Passed: r.Passed, Err: r.Err}
+		if !r.Passed && r.Err == nil {
+			c.Diff = diff(indent(r.Want), indent(r.Got))
+		}
+		report.Cases = append(report.Cases, c)
+	}
+	return report
+}
+
+// indent returns the lines of the indented JSON document
+func indent(s string) []string {
+	var b bytes.Buffer
+	if err := json.Indent(&b, []byte(s), "", "  "); err != nil {
+		return strings.Split(s, "\n")
+	}
+	return strings.Split(b.String(), "\n")
+}
+
+// diff returns the line diff of want and got: the removed lines prefixed
+// by "-" and the added ones by "+", with the common lines left out
+func diff(want, got []string) string {
+	// lcs[i][j] is the length of the longest common subsequence of
+	// want[i:] and got[j:]
+	lcs := make([][]int, len(want)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(got)+1)
+	}
+	for i := len(want) - 1; i >= 0; i-- {
+		for j := len(got) - 1; j >= 0; j-- {
+			if want[i] == got[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var b strings.Builder
+	i, j := 0, 0
+	for i < len(want) || j < len(got) {
+		switch {
+		case i < len(want) && j < len(got) && want[i] == got[j]:
+			i++
+			j++
+		case i < len(want) && (j == len(got) || lcs[i+1][j] >= lcs[i][j+1]):
+			b.WriteString("-" + want[i] + "\n")
+			i++
+		default:
+			b.WriteString("+" + got[j] + "\n")
+			j++
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,53 @@
+package conformance
+
+import (
+	"testing"
+
+	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/spec"
+)
+
+func TestRun(t *testing.T) {
+	report, err := Run(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests, err := spec.Canonical()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.SpecVersion != tests.Version || len(report.Cases) != len(tests.Tests) {
+		t.Fatalf("report of version %d with %d cases, want %d with %d", report.SpecVersion, len(report.Cases), tests.Version, len(tests.Tests))
+	}
+	if report.Passed()+len(report.Failed()) != len(report.Cases) {
+		t.Errorf("Passed() = %d, Failed() = %d, want %d cases", report.Passed(), len(report.Failed()), len(report.Cases))
+	}
+	for _, c := range report.Cases {
+		if c.Passed != (c.Diff == "" && c.Err == nil) {
+			t.Errorf("%s: Passed = %v with Diff %q and Err %v", c.Name, c.Passed, c.Diff, c.Err)
+		}
+	}
+}
+
+func TestRunTests(t *testing.T) {
+	tests := &spec.Tests{Version: 1, Tests: map[string]spec.TestCase{
+		"pass": {Source: "Boil water.", Result: spec.Result{
+			Steps:    [][]spec.Item{{{Type: "text", Value: "Boil water."}}},
+			Metadata: map[string]any{},
+		}},
+		"fail": {Source: "Boil water.\n\nServe.", Result: spec.Result{
+			Steps:    [][]spec.Item{{{Type: "text", Value: "Boil the water."}}, {{Type: "text", Value: "Serve."}}},
+			Metadata: map[string]any{},
+		}},
+	}}
+	report := RunTests(tests, cooklang.NewParserV2(nil))
+	if report.Passed() != 1 || len(report.Failed()) != 1 || report.Failed()[0].Name != "fail" {
+		t.Fatalf("report = %+v, want a passing and a failing case", report)
+	}
+	want := `-        "value": "Boil the water."
++        "value": "Boil water."
+`
+	if got := report.Failed()[0].Diff; got != want {
+		t.Errorf("Diff = %s, want %s", got, want)
+	}
+}
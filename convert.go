@@ -0,0 +1,248 @@
+package cooklang
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// itemDirections returns the text of the item in the step directions
+func itemDirections(item any) string {
+	t := tokenizer{features: latestFeatures}
+	t.appendItem(item)
+	return string(t.directions)
+}
+
+// ToV2 converts the recipe to the v2 model. The v1 steps do not keep the
+// order of the items, so it is recovered from the directions: every item is
+// placed at the first remaining occurrence of its text, which can differ from
+// the source when an item name is also written as plain text before the
+// item, and the hidden ingredients, which have no text, are placed at the
+// position of the previous item. The comments are placed at their positions when the recipe was
+// parsed with ParseConfig.KeepCommentPositions, otherwise they are appended
+// to the step. Textual quantities are not part of the v2 model.
+func (r *Recipe) ToV2() *RecipeV2 {
+	result := &RecipeV2{Steps: make([]StepV2, 0, len(r.Steps)), Metadata: make(Metadata, len(r.Metadata)), MetadataOrder: slices.Clone(r.MetadataOrder), MetadataLists: cloneLists(r.MetadataLists)}
+	for k, v := range r.Metadata {
+		result.Metadata[k] = v
+	}
+	for _, step := range r.Steps {
+		result.Steps = append(result.Steps, stepToV2(step))
+	}
+	return result
+}
+
+func stepToV2(step Step) StepV2 {
+	if step.Directions == "" && step.Ingredients == nil && step.Cookware == nil && step.Timers == nil && len(step.Comments) == 1 {
+		// line comment
+		return StepV2{Comment{CommentTypeLine, step.Comments[0]}}
+	}
+	var items []any
+	for _, i := range step.Ingredients {
+		items = append(items, i)
+	}
+	var cookware []any
+	for _, c := range step.Cookware {
+		cookware = append(cookware, c)
+	}
+	var timers []any
+	for _, t := range step.Timers {
+		timers = append(timers, t)
+	}
+	var links []any
+	for _, l := range step.Links {
+		links = append(links, l)
+	}
+	queues := [][]any{items, cookware, timers, links}
+	comments := step.TypedComments
+	if comments == nil {
+		for _, c := range step.Comments {
+			comments = append(comments, StepComment{CommentTypeBlock, c, len(step.Directions)})
+		}
+	}
+
+	result := StepV2{}
+	text := step.Directions
+	pos := 0
+	emitText := func(end int) {
+		if end > pos {
+			result = append(result, TextV2{ItemTypeText, text[pos:end]})
+			pos = end
+		}
+	}
+	for {
+		// the comment or the item which comes first, comments win the ties
+		next, index, queue := any(nil), -1, -1
+		if len(comments) > 0 {
+			next, index = Comment{comments[0].Type, comments[0].Value}, max(comments[0].Offset, pos)-pos
+		}
+		for q, items := range queues {
+			if len(items) == 0 {
+				continue
+			}
+			i := strings.Index(text[pos:], itemDirections(items[0]))
+			if i != -1 && (index == -1 || i < index) {
+				next, index, queue = items[0], i, q
+			}
+		}
+		if next == nil {
+			break
+		}
+		emitText(pos + index)
+		switch v := next.(type) {
+		case Comment:
+			result = append(result, v)
+			comments = comments[1:]
+		case Ingredient:
+			result = append(result, v.asIngredientV2())
+		case Cookware:
+			result = append(result, v.asCookwareV2())
+		case Timer:
+			result = append(result, v.asTimerV2())
+		case Link:
+			result = append(result, v.asLinkV2())
+		}
+		if queue != -1 {
+			queues[queue] = queues[queue][1:]
+			pos += len(itemDirections(next))
+		}
+	}
+	emitText(len(text))
+	// items whose text was not found in the directions
+	for _, items := range queues {
+		for _, item := range items {
+			switch v := item.(type) {
+			case Ingredient:
+				result = append(result, v.asIngredientV2())
+			case Cookware:
+				result = append(result, v.asCookwareV2())
+			case Timer:
+				result = append(result, v.asTimerV2())
+			case Link:
+				result = append(result, v.asLinkV2())
+			}
+		}
+	}
+	return result
+}
+
+// cloneLists returns a deep copy of the metadata lists
+func cloneLists(lists map[string][]string) map[string][]string {
+	if lists == nil {
+		return nil
+	}
+	result := make(map[string][]string, len(lists))
+	for k, v := range lists {
+		result[k] = slices.Clone(v)
+	}
+	return result
+}
+
+// ToV1 converts the recipe to the v1 model. The v2 model has no textual
+// quantities: zero quantities without units become empty amounts
+// (@salt{}), ingredient quantities of one without units and cookware
+// quantities of one become single word amounts (@salt, #pot) and the other
+// quantities are numeric. Temperature items are written as text (180°C)
+// and custom items are kept in the directions only. Returns an error for
+// unknown item types.
+func (r *RecipeV2) ToV1() (*Recipe, error) {
+	result := &Recipe{Steps: make([]Step, 0, len(r.Steps)), Metadata: make(Metadata, len(r.Metadata)), MetadataOrder: slices.Clone(r.MetadataOrder), MetadataLists: cloneLists(r.MetadataLists)}
+	for k, v := range r.Metadata {
+		result.Metadata[k] = v
+	}
+	for i, step := range r.Steps {
+		s, err := stepToV1(step)
+		if err != nil {
+			return nil, fmt.Errorf("step %d: %w", i+1, err)
+		}
+		result.Steps = append(result.Steps, s)
+	}
+	return result, nil
+}
+
+func stepToV1(step StepV2) (Step, error) {
+	if len(step) == 1 {
+		if c, ok := step[0].(Comment); ok && c.Type == CommentTypeLine {
+			return Step{Comments: []string{c.Value}}, nil
+		}
+	}
+	s := Step{
+		Timers:      make([]Timer, 0),
+		Ingredients: make([]Ingredient, 0),
+		Cookware:    make([]Cookware, 0),
+	}
+	for _, item := range step {
+		switch v := item.(type) {
+		case TextV2:
+			// the text is part of the directions only
+		case TemperatureV2:
+			s.Temperatures = append(s.Temperatures, v.asTemperature())
+		case Comment:
+			s.Comments = append(s.Comments, v.Value)
+		case IngredientV2:
+			ingredient := Ingredient{Name: v.Name, Amount: IngredientAmount{Quantity: v.Quantity, Unit: v.Units}, Reference: v.Reference, Hidden: v.Hidden, Fixed: v.Fixed, Optional: v.Optional}
+			if (v.Quantity != 0 && v.Quantity != 1) || v.Units != "" {
+				ingredient.Amount.IsNumeric = true
+				ingredient.Amount.QuantityRaw = strconv.FormatFloat(v.Quantity, 'f', -1, 64)
+			}
+			s.Ingredients = append(s.Ingredients, ingredient)
+		case CookwareV2:
+			cookware := Cookware{Name: v.Name, Quantity: v.Quantity, Note: v.Note, Optional: v.Optional}
+			if v.Quantity != 1 {
+				cookware.IsNumeric = true
+				cookware.QuantityRaw = strconv.FormatFloat(v.Quantity, 'f', -1, 64)
+			}
+			s.Cookware = append(s.Cookware, cookware)
+		case TimerV2:
+			s.Timers = append(s.Timers, v.asTimer())
+		case LinkV2:
+			s.Links = append(s.Links, Link{v.URL})
+		case CustomItem:
+		default:
+			return Step{}, fmt.Errorf("unknown item type %T", item)
+		}
+	}
+	s.Directions = step.Directions()
+	return s, nil
+}
+
+// Directions returns the step directions as plain text, assembled like
+// Step.Directions: the text as written, the names of the ingredients and
+// the cookware, the timers as "N unit" (or their name without duration)
+// and the custom items and the links as written. The comments are left out.
+func (s StepV2) Directions() string {
+	var b strings.Builder
+	for _, item := range s {
+		switch v := item.(type) {
+		case TextV2:
+			b.WriteString(v.Value)
+		case TemperatureV2:
+			b.WriteString(v.asTemperature().Raw)
+		case IngredientV2:
+			if !v.Hidden {
+				b.WriteString(v.Name)
+				continue
+			}
+			// no gap is left for the hidden ingredients
+			text := strings.TrimRightFunc(b.String(), unicode.IsSpace)
+			b.Reset()
+			b.WriteString(text)
+		case CookwareV2:
+			b.WriteString(v.Name)
+		case TimerV2:
+			b.WriteString(itemDirections(v.asTimer()))
+		case CustomItem:
+			b.WriteString(itemDirections(v))
+		case LinkV2:
+			b.WriteString(v.URL)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// String returns the step directions (see Directions)
+func (s StepV2) String() string {
+	return s.Directions()
+}
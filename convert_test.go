@@ -0,0 +1,141 @@
+package cooklang
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestRecipeToV2(t *testing.T) {
+	tests := []struct {
+		name   string
+		recipe string
+	}{
+		{
+			"items in order",
+			`>> servings: 2
+Put @salt and @water{1%l} in the #pot{} for ~{10%minutes}.`,
+		},
+		{
+			"repeated names",
+			`Add @salt, then more @salt{2%g} and ~rest for ~{5%minutes}.`,
+		},
+		{
+			"comments",
+			`-- line comment
+Mix @flour{200%g} [- not too long -] with @water{}`,
+		},
+		{
+			"adjacent items",
+			`@eggs{2}#bowl{}~{3%minutes}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v1, err := NewParser(&ParseConfig{KeepCommentPositions: true}).ParseString(tt.recipe)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := NewParserV2(&ParseV2Config{}).ParseString(tt.recipe)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := v1.ToV2(); !reflect.DeepEqual(got, want) {
+				t.Errorf("ToV2() = %#v, want %#v", got, want)
+			}
+		})
+	}
+}
+
+func TestRecipeToV2WithoutCommentPositions(t *testing.T) {
+	r, err := ParseString(`Mix [- gently -] @flour`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := StepV2{
+		TextV2{ItemTypeText, "Mix  "},
+		IngredientV2{ItemTypeIngredient, "flour", 1, "", false, false, false, false},
+		Comment{CommentTypeBlock, "gently"},
+	}
+	if got := r.ToV2().Steps[0]; !reflect.DeepEqual(got, want) {
+		t.Errorf("ToV2() = %#v, want %#v", got, want)
+	}
+}
+
+func TestRecipeV2ToV1(t *testing.T) {
+	tests := []struct {
+		name   string
+		recipe string
+	}{
+		{
+			"items",
+			`>> servings: 2
+Put @salt and @water{1.5%l} in the #pot and #pans{2} for ~{10%minutes}.`,
+		},
+		{
+			"empty amounts and timers without duration",
+			`Add @pepper{} and ~rest.`,
+		},
+		{
+			"comments",
+			`-- line comment
+Mix @flour{200%g} [- not too long -] with @water{}`,
+		},
+		{
+			"temperatures",
+			`Bake in the #oven at 180°C for ~{30%minutes}.`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v2, err := NewParserV2(&ParseV2Config{DetectTemperatures: true}).ParseString(tt.recipe)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := NewParser(&ParseConfig{DetectTemperatures: true}).ParseString(tt.recipe)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := v2.ToV1()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ToV1() = %#v, want %#v", got, want)
+			}
+		})
+	}
+}
+
+func TestRecipeV2ToV1UnknownItem(t *testing.T) {
+	r := &RecipeV2{Steps: []StepV2{{TextV2{ItemTypeText, "Mix"}, 42}}, Metadata: Metadata{}}
+	if _, err := r.ToV1(); err == nil {
+		t.Error("ToV1() error = nil, want an error for the unknown item type")
+	}
+}
+
+func TestStepV2_Directions(t *testing.T) {
+	tests := []string{
+		"Mix @flour{200%g} in a #bowl{} for ~{2%minutes}.",
+		"  Let it ~rest{} [- a note -] then bake at 180°C -- or 356°F",
+		"Add @salt and @black pepper{} to taste ",
+		"-- line comment",
+	}
+	for _, recipe := range tests {
+		v1, err := ParseString(recipe)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v2, err := NewParserV2(&ParseV2Config{DetectTemperatures: true}).ParseString(recipe)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := v1.Steps[0].Directions
+		if got := v2.Steps[0].Directions(); got != want {
+			t.Errorf("Directions() = %q, want %q", got, want)
+		}
+		if got := fmt.Sprint(v2.Steps[0]); got != want {
+			t.Errorf("String() = %q, want %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package cooklang
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// CollectCookware returns the cookware used in the recipe, one item per name
+// (case insensitive, first spelling wins) sorted by name. The same cookware
+// is reused between the steps, so the quantity is the largest one of all
+// steps. Textual quantities ("a large") are kept: the distinct ones are
+// joined in QuantityRaw and the item is not numeric. The first note is kept
+// and the cookware is optional when it is optional in every step.
+func CollectCookware(r *Recipe) []Cookware {
+	var result []Cookware
+	index := make(map[string]int)
+	textual := make(map[string][]string)
+	for _, step := range r.Steps {
+		for _, c := range step.Cookware {
+			key := strings.ToLower(c.Name)
+			i, ok := index[key]
+			if !ok {
+				i = len(result)
+				index[key] = i
+				result = append(result, Cookware{Name: c.Name, Quantity: c.Quantity, Optional: c.Optional})
+			}
+			result[i].Optional = result[i].Optional && c.Optional
+			if result[i].Note == "" {
+				result[i].Note = c.Note
+			}
+			result[i].Quantity = max(result[i].Quantity, c.Quantity)
+			result[i].IsNumeric = result[i].IsNumeric || c.IsNumeric
+			if !c.IsNumeric && c.QuantityRaw != "" && !slices.Contains(textual[key], c.QuantityRaw) {
+				textual[key] = append(textual[key], c.QuantityRaw)
+			}
+		}
+	}
+	for i := range result {
+		c := &result[i]
+		if raws := textual[strings.ToLower(c.Name)]; len(raws) > 0 {
+			if c.IsNumeric {
+				raws = append([]string{strconv.FormatFloat(c.Quantity, 'f', -1, 64)}, raws...)
+			}
+			c.IsNumeric = false
+			c.QuantityRaw = strings.Join(raws, ", ")
+		} else if c.IsNumeric {
+			c.QuantityRaw = strconv.FormatFloat(c.Quantity, 'f', -1, 64)
+		}
+	}
+	slices.SortStableFunc(result, func(a, b Cookware) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	return result
+}
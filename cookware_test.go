@@ -0,0 +1,79 @@
+package cooklang
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCollectCookware(t *testing.T) {
+	tests := []struct {
+		name   string
+		recipe string
+		want   []Cookware
+	}{
+		{
+			"single items",
+			"Put the #pot on the #stove{}.",
+			[]Cookware{{false, "pot", 1, "", "", false}, {false, "stove", 1, "", "", false}},
+		},
+		{
+			"largest quantity",
+			"Put in #pot{2} and #pan{}.\nUse the #Pot{3} and #pan{}.\nUse one #pot{}.",
+			[]Cookware{{false, "pan", 1, "", "", false}, {true, "pot", 3, "3", "", false}},
+		},
+		{
+			"textual quantities",
+			"Use #bowl{a large} and #bowl{2}.\nUse #bowl{a large}.",
+			[]Cookware{{false, "bowl", 2, "2, a large", "", false}},
+		},
+		{
+			"no cookware",
+			"Add @salt.",
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := ParseString(tt.recipe)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := CollectCookware(r); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CollectCookware() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCookwareNotes(t *testing.T) {
+	r, err := ParseString("Heat the #pan{}(cast iron preferred), a #?thermometer and a #pot(large).\nUse the #?pan.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Cookware{
+		{Name: "pan", Quantity: 1, Note: "cast iron preferred"},
+		{Name: "thermometer", Quantity: 1, Optional: true},
+		{Name: "pot", Quantity: 1, Note: "large"},
+	}
+	if !reflect.DeepEqual(r.Steps[0].Cookware, want) {
+		t.Errorf("cookware = %+v, want %+v", r.Steps[0].Cookware, want)
+	}
+	if got := r.Steps[0].Directions; got != "Heat the pan, a thermometer and a pot." {
+		t.Errorf("directions = %q", got)
+	}
+	collected := CollectCookware(r)
+	if c := collected[0]; c.Name != "pan" || c.Optional || c.Note != "cast iron preferred" {
+		t.Errorf("CollectCookware() pan = %+v, want required with the note", c)
+	}
+	v2 := r.ToV2()
+	if got := v2.Steps[0][1]; !reflect.DeepEqual(got, CookwareV2{ItemTypeCookware, "pan", 1, "cast iron preferred", false}) {
+		t.Errorf("ToV2() cookware = %+v", got)
+	}
+	v1, err := v2.ToV1()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(v1.Steps[0].Cookware, want) {
+		t.Errorf("ToV1() cookware = %+v, want %+v", v1.Steps[0].Cookware, want)
+	}
+}
@@ -0,0 +1,133 @@
+package cooklang
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	ItemTypeMetadata ItemType = "metadata"
+	ItemTypeNewline  ItemType = "newline"
+)
+
+// MetadataEntry is a metadata line of the concrete syntax tree
+type MetadataEntry struct {
+	Key   string
+	Value string
+}
+
+// CSTNode is a node of the concrete syntax tree
+type CSTNode struct {
+	Type   ItemType // type of the node
+	Raw    string   // source text of the node as written
+	Offset int      // byte offset of the node in the parsed source
+	Line   int      // one based line number of the node in the parsed source
+	// Item is the parsed value of the node: Text, Ingredient, Cookware, Timer,
+	// Comment or MetadataEntry. It is nil for the line terminators.
+	Item any
+}
+
+// CST is a concrete syntax tree of a recipe. Unlike Recipe it keeps all the
+// source text: white space, blank lines, comments, line terminators and the
+// original spelling of the items, so the concatenation of the Raw values of
+// the nodes is the parsed source. Tools can rewrite the Raw value of some
+// nodes and write the result with String without touching the rest of the
+// file.
+type CST struct {
+	Nodes []CSTNode
+}
+
+// ParseCST parses the recipe source into a concrete syntax tree. Every line
+// is split into nodes followed by an ItemTypeNewline node, except for the
+// last line when the source does not end with a line terminator. Metadata and
+// line comments are single nodes, step lines are split into text and item
+// nodes, and blank lines are text nodes. A block comment spanning several
+// lines is a single node with the line terminators in its Raw value. Errors are reported as by
+// ParseString.
+func ParseCST(src string) (*CST, error) {
+	var cst CST
+	t := tokenizer{features: latestFeatures}
+	offset := 0
+	for lineNumber := 1; offset < len(src); lineNumber++ {
+		line, newline := cutLine(src[offset:])
+		first := lineNumber
+		if isStepLine(line) && openBlockComment(line) {
+			// the lines of a block comment spanning several lines are
+			// part of the step line
+			for openBlockComment(line) {
+				if newline == "" {
+					return nil, fmt.Errorf("line %d: %w", first, ErrUnterminatedComment)
+				}
+				next, nextNewline := cutLine(src[offset+len(line)+len(newline):])
+				line = src[offset : offset+len(line)+len(newline)+len(next)]
+				newline = nextNewline
+				lineNumber++
+			}
+		}
+		add := func(itemType ItemType, start, end int, item any) {
+			cst.Nodes = append(cst.Nodes, CSTNode{itemType, line[start:end], offset + start, first + strings.Count(line[:start], "\n"), item})
+		}
+		switch {
+		case strings.TrimSpace(line) == "":
+			if line != "" {
+				add(ItemTypeText, 0, len(line), newText(line))
+			}
+		case strings.HasPrefix(line, commentsLinePrefix):
+			comment, _ := parseSingleLineComment(line)
+			add(ItemTypeComment, 0, len(line), Comment{CommentTypeLine, comment})
+		case strings.HasPrefix(line, metadataLinePrefix):
+			key, value, err := parseMetadata(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			add(ItemTypeMetadata, 0, len(line), MetadataEntry{key, value})
+		default:
+			_, err := t.tokenize(line, func(item any) (bool, error) {
+				switch item.(type) {
+				case Text:
+					add(ItemTypeText, t.start, t.end, item)
+				case Ingredient:
+					add(ItemTypeIngredient, t.start, t.end, item)
+				case Cookware:
+					add(ItemTypeCookware, t.start, t.end, item)
+				case Timer:
+					add(ItemTypeTimer, t.start, t.end, item)
+				case Comment:
+					add(ItemTypeComment, t.start, t.end, item)
+				}
+				return false, nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", first, err)
+			}
+		}
+		offset += len(line)
+		if newline != "" {
+			cst.Nodes = append(cst.Nodes, CSTNode{ItemTypeNewline, newline, offset, lineNumber, nil})
+			offset += len(newline)
+		}
+	}
+	return &cst, nil
+}
+
+// cutLine returns the first line of s and its line terminator, "\n", "\r\n"
+// or empty for the last line without terminator
+func cutLine(s string) (line, newline string) {
+	line, _, found := strings.Cut(s, "\n")
+	if !found {
+		return line, ""
+	}
+	if before, ok := strings.CutSuffix(line, "\r"); ok {
+		return before, "\r\n"
+	}
+	return line, "\n"
+}
+
+// String returns the source text of the tree
+func (c *CST) String() string {
+	var b strings.Builder
+	for _, n := range c.Nodes {
+		b.WriteString(n.Raw)
+	}
+	return b.String()
+}
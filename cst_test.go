@@ -0,0 +1,67 @@
+package cooklang
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCST(t *testing.T) {
+	src := ">> servings:  2\r\n\r\n  Add @salt and @olive oil{ 2 %tbsp}[- or butter -]\n-- note\nBoil ~{10%min} -- covered"
+	got, err := ParseCST(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []CSTNode{
+		{ItemTypeMetadata, ">> servings:  2", 0, 1, MetadataEntry{"servings", "2"}},
+		{ItemTypeNewline, "\r\n", 15, 1, nil},
+		{ItemTypeNewline, "\r\n", 17, 2, nil},
+		{ItemTypeText, "  Add ", 19, 3, Text{"  Add "}},
+		{ItemTypeIngredient, "@salt", 25, 3, Ingredient{Name: "salt", Amount: IngredientAmount{Quantity: 1}}},
+		{ItemTypeText, " and ", 30, 3, Text{" and "}},
+		{ItemTypeIngredient, "@olive oil{ 2 %tbsp}", 35, 3, Ingredient{Name: "olive oil", Amount: IngredientAmount{IsNumeric: true, Quantity: 2, QuantityRaw: "2", Unit: "tbsp"}}},
+		{ItemTypeComment, "[- or butter -]", 55, 3, Comment{CommentTypeBlock, "or butter"}},
+		{ItemTypeNewline, "\n", 70, 3, nil},
+		{ItemTypeComment, "-- note", 71, 4, Comment{CommentTypeLine, "note"}},
+		{ItemTypeNewline, "\n", 78, 4, nil},
+		{ItemTypeText, "Boil ", 79, 5, Text{"Boil "}},
+		{ItemTypeTimer, "~{10%min}", 84, 5, Timer{Duration: 10, Unit: "min"}},
+		{ItemTypeText, " ", 93, 5, Text{" "}},
+		{ItemTypeComment, "-- covered", 94, 5, Comment{CommentTypeEndLine, "covered"}},
+	}
+	if !reflect.DeepEqual(got.Nodes, want) {
+		t.Errorf("ParseCST() = %+v, want %+v", got.Nodes, want)
+	}
+	for _, n := range got.Nodes {
+		if src[n.Offset:n.Offset+len(n.Raw)] != n.Raw {
+			t.Errorf("node %+v does not match the source at its offset", n)
+		}
+	}
+}
+
+func TestParseCSTString(t *testing.T) {
+	tests := []string{
+		"",
+		"\n\n",
+		"  \t\n",
+		"Mix @x{  and #y  ~\n",
+		"a\r\nb\r",
+		benchmarkRecipe,
+	}
+	for _, src := range tests {
+		cst, err := ParseCST(src)
+		if err != nil {
+			t.Fatalf("ParseCST(%q) error = %v", src, err)
+		}
+		if got := cst.String(); got != src {
+			t.Errorf("ParseCST(%q).String() = %q", src, got)
+		}
+	}
+}
+
+func TestParseCSTError(t *testing.T) {
+	for _, src := range []string{">> no separator", "Mix [- unterminated"} {
+		if _, err := ParseCST(src); err == nil {
+			t.Errorf("ParseCST(%q) error = nil, want error", src)
+		}
+	}
+}
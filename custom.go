@@ -0,0 +1,32 @@
+package cooklang
+
+// ItemTypeCustom is the type of the items with custom prefixes
+const ItemTypeCustom ItemType = "custom"
+
+// CustomExtractor returns the value of a custom prefix item from its raw
+// text without the prefix ("price{3.50}" for "$price{3.50}"). The returned
+// error is reported as an ItemError.
+type CustomExtractor func(raw string) (any, error)
+
+// CustomItem is an item with a prefix registered in
+// ParseV2Config.CustomPrefixes. The raw text follows the same rules as the
+// ingredients: a single word or a name followed by braces ($price{3.50}).
+type CustomItem struct {
+	Type   ItemType `json:"type"`
+	Prefix string   `json:"prefix"`
+	Raw    string   `json:"raw"`
+	Value  any      `json:"value,omitempty"`
+}
+
+func getCustomItem(s string, extractor CustomExtractor) (CustomItem, int, error) {
+	endIndex := findNodeEndIndex(s)
+	raw := s[1:endIndex]
+	if raw == "" {
+		return CustomItem{}, endIndex, newItemError(ItemTypeCustom, s[:endIndex], ErrEmptyItem)
+	}
+	value, err := extractor(raw)
+	if err != nil {
+		return CustomItem{}, endIndex, newItemError(ItemTypeCustom, s[:endIndex], err)
+	}
+	return CustomItem{ItemTypeCustom, s[:1], raw, value}, endIndex, nil
+}
@@ -0,0 +1,80 @@
+package cooklang
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParserV2CustomPrefixes(t *testing.T) {
+	errNoPrice := errors.New("no price")
+	config := &ParseV2Config{
+		CustomPrefixes: map[byte]CustomExtractor{
+			'&': func(raw string) (any, error) { return raw, nil },
+			'$': func(raw string) (any, error) {
+				name, price, found := strings.Cut(strings.TrimSuffix(raw, "}"), "{")
+				if !found {
+					return nil, errNoPrice
+				}
+				f, err := strconv.ParseFloat(price, 64)
+				return map[string]any{"name": name, "price": f}, err
+			},
+			'@': func(raw string) (any, error) { return nil, errors.New("not called") },
+		},
+	}
+	tests := []struct {
+		name   string
+		recipe string
+		want   StepV2
+	}{
+		{
+			"Custom items",
+			"Use &mixer-01 with @flour{} ($flour{1.5})",
+			StepV2{
+				TextV2{ItemTypeText, "Use "},
+				CustomItem{ItemTypeCustom, "&", "mixer-01", "mixer-01"},
+				TextV2{ItemTypeText, " with "},
+				IngredientV2{ItemTypeIngredient, "flour", 0, "", false, false, false, false},
+				TextV2{ItemTypeText, " ("},
+				CustomItem{ItemTypeCustom, "$", "flour{1.5}", map[string]any{"name": "flour", "price": 1.5}},
+				TextV2{ItemTypeText, ")"},
+			},
+		},
+		{
+			"Extractor error is kept as text",
+			"Costs $5 & more",
+			StepV2{TextV2{ItemTypeText, "Costs $5 & more"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewParserV2(config).ParseString(tt.recipe)
+			if err != nil {
+				t.Fatalf("ParseString() error = %v", err)
+			}
+			if !reflect.DeepEqual(got.Steps[0], tt.want) {
+				t.Errorf("ParseString() = %#v, want %#v", got.Steps[0], tt.want)
+			}
+		})
+	}
+
+	config.Strict = true
+	_, err := NewParserV2(config).ParseString("Costs $5")
+	if !errors.Is(err, errNoPrice) {
+		t.Errorf("ParseString() error = %v, want %v", err, errNoPrice)
+	}
+}
+
+func TestCustomItem_MarshalJSON(t *testing.T) {
+	got, err := json.Marshal(CustomItem{ItemTypeCustom, "$", "mixer-01", "mixer-01"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"type":"custom","prefix":"$","raw":"mixer-01","value":"mixer-01"}`
+	if string(got) != want {
+		t.Errorf("json.Marshal() = %s, want %s", got, want)
+	}
+}
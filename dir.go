@@ -0,0 +1,104 @@
+package cooklang
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// RecipeFileExtension is the file extension of cooklang recipe files
+const RecipeFileExtension = ".cook"
+
+// ParseDir walks the dir tree and parses all recipe files using a pool of
+// workers. Returns the parsed recipes keyed by file path and the list of
+// errors for files that could not be parsed.
+func ParseDir(ctx context.Context, dir string, workers int) (map[string]*Recipe, []error) {
+	return NewParser(nil).ParseDir(ctx, dir, workers)
+}
+
+// ParseDir walks the dir tree and parses all recipe files using a pool of
+// workers. Returns the parsed recipes keyed by file path and the list of
+// errors for files that could not be parsed. Less than one worker means
+// one worker per CPU. The directory metadata is inherited from dir down (see
+// ParseConfig.InheritDirMetadata).
+func (p *Parser) ParseDir(ctx context.Context, dir string, workers int) (map[string]*Recipe, []error) {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	var inherited *dirMetadata
+	if p.config.InheritDirMetadata {
+		inherited = newDirMetadata(p, os.DirFS(dir))
+	}
+	type result struct {
+		path   string
+		recipe *Recipe
+		err    error
+	}
+	paths := make(chan string)
+	results := make(chan result)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(paths)
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if err != nil {
+				results <- result{path: path, err: err}
+				if d != nil && d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() || filepath.Ext(path) != RecipeFileExtension || (inherited != nil && d.Name() == DirMetadataFile) {
+				return nil
+			}
+			select {
+			case paths <- path:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil {
+			results <- result{path: dir, err: err}
+		}
+	}()
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range paths {
+				recipe, err := p.ParseFile(path)
+				if err == nil && inherited != nil {
+					var rel string
+					if rel, err = filepath.Rel(dir, path); err == nil {
+						err = inherited.inherit(recipe, filepath.ToSlash(rel))
+					}
+				}
+				results <- result{path, recipe, err}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	recipes := make(map[string]*Recipe)
+	var errs []error
+	for r := range results {
+		if r.err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.path, r.err))
+			continue
+		}
+		recipes[r.path] = r.recipe
+	}
+	return recipes, errs
+}
@@ -0,0 +1,59 @@
+package cooklang
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestParseDir(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"soup.cook":            "Boil @water{1%l}.",
+		"desserts/cake.cook":   ">> servings: 4\nMix @flour{200%g}.",
+		"desserts/broken.cook": ">> no separator",
+		"notes.txt":            "not a recipe",
+	})
+	recipes, errs := ParseDir(context.Background(), dir, 2)
+	if len(errs) != 1 {
+		t.Fatalf("ParseDir() errors = %v, want 1 error", errs)
+	}
+	if len(recipes) != 2 {
+		t.Fatalf("ParseDir() got %d recipes, want 2", len(recipes))
+	}
+	cake, ok := recipes[filepath.Join(dir, "desserts", "cake.cook")]
+	if !ok {
+		t.Fatalf("ParseDir() missing cake recipe in %v", recipes)
+	}
+	if cake.Metadata["servings"] != "4" {
+		t.Errorf("ParseDir() cake servings = %q, want %q", cake.Metadata["servings"], "4")
+	}
+}
+
+func TestParseDirCanceled(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"soup.cook": "Boil @water{1%l}.",
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, errs := ParseDir(ctx, dir, 1)
+	if len(errs) != 1 || !errors.Is(errs[0], context.Canceled) {
+		t.Errorf("ParseDir() errors = %v, want context.Canceled", errs)
+	}
+}
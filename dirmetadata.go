@@ -0,0 +1,124 @@
+package cooklang
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"sync"
+)
+
+// Names of the files with the metadata inherited by the recipes of their
+// directory (see ParseConfig.InheritDirMetadata)
+const (
+	DirMetadataFile     = "config.cook"
+	DirMetadataYAMLFile = "_meta.yml"
+)
+
+// dirMetadata loads and caches the metadata inherited by the recipes of the
+// directories of fsys
+type dirMetadata struct {
+	p    *Parser
+	fsys fs.FS
+	mu   sync.Mutex
+	dirs map[string]*Recipe // merged metadata keyed by slash separated directory
+}
+
+func newDirMetadata(p *Parser, fsys fs.FS) *dirMetadata {
+	return &dirMetadata{p: p, fsys: fsys, dirs: make(map[string]*Recipe)}
+}
+
+// inherit adds the metadata of the directories of the recipe file name
+// which is not defined in the recipe
+func (m *dirMetadata) inherit(r *Recipe, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	meta, err := m.load(path.Dir(name))
+	if err != nil {
+		return err
+	}
+	inheritMetadata(r, meta)
+	return nil
+}
+
+// load returns the metadata of the directory merged with the metadata of its
+// parents
+func (m *dirMetadata) load(dir string) (*Recipe, error) {
+	if meta, ok := m.dirs[dir]; ok {
+		return meta, nil
+	}
+	meta := &Recipe{Metadata: Metadata{}}
+	// the sources in the order of precedence
+	name := path.Join(dir, DirMetadataYAMLFile)
+	data, err := fs.ReadFile(m.fsys, name)
+	switch {
+	case err == nil:
+		entries, err := yamlFrontMatter(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		inheritMetadata(meta, m.entriesRecipe(entries))
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, err
+	}
+	name = path.Join(dir, DirMetadataFile)
+	r, err := m.p.ParseFileFS(m.fsys, name)
+	switch {
+	case err == nil:
+		inheritMetadata(meta, r)
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if dir != "." {
+		parent, err := m.load(path.Dir(dir))
+		if err != nil {
+			return nil, err
+		}
+		inheritMetadata(meta, parent)
+	}
+	m.dirs[dir] = meta
+	return meta, nil
+}
+
+// entriesRecipe returns a recipe with the metadata entries of a YAML file
+func (m *dirMetadata) entriesRecipe(entries []frontMatterEntry) *Recipe {
+	r := &Recipe{Metadata: Metadata{}}
+	for _, e := range entries {
+		if e.list == nil && slices.Contains(m.p.config.ListMetadataKeys, e.key) {
+			e.list = SplitMetadataList(e.value)
+		}
+		if _, ok := r.Metadata[e.key]; !ok {
+			r.MetadataOrder = append(r.MetadataOrder, e.key)
+		}
+		r.Metadata[e.key] = e.value
+		if e.list != nil {
+			if r.MetadataLists == nil {
+				r.MetadataLists = make(map[string][]string)
+			}
+			r.MetadataLists[e.key] = e.list
+		}
+	}
+	return r
+}
+
+// inheritMetadata adds the metadata entries of from which are not defined in
+// r after the entries of r
+func inheritMetadata(r *Recipe, from *Recipe) {
+	for _, key := range from.MetadataKeys() {
+		if _, ok := r.Metadata[key]; ok {
+			continue
+		}
+		if r.Metadata == nil {
+			r.Metadata = Metadata{}
+		}
+		r.Metadata[key] = from.Metadata[key]
+		r.MetadataOrder = append(r.MetadataOrder, key)
+		if list, ok := from.MetadataLists[key]; ok {
+			if r.MetadataLists == nil {
+				r.MetadataLists = make(map[string][]string)
+			}
+			r.MetadataLists[key] = slices.Clone(list)
+		}
+	}
+}
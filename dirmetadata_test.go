@@ -0,0 +1,78 @@
+package cooklang
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+var dirMetadataFiles = map[string]string{
+	"config.cook":                ">> author: Ann\n>> cuisine: french\n>> tags: [dinner, easy]",
+	"soup.cook":                  ">> title: Soup\nBoil @water{1%l}.",
+	"italian/_meta.yml":          "cuisine: italian\ncourse: main\n",
+	"italian/config.cook":        ">> cuisine: sicilian\n>> course: first",
+	"italian/pasta.cook":         ">> title: Pasta\n>> author: Bea\nBoil @pasta{200%g}.",
+	"italian/desserts/cake.cook": ">> title: Cake\nMix @flour{200%g}.",
+}
+
+func TestParseDirInheritMetadata(t *testing.T) {
+	dir := writeTestFiles(t, dirMetadataFiles)
+	recipes, errs := NewParser(&ParseConfig{InheritDirMetadata: true}).ParseDir(context.Background(), dir, 2)
+	if len(errs) != 0 {
+		t.Fatalf("ParseDir() errors = %v", errs)
+	}
+	if len(recipes) != 3 {
+		t.Fatalf("ParseDir() got %d recipes, want 3 without config.cook", len(recipes))
+	}
+	soup := recipes[filepath.Join(dir, "soup.cook")]
+	if got, want := soup.MetadataKeys(), []string{"title", "author", "cuisine", "tags"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("soup metadata keys = %v, want %v", got, want)
+	}
+	if got, want := soup.MetadataList("tags"), []string{"dinner", "easy"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("soup tags = %v, want %v", got, want)
+	}
+	pasta := recipes[filepath.Join(dir, "italian", "pasta.cook")]
+	cake := recipes[filepath.Join(dir, "italian", "desserts", "cake.cook")]
+	for _, tt := range []struct {
+		recipe    *Recipe
+		key, want string
+	}{
+		{pasta, "author", "Bea"},
+		{pasta, "cuisine", "italian"},
+		{pasta, "course", "main"},
+		{cake, "title", "Cake"},
+		{cake, "author", "Ann"},
+		{cake, "cuisine", "italian"},
+	} {
+		if got := tt.recipe.Metadata[tt.key]; got != tt.want {
+			t.Errorf("%s %s = %q, want %q", tt.recipe.Metadata["title"], tt.key, got, tt.want)
+		}
+	}
+
+	recipes, _ = ParseDir(context.Background(), dir, 2)
+	if len(recipes) != 5 || recipes[filepath.Join(dir, "soup.cook")].Metadata["author"] != "" {
+		t.Errorf("ParseDir() = %d recipes, want the metadata not inherited by default", len(recipes))
+	}
+}
+
+func TestParseFSInheritMetadata(t *testing.T) {
+	fsys := fstest.MapFS{}
+	for name, content := range dirMetadataFiles {
+		fsys[name] = &fstest.MapFile{Data: []byte(content)}
+	}
+	fsys["broken/_meta.yml"] = &fstest.MapFile{Data: []byte("- not a mapping")}
+	fsys["broken/bread.cook"] = &fstest.MapFile{Data: []byte("Bake.")}
+	recipes, err := NewParser(&ParseConfig{InheritDirMetadata: true}).ParseFS(fsys, "*/*.cook")
+	if err == nil {
+		t.Error("ParseFS() error = nil, want the error of broken/_meta.yml")
+	}
+	if _, ok := recipes["italian/config.cook"]; ok || len(recipes) != 1 {
+		t.Fatalf("ParseFS() = %v, want only italian/pasta.cook", recipes)
+	}
+	want := Metadata{"title": "Pasta", "author": "Bea", "cuisine": "italian", "course": "main", "tags": "[dinner, easy]"}
+	if got := recipes["italian/pasta.cook"].Metadata; !reflect.DeepEqual(got, want) {
+		t.Errorf("pasta metadata = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,420 @@
+package cooklang
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// document is the syntax tree of a recipe built by a single parser pass.
+// Recipe and RecipeV2 are projections of it, so both models share the line
+// classification, the tokenization, the limits and the error reporting.
+type document struct {
+	metadata      Metadata
+	metadataOrder []string // metadata keys in source order
+	metadataLists map[string][]string
+	steps         []documentStep
+	// first mentions of the ingredients by normalized name (see
+	// resolveReference)
+	ingredients map[string]IngredientAmount
+	includes    []documentInclude // included recipes (see IncludeMode)
+	// tokenizedSteps counts the steps and line comments of Tokenize, which
+	// does not keep the steps, for the step limit
+	tokenizedSteps int
+	// buffers reused by the pooled documents
+	lineBuffer []byte // initial line buffer of the scanner
+	directions []byte // directions buffer of the tokenizer
+}
+
+// maxPooledBuffer is the largest buffer capacity kept by the pooled documents,
+// so a single huge recipe does not pin its buffers
+const maxPooledBuffer = 64 * 1024
+
+// documentPool reuses the documents with their step items and buffers
+// between the parses, so busy servers mostly allocate the parse results
+var documentPool = sync.Pool{New: func() any { return new(document) }}
+
+// release returns the document to the pool. The recipe projections do not
+// share memory with the document except for the metadata, which is not
+// reused, so the document can be released once they are built.
+func (d *document) release() {
+	if cap(d.lineBuffer) > maxPooledBuffer || cap(d.directions) > maxPooledBuffer || cap(d.steps) > maxPooledBuffer {
+		return
+	}
+	steps := d.steps[:cap(d.steps)]
+	for i := range steps {
+		// drop the references to the parsed values
+		items := steps[i].items[:cap(steps[i].items)]
+		clear(items)
+		steps[i] = documentStep{items: items[:0]}
+	}
+	*d = document{steps: steps[:0], lineBuffer: d.lineBuffer[:0], directions: d.directions[:0]}
+	documentPool.Put(d)
+}
+
+// documentStep is a step line or a line comment of the recipe
+type documentStep struct {
+	lineComment bool   // the line is a comment (-- text) with a single StepComment item
+	directions  string // step directions as plain text
+	leading     int    // white space trimmed from the start of the directions
+	// items of the step in source order: Text, Ingredient, Cookware, Timer,
+	// CustomItem and StepComment with the offset in the untrimmed directions
+	items []any
+}
+
+// documentConfig is the part of the configuration shared by the parsers
+type documentConfig struct {
+	features specFeatures // syntax features of the spec version
+	limits   Limits
+	strict   bool
+	custom   map[byte]CustomExtractor
+	listKeys []string // list typed metadata keys
+	// stripListMarkers removes the list markers of the step lines
+	stripListMarkers bool
+	// comments and commentPlaceholder define the comments text
+	comments           CommentMode
+	commentPlaceholder string
+	logger             *slog.Logger // optional logger of the debug events
+	// frontMatter reads the YAML (---) or TOML (+++) block at the start of
+	// the document as metadata
+	frontMatter bool
+	// strictMetadata reports the metadata keys missing from
+	// allowedMetadataKeys
+	strictMetadata      StrictMetadataMode
+	allowedMetadataKeys []string
+	// references reads the ingredients mentioned before as references
+	references ReferenceMode
+	// verbatimSpans and verbatimPatterns define the text which is not
+	// tokenized
+	verbatimSpans    bool
+	verbatimPatterns []*regexp.Regexp
+	links            bool // the URLs are link items
+	// includes and includeSource define how the included recipes are read
+	includes      IncludeMode
+	includeSource includeSource
+}
+
+// tokenizer returns a tokenizer of the configured syntax appending the
+// directions to the buffer
+func (config documentConfig) tokenizer(directions []byte, warnings *warningList) tokenizer {
+	return tokenizer{directions: directions, strict: config.strict, warnings: warnings, custom: config.custom, comments: config.comments, commentPlaceholder: config.commentPlaceholder, logger: config.logger, features: config.features, references: config.references != ReferencesOff,
+		verbatimSpans: config.verbatimSpans, verbatimPatterns: config.verbatimPatterns, links: config.links}
+}
+
+// listMarker matches the numbered ("1.", "2)") and bullet ("-", "*", "+",
+// "•") list markers at the start of a step line with the white space around
+var listMarker = regexp.MustCompile(`^\s*(?:\d{1,3}[.)]|[-*+•])(?:\s+|$)`)
+
+// stripListMarker removes the list marker from the start of the line. The
+// item offsets of the line are relative to the line without the marker.
+func stripListMarker(line string) string {
+	return line[len(listMarker.FindString(line)):]
+}
+
+// parseDocument parses the recipe stream into a pooled document, which is
+// released by the caller once the projections are built. The warnings are
+// collected when the list is not nil.
+func parseDocument(s io.Reader, config documentConfig, warnings *warningList) (*document, error) {
+	doc := documentPool.Get().(*document)
+	doc.metadata = make(Metadata)
+	if doc.lineBuffer == nil {
+		doc.lineBuffer = make([]byte, 0, 4096)
+	}
+	scanner := config.limits.newScanner(s, doc.lineBuffer)
+	t := config.tokenizer(doc.directions, warnings)
+	lineNumber := 0
+	var front *frontMatter // open front matter block
+	comments := commentLines{multiline: config.features.multilineComments}
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		t.line = lineNumber
+		if warnings != nil {
+			warnings.line = lineNumber
+		}
+		if err := config.limits.checkLine(line); err != nil {
+			doc.release()
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		if front != nil {
+			if strings.TrimSpace(line) != front.delimiter {
+				front.text.WriteString(line + "\n")
+				continue
+			}
+			if err := doc.parseFrontMatter(&t, config, front); err != nil {
+				doc.release()
+				return nil, err
+			}
+			front = nil
+			continue
+		}
+		if lineNumber == 1 && config.frontMatter {
+			if delimiter := frontMatterDelimiter(line); delimiter != "" {
+				front = &frontMatter{delimiter: delimiter, line: lineNumber}
+				continue
+			}
+		}
+		line, stepLine, ok := comments.add(line, lineNumber)
+		if !ok {
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if stepLine != lineNumber {
+			// the step of a block comment spanning several lines
+			t.line = stepLine
+			if warnings != nil {
+				warnings.line = stepLine
+			}
+		}
+		if err := doc.parseLine(&t, config, line); err != nil {
+			doc.release()
+			return nil, fmt.Errorf("line %d: %w", stepLine, err)
+		}
+	}
+	doc.directions = t.directions
+	if err := scanner.Err(); err != nil {
+		doc.release()
+		return nil, fmt.Errorf("line %d: %w", lineNumber+1, config.limits.scannerError(err))
+	}
+	if front != nil {
+		doc.release()
+		return nil, fmt.Errorf("line %d: %w: missing closing %s", front.line, ErrInvalidFrontMatter, front.delimiter)
+	}
+	if line := comments.open(); line != 0 {
+		doc.release()
+		return nil, fmt.Errorf("line %d: %w", line, ErrUnterminatedComment)
+	}
+	if len(doc.includes) > 0 {
+		if err := doc.resolveIncludes(config, warnings); err != nil {
+			doc.release()
+			return nil, err
+		}
+	}
+	return doc, nil
+}
+
+func (d *document) parseLine(t *tokenizer, config documentConfig, line string) error {
+	switch {
+	case strings.HasPrefix(line, commentsLinePrefix):
+		comment, err := parseSingleLineComment(line)
+		if err != nil {
+			return err
+		}
+		if t.logger != nil {
+			t.debug("line comment")
+		}
+		d.steps = append(d.steps, documentStep{lineComment: true, items: []any{StepComment{CommentTypeLine, comment, 0}}})
+		return config.limits.checkSteps(len(d.steps))
+	case strings.HasPrefix(line, metadataLinePrefix):
+		key, value, err := parseMetadata(line)
+		if err != nil {
+			if t.logger != nil {
+				t.debug("invalid metadata line", "error", err, "ignored", t.warnings != nil)
+			}
+			if t.warnings != nil {
+				t.warnings.add(WarningIgnoredLine, 0, "%v", err)
+				return nil
+			}
+			return err
+		}
+		var list []string
+		if slices.Contains(config.listKeys, key) || isMetadataList(value) {
+			list = SplitMetadataList(value)
+		}
+		return d.addMetadata(t, config, key, value, list)
+	default:
+		if config.stripListMarkers {
+			stripped := stripListMarker(line)
+			if t.logger != nil && len(stripped) != len(line) {
+				t.debug("list marker removed", "marker", strings.TrimSpace(line[:len(line)-len(stripped)]))
+			}
+			if line = stripped; strings.TrimSpace(line) == "" {
+				return nil
+			}
+		}
+		step := documentStep{}
+		if n := len(d.steps); n < cap(d.steps) {
+			// reuse the items slice of a pooled step
+			step.items = d.steps[:n+1][n].items
+		}
+		directions, err := t.tokenize(line, func(item any) (bool, error) {
+			if err := config.limits.checkItems(len(step.items) + 1); err != nil {
+				return true, err
+			}
+			switch v := item.(type) {
+			case Comment:
+				item = StepComment{v.Type, v.Value, len(t.directions)}
+			case Ingredient:
+				if config.references != ReferencesOff {
+					if err := d.resolveReference(t, config, &v); err != nil {
+						return true, err
+					}
+					item = v
+				}
+			}
+			step.items = append(step.items, item)
+			return false, nil
+		})
+		if err != nil {
+			return err
+		}
+		step.directions = directions
+		step.leading = len(t.directions) - len(bytes.TrimLeftFunc(t.directions, unicode.IsSpace))
+		d.steps = append(d.steps, step)
+		return config.limits.checkSteps(len(d.steps))
+	}
+}
+
+// addMetadata sets the metadata entry, list is the list value or nil
+func (d *document) addMetadata(t *tokenizer, config documentConfig, key, value string, list []string) error {
+	if err := config.checkMetadataKey(t, key); err != nil {
+		return err
+	}
+	if _, ok := d.metadata[key]; ok {
+		t.warnings.add(WarningDuplicateMetadata, 0, "key %q is already defined", key)
+	} else {
+		d.metadataOrder = append(d.metadataOrder, key)
+	}
+	d.metadata[key] = value
+	if key == MetadataInclude && config.includes != IncludesOff {
+		d.addInclude(t, value, list)
+	}
+	if t.logger != nil {
+		t.debug("metadata", "key", key, "list", list != nil)
+	}
+	if list != nil {
+		if d.metadataLists == nil {
+			d.metadataLists = make(map[string][]string)
+		}
+		d.metadataLists[key] = list
+	} else {
+		delete(d.metadataLists, key)
+	}
+	return config.limits.checkMetadata(d.metadata)
+}
+
+// recipe returns the v1 model of the document
+func (d *document) recipe(config *ParseConfig) *Recipe {
+	recipe := Recipe{Steps: make([]Step, 0, len(d.steps)), Metadata: d.metadata, MetadataOrder: d.metadataOrder, MetadataLists: d.metadataLists}
+	for _, s := range d.steps {
+		if s.lineComment {
+			c := s.items[0].(StepComment)
+			step := Step{Comments: []string{c.Value}}
+			if config.KeepCommentPositions {
+				step.TypedComments = []StepComment{c}
+			}
+			recipe.Steps = append(recipe.Steps, step)
+			continue
+		}
+		step := Step{
+			Directions:  s.directions,
+			Timers:      make([]Timer, 0),
+			Ingredients: make([]Ingredient, 0),
+			Cookware:    make([]Cookware, 0),
+		}
+		for _, item := range s.items {
+			switch v := item.(type) {
+			case Timer:
+				step.Timers = append(step.Timers, v)
+			case Ingredient:
+				v.Amount = emptyAmount(config.EmptyAmountMode, config.DefaultQuantity, parseTextualAmount(config.Numbers, v.Amount))
+				step.Ingredients = append(step.Ingredients, v)
+			case Cookware:
+				step.Cookware = append(step.Cookware, parseTextualCookware(config.Numbers, v))
+			case Text:
+				if config.DetectTemperatures {
+					for _, m := range findTemperatures(v.Value) {
+						step.Temperatures = append(step.Temperatures, m.Temperature)
+					}
+				}
+			case Link:
+				step.Links = append(step.Links, v)
+			case StepComment:
+				step.Comments = append(step.Comments, v.Value)
+				if config.KeepCommentPositions {
+					// the offsets are relative to the directions before trimming
+					v.Offset = min(max(v.Offset-s.leading, 0), len(step.Directions))
+					step.TypedComments = append(step.TypedComments, v)
+				}
+			}
+		}
+		recipe.Steps = append(recipe.Steps, step)
+	}
+	return &recipe
+}
+
+// recipeV2 returns the v2 model of the document
+func (d *document) recipeV2(config *ParseV2Config) *RecipeV2 {
+	recipe := RecipeV2{Steps: make([]StepV2, 0, len(d.steps)), Metadata: d.metadata, MetadataOrder: d.metadataOrder, MetadataLists: d.metadataLists}
+	ignored := func(itemType ItemType) bool {
+		return slices.Contains(config.IgnoreTypes, itemType)
+	}
+	for _, s := range d.steps {
+		if s.lineComment {
+			if !ignored(ItemTypeComment) {
+				c := s.items[0].(StepComment)
+				recipe.Steps = append(recipe.Steps, StepV2{Comment{c.Type, c.Value}})
+			}
+			continue
+		}
+		step := StepV2{}
+		for _, item := range s.items {
+			switch v := item.(type) {
+			case Timer:
+				if !ignored(ItemTypeTimer) {
+					step = append(step, v.asTimerV2())
+				}
+			case Ingredient:
+				if !ignored(ItemTypeIngredient) {
+					v.Amount = emptyAmount(config.EmptyAmountMode, config.DefaultQuantity, parseTextualAmount(config.Numbers, v.Amount))
+					step = append(step, v.asIngredientV2())
+				}
+			case Cookware:
+				if !ignored(ItemTypeCookware) {
+					step = append(step, parseTextualCookware(config.Numbers, v).asCookwareV2())
+				}
+			case Text:
+				if !config.DetectTemperatures {
+					if !ignored(ItemTypeText) {
+						step = append(step, v.asTextV2())
+					}
+					break
+				}
+				for _, item := range splitTemperatures(v.Value) {
+					switch i := item.(type) {
+					case Text:
+						if !ignored(ItemTypeText) {
+							step = append(step, i.asTextV2())
+						}
+					case Temperature:
+						if !ignored(ItemTypeTemperature) {
+							step = append(step, i.asTemperatureV2())
+						}
+					}
+				}
+			case Link:
+				if !ignored(ItemTypeLink) {
+					step = append(step, v.asLinkV2())
+				}
+			case StepComment:
+				if !ignored(ItemTypeComment) {
+					step = append(step, Comment{v.Type, v.Value})
+				}
+			case CustomItem:
+				if !ignored(ItemTypeCustom) {
+					step = append(step, v)
+				}
+			}
+		}
+		recipe.Steps = append(recipe.Steps, step)
+	}
+	return &recipe
+}
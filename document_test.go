@@ -0,0 +1,138 @@
+package cooklang
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestParsersShareDocument(t *testing.T) {
+	tests := []struct {
+		name   string
+		recipe string
+		limits Limits
+		strict bool
+	}{
+		{"valid", "-- comment\n>> servings: 2\nAdd @salt{1%tsp} to the #pot{} for ~{5%minutes}.", Limits{}, false},
+		{"invalid metadata", ">> servings\nAdd @salt.", Limits{}, false},
+		{"malformed item", "Add @salt{1%tsp.", Limits{}, true},
+		{"steps limit", "Add @salt.\nStir.", Limits{MaxSteps: 1}, false},
+		{"items limit", "Add @salt and @pepper.", Limits{MaxItemsPerStep: 2}, false},
+		{"line limit", "Add @salt.", Limits{MaxLineLength: 5}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v1, err1 := NewParser(&ParseConfig{Limits: tt.limits, Strict: tt.strict}).ParseString(tt.recipe)
+			v2, err2 := NewParserV2(&ParseV2Config{Limits: tt.limits, Strict: tt.strict}).ParseString(tt.recipe)
+			if fmt.Sprint(err1) != fmt.Sprint(err2) {
+				t.Fatalf("v1 error = %v, v2 error = %v", err1, err2)
+			}
+			if err1 != nil {
+				return
+			}
+			if got := v1.ToV2(); !reflect.DeepEqual(got, v2) {
+				t.Errorf("v1 = %#v, v2 = %#v", got, v2)
+			}
+		})
+	}
+}
+
+func TestStripListMarkers(t *testing.T) {
+	recipe := "1. Mix @flour{200%g}.\n2) Add @eggs{2}.\n  - Rest for ~{10%minutes}.\n* Serve\n3.\n1.5 l of @water{} -- boil first\n-- comment"
+	tests := []struct {
+		name  string
+		strip bool
+		want  []string
+	}{
+		{"Disabled", false, []string{"1. Mix flour.", "2) Add eggs.", "- Rest for 10 minutes.", "* Serve", "3.", "1.5 l of water", ""}},
+		{"Enabled", true, []string{"Mix flour.", "Add eggs.", "Rest for 10 minutes.", "Serve", "1.5 l of water", ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewParser(&ParseConfig{StripListMarkers: tt.strip}).ParseString(recipe)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, step := range r.Steps {
+				got = append(got, step.Directions)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Directions = %q, want %q", got, tt.want)
+			}
+			v2, err := NewParserV2(&ParseV2Config{StripListMarkers: tt.strip}).ParseString(recipe)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := v2.Steps[0][0].(TextV2).Value; got != tt.want[0][:len(got)] {
+				t.Errorf("v2 first text = %q, want a prefix of %q", got, tt.want[0])
+			}
+		})
+	}
+}
+
+func TestParserLogger(t *testing.T) {
+	var b strings.Builder
+	logger := slog.New(slog.NewTextHandler(&b, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == slog.LevelKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	recipe := "-- intro\n>> tags: [a, b]\n1. Add @ salt, @pepper{1%tsp and @oil{} [- note -]"
+	if _, err := NewParser(&ParseConfig{Logger: logger, StripListMarkers: true}).ParseString(recipe); err != nil {
+		t.Fatal(err)
+	}
+	want := `msg="line comment" line=1
+msg=metadata line=2 key=tags list=true
+msg="list marker removed" line=3 marker=1.
+msg="prefix followed by white space kept as text" line=3 prefix=@ offset=4
+msg="malformed item kept as text" line=3 raw=@pepper{1%tsp offset=12 error="unterminated amount"
+msg=item line=3 raw=@oil{} offset=30
+msg=comment line=3 raw="[- note -]" offset=37 kept=""
+`
+	if got := b.String(); got != want {
+		t.Errorf("log = %s, want %s", got, want)
+	}
+}
+
+func TestParsePooledDocuments(t *testing.T) {
+	recipes := []string{
+		benchmarkRecipe,
+		">> servings: 2\nAdd @salt{1%tsp} -- to taste\n\n-- note\nStir.",
+		"Mix @flour{200%g.",
+		strings.Repeat("Add @water{} and ", 100),
+	}
+	want := make([]*RecipeV2, len(recipes))
+	for i, recipe := range recipes {
+		want[i], _ = NewParserV2(nil).ParseString(recipe)
+	}
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range 50 {
+				i := n % len(recipes)
+				got, err := NewParserV2(&ParseV2Config{Strict: i == 2}).ParseString(recipes[i])
+				if i == 2 {
+					if err == nil {
+						t.Errorf("recipe %d: want an error", i)
+					}
+					continue
+				}
+				if err != nil || !reflect.DeepEqual(got, want[i]) {
+					t.Errorf("recipe %d = %v, %v, want %v", i, got, err, want[i])
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
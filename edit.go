@@ -0,0 +1,155 @@
+package cooklang
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrStepIndex is returned when a step index is out of range
+	ErrStepIndex = errors.New("step index out of range")
+	// ErrItemNotFound is returned when the edited item is not in the step
+	ErrItemNotFound = errors.New("item not found")
+	// ErrInconsistentStep is returned when an item of a step is not
+	// mentioned in its directions
+	ErrInconsistentStep = errors.New("item not in the step directions")
+)
+
+// checkStep returns ErrInconsistentStep when the name of an ingredient,
+// cookware or timer is not in the directions
+func checkStep(step Step) error {
+	var names []string
+	for _, i := range step.Ingredients {
+		names = append(names, i.Name)
+	}
+	for _, c := range step.Cookware {
+		names = append(names, c.Name)
+	}
+	for _, t := range step.Timers {
+		names = append(names, t.Name)
+	}
+	for _, name := range names {
+		if !strings.Contains(step.Directions, name) {
+			return fmt.Errorf("%w: %q", ErrInconsistentStep, name)
+		}
+	}
+	return nil
+}
+
+// syncImages renumbers the step images of r.Images after the steps, which
+// keep their image when they are moved, were edited
+func (r *Recipe) syncImages() {
+	if r.Images == nil || len(r.Images.Steps) == 0 {
+		return
+	}
+	images := make(map[int]string, len(r.Images.Steps))
+	number := 0
+	for _, step := range r.Steps {
+		if step.IsComment() {
+			continue
+		}
+		if step.Image != "" {
+			images[number] = step.Image
+		}
+		number++
+	}
+	r.Images.Steps = images
+}
+
+// InsertStep inserts the step before the step at index i, len(r.Steps)
+// appends it. The names of the step items must be in its directions.
+func (r *Recipe) InsertStep(i int, step Step) error {
+	if i < 0 || i > len(r.Steps) {
+		return fmt.Errorf("%w: %d", ErrStepIndex, i)
+	}
+	if err := checkStep(step); err != nil {
+		return err
+	}
+	r.Steps = slices.Insert(r.Steps, i, step)
+	r.syncImages()
+	return nil
+}
+
+// RemoveStep removes the step at index i
+func (r *Recipe) RemoveStep(i int) error {
+	if i < 0 || i >= len(r.Steps) {
+		return fmt.Errorf("%w: %d", ErrStepIndex, i)
+	}
+	r.Steps = slices.Delete(r.Steps, i, i+1)
+	r.syncImages()
+	return nil
+}
+
+// MoveStep moves the step at index from to index to, shifting the steps in
+// between
+func (r *Recipe) MoveStep(from, to int) error {
+	for _, i := range []int{from, to} {
+		if i < 0 || i >= len(r.Steps) {
+			return fmt.Errorf("%w: %d", ErrStepIndex, i)
+		}
+	}
+	step := r.Steps[from]
+	r.Steps = slices.Insert(slices.Delete(r.Steps, from, from+1), to, step)
+	r.syncImages()
+	return nil
+}
+
+// ReplaceIngredient replaces the first ingredient of the step with the name
+// (case insensitive). The mention of the ingredient in the directions is
+// replaced with the new name and the comment offsets after it are adjusted.
+// The directions are left unchanged when the replaced ingredient is hidden.
+func (r *Recipe) ReplaceIngredient(stepIndex int, name string, ingredient Ingredient) error {
+	if stepIndex < 0 || stepIndex >= len(r.Steps) {
+		return fmt.Errorf("%w: %d", ErrStepIndex, stepIndex)
+	}
+	if strings.TrimSpace(ingredient.Name) == "" {
+		return fmt.Errorf("%w: empty ingredient name", ErrInvalidName)
+	}
+	step := &r.Steps[stepIndex]
+	name = strings.TrimSpace(name)
+	index := slices.IndexFunc(step.Ingredients, func(i Ingredient) bool {
+		return strings.EqualFold(i.Name, name)
+	})
+	if index == -1 {
+		return fmt.Errorf("%w: %q", ErrItemNotFound, name)
+	}
+	old := step.Ingredients[index]
+	if old.Hidden {
+		// hidden ingredients are not mentioned in the directions
+		step.Ingredients = slices.Clone(step.Ingredients)
+		step.Ingredients[index] = ingredient
+		return nil
+	}
+	// the ingredients which are not hidden are mentioned in the directions
+	// in order
+	from := 0
+	for _, i := range step.Ingredients[:index] {
+		if i.Hidden {
+			continue
+		}
+		offset := strings.Index(step.Directions[from:], i.Name)
+		if offset == -1 {
+			return fmt.Errorf("%w: %q", ErrInconsistentStep, i.Name)
+		}
+		from += offset + len(i.Name)
+	}
+	offset := strings.Index(step.Directions[from:], old.Name)
+	if offset == -1 {
+		return fmt.Errorf("%w: %q", ErrInconsistentStep, old.Name)
+	}
+	offset += from
+	step.Ingredients = slices.Clone(step.Ingredients)
+	step.Ingredients[index] = ingredient
+	step.Directions = step.Directions[:offset] + ingredient.Name + step.Directions[offset+len(old.Name):]
+	if delta := len(ingredient.Name) - len(old.Name); delta != 0 && len(step.TypedComments) > 0 {
+		step.TypedComments = slices.Clone(step.TypedComments)
+		for i := range step.TypedComments {
+			if step.TypedComments[i].Offset > offset {
+				step.TypedComments[i].Offset += delta
+			}
+		}
+	}
+	return nil
+}
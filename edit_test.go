@@ -0,0 +1,183 @@
+package cooklang
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func parseEditRecipe(t *testing.T) *Recipe {
+	t.Helper()
+	r, err := ParseString("Mix @flour{200%g}.\n\nAdd @salt and more @salt.\n\nBake.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.AttachImages(RecipeImages{Steps: map[int]string{0: "0.jpg", 2: "2.jpg"}})
+	return r
+}
+
+func directions(r *Recipe) []string {
+	var result []string
+	for _, step := range r.Steps {
+		result = append(result, step.Directions)
+	}
+	return result
+}
+
+func TestRecipe_InsertStep(t *testing.T) {
+	tests := []struct {
+		name       string
+		index      int
+		step       Step
+		want       []string
+		wantImages map[int]string
+		wantErr    error
+	}{
+		{
+			"Insert",
+			1,
+			Step{Directions: "Rest in the #bowl.", Cookware: []Cookware{{Name: "bowl"}}},
+			[]string{"Mix flour.", "Rest in the #bowl.", "Add salt and more salt.", "Bake."},
+			map[int]string{0: "0.jpg", 3: "2.jpg"},
+			nil,
+		},
+		{"Append", 3, Step{Directions: "Serve."}, []string{"Mix flour.", "Add salt and more salt.", "Bake.", "Serve."}, map[int]string{0: "0.jpg", 2: "2.jpg"}, nil},
+		{"Out of range", 4, Step{}, nil, nil, ErrStepIndex},
+		{"Inconsistent", 0, Step{Directions: "Serve.", Ingredients: []Ingredient{{Name: "milk"}}}, nil, nil, ErrInconsistentStep},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := parseEditRecipe(t)
+			err := r.InsertStep(tt.index, tt.step)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("InsertStep() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := directions(r); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("InsertStep() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(r.Images.Steps, tt.wantImages) {
+				t.Errorf("InsertStep() images = %v, want %v", r.Images.Steps, tt.wantImages)
+			}
+		})
+	}
+}
+
+func TestRecipe_RemoveStep(t *testing.T) {
+	r := parseEditRecipe(t)
+	if err := r.RemoveStep(3); !errors.Is(err, ErrStepIndex) {
+		t.Errorf("RemoveStep() error = %v, want %v", err, ErrStepIndex)
+	}
+	if err := r.RemoveStep(0); err != nil {
+		t.Fatalf("RemoveStep() error = %v", err)
+	}
+	if want := []string{"Add salt and more salt.", "Bake."}; !reflect.DeepEqual(directions(r), want) {
+		t.Errorf("RemoveStep() = %v, want %v", directions(r), want)
+	}
+	if want := map[int]string{1: "2.jpg"}; !reflect.DeepEqual(r.Images.Steps, want) {
+		t.Errorf("RemoveStep() images = %v, want %v", r.Images.Steps, want)
+	}
+}
+
+func TestRecipe_MoveStep(t *testing.T) {
+	tests := []struct {
+		name       string
+		from, to   int
+		want       []string
+		wantImages map[int]string
+	}{
+		{"Forward", 0, 2, []string{"Add salt and more salt.", "Bake.", "Mix flour."}, map[int]string{1: "2.jpg", 2: "0.jpg"}},
+		{"Backward", 2, 1, []string{"Mix flour.", "Bake.", "Add salt and more salt."}, map[int]string{0: "0.jpg", 1: "2.jpg"}},
+		{"Same", 1, 1, []string{"Mix flour.", "Add salt and more salt.", "Bake."}, map[int]string{0: "0.jpg", 2: "2.jpg"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := parseEditRecipe(t)
+			if err := r.MoveStep(tt.from, tt.to); err != nil {
+				t.Fatalf("MoveStep() error = %v", err)
+			}
+			if got := directions(r); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MoveStep() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(r.Images.Steps, tt.wantImages) {
+				t.Errorf("MoveStep() images = %v, want %v", r.Images.Steps, tt.wantImages)
+			}
+		})
+	}
+	r, err := ParseString("-- note\n\nMix @flour.\n\nBake.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.AttachImages(RecipeImages{Steps: map[int]string{0: "0.jpg", 1: "1.jpg"}})
+	if err := r.MoveStep(0, 1); err != nil {
+		t.Fatalf("MoveStep() error = %v", err)
+	}
+	if want := map[int]string{0: "0.jpg", 1: "1.jpg"}; !reflect.DeepEqual(r.Images.Steps, want) {
+		t.Errorf("MoveStep() comment step images = %v, want %v", r.Images.Steps, want)
+	}
+	if err := parseEditRecipe(t).MoveStep(0, 3); !errors.Is(err, ErrStepIndex) {
+		t.Errorf("MoveStep() error = %v, want %v", err, ErrStepIndex)
+	}
+}
+
+func TestRecipe_ReplaceIngredient(t *testing.T) {
+	r, err := NewParser(&ParseConfig{KeepCommentPositions: true}).ParseString("Add @salt{} and more @salt{1%pinch} -- taste\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.ReplaceIngredient(0, "SALT", Ingredient{Name: "sea salt"}); err != nil {
+		t.Fatalf("ReplaceIngredient() error = %v", err)
+	}
+	if err := r.ReplaceIngredient(0, "salt", Ingredient{Name: "pepper"}); err != nil {
+		t.Fatalf("ReplaceIngredient() error = %v", err)
+	}
+	step := r.Steps[0]
+	if want := "Add sea salt and more pepper"; step.Directions != want {
+		t.Errorf("Directions = %q, want %q", step.Directions, want)
+	}
+	if want := []Ingredient{{Name: "sea salt"}, {Name: "pepper"}}; !reflect.DeepEqual(step.Ingredients, want) {
+		t.Errorf("Ingredients = %v, want %v", step.Ingredients, want)
+	}
+	if want := len("Add sea salt and more pepper"); step.TypedComments[0].Offset != want {
+		t.Errorf("comment offset = %d, want %d", step.TypedComments[0].Offset, want)
+	}
+	if err := r.ReplaceIngredient(0, "milk", Ingredient{Name: "oat milk"}); !errors.Is(err, ErrItemNotFound) {
+		t.Errorf("ReplaceIngredient() error = %v, want %v", err, ErrItemNotFound)
+	}
+	if err := r.ReplaceIngredient(1, "salt", Ingredient{Name: "pepper"}); !errors.Is(err, ErrStepIndex) {
+		t.Errorf("ReplaceIngredient() error = %v, want %v", err, ErrStepIndex)
+	}
+}
+
+func TestRecipe_ReplaceIngredientHidden(t *testing.T) {
+	r, err := ParseString("Add @-salt{1%tsp} and @water{1%l}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	directions := r.Steps[0].Directions
+	if err := r.ReplaceIngredient(0, "water", Ingredient{Name: "stock"}); err != nil {
+		t.Fatalf("ReplaceIngredient() error = %v", err)
+	}
+	if want := strings.Replace(directions, "water", "stock", 1); r.Steps[0].Directions != want {
+		t.Errorf("Directions = %q, want %q", r.Steps[0].Directions, want)
+	}
+
+	r, err = ParseString("Season with @-salt and add the salted @butter.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	directions = r.Steps[0].Directions
+	if err := r.ReplaceIngredient(0, "salt", Ingredient{Name: "pepper", Hidden: true}); err != nil {
+		t.Fatalf("ReplaceIngredient() error = %v", err)
+	}
+	step := r.Steps[0]
+	if step.Directions != directions {
+		t.Errorf("Directions = %q, want %q", step.Directions, directions)
+	}
+	if step.Ingredients[0].Name != "pepper" {
+		t.Errorf("Ingredients[0].Name = %q, want %q", step.Ingredients[0].Name, "pepper")
+	}
+}
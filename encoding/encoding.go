@@ -0,0 +1,232 @@
+// Package encoding serializes parsed recipes to a compact binary format for
+// cache persistence and inter-process communication. The data starts with a
+// header holding the format version, so data written by other versions of
+// the package is rejected with ErrVersion instead of being decoded wrongly.
+package encoding
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+// Version is the version of the binary format. It changes whenever the
+// encoded recipe structures change.
+const Version = 2
+
+const magic = "COOK"
+
+const (
+	kindRecipe   byte = 1
+	kindRecipeV2 byte = 2
+)
+
+var (
+	// ErrInvalidFormat is returned when the data is not an encoded recipe
+	ErrInvalidFormat = errors.New("invalid recipe encoding")
+	// ErrVersion is returned when the data was encoded with another version
+	// of the format
+	ErrVersion = errors.New("unsupported recipe encoding version")
+)
+
+func init() {
+	// RecipeV2 step items and the common step attribute and custom item
+	// values. Other custom item value types must be registered with
+	// gob.Register by the caller.
+	gob.Register(cooklang.TextV2{})
+	gob.Register(cooklang.IngredientV2{})
+	gob.Register(cooklang.CookwareV2{})
+	gob.Register(cooklang.TimerV2{})
+	gob.Register(cooklang.TemperatureV2{})
+	gob.Register(cooklang.LinkV2{})
+	gob.Register(cooklang.Comment{})
+	gob.Register(cooklang.CustomItem{})
+	gob.Register([]any{})
+	gob.Register(map[string]any{})
+}
+
+// bits of gobStep.Empty, set for the empty but non-nil fields, which gob
+// decodes as nil
+const (
+	emptyTimers = 1 << iota
+	emptyIngredients
+	emptyCookware
+	emptyComments
+	emptyTemperatures
+	emptyTypedComments
+	emptyAttributes
+	emptyLinks
+)
+
+// bits of gobRecipe.Empty and gobRecipeV2.Empty
+const (
+	emptySteps = 1 << iota
+	emptyMetadata
+	emptyImageSteps
+)
+
+type gobStep struct {
+	Step  cooklang.Step
+	Empty uint8
+}
+
+type gobRecipe struct {
+	Steps         []gobStep
+	Metadata      cooklang.Metadata
+	MetadataOrder []string
+	MetadataLists map[string][]string
+	Images        *cooklang.RecipeImages
+	Empty         uint8
+}
+
+type gobStepV2 struct {
+	Items []any
+	Empty bool
+}
+
+type gobRecipeV2 struct {
+	Steps         []gobStepV2
+	Metadata      cooklang.Metadata
+	MetadataOrder []string
+	MetadataLists map[string][]string
+	Empty         uint8
+}
+
+// flag returns bit when the slice or map of length n is empty but not nil
+func flag(isNil bool, n int, bit uint8) uint8 {
+	if !isNil && n == 0 {
+		return bit
+	}
+	return 0
+}
+
+// Marshal encodes the recipe
+func Marshal(r *cooklang.Recipe) ([]byte, error) {
+	g := gobRecipe{Metadata: r.Metadata, MetadataOrder: r.MetadataOrder, MetadataLists: r.MetadataLists, Images: r.Images}
+	g.Empty = flag(r.Steps == nil, len(r.Steps), emptySteps) | flag(r.Metadata == nil, len(r.Metadata), emptyMetadata)
+	if r.Images != nil {
+		g.Empty |= flag(r.Images.Steps == nil, len(r.Images.Steps), emptyImageSteps)
+	}
+	for _, s := range r.Steps {
+		g.Steps = append(g.Steps, gobStep{s, flag(s.Timers == nil, len(s.Timers), emptyTimers) |
+			flag(s.Ingredients == nil, len(s.Ingredients), emptyIngredients) |
+			flag(s.Cookware == nil, len(s.Cookware), emptyCookware) |
+			flag(s.Comments == nil, len(s.Comments), emptyComments) |
+			flag(s.Temperatures == nil, len(s.Temperatures), emptyTemperatures) |
+			flag(s.TypedComments == nil, len(s.TypedComments), emptyTypedComments) |
+			flag(s.Attributes == nil, len(s.Attributes), emptyAttributes) |
+			flag(s.Links == nil, len(s.Links), emptyLinks)})
+	}
+	return encode(kindRecipe, g)
+}
+
+// Unmarshal decodes a recipe encoded with Marshal
+func Unmarshal(data []byte) (*cooklang.Recipe, error) {
+	var g gobRecipe
+	if err := decode(data, kindRecipe, &g); err != nil {
+		return nil, err
+	}
+	r := &cooklang.Recipe{Metadata: g.Metadata, MetadataOrder: g.MetadataOrder, MetadataLists: g.MetadataLists, Images: g.Images}
+	if g.Empty&emptySteps != 0 {
+		r.Steps = []cooklang.Step{}
+	}
+	if g.Empty&emptyMetadata != 0 {
+		r.Metadata = cooklang.Metadata{}
+	}
+	if g.Empty&emptyImageSteps != 0 && r.Images != nil {
+		r.Images.Steps = map[int]string{}
+	}
+	for _, gs := range g.Steps {
+		s := gs.Step
+		if gs.Empty&emptyTimers != 0 {
+			s.Timers = []cooklang.Timer{}
+		}
+		if gs.Empty&emptyIngredients != 0 {
+			s.Ingredients = []cooklang.Ingredient{}
+		}
+		if gs.Empty&emptyCookware != 0 {
+			s.Cookware = []cooklang.Cookware{}
+		}
+		if gs.Empty&emptyComments != 0 {
+			s.Comments = []string{}
+		}
+		if gs.Empty&emptyTemperatures != 0 {
+			s.Temperatures = []cooklang.Temperature{}
+		}
+		if gs.Empty&emptyTypedComments != 0 {
+			s.TypedComments = []cooklang.StepComment{}
+		}
+		if gs.Empty&emptyAttributes != 0 {
+			s.Attributes = map[string]any{}
+		}
+		if gs.Empty&emptyLinks != 0 {
+			s.Links = []cooklang.Link{}
+		}
+		r.Steps = append(r.Steps, s)
+	}
+	return r, nil
+}
+
+// MarshalV2 encodes the recipe
+func MarshalV2(r *cooklang.RecipeV2) ([]byte, error) {
+	g := gobRecipeV2{Metadata: r.Metadata, MetadataOrder: r.MetadataOrder, MetadataLists: r.MetadataLists}
+	g.Empty = flag(r.Steps == nil, len(r.Steps), emptySteps) | flag(r.Metadata == nil, len(r.Metadata), emptyMetadata)
+	for _, s := range r.Steps {
+		g.Steps = append(g.Steps, gobStepV2{s, s != nil && len(s) == 0})
+	}
+	return encode(kindRecipeV2, g)
+}
+
+// UnmarshalV2 decodes a recipe encoded with MarshalV2
+func UnmarshalV2(data []byte) (*cooklang.RecipeV2, error) {
+	var g gobRecipeV2
+	if err := decode(data, kindRecipeV2, &g); err != nil {
+		return nil, err
+	}
+	r := &cooklang.RecipeV2{Metadata: g.Metadata, MetadataOrder: g.MetadataOrder, MetadataLists: g.MetadataLists}
+	if g.Empty&emptySteps != 0 {
+		r.Steps = []cooklang.StepV2{}
+	}
+	if g.Empty&emptyMetadata != 0 {
+		r.Metadata = cooklang.Metadata{}
+	}
+	for _, gs := range g.Steps {
+		s := cooklang.StepV2(gs.Items)
+		if gs.Empty {
+			s = cooklang.StepV2{}
+		}
+		r.Steps = append(r.Steps, s)
+	}
+	return r, nil
+}
+
+func encode(kind byte, v any) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString(magic)
+	b.WriteByte(Version)
+	b.WriteByte(kind)
+	if err := gob.NewEncoder(&b).Encode(v); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func decode(data []byte, kind byte, v any) error {
+	header := len(magic) + 2
+	if len(data) < header || string(data[:len(magic)]) != magic {
+		return ErrInvalidFormat
+	}
+	if data[len(magic)] != Version {
+		return fmt.Errorf("%w: %d", ErrVersion, data[len(magic)])
+	}
+	if data[len(magic)+1] != kind {
+		return fmt.Errorf("%w: unexpected recipe type", ErrInvalidFormat)
+	}
+	if err := gob.NewDecoder(bytes.NewReader(data[header:])).Decode(v); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package encoding
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+const source = `>> servings: 2
+-- prepare everything
+Preheat the #oven{} to 180°C. [- or 350°F -]
+Mix @flour{200%g}, @water{} and @salt, bake ~{25%minutes}.`
+
+func TestMarshal(t *testing.T) {
+	r, err := cooklang.NewParser(&cooklang.ParseConfig{KeepCommentPositions: true}).ParseString(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.AttachImages(cooklang.RecipeImages{Cover: "cake.jpg", Steps: map[int]string{1: "cake.1.jpg"}})
+	r.Steps[2].Attributes = map[string]any{"tags": []any{"a", 1.5}, "note": "hot"}
+	tests := []struct {
+		name   string
+		recipe *cooklang.Recipe
+	}{
+		{"Parsed", r},
+		{"Empty", &cooklang.Recipe{Steps: []cooklang.Step{{Timers: []cooklang.Timer{}}}, Metadata: cooklang.Metadata{}, Images: &cooklang.RecipeImages{Steps: map[int]string{}}}},
+		{"Zero", &cooklang.Recipe{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Marshal(tt.recipe)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Unmarshal(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.recipe) {
+				t.Errorf("Unmarshal(Marshal()) = %#v, want %#v", got, tt.recipe)
+			}
+		})
+	}
+}
+
+func TestMarshalV2(t *testing.T) {
+	p := cooklang.NewParserV2(&cooklang.ParseV2Config{
+		DetectTemperatures: true,
+		CustomPrefixes: map[byte]cooklang.CustomExtractor{
+			'$': func(raw string) (any, error) { return raw, nil },
+		},
+	})
+	r, err := p.ParseString(source + " Costs $price{3}.\n[- empty -]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := MarshalV2(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := UnmarshalV2(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, r) {
+		t.Errorf("UnmarshalV2(MarshalV2()) = %#v, want %#v", got, r)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	valid, err := Marshal(&cooklang.Recipe{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldVersion := append([]byte{}, valid...)
+	oldVersion[len(magic)] = Version + 1
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"Empty", nil, ErrInvalidFormat},
+		{"Not a recipe", []byte("hello world"), ErrInvalidFormat},
+		{"Version", oldVersion, ErrVersion},
+		{"Truncated", valid[:len(valid)-2], ErrInvalidFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Unmarshal(tt.data); !errors.Is(err, tt.want) {
+				t.Errorf("Unmarshal() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+	if _, err := UnmarshalV2(valid); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("UnmarshalV2() error = %v, want %v", err, ErrInvalidFormat)
+	}
+}
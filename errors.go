@@ -0,0 +1,38 @@
+package cooklang
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUnterminatedAmount is returned when the item amount is missing the closing brace
+	ErrUnterminatedAmount = errors.New("unterminated amount")
+	// ErrEmptyItem is returned when the item has neither name nor amount
+	ErrEmptyItem = errors.New("empty item")
+	// ErrInvalidQuantity is returned when the item quantity must be numeric but is not
+	ErrInvalidQuantity = errors.New("invalid quantity")
+	// ErrInvalidFrontMatter is returned when the front matter can not be
+	// decoded or is not terminated
+	ErrInvalidFrontMatter = errors.New("invalid front matter")
+	// ErrUnterminatedComment is returned when a block comment is not
+	// closed until the end of the recipe
+	ErrUnterminatedComment = errors.New("unterminated block comment")
+)
+
+// ItemError describes an item (ingredient, cookware or timer) which could
+// not be parsed
+type ItemError struct {
+	Type   ItemType // type of the item
+	Raw    string   // raw item text including the prefix
+	Offset int      // byte offset of the item in the line
+	Err    error    // the underlying error
+}
+
+func (e *ItemError) Error() string {
+	return fmt.Sprintf("invalid %s %q at offset %d: %v", e.Type, e.Raw, e.Offset, e.Err)
+}
+
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,267 @@
+package cooklang
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	// ErrUnknownVariable is returned when a template references a variable
+	// which is not defined
+	ErrUnknownVariable = errors.New("unknown variable")
+	// ErrInvalidExpression is returned when a template expression can not be
+	// evaluated
+	ErrInvalidExpression = errors.New("invalid expression")
+)
+
+var templateExpression = regexp.MustCompile(`\{\{(.*?)\}\}`)
+
+// Evaluate replaces the template expressions ({{servings * 2}}) in the
+// metadata values and in the ingredient and cookware quantities
+// (@flour{{{servings * 100}}%g}) with their values. Expressions support
+// numbers, the + - * / operators, parentheses and variables. Variables are
+// looked up in vars first and then in the metadata, where spaces in the keys
+// are replaced with underscores ("prep time" becomes prep_time). Metadata
+// values can be templates themselves.
+func Evaluate(r *Recipe, vars map[string]any) error {
+	e := evaluator{r: r, vars: vars, resolving: make(map[string]bool)}
+	for key := range r.Metadata {
+		if _, err := e.metadata(key); err != nil {
+			return err
+		}
+	}
+	for i := range r.Steps {
+		for j := range r.Steps[i].Ingredients {
+			amount := &r.Steps[i].Ingredients[j].Amount
+			if err := e.quantity(&amount.Quantity, &amount.QuantityRaw, &amount.IsNumeric); err != nil {
+				return fmt.Errorf("ingredient %q: %w", r.Steps[i].Ingredients[j].Name, err)
+			}
+		}
+		for j := range r.Steps[i].Cookware {
+			c := &r.Steps[i].Cookware[j]
+			if err := e.quantity(&c.Quantity, &c.QuantityRaw, &c.IsNumeric); err != nil {
+				return fmt.Errorf("cookware %q: %w", c.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+type evaluator struct {
+	r         *Recipe
+	vars      map[string]any
+	resolving map[string]bool // metadata keys being evaluated, to detect cycles
+}
+
+// metadata evaluates the templates in the metadata value and stores the result
+func (e *evaluator) metadata(key string) (string, error) {
+	value := e.r.Metadata[key]
+	if !strings.Contains(value, "{{") {
+		return value, nil
+	}
+	if e.resolving[key] {
+		return "", fmt.Errorf("%w: %q references itself", ErrInvalidExpression, key)
+	}
+	e.resolving[key] = true
+	defer delete(e.resolving, key)
+	result, err := e.replace(value)
+	if err != nil {
+		return "", fmt.Errorf("metadata %q: %w", key, err)
+	}
+	e.r.Metadata[key] = result
+	return result, nil
+}
+
+func (e *evaluator) replace(s string) (string, error) {
+	var err error
+	result := templateExpression.ReplaceAllStringFunc(s, func(m string) string {
+		if err != nil {
+			return m
+		}
+		var f float64
+		f, err = e.eval(templateExpression.FindStringSubmatch(m)[1])
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	})
+	return result, err
+}
+
+// quantity evaluates a templated raw quantity
+func (e *evaluator) quantity(quantity *float64, raw *string, isNumeric *bool) error {
+	m := templateExpression.FindStringSubmatch(*raw)
+	if m == nil {
+		return nil
+	}
+	if m[0] != strings.TrimSpace(*raw) {
+		// text around the template: keep as text
+		result, err := e.replace(*raw)
+		*raw = result
+		return err
+	}
+	f, err := e.eval(m[1])
+	if err != nil {
+		return err
+	}
+	*quantity = f
+	*raw = strconv.FormatFloat(f, 'f', -1, 64)
+	*isNumeric = true
+	return nil
+}
+
+func (e *evaluator) variable(name string) (float64, error) {
+	if v, ok := e.vars[name]; ok {
+		return toFloat(name, v)
+	}
+	for key := range e.r.Metadata {
+		if strings.ReplaceAll(key, " ", "_") != name {
+			continue
+		}
+		value, err := e.metadata(key)
+		if err != nil {
+			return 0, err
+		}
+		return toFloat(name, value)
+	}
+	return 0, fmt.Errorf("%w: %q", ErrUnknownVariable, name)
+}
+
+func toFloat(name string, v any) (float64, error) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	case float32:
+		return float64(n), nil
+	case float64:
+		return n, nil
+	case string:
+		if _, f, err := getFloat(n); err == nil && strings.TrimSpace(n) != "" {
+			return f, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: variable %q is not a number", ErrInvalidExpression, name)
+}
+
+// eval evaluates the arithmetic expression
+func (e *evaluator) eval(expression string) (float64, error) {
+	p := expressionParser{e: e, s: expression}
+	f, err := p.sum()
+	if err != nil {
+		return 0, err
+	}
+	p.skipSpace()
+	if p.pos != len(p.s) {
+		return 0, fmt.Errorf("%w: unexpected %q in %q", ErrInvalidExpression, p.s[p.pos:], expression)
+	}
+	return f, nil
+}
+
+// expressionParser is a recursive descent parser for the template expressions
+type expressionParser struct {
+	e   *evaluator
+	s   string
+	pos int
+}
+
+func (p *expressionParser) skipSpace() {
+	for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *expressionParser) peek() byte {
+	p.skipSpace()
+	if p.pos < len(p.s) {
+		return p.s[p.pos]
+	}
+	return 0
+}
+
+func (p *expressionParser) sum() (float64, error) {
+	left, err := p.product()
+	if err != nil {
+		return 0, err
+	}
+	for op := p.peek(); op == '+' || op == '-'; op = p.peek() {
+		p.pos++
+		right, err := p.product()
+		if err != nil {
+			return 0, err
+		}
+		if op == '+' {
+			left += right
+		} else {
+			left -= right
+		}
+	}
+	return left, nil
+}
+
+func (p *expressionParser) product() (float64, error) {
+	left, err := p.factor()
+	if err != nil {
+		return 0, err
+	}
+	for op := p.peek(); op == '*' || op == '/'; op = p.peek() {
+		p.pos++
+		right, err := p.factor()
+		if err != nil {
+			return 0, err
+		}
+		if op == '*' {
+			left *= right
+		} else if right == 0 {
+			return 0, fmt.Errorf("%w: division by zero in %q", ErrInvalidExpression, p.s)
+		} else {
+			left /= right
+		}
+	}
+	return left, nil
+}
+
+func (p *expressionParser) factor() (float64, error) {
+	switch ch := p.peek(); {
+	case ch == '-':
+		p.pos++
+		f, err := p.factor()
+		return -f, err
+	case ch == '(':
+		p.pos++
+		f, err := p.sum()
+		if err != nil {
+			return 0, err
+		}
+		if p.peek() != ')' {
+			return 0, fmt.Errorf("%w: missing closing parenthesis in %q", ErrInvalidExpression, p.s)
+		}
+		p.pos++
+		return f, nil
+	case ch == '.' || ('0' <= ch && ch <= '9'):
+		start := p.pos
+		for p.pos < len(p.s) && (p.s[p.pos] == '.' || ('0' <= p.s[p.pos] && p.s[p.pos] <= '9')) {
+			p.pos++
+		}
+		f, err := strconv.ParseFloat(p.s[start:p.pos], 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: invalid number %q", ErrInvalidExpression, p.s[start:p.pos])
+		}
+		return f, nil
+	case ch == '_' || unicode.IsLetter(rune(ch)) || ch >= 0x80:
+		start := p.pos
+		for _, r := range p.s[start:] {
+			if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				break
+			}
+			p.pos += len(string(r))
+		}
+		return p.e.variable(p.s[start:p.pos])
+	case ch == 0:
+		return 0, fmt.Errorf("%w: unexpected end of %q", ErrInvalidExpression, p.s)
+	default:
+		return 0, fmt.Errorf("%w: unexpected %q in %q", ErrInvalidExpression, ch, p.s)
+	}
+}
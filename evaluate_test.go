@@ -0,0 +1,83 @@
+package cooklang
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name            string
+		recipe          string
+		vars            map[string]any
+		wantMetadata    Metadata
+		wantIngredients []Ingredient
+		wantErr         error
+	}{
+		{
+			"Metadata",
+			">> servings: 4\n>> yield: {{servings * 2}} pancakes\n>> prep time: 10\n>> total: {{ prep_time + 5 }}\nAdd @salt{}.",
+			nil,
+			Metadata{"servings": "4", "yield": "8 pancakes", "prep time": "10", "total": "15"},
+			[]Ingredient{{"salt", IngredientAmount{false, 0, "", ""}, false, false, false, false}},
+			nil,
+		},
+		{
+			"Quantities",
+			">> servings: 2\nAdd @flour{{{servings * (100 + 25)}}%g} and @eggs{{{eggs / 2}}}.",
+			map[string]any{"eggs": 3, "servings": 4.0},
+			Metadata{"servings": "2"},
+			[]Ingredient{
+				{"flour", IngredientAmount{true, 500, "500", "g"}, false, false, false, false},
+				{"eggs", IngredientAmount{true, 1.5, "1.5", ""}, false, false, false, false},
+			},
+			nil,
+		},
+		{
+			"Unknown variable",
+			"Add @flour{{{grams}}%g}.",
+			nil,
+			nil,
+			nil,
+			ErrUnknownVariable,
+		},
+		{
+			"Invalid expression",
+			">> yield: {{2 * }}",
+			nil,
+			nil,
+			nil,
+			ErrInvalidExpression,
+		},
+		{
+			"Cycle",
+			">> a: {{b}}\n>> b: {{a + 1}}",
+			nil,
+			nil,
+			nil,
+			ErrInvalidExpression,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := ParseString(tt.recipe)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = Evaluate(r, tt.vars)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Evaluate() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !reflect.DeepEqual(r.Metadata, tt.wantMetadata) {
+				t.Errorf("Evaluate() metadata = %v, want %v", r.Metadata, tt.wantMetadata)
+			}
+			if !reflect.DeepEqual(r.Steps[0].Ingredients, tt.wantIngredients) {
+				t.Errorf("Evaluate() ingredients = %#v, want %#v", r.Steps[0].Ingredients, tt.wantIngredients)
+			}
+		})
+	}
+}
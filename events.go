@@ -0,0 +1,164 @@
+package cooklang
+
+import (
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+)
+
+// EventType defines the type of a tokenizer event
+type EventType int
+
+const (
+	EventStepStart EventType = iota + 1 // a recipe step starts
+	EventItem                           // an item (text, ingredient, cookware, timer or comment) of the current step
+	EventStepEnd                        // the current step ends
+	EventMetadata                       // a metadata line
+	EventComment                        // a single line comment
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventStepStart:
+		return "StepStart"
+	case EventItem:
+		return "Item"
+	case EventStepEnd:
+		return "StepEnd"
+	case EventMetadata:
+		return "Metadata"
+	case EventComment:
+		return "Comment"
+	}
+	return fmt.Sprintf("EventType(%d)", int(t))
+}
+
+// Event is a low level event emitted while tokenizing a recipe
+type Event struct {
+	Type  EventType // type of the event
+	Line  int       // source line number of the event
+	Item  any       // Text, Ingredient, Cookware, Timer or Comment for EventItem
+	Key   string    // metadata key for EventMetadata
+	Value string    // metadata value for EventMetadata or comment text for EventComment
+}
+
+// Tokenize returns an iterator over the recipe events in the stream
+func Tokenize(r io.Reader) iter.Seq2[Event, error] {
+	return NewParser(nil).Tokenize(r)
+}
+
+// Tokenize returns an iterator over the recipe events in the stream. The
+// iteration stops after the first error.
+func (p *Parser) Tokenize(r io.Reader) iter.Seq2[Event, error] {
+	return func(yield func(Event, error) bool) {
+		config, err := p.documentConfig(includeSource{})
+		if err != nil {
+			yield(Event{}, err)
+			return
+		}
+		scanner := config.limits.newScanner(r, nil)
+		t := config.tokenizer(nil, nil)
+		// the ingredients mentioned before for the references, the metadata
+		// and the number of steps for the limits
+		doc := &document{metadata: make(Metadata)}
+		lineNumber := 0
+		comments := commentLines{multiline: config.features.multilineComments}
+		for scanner.Scan() {
+			lineNumber++
+			line := scanner.Text()
+			if err := config.limits.checkLine(line); err != nil {
+				yield(Event{Line: lineNumber}, fmt.Errorf("line %d: %w", lineNumber, err))
+				return
+			}
+			line, stepLine, ok := comments.add(line, lineNumber)
+			if !ok || strings.TrimSpace(line) == "" {
+				continue
+			}
+			t.line = stepLine
+			if !doc.tokenizeLine(&t, config, stepLine, line, yield) {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			yield(Event{Line: lineNumber + 1}, fmt.Errorf("line %d: %w", lineNumber+1, config.limits.scannerError(err)))
+			return
+		}
+		if line := comments.open(); line != 0 {
+			yield(Event{Line: line}, fmt.Errorf("line %d: %w", line, ErrUnterminatedComment))
+		}
+	}
+}
+
+// tokenizeLine emits the events for a single line and returns false when the
+// iteration must stop. The line is read as by parseLine.
+func (d *document) tokenizeLine(t *tokenizer, config documentConfig, lineNumber int, line string, yield func(Event, error) bool) bool {
+	if strings.HasPrefix(line, commentsLinePrefix) {
+		comment, err := parseSingleLineComment(line)
+		if err != nil {
+			yield(Event{Line: lineNumber}, fmt.Errorf("line %d: %w", lineNumber, err))
+			return false
+		}
+		if err := d.addTokenizedStep(config); err != nil {
+			yield(Event{Line: lineNumber}, fmt.Errorf("line %d: %w", lineNumber, err))
+			return false
+		}
+		return yield(Event{Type: EventComment, Line: lineNumber, Value: comment}, nil)
+	}
+	if strings.HasPrefix(line, metadataLinePrefix) {
+		key, value, err := parseMetadata(line)
+		if err != nil {
+			yield(Event{Line: lineNumber}, fmt.Errorf("line %d: %w", lineNumber, err))
+			return false
+		}
+		d.metadata[key] = value
+		if err := config.limits.checkMetadata(d.metadata); err != nil {
+			yield(Event{Line: lineNumber}, fmt.Errorf("line %d: %w", lineNumber, err))
+			return false
+		}
+		return yield(Event{Type: EventMetadata, Line: lineNumber, Key: key, Value: value}, nil)
+	}
+	if config.stripListMarkers {
+		if line = stripListMarker(line); strings.TrimSpace(line) == "" {
+			return true
+		}
+	}
+	if err := d.addTokenizedStep(config); err != nil {
+		yield(Event{Line: lineNumber}, fmt.Errorf("line %d: %w", lineNumber, err))
+		return false
+	}
+	if !yield(Event{Type: EventStepStart, Line: lineNumber}, nil) {
+		return false
+	}
+	items := 0
+	stopped := false
+	_, err := t.tokenize(line, func(item any) (bool, error) {
+		items++
+		if err := config.limits.checkItems(items); err != nil {
+			return true, err
+		}
+		if v, ok := item.(Ingredient); ok && config.references != ReferencesOff {
+			if err := d.resolveReference(t, config, &v); err != nil {
+				return true, err
+			}
+			item = v
+		}
+		stopped = !yield(Event{Type: EventItem, Line: lineNumber, Item: item}, nil)
+		return stopped, nil
+	})
+	if err != nil {
+		yield(Event{Line: lineNumber}, fmt.Errorf("line %d: %w", lineNumber, err))
+		return false
+	}
+	if stopped {
+		return false
+	}
+	return yield(Event{Type: EventStepEnd, Line: lineNumber}, nil)
+}
+
+// addTokenizedStep counts a step or line comment and checks the step limit,
+// as parseLine does for the parsed steps
+func (d *document) addTokenizedStep(config documentConfig) error {
+	d.tokenizedSteps++
+	return config.limits.checkSteps(d.tokenizedSteps)
+}
@@ -0,0 +1,143 @@
+package cooklang
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	recipe := `>> servings: 2
+-- line comment
+Add @salt{1%g} [- to taste -]`
+	var got []Event
+	for e, err := range Tokenize(strings.NewReader(recipe)) {
+		if err != nil {
+			t.Fatalf("Tokenize() error = %v", err)
+		}
+		got = append(got, e)
+	}
+	want := []Event{
+		{Type: EventMetadata, Line: 1, Key: "servings", Value: "2"},
+		{Type: EventComment, Line: 2, Value: "line comment"},
+		{Type: EventStepStart, Line: 3},
+		{Type: EventItem, Line: 3, Item: Text{"Add "}},
+		{Type: EventItem, Line: 3, Item: Ingredient{Name: "salt", Amount: IngredientAmount{true, 1, "1", "g"}}},
+		{Type: EventItem, Line: 3, Item: Text{" "}},
+		{Type: EventItem, Line: 3, Item: Comment{CommentTypeBlock, "to taste"}},
+		{Type: EventStepEnd, Line: 3},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokenize() = %v, want %v", got, want)
+	}
+}
+
+func TestTokenizeStopAndError(t *testing.T) {
+	events := 0
+	for range Tokenize(strings.NewReader("Add @salt and @pepper")) {
+		events++
+		if events == 2 {
+			break
+		}
+	}
+	if events != 2 {
+		t.Errorf("Tokenize() events after break = %d, want 2", events)
+	}
+	var lastErr error
+	for _, err := range Tokenize(strings.NewReader("Step\n>> invalid")) {
+		lastErr = err
+	}
+	if lastErr == nil {
+		t.Errorf("Tokenize() expected error for invalid metadata")
+	}
+}
+
+func TestParserTokenizeLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		limits  Limits
+		recipe  string
+		wantErr error
+	}{
+		{"Steps", Limits{MaxSteps: 2}, "Add @salt.\n-- taste\nServe.", ErrLimitExceeded},
+		{"Steps within limit", Limits{MaxSteps: 3}, "Add @salt.\n-- taste\nServe.", nil},
+		{"Metadata", Limits{MaxMetadataSize: 10}, ">> title: Soup\n>> servings: 2\nServe.", ErrLimitExceeded},
+		{"Metadata within limit", Limits{MaxMetadataSize: 20}, ">> title: Soup\n>> servings: 2\nServe.", nil},
+		{"Items", Limits{MaxItemsPerStep: 2}, "Add @salt and @pepper.", ErrLimitExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewParser(&ParseConfig{Limits: tt.limits})
+			_, parseErr := p.ParseString(tt.recipe)
+			var err error
+			for _, err = range p.Tokenize(strings.NewReader(tt.recipe)) {
+				if err != nil {
+					break
+				}
+			}
+			if !errors.Is(parseErr, tt.wantErr) || !errors.Is(err, tt.wantErr) {
+				t.Errorf("Tokenize() error = %v, ParseString() error = %v, want %v", err, parseErr, tt.wantErr)
+			}
+			if err != nil && err.Error() != parseErr.Error() {
+				t.Errorf("Tokenize() error = %q, want %q", err, parseErr)
+			}
+		})
+	}
+}
+
+func TestParserTokenizeConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *ParseConfig
+		recipe string
+	}{
+		{"Spec version 2021", &ParseConfig{SpecVersion: SpecVersion2021}, "Let it ~rest then ~{5%minutes}"},
+		{"References", &ParseConfig{References: ReferencesImplicit}, "Add @flour{200%g}.\nFold in the @flour and @&flour{50%g}."},
+		{"List markers", &ParseConfig{StripListMarkers: true}, "1. Add @salt\n2) Stir"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewParser(tt.config)
+			r, err := p.ParseString(tt.recipe)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ingredients []Ingredient
+			var timers []Timer
+			for e, err := range p.Tokenize(strings.NewReader(tt.recipe)) {
+				if err != nil {
+					t.Fatalf("Tokenize() error = %v", err)
+				}
+				switch v := e.Item.(type) {
+				case Ingredient:
+					ingredients = append(ingredients, v)
+				case Timer:
+					timers = append(timers, v)
+				}
+			}
+			var wantIngredients []Ingredient
+			var wantTimers []Timer
+			for _, step := range r.Steps {
+				wantIngredients = append(wantIngredients, step.Ingredients...)
+				wantTimers = append(wantTimers, step.Timers...)
+			}
+			if !reflect.DeepEqual(ingredients, wantIngredients) {
+				t.Errorf("Tokenize() ingredients = %v, want %v", ingredients, wantIngredients)
+			}
+			if !reflect.DeepEqual(timers, wantTimers) {
+				t.Errorf("Tokenize() timers = %v, want %v", timers, wantTimers)
+			}
+		})
+	}
+}
+
+func TestParserTokenizeSpecVersion(t *testing.T) {
+	var lastErr error
+	for _, err := range NewParser(&ParseConfig{SpecVersion: "1999"}).Tokenize(strings.NewReader("Step")) {
+		lastErr = err
+	}
+	if !errors.Is(lastErr, ErrUnknownSpecVersion) {
+		t.Errorf("Tokenize() error = %v, want %v", lastErr, ErrUnknownSpecVersion)
+	}
+}
@@ -0,0 +1,43 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"slices"
+	"strconv"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+// ingredientsCSVColumns is the header row of IngredientsCSV
+var ingredientsCSVColumns = []string{"recipe", "section", "ingredient", "quantity", "unit", "optional"}
+
+// IngredientsCSV writes the ingredients of the recipe collection keyed by
+// name (see cooklang.ParseDir) as CSV with the columns recipe, section,
+// ingredient, quantity, unit and optional, for spreadsheets. The recipes are
+// written in the order of their names and every ingredient mention is a row
+// grouped by the recipe sections, the references are left out (see
+// cooklang.Recipe.IngredientsBySection).
+func IngredientsCSV(recipes map[string]*cooklang.Recipe, w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(ingredientsCSVColumns); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(recipes))
+	for name := range recipes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		for _, section := range recipes[name].IngredientsBySection(nil) {
+			for _, i := range section.Ingredients {
+				row := []string{name, section.Name, i.Name, formatQuantity(i.Amount), i.Amount.Unit, strconv.FormatBool(i.Optional)}
+				if err := cw.Write(row); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
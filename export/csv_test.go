@@ -0,0 +1,35 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+func TestIngredientsCSV(t *testing.T) {
+	salad, err := cooklang.ParseString("Whisk @oil{3%tbsp}, @vinegar{1%tbsp} and @?mustard{1%tsp}.\nToss @lettuce{1%head, large} with the dressing.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	salad.Steps[0].Attributes = map[string]any{cooklang.AttributeSection: "Dressing"}
+	salad.Steps[1].Attributes = map[string]any{cooklang.AttributeSection: "Salad"}
+	recipes := map[string]*cooklang.Recipe{"salad": salad, "pancakes": parseTestRecipe(t)}
+	var b strings.Builder
+	if err := IngredientsCSV(recipes, &b); err != nil {
+		t.Fatalf("IngredientsCSV() error = %v", err)
+	}
+	want := `recipe,section,ingredient,quantity,unit,optional
+pancakes,,flour,200,g,false
+pancakes,,milk,300,ml,false
+pancakes,,salt,a pinch,,false
+pancakes,,honey,,,false
+salad,Dressing,oil,3,tbsp,false
+salad,Dressing,vinegar,1,tbsp,false
+salad,Dressing,mustard,1,tsp,true
+salad,Salad,lettuce,1,"head, large",false
+`
+	if got := b.String(); got != want {
+		t.Errorf("IngredientsCSV() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,77 @@
+// Package export converts parsed cooklang recipes to the formats of other
+// recipe managers. The SQLite export is built with the sqlite build tag,
+// which adds the cgo SQLite driver dependency.
+package export
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+// Metadata keys used by the exporters
+const (
+	MetadataTitle       = "title"
+	MetadataDescription = "description"
+	MetadataServings    = "servings"
+	MetadataSource      = "source"
+	MetadataTags        = "tags"
+	MetadataPrepTime    = "prep time"
+	MetadataCookTime    = "cook time"
+	MetadataTotalTime   = "time"
+)
+
+// ErrSQLiteDisabled is returned by the SQLite functions without the sqlite
+// build tag
+var ErrSQLiteDisabled = errors.New("SQLite is not supported, build with -tags sqlite")
+
+func formatQuantity(amount cooklang.IngredientAmount) string {
+	if amount.IsNumeric {
+		return strconv.FormatFloat(amount.Quantity, 'f', -1, 64)
+	}
+	return amount.QuantityRaw
+}
+
+// formatIngredient returns the ingredient as a single line of text
+// ("200 g flour")
+func formatIngredient(i cooklang.Ingredient) string {
+	return strings.Join(strings.Fields(formatQuantity(i.Amount)+" "+i.Amount.Unit+" "+i.Name), " ")
+}
+
+func ingredients(r *cooklang.Recipe) []cooklang.Ingredient {
+	return r.AllIngredients(nil)
+}
+
+// directions returns the directions of the steps which have any
+func directions(r *cooklang.Recipe) []string {
+	var result []string
+	for _, step := range r.Steps {
+		if step.Directions != "" {
+			result = append(result, step.Directions)
+		}
+	}
+	return result
+}
+
+// comments returns all recipe comments
+func comments(r *cooklang.Recipe) []string {
+	var result []string
+	for _, step := range r.Steps {
+		result = append(result, step.Comments...)
+	}
+	return result
+}
+
+// tags returns the tags metadata list. Recipes parsed without the tags list
+// key have the comma separated value split.
+func tags(r *cooklang.Recipe) []string {
+	if list := r.MetadataList(MetadataTags); list != nil {
+		return list
+	}
+	if r.Metadata[MetadataTags] == "" {
+		return nil
+	}
+	return cooklang.SplitMetadataList(r.Metadata[MetadataTags])
+}
@@ -0,0 +1,25 @@
+package export
+
+import (
+	"testing"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+const testRecipe = `>> title: Pancakes
+>> servings: 4
+>> tags: breakfast, sweet
+>> source: https://example.com/pancakes
+
+Mix @flour{200%g}, @milk{300%ml} and @salt{a pinch} in a #bowl.
+-- rest the batter
+Fry in a #pan{} for ~{2%minutes} & serve with @honey{}.`
+
+func parseTestRecipe(t *testing.T) *cooklang.Recipe {
+	t.Helper()
+	r, err := cooklang.ParseString(testRecipe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
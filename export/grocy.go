@@ -0,0 +1,73 @@
+package export
+
+import (
+	"encoding/json"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+// GrocyRecipe is the payload of the Grocy recipes object
+// (POST /api/objects/recipes)
+type GrocyRecipe struct {
+	Name         string  `json:"name"`
+	Description  string  `json:"description,omitempty"`
+	BaseServings float64 `json:"base_servings,omitempty"`
+}
+
+// GrocyPosition is an ingredient of a Grocy recipe
+// (POST /api/objects/recipes_pos). Grocy references products and quantity
+// units by ID, so ProductName and Unit have to be resolved to product_id and
+// qu_id by the client before the position is sent.
+type GrocyPosition struct {
+	ProductName string  `json:"product_name"`
+	Unit        string  `json:"unit,omitempty"`
+	Amount      float64 `json:"amount"`
+	Note        string  `json:"note,omitempty"`
+}
+
+// GrocyPayload contains the Grocy API payloads of a recipe
+type GrocyPayload struct {
+	Recipe    GrocyRecipe     `json:"recipe"`
+	Positions []GrocyPosition `json:"positions"`
+}
+
+// ToGrocy converts the recipe to the Grocy API payloads. The description
+// contains the directions as HTML paragraphs.
+func ToGrocy(r *cooklang.Recipe) GrocyPayload {
+	var description strings.Builder
+	for _, d := range directions(r) {
+		description.WriteString("<p>" + html.EscapeString(d) + "</p>")
+	}
+	p := GrocyPayload{
+		Recipe: GrocyRecipe{
+			Name:        r.Metadata[MetadataTitle],
+			Description: description.String(),
+		},
+		Positions: make([]GrocyPosition, 0),
+	}
+	if servings, err := strconv.ParseFloat(strings.TrimSpace(r.Metadata[MetadataServings]), 64); err == nil {
+		p.Recipe.BaseServings = servings
+	}
+	for _, i := range ingredients(r) {
+		position := GrocyPosition{ProductName: i.Name, Unit: i.Amount.Unit, Amount: i.Amount.Quantity}
+		if !i.Amount.IsNumeric {
+			// textual amounts (a pinch) are kept in the note
+			position.Amount = 1
+			position.Note = strings.TrimSpace(i.Amount.QuantityRaw + " " + i.Amount.Unit)
+		}
+		p.Positions = append(p.Positions, position)
+	}
+	return p
+}
+
+// Grocy writes the Grocy API payloads of the recipe as JSON
+func Grocy(w io.Writer, r *cooklang.Recipe) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(ToGrocy(r))
+}
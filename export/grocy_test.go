@@ -0,0 +1,25 @@
+package export
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestToGrocy(t *testing.T) {
+	want := GrocyPayload{
+		Recipe: GrocyRecipe{
+			Name:         "Pancakes",
+			Description:  "<p>Mix flour, milk and salt in a bowl.</p><p>Fry in a pan for 2 minutes &amp; serve with honey.</p>",
+			BaseServings: 4,
+		},
+		Positions: []GrocyPosition{
+			{"flour", "g", 200, ""},
+			{"milk", "ml", 300, ""},
+			{"salt", "", 1, "a pinch"},
+			{"honey", "", 1, ""},
+		},
+	}
+	if got := ToGrocy(parseTestRecipe(t)); !reflect.DeepEqual(got, want) {
+		t.Errorf("ToGrocy() = %#v, want %#v", got, want)
+	}
+}
@@ -0,0 +1,161 @@
+package export
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+// Meal is a recipe planned for a time
+type Meal struct {
+	Recipe *cooklang.Recipe
+	Name   string    // optional event name (default: the recipe title)
+	Time   time.Time // time the meal is served
+}
+
+// MealPlan is a list of planned meals
+type MealPlan struct {
+	Name  string // optional calendar name
+	Meals []Meal
+}
+
+var (
+	metadataDuration = regexp.MustCompile(`(?i)(\d+(?:[.,]\d+)?)\s*(days?|d|hours?|hrs?|h|minutes?|mins?|m|seconds?|secs?|s)`)
+	// now returns the time stamp of the calendar events
+	now = time.Now
+)
+
+// parseDuration parses durations written in the metadata ("1 hour 30
+// minutes", "1h30m", "45 min")
+func parseDuration(s string) (time.Duration, bool) {
+	var total time.Duration
+	matches := metadataDuration.FindAllStringSubmatch(s, -1)
+	for _, m := range matches {
+		quantity, err := strconv.ParseFloat(strings.ReplaceAll(m[1], ",", "."), 64)
+		if err != nil {
+			return 0, false
+		}
+		d, ok := cooklang.Timer{Duration: quantity, Unit: m[2]}.ToDuration()
+		if !ok {
+			return 0, false
+		}
+		total += d
+	}
+	return total, len(matches) > 0
+}
+
+// MealTimes returns the preparation and cooking time of the recipe from the
+// prep time and cook time metadata. Without them the total time metadata or
+// else the sum of the timers is used as the cooking time.
+func MealTimes(r *cooklang.Recipe) (prep, cook time.Duration) {
+	prep, _ = parseDuration(r.Metadata[MetadataPrepTime])
+	cook, ok := parseDuration(r.Metadata[MetadataCookTime])
+	if ok || prep > 0 {
+		return prep, cook
+	}
+	if total, ok := parseDuration(r.Metadata[MetadataTotalTime]); ok {
+		return 0, total
+	}
+	return 0, cooklang.Stats(r).TotalTime
+}
+
+// formatDuration formats the duration without the zero trailing units
+// ("1h30m")
+func formatDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// escapeICSText escapes the text property values
+var escapeICSText = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// writeICSLine writes the content line folded at 75 octets. The folded
+// lines start with a space.
+func writeICSLine(b *strings.Builder, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			// do not split UTF-8 sequences
+			cut--
+		}
+		b.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		limit = 74
+	}
+	b.WriteString(line + "\r\n")
+}
+
+// ICS writes the meal plan as an iCalendar file with an event for every
+// meal. The events start when the preparation has to start (see MealTimes)
+// and end when the meal is served. The description lists the times and the
+// ingredients.
+func ICS(w io.Writer, plan MealPlan) error {
+	const timeFormat = "20060102T150405Z"
+	var b strings.Builder
+	writeICSLine(&b, "BEGIN:VCALENDAR")
+	writeICSLine(&b, "VERSION:2.0")
+	writeICSLine(&b, "PRODID:-//aquilax//cooklang-go//EN")
+	writeICSLine(&b, "CALSCALE:GREGORIAN")
+	if plan.Name != "" {
+		writeICSLine(&b, "X-WR-CALNAME:"+escapeICSText.Replace(plan.Name))
+	}
+	stamp := now().UTC().Format(timeFormat)
+	for _, meal := range plan.Meals {
+		name := meal.Name
+		if name == "" {
+			name = meal.Recipe.Metadata[MetadataTitle]
+		}
+		if name == "" {
+			name = "Meal"
+		}
+		prep, cook := MealTimes(meal.Recipe)
+		end := meal.Time.UTC()
+		start := end.Add(-(prep + cook))
+		var description []string
+		if prep > 0 {
+			description = append(description, "Prep time: "+formatDuration(prep))
+		}
+		if cook > 0 {
+			description = append(description, "Cook time: "+formatDuration(cook))
+		}
+		if list := ingredients(meal.Recipe); len(list) > 0 {
+			description = append(description, "", "Ingredients:")
+			for _, i := range list {
+				description = append(description, "- "+formatIngredient(i))
+			}
+		}
+		uid := sha256.Sum256([]byte(name + "\x00" + end.Format(timeFormat)))
+		writeICSLine(&b, "BEGIN:VEVENT")
+		writeICSLine(&b, "UID:"+hex.EncodeToString(uid[:16])+"@cooklang-go")
+		writeICSLine(&b, "DTSTAMP:"+stamp)
+		writeICSLine(&b, "DTSTART:"+start.Format(timeFormat))
+		writeICSLine(&b, "DTEND:"+end.Format(timeFormat))
+		writeICSLine(&b, "SUMMARY:"+escapeICSText.Replace(name))
+		if len(description) > 0 {
+			writeICSLine(&b, "DESCRIPTION:"+escapeICSText.Replace(strings.Join(description, "\n")))
+		}
+		writeICSLine(&b, "END:VEVENT")
+	}
+	writeICSLine(&b, "END:VCALENDAR")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// RecipeICS writes an iCalendar file with a single event for the recipe
+// served at the time (see ICS)
+func RecipeICS(w io.Writer, r *cooklang.Recipe, served time.Time) error {
+	return ICS(w, MealPlan{Meals: []Meal{{Recipe: r, Time: served}}})
+}
@@ -0,0 +1,97 @@
+package export
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+func parse(t *testing.T, source string) *cooklang.Recipe {
+	t.Helper()
+	r, err := cooklang.ParseString(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestICS(t *testing.T) {
+	now = func() time.Time { return time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+	dinner := parse(t, ">> title: Pasta, quick\n>> prep time: 10 min\n>> cook time: 1 hour 5 minutes\nBoil @pasta{200%g}.")
+	lunch := parse(t, "Simmer @soup{1%l} for ~{20%minutes} and rest ~{5%min}.")
+	served := time.Date(2024, 5, 1, 19, 0, 0, 0, time.UTC)
+	var b strings.Builder
+	err := ICS(&b, MealPlan{Name: "Week 18", Meals: []Meal{
+		{Recipe: dinner, Time: served},
+		{Recipe: lunch, Name: "Lunch", Time: served.Add(-6 * time.Hour)},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.ReplaceAll(`BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//aquilax//cooklang-go//EN
+CALSCALE:GREGORIAN
+X-WR-CALNAME:Week 18
+BEGIN:VEVENT
+UID:<hash>@cooklang-go
+DTSTAMP:20240501T080000Z
+DTSTART:20240501T174500Z
+DTEND:20240501T190000Z
+SUMMARY:Pasta\, quick
+DESCRIPTION:Prep time: 10m\nCook time: 1h5m\n\nIngredients:\n- 200 g pasta
+END:VEVENT
+BEGIN:VEVENT
+UID:<hash>@cooklang-go
+DTSTAMP:20240501T080000Z
+DTSTART:20240501T123500Z
+DTEND:20240501T130000Z
+SUMMARY:Lunch
+DESCRIPTION:Cook time: 25m\n\nIngredients:\n- 1 l soup
+END:VEVENT
+END:VCALENDAR
+`, "\n", "\r\n")
+	// the UIDs are hashes of the name and time
+	got := regexp.MustCompile(`UID:[0-9a-f]{32}@`).ReplaceAllString(b.String(), "UID:<hash>@")
+	if got != want {
+		t.Errorf("ICS() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestICSFolding(t *testing.T) {
+	r := parse(t, ">> title: "+strings.Repeat("é", 100)+"\nEat.")
+	var b strings.Builder
+	if err := RecipeICS(&b, r, time.Date(2024, 5, 1, 19, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range strings.Split(b.String(), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line longer than 75 octets: %q", line)
+		}
+	}
+	if !strings.Contains(b.String(), "SUMMARY:"+strings.Repeat("é", 33)+"\r\n "+strings.Repeat("é", 37)+"\r\n "+strings.Repeat("é", 30)+"\r\n") {
+		t.Errorf("ICS() summary not folded:\n%s", b.String())
+	}
+}
+
+func TestMealTimes(t *testing.T) {
+	tests := []struct {
+		metadata   string
+		prep, cook time.Duration
+	}{
+		{">> prep time: 15 minutes\n>> cook time: 1h30m", 15 * time.Minute, 90 * time.Minute},
+		{">> prep time: 15 mins", 15 * time.Minute, 0},
+		{">> time: 2 hours", 0, 2 * time.Hour},
+		{">> time: soon", 0, 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		r := parse(t, tt.metadata+"\nBake for ~{10%minutes}.")
+		if prep, cook := MealTimes(r); prep != tt.prep || cook != tt.cook {
+			t.Errorf("MealTimes(%q) = %v, %v, want %v, %v", tt.metadata, prep, cook, tt.prep, tt.cook)
+		}
+	}
+}
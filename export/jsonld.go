@@ -0,0 +1,106 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/classify"
+)
+
+// SchemaRecipe is a schema.org Recipe for the JSON-LD embedded in the recipe
+// web pages. The times are ISO 8601 durations ("PT20M"). Allergens is not a
+// schema.org property, it lists the allergens detected by the classify
+// package.
+type SchemaRecipe struct {
+	Context            string            `json:"@context"`
+	Type               string            `json:"@type"`
+	Name               string            `json:"name"`
+	Description        string            `json:"description,omitempty"`
+	RecipeYield        string            `json:"recipeYield,omitempty"`
+	PrepTime           string            `json:"prepTime,omitempty"`
+	CookTime           string            `json:"cookTime,omitempty"`
+	TotalTime          string            `json:"totalTime,omitempty"`
+	URL                string            `json:"url,omitempty"`
+	Keywords           string            `json:"keywords,omitempty"`
+	RecipeIngredient   []string          `json:"recipeIngredient"`
+	RecipeInstructions []SchemaHowToStep `json:"recipeInstructions"`
+	Allergens          []string          `json:"allergens,omitempty"`
+}
+
+// SchemaHowToStep is a schema.org HowToStep. TimeRequired is the sum of the
+// step timers.
+type SchemaHowToStep struct {
+	Type         string `json:"@type"`
+	Text         string `json:"text"`
+	TimeRequired string `json:"timeRequired,omitempty"`
+}
+
+// isoDuration returns the metadata duration ("1 hour 30 minutes") as ISO
+// 8601 duration or an empty string when the value is not a duration
+func isoDuration(value string) string {
+	if d, ok := parseDuration(value); ok {
+		return cooklang.FormatISO8601Duration(d)
+	}
+	return ""
+}
+
+// stepTime returns the sum of the step timers as ISO 8601 duration
+func stepTime(step cooklang.Step) string {
+	var total time.Duration
+	for _, timer := range step.Timers {
+		if d, ok := timer.ToDuration(); ok {
+			total += d
+		}
+	}
+	if total == 0 {
+		return ""
+	}
+	return cooklang.FormatISO8601Duration(total)
+}
+
+// ToSchemaRecipe converts the recipe to a schema.org Recipe. Without the
+// time metadata the total time is the sum of the timers.
+func ToSchemaRecipe(r *cooklang.Recipe) SchemaRecipe {
+	s := SchemaRecipe{
+		Context:            "https://schema.org",
+		Type:               "Recipe",
+		Name:               r.Metadata[MetadataTitle],
+		Description:        r.Metadata[MetadataDescription],
+		RecipeYield:        r.Metadata[MetadataServings],
+		PrepTime:           isoDuration(r.Metadata[MetadataPrepTime]),
+		CookTime:           isoDuration(r.Metadata[MetadataCookTime]),
+		TotalTime:          isoDuration(r.Metadata[MetadataTotalTime]),
+		URL:                r.Metadata[MetadataSource],
+		Keywords:           strings.Join(tags(r), ", "),
+		RecipeIngredient:   make([]string, 0),
+		RecipeInstructions: make([]SchemaHowToStep, 0),
+	}
+	if _, ok := r.Metadata[MetadataTotalTime]; !ok {
+		if total := cooklang.Stats(r).TotalTime; total > 0 {
+			s.TotalTime = cooklang.FormatISO8601Duration(total)
+		}
+	}
+	for _, i := range ingredients(r) {
+		s.RecipeIngredient = append(s.RecipeIngredient, formatIngredient(i))
+	}
+	for _, a := range classify.Allergens(r) {
+		s.Allergens = append(s.Allergens, string(a.Name))
+	}
+	for _, step := range r.Steps {
+		if step.Directions != "" {
+			s.RecipeInstructions = append(s.RecipeInstructions, SchemaHowToStep{"HowToStep", step.Directions, stepTime(step)})
+		}
+	}
+	return s
+}
+
+// JSONLD writes the recipe as schema.org Recipe JSON-LD
+func JSONLD(w io.Writer, r *cooklang.Recipe) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(ToSchemaRecipe(r))
+}
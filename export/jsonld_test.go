@@ -0,0 +1,65 @@
+package export
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJSONLD(t *testing.T) {
+	var b strings.Builder
+	if err := JSONLD(&b, parseTestRecipe(t)); err != nil {
+		t.Fatalf("JSONLD() error = %v", err)
+	}
+	want := `{
+  "@context": "https://schema.org",
+  "@type": "Recipe",
+  "name": "Pancakes",
+  "recipeYield": "4",
+  "totalTime": "PT2M",
+  "url": "https://example.com/pancakes",
+  "keywords": "breakfast, sweet",
+  "recipeIngredient": [
+    "200 g flour",
+    "300 ml milk",
+    "a pinch salt",
+    "honey"
+  ],
+  "recipeInstructions": [
+    {
+      "@type": "HowToStep",
+      "text": "Mix flour, milk and salt in a bowl."
+    },
+    {
+      "@type": "HowToStep",
+      "text": "Fry in a pan for 2 minutes & serve with honey.",
+      "timeRequired": "PT2M"
+    }
+  ],
+  "allergens": [
+    "gluten",
+    "wheat",
+    "milk"
+  ]
+}
+`
+	if got := b.String(); got != want {
+		t.Errorf("JSONLD() = %s, want %s", got, want)
+	}
+}
+
+func TestIsoDuration(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"1 hour 30 minutes", "PT1H30M"},
+		{"45 min", "PT45M"},
+		{"2 days", "P2D"},
+		{"soon", ""},
+	}
+	for _, tt := range tests {
+		if got := isoDuration(tt.value); got != tt.want {
+			t.Errorf("isoDuration(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
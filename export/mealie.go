@@ -0,0 +1,109 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+// MealieRecipe is a recipe in the Mealie JSON format
+type MealieRecipe struct {
+	Name               string              `json:"name"`
+	Description        string              `json:"description,omitempty"`
+	RecipeYield        string              `json:"recipeYield,omitempty"`
+	PrepTime           string              `json:"prepTime,omitempty"`
+	PerformTime        string              `json:"performTime,omitempty"`
+	TotalTime          string              `json:"totalTime,omitempty"`
+	OrgURL             string              `json:"orgURL,omitempty"`
+	Tags               []MealieTag         `json:"tags,omitempty"`
+	RecipeIngredient   []MealieIngredient  `json:"recipeIngredient"`
+	RecipeInstructions []MealieInstruction `json:"recipeInstructions"`
+	Notes              []MealieNote        `json:"notes,omitempty"`
+}
+
+// MealieTag is a Mealie recipe tag
+type MealieTag struct {
+	Name string `json:"name"`
+}
+
+// MealieUnit is a Mealie ingredient unit
+type MealieUnit struct {
+	Name string `json:"name"`
+}
+
+// MealieFood is a Mealie ingredient food
+type MealieFood struct {
+	Name string `json:"name"`
+}
+
+// MealieIngredient is a Mealie recipe ingredient
+type MealieIngredient struct {
+	Quantity     float64     `json:"quantity,omitempty"`
+	Unit         *MealieUnit `json:"unit,omitempty"`
+	Food         *MealieFood `json:"food,omitempty"`
+	Note         string      `json:"note,omitempty"`
+	Display      string      `json:"display"`
+	OriginalText string      `json:"originalText"`
+}
+
+// MealieInstruction is a Mealie recipe step
+type MealieInstruction struct {
+	Text string `json:"text"`
+}
+
+// MealieNote is a Mealie recipe note
+type MealieNote struct {
+	Title string `json:"title"`
+	Text  string `json:"text"`
+}
+
+// ToMealie converts the recipe to the Mealie format
+func ToMealie(r *cooklang.Recipe) MealieRecipe {
+	m := MealieRecipe{
+		Name:               r.Metadata[MetadataTitle],
+		Description:        r.Metadata[MetadataDescription],
+		RecipeYield:        r.Metadata[MetadataServings],
+		PrepTime:           r.Metadata[MetadataPrepTime],
+		PerformTime:        r.Metadata[MetadataCookTime],
+		TotalTime:          r.Metadata[MetadataTotalTime],
+		OrgURL:             r.Metadata[MetadataSource],
+		RecipeIngredient:   make([]MealieIngredient, 0),
+		RecipeInstructions: make([]MealieInstruction, 0),
+	}
+	for _, tag := range tags(r) {
+		m.Tags = append(m.Tags, MealieTag{tag})
+	}
+	for _, i := range ingredients(r) {
+		text := formatIngredient(i)
+		ingredient := MealieIngredient{
+			Food:         &MealieFood{i.Name},
+			Display:      text,
+			OriginalText: text,
+		}
+		if i.Amount.IsNumeric {
+			ingredient.Quantity = i.Amount.Quantity
+		} else {
+			ingredient.Note = i.Amount.QuantityRaw
+		}
+		if i.Amount.Unit != "" {
+			ingredient.Unit = &MealieUnit{i.Amount.Unit}
+		}
+		m.RecipeIngredient = append(m.RecipeIngredient, ingredient)
+	}
+	for _, d := range directions(r) {
+		m.RecipeInstructions = append(m.RecipeInstructions, MealieInstruction{d})
+	}
+	for _, c := range comments(r) {
+		m.Notes = append(m.Notes, MealieNote{Text: c})
+	}
+	return m
+}
+
+// Mealie writes the recipe as Mealie JSON
+func Mealie(w io.Writer, r *cooklang.Recipe) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(ToMealie(r))
+}
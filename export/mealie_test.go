@@ -0,0 +1,83 @@
+package export
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMealie(t *testing.T) {
+	var b strings.Builder
+	if err := Mealie(&b, parseTestRecipe(t)); err != nil {
+		t.Fatalf("Mealie() error = %v", err)
+	}
+	want := `{
+  "name": "Pancakes",
+  "recipeYield": "4",
+  "orgURL": "https://example.com/pancakes",
+  "tags": [
+    {
+      "name": "breakfast"
+    },
+    {
+      "name": "sweet"
+    }
+  ],
+  "recipeIngredient": [
+    {
+      "quantity": 200,
+      "unit": {
+        "name": "g"
+      },
+      "food": {
+        "name": "flour"
+      },
+      "display": "200 g flour",
+      "originalText": "200 g flour"
+    },
+    {
+      "quantity": 300,
+      "unit": {
+        "name": "ml"
+      },
+      "food": {
+        "name": "milk"
+      },
+      "display": "300 ml milk",
+      "originalText": "300 ml milk"
+    },
+    {
+      "food": {
+        "name": "salt"
+      },
+      "note": "a pinch",
+      "display": "a pinch salt",
+      "originalText": "a pinch salt"
+    },
+    {
+      "food": {
+        "name": "honey"
+      },
+      "display": "honey",
+      "originalText": "honey"
+    }
+  ],
+  "recipeInstructions": [
+    {
+      "text": "Mix flour, milk and salt in a bowl."
+    },
+    {
+      "text": "Fry in a pan for 2 minutes & serve with honey."
+    }
+  ],
+  "notes": [
+    {
+      "title": "",
+      "text": "rest the batter"
+    }
+  ]
+}
+`
+	if got := b.String(); got != want {
+		t.Errorf("Mealie() = %s, want %s", got, want)
+	}
+}
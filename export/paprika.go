@@ -0,0 +1,59 @@
+package export
+
+import (
+	"io"
+	"strings"
+
+	"github.com/aquilax/cooklang-go"
+	"gopkg.in/yaml.v3"
+)
+
+// PaprikaRecipe is a recipe in the Paprika YAML import format
+type PaprikaRecipe struct {
+	Name        string   `yaml:"name"`
+	Servings    string   `yaml:"servings,omitempty"`
+	Source      string   `yaml:"source,omitempty"`
+	Description string   `yaml:"description,omitempty"`
+	PrepTime    string   `yaml:"prep_time,omitempty"`
+	CookTime    string   `yaml:"cook_time,omitempty"`
+	TotalTime   string   `yaml:"total_time,omitempty"`
+	Categories  []string `yaml:"categories,omitempty"`
+	Ingredients string   `yaml:"ingredients"`
+	Directions  string   `yaml:"directions"`
+	Notes       string   `yaml:"notes,omitempty"`
+}
+
+// ToPaprika converts the recipe to the Paprika format
+func ToPaprika(r *cooklang.Recipe) PaprikaRecipe {
+	var list []string
+	for _, i := range ingredients(r) {
+		list = append(list, formatIngredient(i))
+	}
+	return PaprikaRecipe{
+		Name:        r.Metadata[MetadataTitle],
+		Servings:    r.Metadata[MetadataServings],
+		Source:      r.Metadata[MetadataSource],
+		Description: r.Metadata[MetadataDescription],
+		PrepTime:    r.Metadata[MetadataPrepTime],
+		CookTime:    r.Metadata[MetadataCookTime],
+		TotalTime:   r.Metadata[MetadataTotalTime],
+		Categories:  tags(r),
+		Ingredients: strings.Join(list, "\n"),
+		Directions:  strings.Join(directions(r), "\n\n"),
+		Notes:       strings.Join(comments(r), "\n"),
+	}
+}
+
+// Paprika writes the recipes as a Paprika YAML document
+func Paprika(w io.Writer, recipes ...*cooklang.Recipe) error {
+	list := make([]PaprikaRecipe, 0, len(recipes))
+	for _, r := range recipes {
+		list = append(list, ToPaprika(r))
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(list); err != nil {
+		return err
+	}
+	return enc.Close()
+}
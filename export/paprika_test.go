@@ -0,0 +1,33 @@
+package export
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPaprika(t *testing.T) {
+	var b strings.Builder
+	if err := Paprika(&b, parseTestRecipe(t)); err != nil {
+		t.Fatalf("Paprika() error = %v", err)
+	}
+	want := `- name: Pancakes
+  servings: "4"
+  source: https://example.com/pancakes
+  categories:
+    - breakfast
+    - sweet
+  ingredients: |-
+    200 g flour
+    300 ml milk
+    a pinch salt
+    honey
+  directions: |-
+    Mix flour, milk and salt in a bowl.
+
+    Fry in a pan for 2 minutes & serve with honey.
+  notes: rest the batter
+`
+	if got := b.String(); got != want {
+		t.Errorf("Paprika() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,404 @@
+//go:build sqlite
+
+package export
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aquilax/cooklang-go"
+	_ "github.com/mattn/go-sqlite3" // registers the sqlite3 driver
+)
+
+// sqliteSchema is the schema of the SQLite export. The positions are zero
+// based and keep the source order, the quantities of the textual amounts
+// are NULL.
+const sqliteSchema = `
+CREATE TABLE recipes (
+	id INTEGER PRIMARY KEY,
+	name TEXT NOT NULL UNIQUE,
+	title TEXT NOT NULL
+);
+CREATE TABLE metadata (
+	recipe_id INTEGER NOT NULL REFERENCES recipes(id),
+	position INTEGER NOT NULL,
+	key TEXT NOT NULL,
+	value TEXT NOT NULL,
+	PRIMARY KEY (recipe_id, key)
+);
+CREATE TABLE tags (
+	recipe_id INTEGER NOT NULL REFERENCES recipes(id),
+	position INTEGER NOT NULL,
+	tag TEXT NOT NULL
+);
+CREATE TABLE steps (
+	id INTEGER PRIMARY KEY,
+	recipe_id INTEGER NOT NULL REFERENCES recipes(id),
+	position INTEGER NOT NULL,
+	section TEXT NOT NULL,
+	directions TEXT NOT NULL,
+	comments TEXT NOT NULL
+);
+CREATE TABLE ingredients (
+	step_id INTEGER NOT NULL REFERENCES steps(id),
+	position INTEGER NOT NULL,
+	name TEXT NOT NULL,
+	quantity REAL,
+	quantity_text TEXT NOT NULL,
+	unit TEXT NOT NULL,
+	reference INTEGER NOT NULL,
+	hidden INTEGER NOT NULL,
+	fixed INTEGER NOT NULL,
+	optional INTEGER NOT NULL
+);
+CREATE TABLE cookware (
+	step_id INTEGER NOT NULL REFERENCES steps(id),
+	position INTEGER NOT NULL,
+	name TEXT NOT NULL,
+	quantity REAL,
+	quantity_text TEXT NOT NULL,
+	note TEXT NOT NULL,
+	optional INTEGER NOT NULL
+);
+CREATE TABLE timers (
+	step_id INTEGER NOT NULL REFERENCES steps(id),
+	position INTEGER NOT NULL,
+	name TEXT NOT NULL,
+	duration REAL NOT NULL,
+	unit TEXT NOT NULL
+);
+`
+
+// SQLite writes the recipe collection keyed by name (see cooklang.ParseDir)
+// to the SQLite database at path, which is created when it does not exist
+// and must not have the tables yet. The recipes, their metadata, tags,
+// steps, ingredients, cookware and timers are normalized tables, so the
+// collection can be queried with SQL:
+//
+//	SELECT r.name FROM recipes r JOIN steps s ON s.recipe_id = r.id
+//	JOIN ingredients i ON i.step_id = s.id WHERE i.name = 'basil'
+//
+// The database is written in a single transaction.
+func SQLite(path string, recipes map[string]*cooklang.Recipe) error {
+	db, err := sql.Open("sqlite3", sqliteURI(path, "mode=rwc"))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(sqliteSchema); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(recipes))
+	for name := range recipes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if err := insertRecipe(tx, name, recipes[name]); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// sqlQuantity returns the numeric quantities and NULL for the textual ones
+func sqlQuantity(isNumeric bool, quantity float64) sql.NullFloat64 {
+	return sql.NullFloat64{Float64: quantity, Valid: isNumeric}
+}
+
+func insertRecipe(tx *sql.Tx, name string, r *cooklang.Recipe) error {
+	res, err := tx.Exec("INSERT INTO recipes (name, title) VALUES (?, ?)", name, r.Metadata[MetadataTitle])
+	if err != nil {
+		return err
+	}
+	recipeID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	for i, key := range r.MetadataKeys() {
+		if _, err := tx.Exec("INSERT INTO metadata (recipe_id, position, key, value) VALUES (?, ?, ?, ?)", recipeID, i, key, r.Metadata[key]); err != nil {
+			return err
+		}
+	}
+	for i, tag := range tags(r) {
+		if _, err := tx.Exec("INSERT INTO tags (recipe_id, position, tag) VALUES (?, ?, ?)", recipeID, i, tag); err != nil {
+			return err
+		}
+	}
+	for i, step := range r.Steps {
+		res, err := tx.Exec("INSERT INTO steps (recipe_id, position, section, directions, comments) VALUES (?, ?, ?, ?, ?)",
+			recipeID, i, step.Section(), step.Directions, strings.Join(step.Comments, "\n"))
+		if err != nil {
+			return err
+		}
+		stepID, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		for j, ingredient := range step.Ingredients {
+			a := ingredient.Amount
+			if _, err := tx.Exec("INSERT INTO ingredients (step_id, position, name, quantity, quantity_text, unit, reference, hidden, fixed, optional) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+				stepID, j, ingredient.Name, sqlQuantity(a.IsNumeric, a.Quantity), a.QuantityRaw, a.Unit, ingredient.Reference, ingredient.Hidden, ingredient.Fixed, ingredient.Optional); err != nil {
+				return err
+			}
+		}
+		for j, c := range step.Cookware {
+			if _, err := tx.Exec("INSERT INTO cookware (step_id, position, name, quantity, quantity_text, note, optional) VALUES (?, ?, ?, ?, ?, ?, ?)",
+				stepID, j, c.Name, sqlQuantity(c.IsNumeric, c.Quantity), c.QuantityRaw, c.Note, c.Optional); err != nil {
+				return err
+			}
+		}
+		for j, t := range step.Timers {
+			if _, err := tx.Exec("INSERT INTO timers (step_id, position, name, duration, unit) VALUES (?, ?, ?, ?, ?)", stepID, j, t.Name, t.Duration, t.Unit); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ConvertSQLite reads the recipes of a database written by SQLite and
+// returns their cooklang markup keyed by recipe name on a best effort basis:
+// the items are placed in the directions as recovered by
+// cooklang.Recipe.ToV2, the comments, the sections and the item modifiers
+// (hidden, optional, ...) are left out and the timers without duration are
+// written as text.
+func ConvertSQLite(path string) (map[string]string, error) {
+	recipes, err := readSQLite(path)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]string, len(recipes))
+	for name, r := range recipes {
+		src, err := cookSource(r)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		result[name] = src
+	}
+	return result, nil
+}
+
+// ImportSQLite reads the recipes of a database written by SQLite. See
+// ConvertSQLite for what is kept.
+func ImportSQLite(path string) (map[string]*cooklang.Recipe, error) {
+	sources, err := ConvertSQLite(path)
+	if err != nil {
+		return nil, err
+	}
+	recipes := make(map[string]*cooklang.Recipe, len(sources))
+	for name, src := range sources {
+		r, err := cooklang.ParseString(src)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		recipes[name] = r
+	}
+	return recipes, nil
+}
+
+// sqliteURIEscaper escapes the characters with a meaning in SQLite URI
+// filenames
+var sqliteURIEscaper = strings.NewReplacer("%", "%25", "?", "%3f", "#", "%23")
+
+// sqliteURI returns the URI filename of the database at path
+func sqliteURI(path, query string) string {
+	return "file:" + sqliteURIEscaper.Replace(path) + "?" + query
+}
+
+// readSQLite returns the recipes of the database keyed by name. The rows
+// referencing missing recipes or steps are reported as errors because
+// SQLite does not enforce the foreign keys by default.
+func readSQLite(path string) (map[string]*cooklang.Recipe, error) {
+	db, err := sql.Open("sqlite3", sqliteURI(path, "mode=ro"))
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	recipes := make(map[string]*cooklang.Recipe)
+	ids := make(map[int64]*cooklang.Recipe)
+	err = query(db, "SELECT id, name FROM recipes", func(rows *sql.Rows) error {
+		var id int64
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return err
+		}
+		r := &cooklang.Recipe{Metadata: cooklang.Metadata{}, Steps: []cooklang.Step{}}
+		recipes[name], ids[id] = r, r
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = query(db, "SELECT recipe_id, key, value FROM metadata ORDER BY recipe_id, position", func(rows *sql.Rows) error {
+		var id int64
+		var key, value string
+		if err := rows.Scan(&id, &key, &value); err != nil {
+			return err
+		}
+		r, ok := ids[id]
+		if !ok {
+			return fmt.Errorf("metadata %q: unknown recipe id %d", key, id)
+		}
+		r.Metadata[key] = value
+		r.MetadataOrder = append(r.MetadataOrder, key)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	steps := make(map[int64]*cooklang.Step)
+	// the step pointers are taken once all the steps are appended
+	var stepIDs []int64
+	var stepRecipes []*cooklang.Recipe
+	err = query(db, "SELECT id, recipe_id, directions, comments FROM steps ORDER BY recipe_id, position", func(rows *sql.Rows) error {
+		var id, recipeID int64
+		var directions, comments string
+		if err := rows.Scan(&id, &recipeID, &directions, &comments); err != nil {
+			return err
+		}
+		step := cooklang.Step{Directions: directions, Ingredients: []cooklang.Ingredient{}, Cookware: []cooklang.Cookware{}, Timers: []cooklang.Timer{}}
+		if comments != "" {
+			step.Comments = strings.Split(comments, "\n")
+		}
+		r, ok := ids[recipeID]
+		if !ok {
+			return fmt.Errorf("step %d: unknown recipe id %d", id, recipeID)
+		}
+		r.Steps = append(r.Steps, step)
+		stepIDs, stepRecipes = append(stepIDs, id), append(stepRecipes, r)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[*cooklang.Recipe]int)
+	for i, id := range stepIDs {
+		r := stepRecipes[i]
+		steps[id] = &r.Steps[index[r]]
+		index[r]++
+	}
+	err = query(db, "SELECT step_id, name, quantity, quantity_text, unit FROM ingredients ORDER BY step_id, position", func(rows *sql.Rows) error {
+		var id int64
+		var i cooklang.Ingredient
+		var quantity sql.NullFloat64
+		if err := rows.Scan(&id, &i.Name, &quantity, &i.Amount.QuantityRaw, &i.Amount.Unit); err != nil {
+			return err
+		}
+		i.Amount.IsNumeric, i.Amount.Quantity = quantity.Valid, quantity.Float64
+		step, ok := steps[id]
+		if !ok {
+			return fmt.Errorf("ingredient %q: unknown step id %d", i.Name, id)
+		}
+		step.Ingredients = append(step.Ingredients, i)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = query(db, "SELECT step_id, name, quantity, quantity_text FROM cookware ORDER BY step_id, position", func(rows *sql.Rows) error {
+		var id int64
+		var c cooklang.Cookware
+		var quantity sql.NullFloat64
+		if err := rows.Scan(&id, &c.Name, &quantity, &c.QuantityRaw); err != nil {
+			return err
+		}
+		c.IsNumeric, c.Quantity = quantity.Valid, quantity.Float64
+		step, ok := steps[id]
+		if !ok {
+			return fmt.Errorf("cookware %q: unknown step id %d", c.Name, id)
+		}
+		step.Cookware = append(step.Cookware, c)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = query(db, "SELECT step_id, name, duration, unit FROM timers ORDER BY step_id, position", func(rows *sql.Rows) error {
+		var id int64
+		var t cooklang.Timer
+		if err := rows.Scan(&id, &t.Name, &t.Duration, &t.Unit); err != nil {
+			return err
+		}
+		step, ok := steps[id]
+		if !ok {
+			return fmt.Errorf("timer %q: unknown step id %d", t.Name, id)
+		}
+		step.Timers = append(step.Timers, t)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return recipes, nil
+}
+
+// query calls scan for every row of the query result
+func query(db *sql.DB, q string, scan func(rows *sql.Rows) error) error {
+	rows, err := db.Query(q)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// cookSource returns the cooklang markup of the recipe
+func cookSource(r *cooklang.Recipe) (string, error) {
+	b := cooklang.NewBuilder()
+	for _, key := range r.MetadataKeys() {
+		b.Meta(key, r.Metadata[key])
+	}
+	v2 := r.ToV2()
+	for i, step := range r.Steps {
+		if step.Directions == "" {
+			// comments only
+			continue
+		}
+		b.Step()
+		var ingredients, cookware, timers int
+		for _, item := range v2.Steps[i] {
+			switch v := item.(type) {
+			case cooklang.TextV2:
+				b.Text(v.Value)
+			case cooklang.IngredientV2:
+				ingredient := step.Ingredients[ingredients]
+				ingredients++
+				if a := ingredient.Amount; a.IsNumeric {
+					b.Ingredient(ingredient.Name, a.Quantity, a.Unit)
+				} else {
+					b.IngredientText(ingredient.Name, a.QuantityRaw, a.Unit)
+				}
+			case cooklang.CookwareV2:
+				c := step.Cookware[cookware]
+				cookware++
+				if c.IsNumeric {
+					b.Cookware(c.Name, c.Quantity)
+				} else {
+					b.Cookware(c.Name, 0)
+				}
+			case cooklang.TimerV2:
+				t := step.Timers[timers]
+				timers++
+				if t.HasDuration() {
+					b.Timer(t.Name, t.Duration, t.Unit)
+				} else {
+					b.Text(t.Name)
+				}
+			}
+		}
+	}
+	return b.Source()
+}
@@ -0,0 +1,21 @@
+//go:build !sqlite
+
+package export
+
+import "github.com/aquilax/cooklang-go"
+
+// SQLite is not available without the sqlite build tag, which adds the cgo
+// SQLite driver dependency
+func SQLite(path string, recipes map[string]*cooklang.Recipe) error {
+	return ErrSQLiteDisabled
+}
+
+// ConvertSQLite is not available without the sqlite build tag
+func ConvertSQLite(path string) (map[string]string, error) {
+	return nil, ErrSQLiteDisabled
+}
+
+// ImportSQLite is not available without the sqlite build tag
+func ImportSQLite(path string) (map[string]*cooklang.Recipe, error) {
+	return nil, ErrSQLiteDisabled
+}
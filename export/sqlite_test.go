@@ -0,0 +1,130 @@
+//go:build sqlite
+
+package export
+
+import (
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+func TestSQLite(t *testing.T) {
+	src := ">> title: Soup\n>> tags: quick, vegan\n\nBoil @water{1%l} in a #pot{} for ~{10%minutes}.\n-- taste it\nAdd @salt{a pinch} and @pepper{}.\n"
+	soup, err := cooklang.ParseString(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "recipes.db")
+	recipes := map[string]*cooklang.Recipe{"soup": soup, "pancakes": parseTestRecipe(t)}
+	if err := SQLite(path, recipes); err != nil {
+		t.Fatalf("SQLite() error = %v", err)
+	}
+	if err := SQLite(path, recipes); err == nil {
+		t.Error("SQLite() on an existing export error = nil")
+	}
+
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	rows, err := db.Query("SELECT i.name, i.quantity, i.quantity_text FROM recipes r JOIN steps s ON s.recipe_id = r.id JOIN ingredients i ON i.step_id = s.id WHERE r.name = 'soup' ORDER BY s.position, i.position")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	type row struct {
+		name     string
+		quantity sql.NullFloat64
+		text     string
+	}
+	var got []row
+	for rows.Next() {
+		var r row
+		if err := rows.Scan(&r.name, &r.quantity, &r.text); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, r)
+	}
+	want := []row{
+		{"water", sql.NullFloat64{Float64: 1, Valid: true}, "1"},
+		{"salt", sql.NullFloat64{}, "a pinch"},
+		{"pepper", sql.NullFloat64{}, ""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ingredients = %v, want %v", got, want)
+	}
+	var tags []string
+	if err := query(db, "SELECT t.tag FROM tags t JOIN recipes r ON r.id = t.recipe_id WHERE r.name = 'soup' ORDER BY t.position", func(rows *sql.Rows) error {
+		var tag string
+		tags = append(tags, tag)
+		return rows.Scan(&tags[len(tags)-1])
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"quick", "vegan"}; !reflect.DeepEqual(tags, want) {
+		t.Errorf("tags = %v, want %v", tags, want)
+	}
+
+	sources, err := ConvertSQLite(path)
+	if err != nil {
+		t.Fatalf("ConvertSQLite() error = %v", err)
+	}
+	wantSoup := ">> title: Soup\n>> tags: quick, vegan\n\nBoil @water{1%l} in a #pot for ~{10%minutes}.\n\nAdd @salt{a pinch} and @pepper{}.\n"
+	if got := sources["soup"]; got != wantSoup {
+		t.Errorf("ConvertSQLite()[soup] = %q, want %q", got, wantSoup)
+	}
+	imported, err := ImportSQLite(path)
+	if err != nil {
+		t.Fatalf("ImportSQLite() error = %v", err)
+	}
+	if len(imported) != 2 {
+		t.Fatalf("ImportSQLite() = %d recipes, want 2", len(imported))
+	}
+	if got, want := ingredients(imported["pancakes"]), ingredients(recipes["pancakes"]); !reflect.DeepEqual(got, want) {
+		t.Errorf("ImportSQLite()[pancakes] ingredients = %v, want %v", got, want)
+	}
+}
+
+func TestImportSQLiteOrphanRows(t *testing.T) {
+	soup, err := cooklang.ParseString("Boil @water{1%l}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		insert string
+	}{
+		{"metadata", "INSERT INTO metadata (recipe_id, position, key, value) VALUES (99, 0, 'title', 'Lost')"},
+		{"step", "INSERT INTO steps (recipe_id, position, section, directions, comments) VALUES (99, 0, '', 'Lost.', '')"},
+		{"ingredient", "INSERT INTO ingredients (step_id, position, name, quantity, quantity_text, unit, reference, hidden, fixed, optional) VALUES (99, 0, 'salt', NULL, '', '', 0, 0, 0, 0)"},
+		{"cookware", "INSERT INTO cookware (step_id, position, name, quantity, quantity_text, note, optional) VALUES (99, 0, 'pot', NULL, '', '', 0)"},
+		{"timer", "INSERT INTO timers (step_id, position, name, duration, unit) VALUES (99, 0, '', 1, 'minute')"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the characters with a meaning in SQLite URIs are escaped
+			path := filepath.Join(t.TempDir(), "what?#%.db")
+			if err := SQLite(path, map[string]*cooklang.Recipe{"soup": soup}); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := ImportSQLite(path); err != nil {
+				t.Fatalf("ImportSQLite() error = %v", err)
+			}
+			db, err := sql.Open("sqlite3", sqliteURI(path, "mode=rw"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := db.Exec(tt.insert); err != nil {
+				t.Fatal(err)
+			}
+			db.Close()
+			if _, err := ImportSQLite(path); err == nil {
+				t.Error("ImportSQLite() error = nil")
+			}
+		})
+	}
+}
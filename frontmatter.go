@@ -0,0 +1,183 @@
+package cooklang
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Front matter delimiters
+const (
+	frontMatterYAML = "---"
+	frontMatterTOML = "+++" // Hugo style
+)
+
+// frontMatter collects the lines of a front matter block
+type frontMatter struct {
+	delimiter string
+	line      int // line number of the opening delimiter
+	text      strings.Builder
+}
+
+// frontMatterDelimiter returns the delimiter the line opens a front matter
+// block with or an empty string
+func frontMatterDelimiter(line string) string {
+	switch line = strings.TrimSpace(strings.TrimPrefix(line, "\uFEFF")); line {
+	case frontMatterYAML, frontMatterTOML:
+		return line
+	}
+	return ""
+}
+
+// frontMatterEntry is a metadata entry of the front matter, list is nil for
+// the values which are not lists
+type frontMatterEntry struct {
+	key, value string
+	list       []string
+}
+
+// parseFrontMatter adds the entries of the front matter to the metadata
+func (d *document) parseFrontMatter(t *tokenizer, config documentConfig, f *frontMatter) error {
+	var entries []frontMatterEntry
+	var err error
+	if f.delimiter == frontMatterTOML {
+		entries, err = tomlFrontMatter(f.text.String())
+	} else {
+		entries, err = yamlFrontMatter(f.text.String())
+	}
+	if err != nil {
+		return fmt.Errorf("line %d: %w: %w", f.line, ErrInvalidFrontMatter, err)
+	}
+	for _, e := range entries {
+		if e.list == nil && slices.Contains(config.listKeys, e.key) {
+			e.list = SplitMetadataList(e.value)
+		}
+		if err := d.addMetadata(t, config, e.key, e.value, e.list); err != nil {
+			return fmt.Errorf("line %d: %w", f.line, err)
+		}
+	}
+	return nil
+}
+
+// newFrontMatterEntry returns the entry of the value, the list values are
+// also joined with commas as the metadata value
+func newFrontMatterEntry(key string, value string, list []string) frontMatterEntry {
+	if list != nil {
+		value = strings.Join(list, ", ")
+	}
+	return frontMatterEntry{key, value, list}
+}
+
+// yamlFrontMatter returns the entries of the YAML mapping in document order.
+// The keys of nested mappings are joined with dots ("source.name").
+func yamlFrontMatter(text string) ([]frontMatterEntry, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(text), &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	var entries []frontMatterEntry
+	var walk func(prefix string, node *yaml.Node) error
+	walk = func(prefix string, node *yaml.Node) error {
+		if node.Kind != yaml.MappingNode {
+			return fmt.Errorf("expected a mapping")
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := prefix+node.Content[i].Value, node.Content[i+1]
+			if value.Kind == yaml.AliasNode {
+				value = value.Alias
+			}
+			switch value.Kind {
+			case yaml.MappingNode:
+				if err := walk(key+".", value); err != nil {
+					return err
+				}
+			case yaml.SequenceNode:
+				list := make([]string, 0, len(value.Content))
+				for _, item := range value.Content {
+					var v any
+					if err := item.Decode(&v); err != nil {
+						return err
+					}
+					list = append(list, formatFrontMatterValue(v))
+				}
+				entries = append(entries, newFrontMatterEntry(key, "", list))
+			default:
+				var v any
+				if err := value.Decode(&v); err != nil {
+					return err
+				}
+				entries = append(entries, newFrontMatterEntry(key, formatFrontMatterValue(v), nil))
+			}
+		}
+		return nil
+	}
+	return entries, walk("", doc.Content[0])
+}
+
+// tomlFrontMatter returns the entries of the TOML document in document
+// order. The keys of tables are joined with dots ("source.name").
+func tomlFrontMatter(text string) ([]frontMatterEntry, error) {
+	var values map[string]any
+	md, err := toml.Decode(text, &values)
+	if err != nil {
+		return nil, err
+	}
+	var entries []frontMatterEntry
+	for _, key := range md.Keys() {
+		var v any = values
+		for _, k := range key {
+			m, ok := v.(map[string]any)
+			if !ok {
+				// keys of the arrays of tables
+				v = nil
+				break
+			}
+			v = m[k]
+		}
+		switch v := v.(type) {
+		case nil, map[string]any:
+		case []any:
+			list := make([]string, 0, len(v))
+			for _, item := range v {
+				list = append(list, formatFrontMatterValue(item))
+			}
+			entries = append(entries, newFrontMatterEntry(strings.Join(key, "."), "", list))
+		case []map[string]any:
+			list := make([]string, 0, len(v))
+			for _, item := range v {
+				list = append(list, formatFrontMatterValue(item))
+			}
+			entries = append(entries, newFrontMatterEntry(strings.Join(key, "."), "", list))
+		default:
+			entries = append(entries, newFrontMatterEntry(strings.Join(key, "."), formatFrontMatterValue(v), nil))
+		}
+	}
+	return entries, nil
+}
+
+// formatFrontMatterValue returns the decoded value as metadata text
+func formatFrontMatterValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,80 @@
+package cooklang
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseV2FrontMatter(t *testing.T) {
+	tests := []struct {
+		name      string
+		recipe    string
+		wantMeta  Metadata
+		wantOrder []string
+		wantLists map[string][]string
+		wantErr   error
+	}{
+		{
+			"YAML",
+			"---\ntitle: Pancakes\nservings: 4\ntags: [breakfast, sweet]\nsource:\n  name: Grandma\n---\nMix @flour{200%g}.",
+			Metadata{"title": "Pancakes", "servings": "4", "tags": "breakfast, sweet", "source.name": "Grandma"},
+			[]string{"title", "servings", "tags", "source.name"},
+			map[string][]string{"tags": {"breakfast", "sweet"}},
+			nil,
+		},
+		{
+			"TOML",
+			"+++\ntitle = \"Pancakes\"\n\"prep time\" = \"10 min\"\nservings = 4\ntags = [\"breakfast\", \"sweet\"]\n\n[source]\nname = \"Grandma\"\n+++\n>> course: dessert\nMix @flour{200%g}.",
+			Metadata{"title": "Pancakes", "prep time": "10 min", "servings": "4", "tags": "breakfast, sweet", "source.name": "Grandma", "course": "dessert"},
+			[]string{"title", "prep time", "servings", "tags", "source.name", "course"},
+			map[string][]string{"tags": {"breakfast", "sweet"}},
+			nil,
+		},
+		{
+			"unterminated",
+			"+++\ntitle = \"Pancakes\"\nMix @flour{200%g}.",
+			nil, nil, nil,
+			ErrInvalidFrontMatter,
+		},
+		{
+			"invalid TOML",
+			"+++\ntitle: Pancakes\n+++\nMix @flour{200%g}.",
+			nil, nil, nil,
+			ErrInvalidFrontMatter,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewParserV2(&ParseV2Config{FrontMatter: true}).ParseString(tt.recipe)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseString() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(r.Metadata, tt.wantMeta) {
+				t.Errorf("Metadata = %v, want %v", r.Metadata, tt.wantMeta)
+			}
+			if !reflect.DeepEqual(r.MetadataOrder, tt.wantOrder) {
+				t.Errorf("MetadataOrder = %v, want %v", r.MetadataOrder, tt.wantOrder)
+			}
+			if !reflect.DeepEqual(r.MetadataLists, tt.wantLists) {
+				t.Errorf("MetadataLists = %v, want %v", r.MetadataLists, tt.wantLists)
+			}
+			if len(r.Steps) != 1 {
+				t.Errorf("Steps = %v, want a single step", r.Steps)
+			}
+		})
+	}
+}
+
+func TestParseV2FrontMatterDisabled(t *testing.T) {
+	r, err := NewParserV2(nil).ParseString("+++\ntitle = \"Pancakes\"\n+++")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Metadata) != 0 || len(r.Steps) != 3 {
+		t.Errorf("ParseString() = %+v, want the front matter as steps", r)
+	}
+}
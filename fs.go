@@ -0,0 +1,73 @@
+package cooklang
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+)
+
+// ParseFileFS parses a cooklang recipe file from the fsys file system
+func ParseFileFS(fsys fs.FS, name string) (*Recipe, error) {
+	return NewParser(nil).ParseFileFS(fsys, name)
+}
+
+// ParseFileFS parses a cooklang recipe file from the fsys file system
+func (p *Parser) ParseFileFS(fsys fs.FS, name string) (*Recipe, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return p.parseStream(bufio.NewReader(f), nil, fileIncludeSource(fsys, name))
+}
+
+// ParseFS parses all files in fsys matching the glob pattern (see fs.Glob).
+// Returns the parsed recipes keyed by file name and the joined errors of the
+// files that could not be parsed.
+func ParseFS(fsys fs.FS, glob string) (map[string]*Recipe, error) {
+	return NewParser(nil).ParseFS(fsys, glob)
+}
+
+// ParseFS parses all files in fsys matching the glob pattern (see fs.Glob).
+// Returns the parsed recipes keyed by file name and the joined errors of the
+// files that could not be parsed. The directory metadata is inherited from
+// the root of fsys down (see ParseConfig.InheritDirMetadata).
+func (p *Parser) ParseFS(fsys fs.FS, glob string) (map[string]*Recipe, error) {
+	names, err := fs.Glob(fsys, glob)
+	if err != nil {
+		return nil, err
+	}
+	var inherited *dirMetadata
+	if p.config.InheritDirMetadata {
+		inherited = newDirMetadata(p, fsys)
+	}
+	recipes := make(map[string]*Recipe, len(names))
+	var errs []error
+	for _, name := range names {
+		if inherited != nil && path.Base(name) == DirMetadataFile {
+			continue
+		}
+		recipe, err := p.ParseFileFS(fsys, name)
+		if err == nil && inherited != nil {
+			err = inherited.inherit(recipe, name)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		recipes[name] = recipe
+	}
+	return recipes, errors.Join(errs...)
+}
+
+// ParseFileFS parses a cooklang recipe file from the fsys file system
+func (p *ParserV2) ParseFileFS(fsys fs.FS, name string) (*RecipeV2, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return p.parseStream(bufio.NewReader(f), fileIncludeSource(fsys, name))
+}
@@ -0,0 +1,48 @@
+package cooklang
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestParseFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"recipes/soup.cook":   {Data: []byte("Boil @water{1%l}.")},
+		"recipes/cake.cook":   {Data: []byte(">> servings: 4\nMix @flour{200%g}.")},
+		"recipes/broken.cook": {Data: []byte(">> no separator")},
+		"recipes/notes.txt":   {Data: []byte("not a recipe")},
+	}
+	recipes, err := ParseFS(fsys, "recipes/*.cook")
+	if err == nil {
+		t.Errorf("ParseFS() expected error for the broken recipe")
+	}
+	if len(recipes) != 2 {
+		t.Fatalf("ParseFS() got %d recipes, want 2", len(recipes))
+	}
+	if got := recipes["recipes/soup.cook"].Steps[0].Directions; got != "Boil water." {
+		t.Errorf("ParseFS() soup directions = %q, want %q", got, "Boil water.")
+	}
+}
+
+func TestParseFileFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"cake.cook": {Data: []byte(">> servings: 4\nMix @flour{200%g}.")},
+	}
+	r, err := ParseFileFS(fsys, "cake.cook")
+	if err != nil {
+		t.Fatalf("ParseFileFS() error = %v", err)
+	}
+	if r.Metadata["servings"] != "4" {
+		t.Errorf("ParseFileFS() servings = %q, want %q", r.Metadata["servings"], "4")
+	}
+	if _, err := ParseFileFS(fsys, "missing.cook"); err == nil {
+		t.Errorf("ParseFileFS() expected error for missing file")
+	}
+	r2, err := NewParserV2(&ParseV2Config{}).ParseFileFS(fsys, "cake.cook")
+	if err != nil {
+		t.Fatalf("ParserV2.ParseFileFS() error = %v", err)
+	}
+	if len(r2.Steps) != 1 {
+		t.Errorf("ParserV2.ParseFileFS() got %d steps, want 1", len(r2.Steps))
+	}
+}
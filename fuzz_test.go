@@ -0,0 +1,66 @@
+package cooklang
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func addCanonicalSeeds(f *testing.F) {
+	f.Helper()
+	b, err := os.ReadFile("spec/canonical.json")
+	if err != nil {
+		f.Fatal(err)
+	}
+	var specs struct {
+		Tests map[string]struct {
+			Source string `json:"source"`
+		} `json:"tests"`
+	}
+	if err := json.Unmarshal(b, &specs); err != nil {
+		f.Fatal(err)
+	}
+	for _, tc := range specs.Tests {
+		f.Add(tc.Source)
+	}
+}
+
+func FuzzParseString(f *testing.F) {
+	addCanonicalSeeds(f)
+	f.Add(benchmarkRecipe)
+	f.Add("@x{")
+	f.Add("~{5%")
+	f.Add("[-]")
+	parserV2 := NewParserV2(&ParseV2Config{})
+	f.Fuzz(func(t *testing.T, s string) {
+		// errors are fine, panics are not
+		_, _ = ParseString(s)
+		_, _ = parserV2.ParseString(s)
+	})
+}
+
+func FuzzRoundTrip(f *testing.F) {
+	addCanonicalSeeds(f)
+	f.Add(benchmarkRecipe)
+	f.Add("#pot{}-- x")
+	f.Add("~rest{}ing")
+	f.Add("#a~b(c)")
+	f.Fuzz(func(t *testing.T, s string) {
+		checkRoundTrip(t, s)
+	})
+}
+
+func FuzzParseCST(f *testing.F) {
+	addCanonicalSeeds(f)
+	f.Add(benchmarkRecipe)
+	f.Add("a\r\n\r\nb")
+	f.Fuzz(func(t *testing.T, s string) {
+		cst, err := ParseCST(s)
+		if err != nil {
+			return
+		}
+		if got := cst.String(); got != s {
+			t.Errorf("ParseCST(%q).String() = %q", s, got)
+		}
+	})
+}
@@ -0,0 +1,151 @@
+// Package graph infers the preparation flow of a recipe as a directed acyclic
+// graph of steps and exports it as DOT or Mermaid
+package graph
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+// MaxLabelLength is the maximum length in runes of the step node labels
+const MaxLabelLength = 40
+
+// Node is a step or a referenced recipe
+type Node struct {
+	ID    string // unique node identifier
+	Step  int    // zero based step index or -1 for recipe references
+	Label string // human readable label
+}
+
+// Edge connects the node producing an output to the node using it
+type Edge struct {
+	From  string // identifier of the source node
+	To    string // identifier of the target node
+	Label string // names of the shared cookware
+}
+
+// Graph is the directed acyclic graph of the recipe steps
+type Graph struct {
+	Nodes []Node
+	Edges []Edge
+}
+
+// isRecipeReference returns true for ingredients referencing another recipe
+// file (@./sauces/Hollandaise{150%g})
+func isRecipeReference(name string) bool {
+	return strings.HasPrefix(name, "./") || strings.HasPrefix(name, "../")
+}
+
+func stepLabel(number int, directions string) string {
+	runes := []rune(directions)
+	if len(runes) > MaxLabelLength {
+		directions = strings.TrimSpace(string(runes[:MaxLabelLength-1])) + "…"
+	}
+	return fmt.Sprintf("%d. %s", number, directions)
+}
+
+// Build infers the graph of the recipe steps. A step depends on the last
+// previous step which used the same cookware (case insensitive) and on the
+// recipes it references. Comment only steps are omitted.
+func Build(r *cooklang.Recipe) *Graph {
+	g := &Graph{}
+	lastUse := make(map[string]string) // cookware name -> node id
+	recipes := make(map[string]string) // recipe reference -> node id
+	edges := make(map[[2]string]int)   // from, to -> edge index
+	addEdge := func(from, to, label string) {
+		key := [2]string{from, to}
+		if i, ok := edges[key]; ok {
+			if label != "" {
+				g.Edges[i].Label += ", " + label
+			}
+			return
+		}
+		edges[key] = len(g.Edges)
+		g.Edges = append(g.Edges, Edge{from, to, label})
+	}
+	number := 0
+	for i, step := range r.Steps {
+		if step.IsComment() {
+			continue
+		}
+		number++
+		id := fmt.Sprintf("s%d", i)
+		g.Nodes = append(g.Nodes, Node{id, i, stepLabel(number, step.Directions)})
+		for _, ingredient := range step.Ingredients {
+			if !isRecipeReference(ingredient.Name) {
+				continue
+			}
+			from, ok := recipes[ingredient.Name]
+			if !ok {
+				from = fmt.Sprintf("r%d", len(recipes))
+				recipes[ingredient.Name] = from
+				g.Nodes = append(g.Nodes, Node{from, -1, ingredient.Name})
+			}
+			addEdge(from, id, "")
+		}
+		for _, c := range step.Cookware {
+			name := strings.ToLower(c.Name)
+			if from, ok := lastUse[name]; ok && from != id {
+				addEdge(from, id, c.Name)
+			}
+			lastUse[name] = id
+		}
+	}
+	return g
+}
+
+// DOT writes the graph in the Graphviz DOT format
+func DOT(w io.Writer, g *Graph) error {
+	var b strings.Builder
+	b.WriteString("digraph recipe {\n")
+	for _, n := range g.Nodes {
+		shape := "box"
+		if n.Step == -1 {
+			shape = "note"
+		}
+		fmt.Fprintf(&b, "\t%s [label=%s shape=%s];\n", n.ID, dotQuote(n.Label), shape)
+	}
+	for _, e := range g.Edges {
+		if e.Label != "" {
+			fmt.Fprintf(&b, "\t%s -> %s [label=%s];\n", e.From, e.To, dotQuote(e.Label))
+		} else {
+			fmt.Fprintf(&b, "\t%s -> %s;\n", e.From, e.To)
+		}
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Mermaid writes the graph as a Mermaid flowchart
+func Mermaid(w io.Writer, g *Graph) error {
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+	for _, n := range g.Nodes {
+		if n.Step == -1 {
+			fmt.Fprintf(&b, "    %s[/%s/]\n", n.ID, mermaidQuote(n.Label))
+		} else {
+			fmt.Fprintf(&b, "    %s[%s]\n", n.ID, mermaidQuote(n.Label))
+		}
+	}
+	for _, e := range g.Edges {
+		if e.Label != "" {
+			fmt.Fprintf(&b, "    %s -->|%s| %s\n", e.From, mermaidQuote(e.Label), e.To)
+		} else {
+			fmt.Fprintf(&b, "    %s --> %s\n", e.From, e.To)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
@@ -0,0 +1,88 @@
+package graph
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+const testRecipe = `Whisk @eggs{2} in a #bowl{}.
+-- heat the pan first
+Melt @butter{10%g} in a #pan{}.
+Pour the eggs from the #bowl{} into the #pan{} and add @./sauces/Hollandaise{50%g}.
+Serve on a #plate{} with @./sauces/Hollandaise{}.`
+
+func parseTestRecipe(t *testing.T) *cooklang.Recipe {
+	t.Helper()
+	r, err := cooklang.ParseString(testRecipe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestBuild(t *testing.T) {
+	want := &Graph{
+		Nodes: []Node{
+			{"s0", 0, "1. Whisk eggs in a bowl."},
+			{"s2", 2, "2. Melt butter in a pan."},
+			{"s3", 3, "3. Pour the eggs from the bowl into the pa…"},
+			{"r0", -1, "./sauces/Hollandaise"},
+			{"s4", 4, "4. Serve on a plate with ./sauces/Hollanda…"},
+		},
+		Edges: []Edge{
+			{"r0", "s3", ""},
+			{"s0", "s3", "bowl"},
+			{"s2", "s3", "pan"},
+			{"r0", "s4", ""},
+		},
+	}
+	if got := Build(parseTestRecipe(t)); !reflect.DeepEqual(got, want) {
+		t.Errorf("Build() = %#v, want %#v", got, want)
+	}
+}
+
+func TestDOT(t *testing.T) {
+	var b strings.Builder
+	if err := DOT(&b, Build(parseTestRecipe(t))); err != nil {
+		t.Fatalf("DOT() error = %v", err)
+	}
+	want := `digraph recipe {
+	s0 [label="1. Whisk eggs in a bowl." shape=box];
+	s2 [label="2. Melt butter in a pan." shape=box];
+	s3 [label="3. Pour the eggs from the bowl into the pa…" shape=box];
+	r0 [label="./sauces/Hollandaise" shape=note];
+	s4 [label="4. Serve on a plate with ./sauces/Hollanda…" shape=box];
+	r0 -> s3;
+	s0 -> s3 [label="bowl"];
+	s2 -> s3 [label="pan"];
+	r0 -> s4;
+}
+`
+	if got := b.String(); got != want {
+		t.Errorf("DOT() = %s, want %s", got, want)
+	}
+}
+
+func TestMermaid(t *testing.T) {
+	g := &Graph{
+		Nodes: []Node{{"s0", 0, `1. Say "hi"`}, {"r0", -1, "./Dough"}, {"s1", 1, "2. Bake"}},
+		Edges: []Edge{{"s0", "s1", "oven, tray"}, {"r0", "s1", ""}},
+	}
+	var b strings.Builder
+	if err := Mermaid(&b, g); err != nil {
+		t.Fatalf("Mermaid() error = %v", err)
+	}
+	want := `flowchart TD
+    s0["1. Say #quot;hi#quot;"]
+    r0[/"./Dough"/]
+    s1["2. Bake"]
+    s0 -->|"oven, tray"| s1
+    r0 --> s1
+`
+	if got := b.String(); got != want {
+		t.Errorf("Mermaid() = %s, want %s", got, want)
+	}
+}
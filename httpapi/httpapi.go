@@ -0,0 +1,182 @@
+// Package httpapi serves the recipes of a file system over HTTP, rendered on
+// the fly, so recipe serving can be mounted inside other Go servers
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/cache"
+	"github.com/aquilax/cooklang-go/export"
+	"github.com/aquilax/cooklang-go/ingredients"
+	"github.com/aquilax/cooklang-go/render"
+)
+
+// Options contains the handler options
+type Options struct {
+	Parse  *cooklang.ParseConfig    // parser configuration, nil uses the defaults
+	Render *render.Options          // rendering options of the HTML and Markdown responses
+	Merge  ingredients.MergeOptions // merge options of the shopping list
+	Cache  *cache.Cache             // optional cache of the parsed recipes, used instead of Parse
+}
+
+type handler struct {
+	fsys   fs.FS
+	parser *cooklang.Parser
+	opts   Options
+}
+
+// NewHandler returns a handler serving the recipe files (name.cook) of fsys:
+//
+//	GET /recipes/{name}.json          the parsed recipe as JSON
+//	GET /recipes/{name}.html          the recipe rendered as HTML
+//	GET /recipes/{name}.md            the recipe rendered as Markdown
+//	GET /recipes/{name}.jsonld        the recipe as schema.org Recipe JSON-LD
+//	GET /timers/{name}                the timers of the recipe as JSON
+//	GET /shopping-list?recipes=a,b    the merged ingredients of the recipes as JSON
+//
+// Names can contain slashes for recipes in sub directories. The locale query
+// parameter overrides the rendering locale and the scale query parameter
+// scales the recipe ("2x" or number of servings, see cooklang.ScaleFactor).
+// Nil options use the defaults.
+func NewHandler(fsys fs.FS, opts *Options) http.Handler {
+	h := &handler{fsys: fsys}
+	if opts != nil {
+		h.opts = *opts
+	}
+	h.parser = cooklang.NewParser(h.opts.Parse)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /recipes/{name...}", h.recipe)
+	mux.HandleFunc("GET /timers/{name...}", h.timers)
+	mux.HandleFunc("GET /shopping-list", h.shoppingList)
+	return mux
+}
+
+// parse parses the recipe file of the name and writes the error response on
+// failure
+func (h *handler) parse(w http.ResponseWriter, name string) *cooklang.Recipe {
+	fileName := name + cooklang.RecipeFileExtension
+	if !fs.ValidPath(fileName) {
+		http.Error(w, "invalid recipe name", http.StatusBadRequest)
+		return nil
+	}
+	var r *cooklang.Recipe
+	var err error
+	if h.opts.Cache != nil {
+		r, err = h.opts.Cache.ParseFileFS(h.fsys, fileName)
+	} else {
+		r, err = h.parser.ParseFileFS(h.fsys, fileName)
+	}
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		http.Error(w, "recipe not found", http.StatusNotFound)
+		return nil
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return nil
+	}
+	return r
+}
+
+func (h *handler) renderOptions(req *http.Request) *render.Options {
+	var opts render.Options
+	if h.opts.Render != nil {
+		opts = *h.opts.Render
+	}
+	if locale := req.URL.Query().Get("locale"); locale != "" {
+		opts.Locale = locale
+	}
+	return &opts
+}
+
+func (h *handler) recipe(w http.ResponseWriter, req *http.Request) {
+	name := req.PathValue("name")
+	ext := path.Ext(name)
+	var contentType string
+	switch ext {
+	case ".json":
+		contentType = "application/json"
+	case ".html":
+		contentType = "text/html; charset=utf-8"
+	case ".md":
+		contentType = "text/markdown; charset=utf-8"
+	case ".jsonld":
+		contentType = "application/ld+json"
+	default:
+		http.NotFound(w, req)
+		return
+	}
+	r := h.parse(w, strings.TrimSuffix(name, ext))
+	if r == nil {
+		return
+	}
+	if scale := req.URL.Query().Get("scale"); scale != "" {
+		factor, err := cooklang.ScaleFactor(r, scale)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		r = cooklang.Scale(r, factor)
+	}
+	w.Header().Set("Content-Type", contentType)
+	switch ext {
+	case ".json":
+		_ = json.NewEncoder(w).Encode(r)
+	case ".html":
+		_ = render.HTML(w, r, h.renderOptions(req))
+	case ".md":
+		_ = render.Markdown(w, r, h.renderOptions(req))
+	case ".jsonld":
+		_ = export.JSONLD(w, r)
+	}
+}
+
+// timer is a recipe timer in the timers response
+type timer struct {
+	Step     int     `json:"step"`  // zero based step index
+	Index    int     `json:"index"` // zero based index of the timer in the step
+	Name     string  `json:"name,omitempty"`
+	Duration float64 `json:"duration"`
+	Unit     string  `json:"unit,omitempty"`
+	ISO8601  string  `json:"iso8601,omitempty"` // duration as ISO 8601 duration ("PT20M")
+}
+
+func (h *handler) timers(w http.ResponseWriter, req *http.Request) {
+	r := h.parse(w, req.PathValue("name"))
+	if r == nil {
+		return
+	}
+	list := make([]timer, 0)
+	for _, t := range r.Timers() {
+		list = append(list, timer{t.Step, t.Index, t.Name, t.Duration, t.Unit, t.ISO8601()})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(list)
+}
+
+func (h *handler) shoppingList(w http.ResponseWriter, req *http.Request) {
+	names := strings.Split(req.URL.Query().Get("recipes"), ",")
+	var list []cooklang.Ingredient
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		r := h.parse(w, name)
+		if r == nil {
+			return
+		}
+		list = append(list, r.AllIngredients(nil)...)
+	}
+	merged := ingredients.Merge(list, h.opts.Merge)
+	if merged == nil {
+		merged = []cooklang.Ingredient{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(merged)
+}
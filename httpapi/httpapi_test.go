@@ -0,0 +1,124 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/cache"
+	"github.com/aquilax/cooklang-go/render"
+)
+
+var testFS = fstest.MapFS{
+	"soup.cook":          {Data: []byte(">> title: Soup\nBoil @water{1%l} with @salt{5%g} for ~{10%minutes}.")},
+	"desserts/cake.cook": {Data: []byte("Mix @flour{200%g} with @salt{2%g}.")},
+	"broken.cook":        {Data: []byte(">> no separator")},
+}
+
+func TestHandler(t *testing.T) {
+	h := NewHandler(testFS, &Options{Render: &render.Options{Locale: "en"}})
+	tests := []struct {
+		name        string
+		url         string
+		status      int
+		contentType string
+		contains    string
+	}{
+		{"JSON", "/recipes/soup.json", http.StatusOK, "application/json", `"Name":"water"`},
+		{"HTML", "/recipes/soup.html", http.StatusOK, "text/html; charset=utf-8", "<h1>Soup</h1>"},
+		{"Markdown", "/recipes/desserts/cake.md", http.StatusOK, "text/markdown; charset=utf-8", "- 200 g flour"},
+		{"Locale", "/recipes/soup.md?locale=de", http.StatusOK, "text/markdown; charset=utf-8", "Minuten"},
+		{"JSON-LD", "/recipes/soup.jsonld", http.StatusOK, "application/ld+json", `"totalTime": "PT10M"`},
+		{"Timers", "/timers/soup", http.StatusOK, "application/json", `[{"step":0,"index":0,"duration":10,"unit":"minutes","iso8601":"PT10M"}]`},
+		{"Timers not found", "/timers/missing", http.StatusNotFound, "", "recipe not found"},
+		{"Scale", "/recipes/desserts/cake.md?scale=2x", http.StatusOK, "text/markdown; charset=utf-8", "- 400 g flour"},
+		{"Invalid scale", "/recipes/desserts/cake.md?scale=4", http.StatusBadRequest, "", "servings"},
+		{"Not found", "/recipes/missing.json", http.StatusNotFound, "", "recipe not found"},
+		{"Unknown format", "/recipes/soup.pdf", http.StatusNotFound, "", ""},
+		{"Parse error", "/recipes/broken.html", http.StatusUnprocessableEntity, "", "invalid metadata"},
+		{"Method", "/recipes/soup.json", http.StatusMethodNotAllowed, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := http.MethodGet
+			if tt.status == http.StatusMethodNotAllowed {
+				method = http.MethodPost
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(method, tt.url, nil))
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d (%s)", rec.Code, tt.status, rec.Body)
+			}
+			if tt.contentType != "" && rec.Header().Get("Content-Type") != tt.contentType {
+				t.Errorf("Content-Type = %q, want %q", rec.Header().Get("Content-Type"), tt.contentType)
+			}
+			if !strings.Contains(rec.Body.String(), tt.contains) {
+				t.Errorf("body = %q, want it to contain %q", rec.Body, tt.contains)
+			}
+		})
+	}
+}
+
+func TestHandlerShoppingList(t *testing.T) {
+	h := NewHandler(testFS, nil)
+	tests := []struct {
+		url    string
+		status int
+		want   []cooklang.Ingredient
+	}{
+		{
+			"/shopping-list?recipes=soup,desserts/cake",
+			http.StatusOK,
+			[]cooklang.Ingredient{
+				{Name: "flour", Amount: cooklang.IngredientAmount{IsNumeric: true, Quantity: 200, QuantityRaw: "200", Unit: "g"}},
+				{Name: "salt", Amount: cooklang.IngredientAmount{IsNumeric: true, Quantity: 7, QuantityRaw: "7", Unit: "g"}},
+				{Name: "water", Amount: cooklang.IngredientAmount{IsNumeric: true, Quantity: 1, QuantityRaw: "1", Unit: "l"}},
+			},
+		},
+		{"/shopping-list", http.StatusOK, []cooklang.Ingredient{}},
+		{"/shopping-list?recipes=soup,missing", http.StatusNotFound, nil},
+		{"/shopping-list?recipes=../soup", http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.url, nil))
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d (%s)", rec.Code, tt.status, rec.Body)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			var got []cooklang.Ingredient
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("shopping list = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandlerCache(t *testing.T) {
+	c, err := cache.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewHandler(testFS, &Options{Cache: c})
+	for range 3 {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/recipes/soup.json", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+		}
+	}
+	if got, want := c.Stats(), (cache.Stats{Hits: 2, Misses: 1}); got != want {
+		t.Errorf("cache stats = %+v, want %+v", got, want)
+	}
+}
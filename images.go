@@ -0,0 +1,79 @@
+package cooklang
+
+import (
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ImageExtensions lists the file extensions recognized as recipe images
+var ImageExtensions = []string{".jpg", ".jpeg", ".png"}
+
+// RecipeImages contains the images related to a recipe file
+type RecipeImages struct {
+	Cover string         // path of the recipe image
+	Steps map[int]string // paths of the step images keyed by the zero based step number (comment only steps are not counted)
+}
+
+// FindImages finds the images for the recipe at recipePath following the
+// spec naming convention: "Recipe Name.jpg" is the recipe image and
+// "Recipe Name.2.jpg" is the image of the third step. Like in the rendered
+// recipes, the comment only steps (see Step.IsComment) are not counted. The
+// result can be attached to the parsed recipe using Recipe.AttachImages.
+func FindImages(fsys fs.FS, recipePath string) (RecipeImages, error) {
+	images := RecipeImages{Steps: make(map[int]string)}
+	dir := path.Dir(recipePath)
+	base := path.Base(recipePath)
+	base = strings.TrimSuffix(base, path.Ext(base))
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return images, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		ext := path.Ext(name)
+		if !slices.Contains(ImageExtensions, strings.ToLower(ext)) {
+			continue
+		}
+		stem := strings.TrimSuffix(name, ext)
+		if stem == base {
+			if images.Cover == "" {
+				images.Cover = path.Join(dir, name)
+			}
+			continue
+		}
+		suffix, found := strings.CutPrefix(stem, base+".")
+		if !found {
+			continue
+		}
+		index, err := strconv.Atoi(suffix)
+		if err != nil || index < 0 {
+			continue
+		}
+		if _, ok := images.Steps[index]; !ok {
+			images.Steps[index] = path.Join(dir, name)
+		}
+	}
+	return images, nil
+}
+
+// AttachImages sets the recipe images and the image of every step which has
+// one
+func (r *Recipe) AttachImages(images RecipeImages) {
+	r.Images = &images
+	number := 0
+	for i := range r.Steps {
+		if r.Steps[i].IsComment() {
+			continue
+		}
+		if image, ok := images.Steps[number]; ok {
+			r.Steps[i].Image = image
+		}
+		number++
+	}
+}
@@ -0,0 +1,51 @@
+package cooklang
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFindImages(t *testing.T) {
+	fsys := fstest.MapFS{
+		"recipes/Baked Potato.cook":  {Data: []byte("Bake @potato.")},
+		"recipes/Baked Potato.jpg":   {},
+		"recipes/Baked Potato.0.png": {},
+		"recipes/Baked Potato.2.JPG": {},
+		"recipes/Baked Potato.x.jpg": {},
+		"recipes/Baked Potato.1.txt": {},
+		"recipes/Potato Salad.1.jpg": {},
+	}
+	got, err := FindImages(fsys, "recipes/Baked Potato.cook")
+	if err != nil {
+		t.Fatalf("FindImages() error = %v", err)
+	}
+	want := RecipeImages{
+		Cover: "recipes/Baked Potato.jpg",
+		Steps: map[int]string{
+			0: "recipes/Baked Potato.0.png",
+			2: "recipes/Baked Potato.2.JPG",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindImages() = %#v, want %#v", got, want)
+	}
+	if _, err := FindImages(fsys, "missing/recipe.cook"); err == nil {
+		t.Errorf("FindImages() expected error for missing directory")
+	}
+}
+
+func TestRecipe_AttachImages(t *testing.T) {
+	r, err := ParseString("Bake @potato.\n\n-- meanwhile\n\nMash it.\n\nServe.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.AttachImages(RecipeImages{Steps: map[int]string{1: "1.jpg", 2: "2.jpg", 3: "3.jpg"}})
+	var got []string
+	for _, step := range r.Steps {
+		got = append(got, step.Image)
+	}
+	if want := []string{"", "", "1.jpg", "2.jpg"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AttachImages() step images = %q, want %q", got, want)
+	}
+}
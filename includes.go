@@ -0,0 +1,157 @@
+package cooklang
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// MetadataInclude is the metadata key of the included recipes
+// (>> include: ./base.cook, see IncludeMode)
+const MetadataInclude = "include"
+
+// ErrIncludeCycle is returned when a recipe includes itself directly or
+// through other recipes
+var ErrIncludeCycle = errors.New("include cycle")
+
+// IncludeMode defines how the recipes listed in the include metadata are
+// added to the recipe. The paths are relative to the directory of the
+// recipe file, or to the working directory for the recipes which are not
+// read from a file. The metadata of the included recipes is added for the
+// keys the recipe does not define.
+type IncludeMode int
+
+const (
+	// IncludesOff keeps include as a regular metadata key (default)
+	IncludesOff IncludeMode = iota
+	// IncludeSteps inserts the steps of the included recipe at the
+	// position of the include line and adds its metadata
+	IncludeSteps
+	// IncludeMetadata adds only the metadata of the included recipe
+	IncludeMetadata
+)
+
+// documentInclude is an included recipe and the position of its include
+// line
+type documentInclude struct {
+	ref  string // path as written
+	step int    // number of the steps before the include line
+	line int    // line number of the include line
+}
+
+// includeSource reads the included recipes from the file system of the
+// including recipe
+type includeSource struct {
+	fsys  fs.FS    // nil for the os file system
+	name  string   // name of the including recipe, empty for the streams
+	stack []string // names of the recipes being parsed
+}
+
+// fileIncludeSource returns the source of the recipes included by the file
+func fileIncludeSource(fsys fs.FS, name string) includeSource {
+	if fsys == nil {
+		name = filepath.Clean(name)
+	}
+	return includeSource{fsys, name, []string{name}}
+}
+
+// resolve returns the name of the included recipe
+func (s includeSource) resolve(ref string) string {
+	if s.fsys != nil {
+		return path.Join(path.Dir(s.name), ref)
+	}
+	if ref = filepath.FromSlash(ref); filepath.IsAbs(ref) {
+		return filepath.Clean(ref)
+	}
+	return filepath.Join(filepath.Dir(s.name), ref)
+}
+
+func (s includeSource) open(name string) (io.ReadCloser, error) {
+	if s.fsys != nil {
+		return s.fsys.Open(name)
+	}
+	return os.Open(name)
+}
+
+// addInclude records the included recipes of the include metadata value
+func (d *document) addInclude(t *tokenizer, value string, list []string) {
+	if list == nil {
+		list = []string{strings.TrimSpace(value)}
+	}
+	for _, ref := range list {
+		if ref != "" {
+			d.includes = append(d.includes, documentInclude{ref, len(d.steps), t.line})
+		}
+	}
+}
+
+// resolveIncludes parses the included recipes and adds their metadata and
+// steps (see IncludeMode)
+func (d *document) resolveIncludes(config documentConfig, warnings *warningList) error {
+	source := config.includeSource
+	var steps []documentStep
+	last := 0
+	for _, include := range d.includes {
+		name := source.resolve(include.ref)
+		if slices.Contains(source.stack, name) {
+			return fmt.Errorf("line %d: %w: %s", include.line, ErrIncludeCycle, strings.Join(append(slices.Clone(source.stack), name), " -> "))
+		}
+		included, err := d.parseInclude(config, name, warnings)
+		if err != nil {
+			return fmt.Errorf("line %d: include %q: %w", include.line, include.ref, err)
+		}
+		d.inheritMetadata(included)
+		if config.includes == IncludeSteps {
+			steps = append(steps, d.steps[last:include.step]...)
+			for _, s := range included.steps {
+				// the items of the released document are reused
+				s.items = slices.Clone(s.items)
+				steps = append(steps, s)
+			}
+			last = include.step
+		}
+		included.release()
+	}
+	if config.includes == IncludeSteps {
+		d.steps = append(steps, d.steps[last:]...)
+	}
+	return config.limits.checkSteps(len(d.steps))
+}
+
+// parseInclude parses the included recipe with the configuration of the
+// including one
+func (d *document) parseInclude(config documentConfig, name string, warnings *warningList) (*document, error) {
+	source := config.includeSource
+	f, err := source.open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	config.includeSource = includeSource{source.fsys, name, append(slices.Clip(source.stack), name)}
+	return parseDocument(bufio.NewReader(f), config, warnings)
+}
+
+// inheritMetadata adds the metadata entries of the included document which
+// the document does not define
+func (d *document) inheritMetadata(included *document) {
+	for _, key := range included.metadataOrder {
+		if _, ok := d.metadata[key]; ok || key == MetadataInclude {
+			continue
+		}
+		d.metadata[key] = included.metadata[key]
+		d.metadataOrder = append(d.metadataOrder, key)
+		if list, ok := included.metadataLists[key]; ok {
+			if d.metadataLists == nil {
+				d.metadataLists = make(map[string][]string)
+			}
+			d.metadataLists[key] = list
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package cooklang
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestParseIncludes(t *testing.T) {
+	fsys := fstest.MapFS{
+		"base/dough.cook": &fstest.MapFile{Data: []byte(">> author: Ann\n>> servings: 2\nMix @flour{500%g} and @water{300%ml}.\nKnead the dough.")},
+		"pizza.cook":      &fstest.MapFile{Data: []byte(">> title: Pizza\n>> servings: 4\nPreheat the #oven.\n>> include: base/dough.cook\nAdd @tomatoes{200%g} and bake.")},
+	}
+	tests := []struct {
+		name       string
+		mode       IncludeMode
+		directions []string
+		keys       []string
+	}{
+		{"Off", IncludesOff, []string{"Preheat the oven.", "Add tomatoes and bake."}, []string{"title", "servings", "include"}},
+		{"Steps", IncludeSteps, []string{"Preheat the oven.", "Mix flour and water.", "Knead the dough.", "Add tomatoes and bake."}, []string{"title", "servings", "include", "author"}},
+		{"Metadata", IncludeMetadata, []string{"Preheat the oven.", "Add tomatoes and bake."}, []string{"title", "servings", "include", "author"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewParser(&ParseConfig{Includes: tt.mode}).ParseFileFS(fsys, "pizza.cook")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var directions []string
+			for _, s := range r.Steps {
+				directions = append(directions, s.Directions)
+			}
+			if !reflect.DeepEqual(directions, tt.directions) {
+				t.Errorf("directions = %q, want %q", directions, tt.directions)
+			}
+			if got := r.MetadataKeys(); !reflect.DeepEqual(got, tt.keys) {
+				t.Errorf("metadata keys = %v, want %v", got, tt.keys)
+			}
+			if r.Metadata["servings"] != "4" {
+				t.Errorf("servings = %q, want the recipe value", r.Metadata["servings"])
+			}
+		})
+	}
+
+	r, err := NewParserV2(&ParseV2Config{Includes: IncludeSteps}).ParseFileFS(fsys, "pizza.cook")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Steps) != 4 || r.Steps[1].Directions() != "Mix flour and water." {
+		t.Errorf("v2 steps = %v, want the included steps", r.Steps)
+	}
+}
+
+func TestParseIncludeErrors(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"a.cook":       ">> include: sub/b.cook\nStep a.",
+		"sub/b.cook":   ">> include: ../a.cook\nStep b.",
+		"self.cook":    ">> include: [./self.cook]\nStep.",
+		"missing.cook": "Step.\n>> include: none.cook",
+	})
+	p := NewParser(&ParseConfig{Includes: IncludeSteps})
+	for _, name := range []string{"a.cook", "self.cook"} {
+		if _, err := p.ParseFile(filepath.Join(dir, name)); !errors.Is(err, ErrIncludeCycle) {
+			t.Errorf("ParseFile(%q) error = %v, want ErrIncludeCycle", name, err)
+		}
+	}
+	_, err := p.ParseFile(filepath.Join(dir, "missing.cook"))
+	if want := `line 2: include "none.cook": `; err == nil || !strings.HasPrefix(err.Error(), want) {
+		t.Errorf("ParseFile() error = %v, want prefix %q", err, want)
+	}
+}
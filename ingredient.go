@@ -0,0 +1,74 @@
+package cooklang
+
+import "strings"
+
+// IngredientMerger merges a list of ingredients, see the MergeOptions of the
+// ingredients package
+type IngredientMerger interface {
+	Merge(list []Ingredient) []Ingredient
+}
+
+// AllIngredients returns the ingredients of all steps merged by m. A nil
+// merger returns the ingredients in step order without merging. The
+// references to earlier ingredients are left out, so they are counted once.
+func (r *Recipe) AllIngredients(m IngredientMerger) []Ingredient {
+	var result []Ingredient
+	for _, step := range r.Steps {
+		result = appendDeclared(result, step.Ingredients)
+	}
+	if m == nil {
+		return result
+	}
+	return m.Merge(result)
+}
+
+// AttributeSection is the step attribute with the name of the recipe section
+// ("Dough", "Sauce") the step belongs to
+const AttributeSection = "section"
+
+// Section returns the name of the recipe section of the step, empty for the
+// steps without section
+func (s Step) Section() string {
+	name, _ := s.Attributes[AttributeSection].(string)
+	return strings.TrimSpace(name)
+}
+
+// IsComment returns true for the comment only steps, which have no
+// directions, ingredients, cookware or timers
+func (s Step) IsComment() bool {
+	return s.Directions == "" && len(s.Ingredients) == 0 && len(s.Cookware) == 0 && len(s.Timers) == 0
+}
+
+// IngredientSection is the ingredients of a recipe section
+type IngredientSection struct {
+	Name        string // empty for the steps without section
+	Ingredients []Ingredient
+}
+
+// IngredientsBySection returns the ingredients grouped by the recipe sections
+// of the steps in the order of their first step. The ingredients of every
+// section are merged by m, a nil merger keeps them in step order. The
+// sections without ingredients are left out.
+func (r *Recipe) IngredientsBySection(m IngredientMerger) []IngredientSection {
+	var sections []IngredientSection
+	index := make(map[string]int)
+	for _, step := range r.Steps {
+		if len(step.Ingredients) == 0 {
+			continue
+		}
+		name := step.Section()
+		i, ok := index[name]
+		if !ok {
+			i = len(sections)
+			index[name] = i
+			sections = append(sections, IngredientSection{Name: name})
+		}
+		sections[i].Ingredients = appendDeclared(sections[i].Ingredients, step.Ingredients)
+	}
+	if m != nil {
+		for i := range sections {
+			sections[i].Ingredients = m.Merge(sections[i].Ingredients)
+		}
+	}
+	return sections
+}
@@ -0,0 +1,203 @@
+package cooklang
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// upperMerger is a merger which only changes the names
+type upperMerger struct{}
+
+func (upperMerger) Merge(list []Ingredient) []Ingredient {
+	for i := range list {
+		list[i].Name = strings.ToUpper(list[i].Name)
+	}
+	return list
+}
+
+func TestRecipeAllIngredients(t *testing.T) {
+	r, err := ParseString("Mix @flour{200%g} and @salt{}.\n-- comment\nAdd @flour{100%g}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Ingredient{
+		{"flour", IngredientAmount{true, 200, "200", "g"}, false, false, false, false},
+		{"salt", IngredientAmount{false, 0, "", ""}, false, false, false, false},
+		{"flour", IngredientAmount{true, 100, "100", "g"}, false, false, false, false},
+	}
+	if got := r.AllIngredients(nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("AllIngredients(nil) = %#v, want %#v", got, want)
+	}
+	if got := r.AllIngredients(upperMerger{}); got[0].Name != "FLOUR" || r.Steps[0].Ingredients[0].Name != "flour" {
+		t.Errorf("AllIngredients() = %v, steps = %v", got, r.Steps)
+	}
+}
+
+func TestParseEmptyAmountMode(t *testing.T) {
+	src := "Add @salt, @pepper{} and @sugar{1%tsp} to the #pot."
+	tests := []struct {
+		name   string
+		config ParseConfig
+		want   []float64
+	}{
+		{"legacy", ParseConfig{}, []float64{1, 0, 1}},
+		{"some", ParseConfig{EmptyAmountMode: EmptyAmountDefault}, []float64{0, 0, 1}},
+		{"default", ParseConfig{EmptyAmountMode: EmptyAmountDefault, DefaultQuantity: 1}, []float64{1, 1, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewParser(&tt.config).ParseString(src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []float64
+			for _, i := range r.Steps[0].Ingredients {
+				got = append(got, i.Amount.Quantity)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("quantities = %v, want %v", got, tt.want)
+			}
+			present := []bool{false, false, true}
+			for i, ingredient := range r.Steps[0].Ingredients {
+				if ingredient.Amount.IsPresent() != present[i] {
+					t.Errorf("%s IsPresent() = %v, want %v", ingredient.Name, ingredient.Amount.IsPresent(), present[i])
+				}
+			}
+			if r.Steps[0].Cookware[0].IsPresent() {
+				t.Errorf("#pot IsPresent() = true, want false")
+			}
+		})
+	}
+}
+
+func TestRecipeIngredientsBySection(t *testing.T) {
+	r, err := ParseString("Prepare @eggs{2}.\n\nMix @flour{200%g} and @water{100%ml}.\n\nAdd @flour{50%g}.\n\nSimmer @tomatoes{400%g}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, section := range []string{"", "Dough", "Dough", "Sauce"} {
+		if section != "" {
+			r.Steps[i].Attributes = map[string]any{AttributeSection: section}
+		}
+	}
+	want := []IngredientSection{
+		{"", []Ingredient{{"eggs", IngredientAmount{true, 2, "2", ""}, false, false, false, false}}},
+		{"Dough", []Ingredient{
+			{"flour", IngredientAmount{true, 200, "200", "g"}, false, false, false, false},
+			{"water", IngredientAmount{true, 100, "100", "ml"}, false, false, false, false},
+			{"flour", IngredientAmount{true, 50, "50", "g"}, false, false, false, false},
+		}},
+		{"Sauce", []Ingredient{{"tomatoes", IngredientAmount{true, 400, "400", "g"}, false, false, false, false}}},
+	}
+	if got := r.IngredientsBySection(nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("IngredientsBySection(nil) = %#v, want %#v", got, want)
+	}
+}
+
+func TestStepIsComment(t *testing.T) {
+	r, err := ParseString("-- just a note\n\nServe.\n\nAdd @salt.\n\nWait ~{5%minutes}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []bool{true, false, false, false} {
+		if got := r.Steps[i].IsComment(); got != want {
+			t.Errorf("Steps[%d].IsComment() = %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestParseOptionalIngredients(t *testing.T) {
+	r, err := NewParser(&ParseConfig{Strict: true}).ParseString("Top with @?parsley{1%tbsp} and @?{}.")
+	if !errors.Is(err, ErrEmptyItem) {
+		t.Fatalf("ParseString() = %v, want ErrEmptyItem for @?{}", r)
+	}
+	r, err = ParseString("Top with @?parsley{1%tbsp} and @chili{}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Ingredient{
+		{Name: "parsley", Amount: IngredientAmount{true, 1, "1", "tbsp"}, Optional: true},
+		{Name: "chili", Amount: IngredientAmount{}},
+	}
+	if !reflect.DeepEqual(r.Steps[0].Ingredients, want) {
+		t.Errorf("ingredients = %v, want %v", r.Steps[0].Ingredients, want)
+	}
+	if got := r.Steps[0].Directions; got != "Top with parsley and chili." {
+		t.Errorf("directions = %q", got)
+	}
+	if got := r.ToV2().Steps[0][1]; !got.(IngredientV2).Optional {
+		t.Errorf("ToV2() ingredient = %v, want optional", got)
+	}
+}
+
+func TestParseHiddenIngredientsDirections(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"Add @-salt{} to taste", "Add to taste"},
+		{"Add @-salt{1%tsp}, then stir", "Add, then stir"},
+		{"@-salt{} Season the stock", "Season the stock"},
+		{"Season the stock @-salt{}", "Season the stock"},
+		{"Add\t@-salt{} and @-pepper{} to taste", "Add and to taste"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			r, err := ParseString(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := r.Steps[0].Directions; got != tt.want {
+				t.Errorf("Directions = %q, want %q", got, tt.want)
+			}
+			v2, err := NewParserV2(nil).ParseString(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := v2.Steps[0].Directions(); got != tt.want {
+				t.Errorf("StepV2.Directions() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHiddenIngredients(t *testing.T) {
+	src := "Bring the stock@-salt{1%tsp} to a boil with @-bay leaves{2}."
+	r, err := ParseString(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Ingredient{
+		{Name: "salt", Amount: IngredientAmount{true, 1, "1", "tsp"}, Hidden: true},
+		{Name: "bay leaves", Amount: IngredientAmount{true, 2, "2", ""}, Hidden: true},
+	}
+	if !reflect.DeepEqual(r.Steps[0].Ingredients, want) {
+		t.Errorf("ingredients = %v, want %v", r.Steps[0].Ingredients, want)
+	}
+	if got := r.Steps[0].Directions; got != "Bring the stock to a boil with." {
+		t.Errorf("directions = %q", got)
+	}
+	if got := r.AllIngredients(nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("AllIngredients() = %v, want %v", got, want)
+	}
+
+	v2, err := NewParserV2(nil).ParseString(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := v2.Steps[0].Directions(); got != r.Steps[0].Directions {
+		t.Errorf("StepV2.Directions() = %q, want %q", got, r.Steps[0].Directions)
+	}
+	v1, err := v2.ToV1()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !v1.Steps[0].Ingredients[0].Hidden {
+		t.Error("ToV1() dropped the hidden marker")
+	}
+	if got := r.ToV2().Steps[0].Directions(); got != r.Steps[0].Directions {
+		t.Errorf("ToV2() directions = %q, want %q", got, r.Steps[0].Directions)
+	}
+}
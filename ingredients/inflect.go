@@ -0,0 +1,182 @@
+package ingredients
+
+import (
+	"strings"
+	"sync"
+)
+
+// Inflector converts ingredient names between singular and plural forms.
+// Irregular words and words without plural can be registered as exceptions.
+// It is safe for concurrent use.
+type Inflector struct {
+	mu        sync.RWMutex
+	plurals   map[string]string // singular to plural
+	singulars map[string]string // plural to singular
+}
+
+// DefaultInflector is the inflector used by Singular, Plural and Merge
+var DefaultInflector = NewInflector()
+
+var defaultIrregulars = map[string]string{
+	"leaf":    "leaves",
+	"loaf":    "loaves",
+	"half":    "halves",
+	"knife":   "knives",
+	"wolf":    "wolves",
+	"shelf":   "shelves",
+	"avocado": "avocados",
+	"mango":   "mangoes",
+	"child":   "children",
+	"tooth":   "teeth",
+	"goose":   "geese",
+	"cookie":  "cookies",
+	"brownie": "brownies",
+	"calorie": "calories",
+}
+
+var defaultUncountables = []string{
+	"asparagus", "couscous", "hummus", "molasses", "swiss", "rice", "flour",
+	"water", "milk", "salt", "sugar", "butter", "bread", "cheese", "oil",
+	"fish", "sheep", "deer", "quinoa", "pasta", "spaghetti", "broccoli",
+	"series", "species", "chives", "grits", "oats", "greens",
+}
+
+// NewInflector creates an inflector with the default exceptions
+func NewInflector() *Inflector {
+	i := &Inflector{
+		plurals:   make(map[string]string),
+		singulars: make(map[string]string),
+	}
+	for singular, plural := range defaultIrregulars {
+		i.AddException(singular, plural)
+	}
+	for _, word := range defaultUncountables {
+		i.AddException(word, word)
+	}
+	return i
+}
+
+// AddException registers an irregular singular/plural pair. Words without a
+// different plural form are registered using the same value for both.
+func (i *Inflector) AddException(singular, plural string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	singular = strings.ToLower(singular)
+	plural = strings.ToLower(plural)
+	i.plurals[singular] = plural
+	i.singulars[plural] = singular
+}
+
+// Singular returns the singular form of the name. Only the last word of
+// multi word names is changed.
+func (i *Inflector) Singular(name string) string {
+	prefix, word := splitLastWord(name)
+	lower := strings.ToLower(word)
+	i.mu.RLock()
+	singular, ok := i.singulars[lower]
+	if !ok {
+		_, ok = i.plurals[lower]
+		singular = lower
+	}
+	i.mu.RUnlock()
+	if ok {
+		return prefix + matchCase(word, singular)
+	}
+	return prefix + singularize(word)
+}
+
+// Plural returns the plural form of the name. Only the last word of multi
+// word names is changed.
+func (i *Inflector) Plural(name string) string {
+	prefix, word := splitLastWord(name)
+	lower := strings.ToLower(word)
+	i.mu.RLock()
+	plural, ok := i.plurals[lower]
+	if !ok {
+		_, ok = i.singulars[lower]
+		plural = lower
+	}
+	i.mu.RUnlock()
+	if ok {
+		return prefix + matchCase(word, plural)
+	}
+	return prefix + pluralize(word)
+}
+
+// Singular returns the singular form of the name using the DefaultInflector
+func Singular(name string) string {
+	return DefaultInflector.Singular(name)
+}
+
+// Plural returns the plural form of the name using the DefaultInflector
+func Plural(name string) string {
+	return DefaultInflector.Plural(name)
+}
+
+func splitLastWord(name string) (string, string) {
+	index := strings.LastIndexAny(name, " -")
+	return name[:index+1], name[index+1:]
+}
+
+// matchCase keeps the original word when only the case differs
+func matchCase(original, inflected string) string {
+	if strings.EqualFold(original, inflected) {
+		return original
+	}
+	return inflected
+}
+
+func isVowel(b byte) bool {
+	return strings.IndexByte("aeiou", b) != -1
+}
+
+// hasSuffixFold reports whether word ends with the ASCII suffix ignoring
+// case. The suffix is matched on word itself so the offset can be used to
+// slice it.
+func hasSuffixFold(word, suffix string) bool {
+	return len(word) >= len(suffix) && strings.EqualFold(word[len(word)-len(suffix):], suffix)
+}
+
+// lowerByteAt returns the ASCII lower case of the byte at index i
+func lowerByteAt(word string, i int) byte {
+	b := word[i]
+	if 'A' <= b && b <= 'Z' {
+		b += 'a' - 'A'
+	}
+	return b
+}
+
+func singularize(word string) string {
+	n := len(word)
+	switch {
+	case n > 4 && hasSuffixFold(word, "ies"):
+		return word[:n-3] + "y"
+	case n > 4 && hasSuffixFold(word, "oes"):
+		return word[:n-2]
+	case hasSuffixFold(word, "ches"), hasSuffixFold(word, "shes"),
+		hasSuffixFold(word, "sses"), hasSuffixFold(word, "xes"),
+		hasSuffixFold(word, "zes"):
+		return word[:n-2]
+	case hasSuffixFold(word, "ss"), hasSuffixFold(word, "us"), hasSuffixFold(word, "is"):
+		return word
+	case n > 1 && hasSuffixFold(word, "s"):
+		return word[:n-1]
+	}
+	return word
+}
+
+func pluralize(word string) string {
+	n := len(word)
+	switch {
+	case n == 0:
+		return word
+	case n > 1 && lowerByteAt(word, n-1) == 'y' && !isVowel(lowerByteAt(word, n-2)):
+		return word[:n-1] + "ies"
+	case n > 1 && lowerByteAt(word, n-1) == 'o' && !isVowel(lowerByteAt(word, n-2)):
+		return word + "es"
+	case hasSuffixFold(word, "s"), hasSuffixFold(word, "x"), hasSuffixFold(word, "z"),
+		hasSuffixFold(word, "ch"), hasSuffixFold(word, "sh"):
+		return word + "es"
+	}
+	return word + "s"
+}
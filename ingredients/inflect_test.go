@@ -0,0 +1,77 @@
+package ingredients
+
+import "testing"
+
+func TestSingularPlural(t *testing.T) {
+	tests := []struct {
+		singular string
+		plural   string
+	}{
+		{"egg", "eggs"},
+		{"tomato", "tomatoes"},
+		{"berry", "berries"},
+		{"peach", "peaches"},
+		{"radish", "radishes"},
+		{"leaf", "leaves"},
+		{"olive", "olives"},
+		{"avocado", "avocados"},
+		{"green onion", "green onions"},
+		{"rice", "rice"},
+		{"asparagus", "asparagus"},
+		{"Egg", "Eggs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.singular, func(t *testing.T) {
+			if got := Plural(tt.singular); got != tt.plural {
+				t.Errorf("Plural(%q) = %q, want %q", tt.singular, got, tt.plural)
+			}
+			if got := Singular(tt.plural); got != tt.singular {
+				t.Errorf("Singular(%q) = %q, want %q", tt.plural, got, tt.singular)
+			}
+			if got := Singular(tt.singular); got != tt.singular {
+				t.Errorf("Singular(%q) = %q, want %q", tt.singular, got, tt.singular)
+			}
+		})
+	}
+}
+
+func TestInflectorAddException(t *testing.T) {
+	i := NewInflector()
+	if got := i.Singular("pommes"); got != "pomme" {
+		t.Errorf("Singular() = %q, want %q", got, "pomme")
+	}
+	i.AddException("pommes", "pommes")
+	if got := i.Singular("pommes"); got != "pommes" {
+		t.Errorf("Singular() = %q, want %q", got, "pommes")
+	}
+	i.AddException("cactus", "cacti")
+	if got := i.Plural("cactus"); got != "cacti" {
+		t.Errorf("Plural() = %q, want %q", got, "cacti")
+	}
+	if got := i.Singular("cacti"); got != "cactus" {
+		t.Errorf("Singular() = %q, want %q", got, "cactus")
+	}
+}
+
+func TestSingularPluralCaseLength(t *testing.T) {
+	// The lower case form of these letters has a different byte length
+	tests := []struct {
+		singular string
+		plural   string
+	}{
+		{"İcat", "İcats"},
+		{"ȺȺȺȺȺ", "ȺȺȺȺȺs"},
+		{"ȺȺȺȺȺy", "ȺȺȺȺȺies"},
+		{"İpeach", "İpeaches"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.singular, func(t *testing.T) {
+			if got := Plural(tt.singular); got != tt.plural {
+				t.Errorf("Plural(%q) = %q, want %q", tt.singular, got, tt.plural)
+			}
+			if got := Singular(tt.plural); got != tt.singular {
+				t.Errorf("Singular(%q) = %q, want %q", tt.plural, got, tt.singular)
+			}
+		})
+	}
+}
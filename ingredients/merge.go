@@ -0,0 +1,181 @@
+// Package ingredients provides helpers for aggregating and normalizing
+// recipe ingredients
+package ingredients
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/units"
+)
+
+// DefaultDescriptors lists the preparation descriptors stripped from the
+// ingredient names when merging
+var DefaultDescriptors = []string{
+	"chopped", "diced", "minced", "sliced", "grated", "shredded", "crushed",
+	"peeled", "melted", "softened", "beaten", "sifted", "cubed", "julienned",
+	"finely", "roughly", "thinly", "freshly",
+}
+
+// MergeOptions controls how ingredients are merged
+type MergeOptions struct {
+	KeepCase        bool       // do not case and accent fold the ingredient names
+	KeepDescriptors bool       // do not strip the preparation descriptors from the names
+	Descriptors     []string   // preparation descriptors to strip, nil uses DefaultDescriptors
+	ConvertUnits    bool       // convert compatible units (e.g. g and kg) before summing
+	KeepPlurals     bool       // do not convert the ingredient names to singular
+	Inflector       *Inflector // inflector used for singular names, nil uses DefaultInflector
+	DisplayNames    bool       // name the merged ingredients as first written instead of by their key
+}
+
+func (o MergeOptions) inflector() *Inflector {
+	if o.Inflector == nil {
+		return DefaultInflector
+	}
+	return o.Inflector
+}
+
+func (o MergeOptions) descriptors() []string {
+	if o.Descriptors == nil {
+		return DefaultDescriptors
+	}
+	return o.Descriptors
+}
+
+// key returns the normalized ingredient name the ingredients are grouped by
+func (o MergeOptions) key(name string) string {
+	if !o.KeepDescriptors {
+		name = stripDescriptors(name, o.descriptors())
+	}
+	if o.KeepCase {
+		name = strings.Join(strings.Fields(name), " ")
+	} else {
+		name = cooklang.NormalizeName(name)
+	}
+	if !o.KeepPlurals {
+		name = o.inflector().Singular(name)
+	}
+	return name
+}
+
+// stripDescriptors removes the preparation descriptors and anything after a
+// comma ("onion, diced") from the name
+func stripDescriptors(name string, descriptors []string) string {
+	if before, _, found := strings.Cut(name, ","); found && strings.TrimSpace(before) != "" {
+		name = before
+	}
+	words := strings.Fields(name)
+	result := words[:0:0]
+	for _, word := range words {
+		if !slices.ContainsFunc(descriptors, func(d string) bool { return strings.EqualFold(d, word) }) {
+			result = append(result, word)
+		}
+	}
+	if len(result) == 0 {
+		return name
+	}
+	return strings.Join(result, " ")
+}
+
+// Merge case and accent folds the ingredient names (see
+// cooklang.NormalizeName), strips preparation descriptors, converts them to
+// singular and sums the amounts of the same ingredient with the same (or
+// convertible) unit. Textual amounts are kept as separate entries, the equal
+// ones (case insensitive) only once. A merged entry is Hidden, Fixed or
+// Optional only when all the ingredients merged into it are. The result is
+// sorted by name and unit.
+func Merge(list []cooklang.Ingredient, opts MergeOptions) []cooklang.Ingredient {
+	var keys []string
+	groups := make(map[string][]cooklang.Ingredient)
+	names := make(map[string]string)
+	for _, ingredient := range list {
+		k := opts.key(ingredient.Name)
+		entries, ok := groups[k]
+		if !ok {
+			keys = append(keys, k)
+			names[k] = k
+			if opts.DisplayNames {
+				names[k] = strings.Join(strings.Fields(ingredient.Name), " ")
+			}
+		}
+		groups[k] = addIngredient(entries, ingredient, opts)
+	}
+	result := make([]cooklang.Ingredient, 0, len(keys))
+	for _, k := range keys {
+		for _, entry := range groups[k] {
+			entry.Name = names[k]
+			result = append(result, entry)
+		}
+	}
+	slices.SortStableFunc(result, func(a, b cooklang.Ingredient) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.Amount.Unit, b.Amount.Unit))
+	})
+	return result
+}
+
+// Merge merges the ingredients with the options (see Merge). MergeOptions
+// implements cooklang.IngredientMerger, so the options can be passed to
+// Recipe.AllIngredients.
+func (o MergeOptions) Merge(list []cooklang.Ingredient) []cooklang.Ingredient {
+	return Merge(list, o)
+}
+
+// addIngredient adds the amount of the ingredient to the merged entries of
+// its name
+func addIngredient(entries []cooklang.Ingredient, ingredient cooklang.Ingredient, opts MergeOptions) []cooklang.Ingredient {
+	amount := ingredient.Amount
+	if !amount.IsNumeric {
+		for i, e := range entries {
+			if a := e.Amount; !a.IsNumeric && strings.EqualFold(a.QuantityRaw, amount.QuantityRaw) && strings.EqualFold(a.Unit, amount.Unit) {
+				entries[i] = mergeFlags(e, ingredient)
+				return entries
+			}
+		}
+		return append(entries, newEntry(ingredient))
+	}
+	for i, e := range entries {
+		a := e.Amount
+		if !a.IsNumeric {
+			continue
+		}
+		quantity := amount.Quantity
+		if !strings.EqualFold(a.Unit, amount.Unit) {
+			if !opts.ConvertUnits || !units.Compatible(amount.Unit, a.Unit) {
+				continue
+			}
+			quantity, _ = units.Convert(quantity, amount.Unit, a.Unit)
+		}
+		entries[i] = mergeFlags(e, ingredient)
+		entries[i].Amount = numericAmount(a.Quantity+quantity, a.Unit)
+		return entries
+	}
+	return append(entries, newEntry(ingredient))
+}
+
+// newEntry returns the merged entry of the first ingredient of an amount
+func newEntry(ingredient cooklang.Ingredient) cooklang.Ingredient {
+	return cooklang.Ingredient{Amount: ingredient.Amount, Hidden: ingredient.Hidden, Fixed: ingredient.Fixed, Optional: ingredient.Optional}
+}
+
+// mergeFlags returns the entry with the flags the ingredient merged into it
+// shares with it
+func mergeFlags(entry, ingredient cooklang.Ingredient) cooklang.Ingredient {
+	entry.Hidden = entry.Hidden && ingredient.Hidden
+	entry.Fixed = entry.Fixed && ingredient.Fixed
+	entry.Optional = entry.Optional && ingredient.Optional
+	return entry
+}
+
+func numericAmount(quantity float64, unit string) cooklang.IngredientAmount {
+	quantity = math.Round(quantity*1e6) / 1e6
+	return cooklang.IngredientAmount{
+		IsNumeric:   true,
+		Quantity:    quantity,
+		QuantityRaw: strconv.FormatFloat(quantity, 'f', -1, 64),
+		Unit:        unit,
+	}
+}
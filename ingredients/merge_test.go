@@ -0,0 +1,173 @@
+package ingredients
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+func amount(q float64, raw, unit string) cooklang.IngredientAmount {
+	return cooklang.IngredientAmount{IsNumeric: true, Quantity: q, QuantityRaw: raw, Unit: unit}
+}
+
+func TestMerge(t *testing.T) {
+	list := []cooklang.Ingredient{
+		{Name: "Onion", Amount: amount(1, "1", "")},
+		{Name: "chopped onions", Amount: amount(2, "2", "")},
+		{Name: "onion, diced", Amount: amount(1, "1", "")},
+		{Name: "flour", Amount: amount(200, "200", "g")},
+		{Name: "Flour", Amount: amount(0.5, "0.5", "kg")},
+		{Name: "thyme", Amount: cooklang.IngredientAmount{Quantity: 0, QuantityRaw: "few", Unit: "sprigs"}},
+		{Name: "thyme", Amount: cooklang.IngredientAmount{Quantity: 0, QuantityRaw: "few", Unit: "sprigs"}},
+		{Name: "salt", Amount: cooklang.IngredientAmount{Quantity: 1}},
+		{Name: "salt", Amount: amount(1, "1", "tsp")},
+	}
+	tests := []struct {
+		name string
+		opts MergeOptions
+		want []cooklang.Ingredient
+	}{
+		{
+			"Default options",
+			MergeOptions{},
+			[]cooklang.Ingredient{
+				{Name: "flour", Amount: amount(200, "200", "g")},
+				{Name: "flour", Amount: amount(0.5, "0.5", "kg")},
+				{Name: "onion", Amount: amount(4, "4", "")},
+				{Name: "salt", Amount: cooklang.IngredientAmount{Quantity: 1}},
+				{Name: "salt", Amount: amount(1, "1", "tsp")},
+				{Name: "thyme", Amount: cooklang.IngredientAmount{Quantity: 0, QuantityRaw: "few", Unit: "sprigs"}},
+			},
+		},
+		{
+			"Convert units",
+			MergeOptions{ConvertUnits: true},
+			[]cooklang.Ingredient{
+				{Name: "flour", Amount: amount(700, "700", "g")},
+				{Name: "onion", Amount: amount(4, "4", "")},
+				{Name: "salt", Amount: cooklang.IngredientAmount{Quantity: 1}},
+				{Name: "salt", Amount: amount(1, "1", "tsp")},
+				{Name: "thyme", Amount: cooklang.IngredientAmount{Quantity: 0, QuantityRaw: "few", Unit: "sprigs"}},
+			},
+		},
+		{
+			"Keep case, descriptors and plurals",
+			MergeOptions{KeepCase: true, KeepDescriptors: true, KeepPlurals: true},
+			[]cooklang.Ingredient{
+				{Name: "Flour", Amount: amount(0.5, "0.5", "kg")},
+				{Name: "Onion", Amount: amount(1, "1", "")},
+				{Name: "chopped onions", Amount: amount(2, "2", "")},
+				{Name: "flour", Amount: amount(200, "200", "g")},
+				{Name: "onion, diced", Amount: amount(1, "1", "")},
+				{Name: "salt", Amount: cooklang.IngredientAmount{Quantity: 1}},
+				{Name: "salt", Amount: amount(1, "1", "tsp")},
+				{Name: "thyme", Amount: cooklang.IngredientAmount{Quantity: 0, QuantityRaw: "few", Unit: "sprigs"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Merge(list, tt.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Merge() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergePlurals(t *testing.T) {
+	list := []cooklang.Ingredient{
+		{Name: "egg", Amount: amount(2, "2", "")},
+		{Name: "eggs", Amount: amount(1, "1", "")},
+	}
+	want := []cooklang.Ingredient{{Name: "egg", Amount: amount(3, "3", "")}}
+	if got := Merge(list, MergeOptions{}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Merge() = %v, want %v", got, want)
+	}
+}
+
+func TestMergeFlags(t *testing.T) {
+	some := cooklang.IngredientAmount{QuantityRaw: "some"}
+	list := []cooklang.Ingredient{
+		{Name: "parsley", Amount: amount(1, "1", "tbsp"), Optional: true},
+		{Name: "parsley", Amount: amount(2, "2", "tbsp"), Optional: true, Fixed: true},
+		{Name: "salt", Amount: amount(1, "1", "tsp"), Hidden: true, Fixed: true},
+		{Name: "salt", Amount: amount(1, "1", "tsp"), Fixed: true},
+		{Name: "pepper", Amount: some, Hidden: true},
+		{Name: "pepper", Amount: cooklang.IngredientAmount{QuantityRaw: "Some"}, Hidden: true, Optional: true},
+		{Name: "chili", Amount: some, Optional: true},
+		{Name: "chili", Amount: amount(1, "1", ""), Hidden: true},
+	}
+	want := []cooklang.Ingredient{
+		{Name: "chili", Amount: some, Optional: true},
+		{Name: "chili", Amount: amount(1, "1", ""), Hidden: true},
+		{Name: "parsley", Amount: amount(3, "3", "tbsp"), Optional: true},
+		{Name: "pepper", Amount: some, Hidden: true},
+		{Name: "salt", Amount: amount(2, "2", "tsp"), Fixed: true},
+	}
+	if got := Merge(list, MergeOptions{}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Merge() = %+v, want %+v", got, want)
+	}
+}
+
+func TestMergeKeepCaseNonASCII(t *testing.T) {
+	list := []cooklang.Ingredient{
+		{Name: "ȺȺȺȺȺs", Amount: amount(2, "2", "")},
+		{Name: "ȺȺȺȺȺ", Amount: amount(1, "1", "")},
+	}
+	want := []cooklang.Ingredient{{Name: "ȺȺȺȺȺ", Amount: amount(3, "3", "")}}
+	if got := Merge(list, MergeOptions{KeepCase: true}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Merge() = %v, want %v", got, want)
+	}
+}
+
+func TestMergeAccents(t *testing.T) {
+	list := []cooklang.Ingredient{
+		{Name: "Crème  Fraîche", Amount: amount(100, "100", "ml")},
+		{Name: "creme fraiche", Amount: amount(50, "50", "ml")},
+		{Name: "jalapeño", Amount: amount(1, "1", "")},
+	}
+	tests := []struct {
+		name string
+		opts MergeOptions
+		want []cooklang.Ingredient
+	}{
+		{
+			"Normalized names",
+			MergeOptions{},
+			[]cooklang.Ingredient{
+				{Name: "creme fraiche", Amount: amount(150, "150", "ml")},
+				{Name: "jalapeno", Amount: amount(1, "1", "")},
+			},
+		},
+		{
+			"Display names",
+			MergeOptions{DisplayNames: true},
+			[]cooklang.Ingredient{
+				{Name: "Crème Fraîche", Amount: amount(150, "150", "ml")},
+				{Name: "jalapeño", Amount: amount(1, "1", "")},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Merge(list, tt.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Merge() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecipeAllIngredients(t *testing.T) {
+	r, err := cooklang.ParseString("Mix @flour{200%g} and @eggs{2}.\nAdd @Flour{0.5%kg} and @egg{1}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []cooklang.Ingredient{
+		{Name: "egg", Amount: amount(3, "3", "")},
+		{Name: "flour", Amount: amount(700, "700", "g")},
+	}
+	if got := r.AllIngredients(MergeOptions{ConvertUnits: true}); !reflect.DeepEqual(got, want) {
+		t.Errorf("AllIngredients() = %#v, want %#v", got, want)
+	}
+}
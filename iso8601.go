@@ -0,0 +1,73 @@
+package cooklang
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidDuration is returned for durations which are not ISO 8601
+// durations of weeks, days, hours, minutes and seconds
+var ErrInvalidDuration = errors.New("invalid ISO 8601 duration")
+
+var iso8601Duration = regexp.MustCompile(`^P(?:(\d+(?:[.,]\d+)?)W)?(?:(\d+(?:[.,]\d+)?)D)?(?:T(?:(\d+(?:[.,]\d+)?)H)?(?:(\d+(?:[.,]\d+)?)M)?(?:(\d+(?:[.,]\d+)?)S)?)?$`)
+
+// iso8601Units are the units of the iso8601Duration groups
+var iso8601Units = []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+
+// FormatISO8601Duration formats the duration as ISO 8601 duration ("PT1H30M",
+// "P1DT2H"). Durations are written in days, hours, minutes and seconds;
+// zero and negative durations are written as "PT0S".
+func FormatISO8601Duration(d time.Duration) string {
+	if d <= 0 {
+		return "PT0S"
+	}
+	var b strings.Builder
+	b.WriteString("P")
+	if days := d / (24 * time.Hour); days > 0 {
+		fmt.Fprintf(&b, "%dD", days)
+		d -= days * 24 * time.Hour
+	}
+	if d == 0 {
+		return b.String()
+	}
+	b.WriteString("T")
+	if hours := d / time.Hour; hours > 0 {
+		fmt.Fprintf(&b, "%dH", hours)
+		d -= hours * time.Hour
+	}
+	if minutes := d / time.Minute; minutes > 0 {
+		fmt.Fprintf(&b, "%dM", minutes)
+		d -= minutes * time.Minute
+	}
+	if d > 0 {
+		b.WriteString(strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "S")
+	}
+	return b.String()
+}
+
+// ParseISO8601Duration parses an ISO 8601 duration ("PT20M", "P1DT2H30M").
+// Years and months have no fixed length and are not supported.
+func ParseISO8601Duration(s string) (time.Duration, error) {
+	value := strings.ToUpper(strings.TrimSpace(s))
+	m := iso8601Duration.FindStringSubmatch(value)
+	if m == nil || value == "P" || strings.HasSuffix(value, "T") {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidDuration, s)
+	}
+	var total float64
+	for i, unit := range iso8601Units {
+		if m[i+1] == "" {
+			continue
+		}
+		n, err := strconv.ParseFloat(strings.ReplaceAll(m[i+1], ",", "."), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidDuration, s)
+		}
+		total += n * float64(unit)
+	}
+	return time.Duration(math.Round(total)), nil
+}
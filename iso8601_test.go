@@ -0,0 +1,61 @@
+package cooklang
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFormatISO8601Duration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "PT0S"},
+		{20 * time.Minute, "PT20M"},
+		{90 * time.Minute, "PT1H30M"},
+		{26 * time.Hour, "P1DT2H"},
+		{48 * time.Hour, "P2D"},
+		{90 * time.Second, "PT1M30S"},
+		{1500 * time.Millisecond, "PT1.5S"},
+	}
+	for _, tt := range tests {
+		if got := FormatISO8601Duration(tt.d); got != tt.want {
+			t.Errorf("FormatISO8601Duration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestParseISO8601Duration(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"PT20M", 20 * time.Minute, false},
+		{"PT1H30M", 90 * time.Minute, false},
+		{" pt1h ", time.Hour, false},
+		{"P1DT2H", 26 * time.Hour, false},
+		{"P1W", 7 * 24 * time.Hour, false},
+		{"PT0.5H", 30 * time.Minute, false},
+		{"PT1,5M", 90 * time.Second, false},
+		{"P", 0, true},
+		{"PT", 0, true},
+		{"P1DT", 0, true},
+		{"P1M", 0, true},
+		{"20 minutes", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseISO8601Duration(tt.s)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseISO8601Duration(%q) error = %v, wantErr %v", tt.s, err, tt.wantErr)
+			continue
+		}
+		if err != nil && !errors.Is(err, ErrInvalidDuration) {
+			t.Errorf("ParseISO8601Duration(%q) error = %v, want ErrInvalidDuration", tt.s, err)
+		}
+		if got != tt.want {
+			t.Errorf("ParseISO8601Duration(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package cooklang
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// schemaRecipe contains the schema.org Recipe properties read by
+// ConvertJSONLD. The properties which have several forms are decoded later.
+type schemaRecipe struct {
+	Name               string          `json:"name"`
+	Description        string          `json:"description"`
+	RecipeYield        json.RawMessage `json:"recipeYield"`
+	PrepTime           string          `json:"prepTime"`
+	CookTime           string          `json:"cookTime"`
+	TotalTime          string          `json:"totalTime"`
+	URL                string          `json:"url"`
+	Keywords           json.RawMessage `json:"keywords"`
+	RecipeIngredient   []string        `json:"recipeIngredient"`
+	RecipeInstructions json.RawMessage `json:"recipeInstructions"`
+}
+
+// findSchemaRecipe returns the first object of the Recipe type in the
+// decoded JSON-LD document, looking into arrays and @graph
+func findSchemaRecipe(v any) map[string]any {
+	switch v := v.(type) {
+	case []any:
+		for _, item := range v {
+			if found := findSchemaRecipe(item); found != nil {
+				return found
+			}
+		}
+	case map[string]any:
+		switch t := v["@type"].(type) {
+		case string:
+			if t == "Recipe" {
+				return v
+			}
+		case []any:
+			if slices.Contains(t, any("Recipe")) {
+				return v
+			}
+		}
+		return findSchemaRecipe(v["@graph"])
+	}
+	return nil
+}
+
+// schemaStrings decodes a property which is a string, a number or a list of
+// them
+func schemaStrings(raw json.RawMessage) []string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return []string{s}
+	}
+	var n json.Number
+	if json.Unmarshal(raw, &n) == nil {
+		return []string{n.String()}
+	}
+	var list []json.RawMessage
+	if json.Unmarshal(raw, &list) != nil {
+		return nil
+	}
+	var result []string
+	for _, item := range list {
+		result = append(result, schemaStrings(item)...)
+	}
+	return result
+}
+
+// schemaInstructions decodes the recipeInstructions property: a text, a list
+// of texts, HowToStep objects or HowToSection objects with the steps in
+// itemListElement
+func schemaInstructions(raw json.RawMessage) []string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		var result []string
+		for _, line := range strings.Split(s, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				result = append(result, line)
+			}
+		}
+		return result
+	}
+	var list []json.RawMessage
+	if json.Unmarshal(raw, &list) == nil {
+		var result []string
+		for _, item := range list {
+			result = append(result, schemaInstructions(item)...)
+		}
+		return result
+	}
+	var step struct {
+		Text            string          `json:"text"`
+		ItemListElement json.RawMessage `json:"itemListElement"`
+	}
+	if json.Unmarshal(raw, &step) != nil {
+		return nil
+	}
+	if step.ItemListElement != nil {
+		return schemaInstructions(step.ItemListElement)
+	}
+	return schemaInstructions(json.RawMessage(strconv.Quote(step.Text)))
+}
+
+// schemaDuration converts the ISO 8601 duration to the metadata notation
+// ("PT1H30M" becomes "1h30m"). Other values are kept as they are.
+func schemaDuration(s string) string {
+	if d, err := ParseISO8601Duration(s); err == nil {
+		return formatDuration(d)
+	}
+	return s
+}
+
+// ConvertJSONLD converts a schema.org Recipe in JSON-LD (as embedded in the
+// recipe web pages) to cooklang markup on a best effort basis. The name,
+// description, yield, url, keywords and the times become metadata, ISO 8601
+// durations are converted to the metadata notation ("1h30m"). The
+// ingredients are matched to the instructions like in ConvertMarkdown.
+func ConvertJSONLD(r io.Reader) (string, error) {
+	var document any
+	if err := json.NewDecoder(r).Decode(&document); err != nil {
+		return "", err
+	}
+	object := findSchemaRecipe(document)
+	if object == nil {
+		return "", fmt.Errorf("no recipe found")
+	}
+	data, err := json.Marshal(object)
+	if err != nil {
+		return "", err
+	}
+	var recipe schemaRecipe
+	if err := json.Unmarshal(data, &recipe); err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	addMetadata := func(key, value string) {
+		if value = strings.Join(strings.Fields(value), " "); value != "" {
+			fmt.Fprintf(&b, "%s %s: %s\n", metadataLinePrefix, key, value)
+		}
+	}
+	addMetadata("title", recipe.Name)
+	addMetadata("description", recipe.Description)
+	if yield := schemaStrings(recipe.RecipeYield); len(yield) > 0 {
+		addMetadata(MetadataServings, yield[0])
+	}
+	addMetadata("source", recipe.URL)
+	var tags []string
+	for _, keywords := range schemaStrings(recipe.Keywords) {
+		tags = append(tags, SplitMetadataList(keywords)...)
+	}
+	addMetadata("tags", strings.Join(tags, ", "))
+	addMetadata("prep time", schemaDuration(recipe.PrepTime))
+	addMetadata("cook time", schemaDuration(recipe.CookTime))
+	addMetadata(MetadataTime, schemaDuration(recipe.TotalTime))
+
+	var ingredients []markdownIngredient
+	for _, line := range recipe.RecipeIngredient {
+		if i := parseMarkdownIngredient(strings.TrimSpace(line)); i.name != "" {
+			ingredients = append(ingredients, i)
+		}
+	}
+	steps := markupSteps(schemaInstructions(recipe.RecipeInstructions), ingredients)
+	if b.Len() > 0 && len(steps) > 0 {
+		b.WriteString("\n")
+	}
+	b.WriteString(strings.Join(steps, "\n\n"))
+	return b.String(), nil
+}
+
+// ImportJSONLD parses a schema.org Recipe in JSON-LD on a best effort basis.
+// See ConvertJSONLD for the recognized properties.
+func ImportJSONLD(r io.Reader) (*Recipe, error) {
+	source, err := ConvertJSONLD(r)
+	if err != nil {
+		return nil, err
+	}
+	return ParseString(source)
+}
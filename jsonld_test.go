@@ -0,0 +1,72 @@
+package cooklang
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConvertJSONLD(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			"HowToStep",
+			`{"@context": "https://schema.org", "@type": "Recipe", "name": "Pancakes",
+			"recipeYield": ["4", "4 pancakes"], "prepTime": "PT10M", "totalTime": "PT1H30M",
+			"keywords": "breakfast, sweet", "url": "https://example.com/pancakes",
+			"recipeIngredient": ["200 g flour", "300 ml milk", "1 pinch salt"],
+			"recipeInstructions": [
+				{"@type": "HowToStep", "text": "Mix the flour and the milk."},
+				{"@type": "HowToStep", "text": "Fry in a pan."}
+			]}`,
+			">> title: Pancakes\n>> servings: 4\n>> source: https://example.com/pancakes\n>> tags: breakfast, sweet\n" +
+				">> prep time: 10m\n>> time: 1h30m\n\nPrepare @salt{1%pinch}.\n\nMix the @flour{200%g} and the @milk{300%ml}.\n\nFry in a pan.",
+			false,
+		},
+		{
+			"Graph with sections",
+			`{"@graph": [{"@type": "WebPage"}, {"@type": ["Recipe"], "name": "Soup", "recipeYield": 2,
+			"cookTime": "not a duration", "keywords": ["quick", "vegan"],
+			"recipeInstructions": [{"@type": "HowToSection", "name": "Soup", "itemListElement": [
+				{"@type": "HowToStep", "text": "Boil the water."}, "Serve."]}]}]}`,
+			">> title: Soup\n>> servings: 2\n>> tags: quick, vegan\n>> cook time: not a duration\n\nBoil the water.\n\nServe.",
+			false,
+		},
+		{
+			"Text instructions",
+			`[{"@type": "Recipe", "name": "Tea", "recipeInstructions": "Boil water.\n\nSteep the tea."}]`,
+			">> title: Tea\n\nBoil water.\n\nSteep the tea.",
+			false,
+		},
+		{"No recipe", `{"@type": "WebPage"}`, "", true},
+		{"Invalid JSON", `{`, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConvertJSONLD(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ConvertJSONLD() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ConvertJSONLD() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImportJSONLD(t *testing.T) {
+	r, err := ImportJSONLD(strings.NewReader(`{"@type": "Recipe", "name": "Rice", "totalTime": "PT20M",
+		"recipeIngredient": ["1 cup rice"], "recipeInstructions": ["Cook the rice."]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Metadata[MetadataTime] != "20m" {
+		t.Errorf("time = %q, want %q", r.Metadata[MetadataTime], "20m")
+	}
+	if len(r.Steps) != 1 || len(r.Steps[0].Ingredients) != 1 || r.Steps[0].Ingredients[0].Amount.Unit != "cup" {
+		t.Errorf("Steps = %+v, want the rice ingredient", r.Steps)
+	}
+}
@@ -0,0 +1,87 @@
+package cooklang
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultMaxLineLength is the maximum line length in bytes used when
+// Limits.MaxLineLength is not set
+const DefaultMaxLineLength = 1024 * 1024
+
+// ErrLimitExceeded is returned when the parsed input exceeds one of the
+// configured limits
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// Limits defines upper bounds for the parsed input. Zero values mean no limit
+// except for MaxLineLength which defaults to DefaultMaxLineLength.
+type Limits struct {
+	MaxLineLength   int // maximum length of a single line in bytes
+	MaxSteps        int // maximum number of steps in a recipe
+	MaxItemsPerStep int // maximum number of items (text, ingredients, cookware, timers, comments) in a step
+	MaxMetadataSize int // maximum combined size of the metadata keys and values in bytes
+}
+
+func (l Limits) maxLineLength() int {
+	if l.MaxLineLength > 0 {
+		return l.MaxLineLength
+	}
+	return DefaultMaxLineLength
+}
+
+// newScanner returns a line scanner of r. The initial line buffer is buf
+// when it is not nil, its capacity is capped to the maximum line length.
+func (l Limits) newScanner(r io.Reader, buf []byte) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	// the buffer must fit the line terminator too
+	maxLength := l.maxLineLength() + 2
+	if buf == nil {
+		buf = make([]byte, 0, min(4096, maxLength))
+	}
+	scanner.Buffer(buf[:0:min(cap(buf), maxLength)], maxLength)
+	return scanner
+}
+
+func (l Limits) scannerError(err error) error {
+	if errors.Is(err, bufio.ErrTooLong) {
+		return fmt.Errorf("%w: line longer than %d bytes", ErrLimitExceeded, l.maxLineLength())
+	}
+	return err
+}
+
+func (l Limits) checkLine(line string) error {
+	if len(line) > l.maxLineLength() {
+		return fmt.Errorf("%w: line longer than %d bytes", ErrLimitExceeded, l.maxLineLength())
+	}
+	return nil
+}
+
+func (l Limits) checkSteps(steps int) error {
+	if l.MaxSteps > 0 && steps > l.MaxSteps {
+		return fmt.Errorf("%w: more than %d steps", ErrLimitExceeded, l.MaxSteps)
+	}
+	return nil
+}
+
+func (l Limits) checkItems(items int) error {
+	if l.MaxItemsPerStep > 0 && items > l.MaxItemsPerStep {
+		return fmt.Errorf("%w: more than %d items in step", ErrLimitExceeded, l.MaxItemsPerStep)
+	}
+	return nil
+}
+
+func (l Limits) checkMetadata(metadata Metadata) error {
+	if l.MaxMetadataSize <= 0 {
+		return nil
+	}
+	size := 0
+	for k, v := range metadata {
+		size += len(k) + len(v)
+	}
+	if size > l.MaxMetadataSize {
+		return fmt.Errorf("%w: metadata larger than %d bytes", ErrLimitExceeded, l.MaxMetadataSize)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package cooklang
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParserLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		limits  Limits
+		recipe  string
+		wantErr bool
+	}{
+		{
+			"No limits",
+			Limits{},
+			">> key: value\nMix @flour{200%g} with @water{100%ml} in a #bowl.\nWait ~{10%minutes}.",
+			false,
+		},
+		{
+			"Line too long",
+			Limits{MaxLineLength: 10},
+			"Mix @flour{200%g} with @water{100%ml} in a #bowl.",
+			true,
+		},
+		{
+			"Line too long for the scanner buffer",
+			Limits{MaxLineLength: 10},
+			"Mix @flour{200%g} with @water{100%ml} in a #bowl.\nshort",
+			true,
+		},
+		{
+			"Too many steps",
+			Limits{MaxSteps: 1},
+			"Step one\n\nStep two",
+			true,
+		},
+		{
+			"Too many items per step",
+			Limits{MaxItemsPerStep: 2},
+			"Mix @flour{200%g} with @water{100%ml} in a #bowl.",
+			true,
+		},
+		{
+			"Metadata too large",
+			Limits{MaxMetadataSize: 10},
+			">> title: very long title",
+			true,
+		},
+		{
+			"Within limits",
+			Limits{MaxLineLength: 100, MaxSteps: 1, MaxItemsPerStep: 7, MaxMetadataSize: 10},
+			">> key: value\nMix @flour{200%g} with @water{100%ml} in a #bowl.",
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewParser(&ParseConfig{Limits: tt.limits}).ParseString(tt.recipe)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parser.ParseString() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && !errors.Is(err, ErrLimitExceeded) {
+				t.Errorf("Parser.ParseString() error = %v, want ErrLimitExceeded", err)
+			}
+			_, err = NewParserV2(&ParseV2Config{Limits: tt.limits}).ParseString(tt.recipe)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParserV2.ParseString() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseStringLongLine(t *testing.T) {
+	line := strings.Repeat("a", 100*1024)
+	r, err := ParseString(line)
+	if err != nil {
+		t.Fatalf("ParseString() error = %v", err)
+	}
+	if r.Steps[0].Directions != line {
+		t.Errorf("ParseString() directions length = %d, want %d", len(r.Steps[0].Directions), len(line))
+	}
+}
@@ -0,0 +1,59 @@
+package cooklang
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ItemTypeLink is the type of the link items
+const ItemTypeLink ItemType = "link"
+
+// linkPattern matches the http and https URLs, the trailing punctuation is
+// removed by findLinks
+var linkPattern = regexp.MustCompile(`\bhttps?://[^\s<>"]+`)
+
+// Link is a URL found in the step text (see ParseConfig.DetectLinks)
+type Link struct {
+	URL string // URL as written in the text
+}
+
+// LinkV2 represents a link item
+type LinkV2 struct {
+	Type ItemType `json:"type"`
+	URL  string   `json:"url"`
+}
+
+func (l Link) asLinkV2() LinkV2 {
+	return LinkV2{ItemTypeLink, l.URL}
+}
+
+// findLinks returns the byte ranges of the URLs in the text. The punctuation
+// ending a sentence and the closing parentheses without an opening one in
+// the URL are not part of it: "(see https://example.com/a_(b))." ends with
+// "b)".
+func findLinks(text string) [][]int {
+	if !strings.Contains(text, "://") {
+		return nil
+	}
+	matches := linkPattern.FindAllStringIndex(text, -1)
+	for _, m := range matches {
+		for m[1] > m[0] {
+			url := text[m[0]:m[1]]
+			last := url[len(url)-1]
+			if strings.IndexByte(".,;:!?'", last) == -1 && (last != ')' || strings.Count(url, "(") >= strings.Count(url, ")")) {
+				break
+			}
+			m[1]--
+		}
+	}
+	return matches
+}
+
+// FindLinks returns the URLs in the text
+func FindLinks(text string) []string {
+	var result []string
+	for _, m := range findLinks(text) {
+		result = append(result, text[m[0]:m[1]])
+	}
+	return result
+}
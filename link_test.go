@@ -0,0 +1,76 @@
+package cooklang
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindLinks(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"no links here", nil},
+		{"See https://example.com/bread#shaping.", []string{"https://example.com/bread#shaping"}},
+		{"(from http://example.com/a_(b)), or https://example.org?", []string{"http://example.com/a_(b)", "https://example.org"}},
+		{"ftp://example.com and example.com", nil},
+	}
+	for _, tt := range tests {
+		if got := FindLinks(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("FindLinks(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestParseLinks(t *testing.T) {
+	src := "Shape the @dough{} (see https://example.com/bread#shaping) in a #banneton{}."
+	r, err := NewParser(&ParseConfig{DetectLinks: true}).ParseString(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	step := r.Steps[0]
+	if want := "Shape the dough (see https://example.com/bread#shaping) in a banneton."; step.Directions != want {
+		t.Errorf("directions = %q, want %q", step.Directions, want)
+	}
+	if want := []Link{{"https://example.com/bread#shaping"}}; !reflect.DeepEqual(step.Links, want) {
+		t.Errorf("links = %v, want %v", step.Links, want)
+	}
+	if len(step.Cookware) != 1 || step.Cookware[0].Name != "banneton" {
+		t.Errorf("cookware = %v, want only the banneton", step.Cookware)
+	}
+
+	r2, err := NewParserV2(&ParseV2Config{DetectLinks: true}).ParseString(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := StepV2{
+		TextV2{"text", "Shape the "},
+		IngredientV2{Type: "ingredient", Name: "dough"},
+		TextV2{"text", " (see "},
+		LinkV2{"link", "https://example.com/bread#shaping"},
+		TextV2{"text", ") in a "},
+		CookwareV2{Type: "cookware", Name: "banneton", Quantity: 1},
+		TextV2{"text", "."},
+	}
+	if !reflect.DeepEqual(r2.Steps[0], want) {
+		t.Errorf("step = %#v, want %#v", r2.Steps[0], want)
+	}
+	if got := r.ToV2().Steps[0]; !reflect.DeepEqual(got, want) {
+		t.Errorf("ToV2() step = %#v, want %#v", got, want)
+	}
+	v1, err := r2.ToV1()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(v1.Steps[0].Links, step.Links) || v1.Steps[0].Directions != step.Directions {
+		t.Errorf("ToV1() step = %+v, want %+v", v1.Steps[0], step)
+	}
+
+	r, err = ParseString(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Steps[0].Links) != 0 || len(r.Steps[0].Cookware) != 2 {
+		t.Errorf("step = %+v, want the URL tokenized by default", r.Steps[0])
+	}
+}
@@ -0,0 +1,265 @@
+package cooklang
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/aquilax/cooklang-go/units"
+)
+
+// MarkdownUnits lists the units recognized in Markdown ingredient lists in
+// addition to the ones known to the units package
+var MarkdownUnits = []string{"pinch", "pinches", "dash", "dashes", "clove", "cloves", "slice", "slices", "can", "cans", "bunch", "bunches", "handful", "handfuls", "sprig", "sprigs", "stick", "sticks", "piece", "pieces"}
+
+var (
+	markdownHeading  = regexp.MustCompile(`^#{1,6}\s+(.*?)\s*#*$`)
+	markdownBullet   = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	markdownNumbered = regexp.MustCompile(`^\s*\d+[.)]\s+(.*)$`)
+	markdownMetadata = regexp.MustCompile(`^\**([\p{L}][\p{L} ]{0,30}?)\**\s*:\s*\**\s*(.+)$`)
+	// quantity: mixed number (1 1/2), fraction (1/2), decimal (1.5 or 1,5)
+	// or an Unicode vulgar fraction optionally following a whole number
+	markdownQuantity = regexp.MustCompile(`^(\d+\s+\d+/\d+|\d+/\d+|\d+(?:[.,]\d+)?\s*[½⅓⅔¼¾⅛]?|[½⅓⅔¼¾⅛])\s*`)
+)
+
+var vulgarFractions = map[rune]float64{'½': 0.5, '⅓': 1.0 / 3, '⅔': 2.0 / 3, '¼': 0.25, '¾': 0.75, '⅛': 0.125}
+
+type markdownIngredient struct {
+	name     string
+	quantity string
+	unit     string
+	used     bool
+}
+
+func (i markdownIngredient) markup() string {
+	if i.quantity == "" {
+		return string(prefixIngredient) + i.name + "{}"
+	}
+	if i.unit == "" {
+		return fmt.Sprintf("%c%s{%s}", prefixIngredient, i.name, i.quantity)
+	}
+	return fmt.Sprintf("%c%s{%s%%%s}", prefixIngredient, i.name, i.quantity, i.unit)
+}
+
+// parseMarkdownQuantity converts the quantity to the cooklang notation.
+// Simple fractions are kept as written, mixed numbers and Unicode fractions
+// are converted to decimals.
+func parseMarkdownQuantity(s string) string {
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", ".")
+	if !strings.ContainsAny(s, " ½⅓⅔¼¾⅛") {
+		return s
+	}
+	var total float64
+	for _, field := range strings.Fields(s) {
+		for r, f := range vulgarFractions {
+			if before, found := strings.CutSuffix(field, string(r)); found {
+				total += f
+				field = before
+			}
+		}
+		if _, f, err := getFloat(field); err == nil {
+			total += f
+		}
+	}
+	return strconv.FormatFloat(math.Round(total*1000)/1000, 'f', -1, 64)
+}
+
+func isMarkdownUnit(s string) bool {
+	if _, ok := units.Lookup(s); ok {
+		return true
+	}
+	return slices.Contains(MarkdownUnits, strings.ToLower(s))
+}
+
+// parseMarkdownIngredient parses an ingredient list item ("200 g flour",
+// "1 1/2 cups milk, warm", "salt")
+func parseMarkdownIngredient(s string) markdownIngredient {
+	var i markdownIngredient
+	if m := markdownQuantity.FindStringSubmatch(s); m != nil {
+		i.quantity = parseMarkdownQuantity(m[1])
+		s = s[len(m[0]):]
+		if unit, rest, found := strings.Cut(s, " "); found && isMarkdownUnit(strings.TrimSuffix(unit, ".")) {
+			i.unit = strings.TrimSuffix(unit, ".")
+			s = rest
+		}
+	}
+	// notes after a comma or in parentheses are dropped
+	if index := strings.IndexAny(s, ",("); index != -1 {
+		s = s[:index]
+	}
+	i.name = strings.TrimSpace(strings.Trim(s, "*_"))
+	return i
+}
+
+// markupStep replaces the first mention of every unused ingredient with its
+// markup. The full name is tried first, then its last word.
+func markupStep(step string, ingredients []markdownIngredient) string {
+	for n := range ingredients {
+		i := &ingredients[n]
+		if i.used {
+			continue
+		}
+		candidates := []string{i.name}
+		if words := strings.Fields(i.name); len(words) > 1 && len(words[len(words)-1]) > 2 {
+			candidates = append(candidates, words[len(words)-1])
+		}
+		for _, candidate := range candidates {
+			lower, word := strings.ToLower(step), strings.ToLower(candidate)
+			if len(lower) != len(step) || len(word) != len(candidate) {
+				// the case mapping changed the byte offsets
+				lower, word = step, candidate
+			}
+			index := indexWord(lower, word)
+			if index == -1 {
+				continue
+			}
+			ingredient := *i
+			ingredient.name = step[index : index+len(candidate)]
+			step = step[:index] + ingredient.markup() + step[index+len(candidate):]
+			i.used = true
+			break
+		}
+	}
+	return step
+}
+
+// markupSteps marks up the ingredients in the steps. The ingredients which
+// are never mentioned are listed in an extra first step.
+func markupSteps(steps []string, ingredients []markdownIngredient) []string {
+	for i := range steps {
+		steps[i] = markupStep(steps[i], ingredients)
+	}
+	var unused []string
+	for _, i := range ingredients {
+		if !i.used {
+			unused = append(unused, i.markup())
+		}
+	}
+	if len(unused) > 0 {
+		steps = append([]string{"Prepare " + strings.Join(unused, ", ") + "."}, steps...)
+	}
+	return steps
+}
+
+// indexWord returns the index of the first occurrence of word in s which is
+// not part of a longer word or of an already inserted item
+func indexWord(s, word string) int {
+	if word == "" {
+		return -1
+	}
+	offset := 0
+	for {
+		index := strings.Index(s[offset:], word)
+		if index == -1 {
+			return -1
+		}
+		index += offset
+		end := index + len(word)
+		before := index == 0 || !isWordByte(s[index-1])
+		after := end == len(s) || !isWordByte(s[end])
+		if before && after {
+			return index
+		}
+		offset = end
+	}
+}
+
+func isWordByte(b byte) bool {
+	return b == prefixIngredient || b == '_' || b >= 0x80 || ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z') || ('0' <= b && b <= '9')
+}
+
+const (
+	markdownIngredients = "ingredients"
+	markdownSteps       = "steps"
+)
+
+// markdownSection returns the type of the section with the heading
+func markdownSection(heading string) string {
+	heading = strings.ToLower(heading)
+	switch {
+	case strings.Contains(heading, "ingredient"):
+		return markdownIngredients
+	case strings.Contains(heading, "direction"), strings.Contains(heading, "step"), strings.Contains(heading, "method"),
+		strings.Contains(heading, "instruction"), strings.Contains(heading, "preparation"):
+		return markdownSteps
+	}
+	return ""
+}
+
+// ConvertMarkdown converts a Markdown recipe to cooklang markup on a best
+// effort basis. The first level one heading becomes the title, "Key: value"
+// lines before the first list become metadata, bullet lists become the
+// ingredients and numbered lists or paragraphs after the ingredients become
+// the steps. The first mention of every ingredient in the steps is replaced
+// by its markup; the ingredients which are never mentioned are listed in an
+// extra first step.
+func ConvertMarkdown(r io.Reader) (string, error) {
+	var metadata []string
+	var ingredients []markdownIngredient
+	var steps []string
+	section := ""
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if m := markdownHeading.FindStringSubmatch(line); m != nil {
+			section = markdownSection(m[1])
+			if strings.HasPrefix(line, "# ") && len(metadata) == 0 {
+				metadata = append(metadata, fmt.Sprintf("%s title: %s", metadataLinePrefix, m[1]))
+			}
+			continue
+		}
+		if m := markdownNumbered.FindStringSubmatch(line); m != nil {
+			steps = append(steps, m[1])
+			continue
+		}
+		if m := markdownBullet.FindStringSubmatch(line); m != nil {
+			if section == markdownIngredients || (section == "" && len(steps) == 0) {
+				ingredients = append(ingredients, parseMarkdownIngredient(m[1]))
+			} else {
+				steps = append(steps, m[1])
+			}
+			continue
+		}
+		if m := markdownMetadata.FindStringSubmatch(line); m != nil && len(ingredients) == 0 && len(steps) == 0 {
+			metadata = append(metadata, fmt.Sprintf("%s %s: %s", metadataLinePrefix, strings.ToLower(strings.TrimSpace(m[1])), strings.TrimSpace(strings.Trim(m[2], "*"))))
+			continue
+		}
+		if section == markdownSteps || (section == "" && len(ingredients) > 0) {
+			steps = append(steps, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	steps = markupSteps(steps, ingredients)
+	var b strings.Builder
+	for _, m := range metadata {
+		b.WriteString(m + "\n")
+	}
+	if len(metadata) > 0 && len(steps) > 0 {
+		b.WriteString("\n")
+	}
+	b.WriteString(strings.Join(steps, "\n\n"))
+	return b.String(), nil
+}
+
+// ImportMarkdown parses a Markdown recipe on a best effort basis. See
+// ConvertMarkdown for the recognized structure.
+func ImportMarkdown(r io.Reader) (*Recipe, error) {
+	source, err := ConvertMarkdown(r)
+	if err != nil {
+		return nil, err
+	}
+	if source == "" {
+		return nil, fmt.Errorf("no recipe found")
+	}
+	return ParseString(source)
+}
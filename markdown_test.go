@@ -0,0 +1,69 @@
+package cooklang
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testMarkdownRecipe = `# Pancakes
+
+**Servings:** 4
+Source: https://example.com/pancakes
+
+## Ingredients
+
+- 1 1/2 cups milk, warm
+- 200 g flour
+- 2 large eggs
+- ½ tsp salt
+- butter (for frying)
+- vanilla
+
+## Directions
+
+1. Whisk the Eggs with the milk.
+2. Add the flour and salt and mix until smooth.
+3. Fry in butter. Flour the surface.
+`
+
+func TestConvertMarkdown(t *testing.T) {
+	got, err := ConvertMarkdown(strings.NewReader(testMarkdownRecipe))
+	if err != nil {
+		t.Fatalf("ConvertMarkdown() error = %v", err)
+	}
+	want := `>> title: Pancakes
+>> servings: 4
+>> source: https://example.com/pancakes
+
+Prepare @vanilla{}.
+
+Whisk the @Eggs{2} with the @milk{1.5%cups}.
+
+Add the @flour{200%g} and @salt{0.5%tsp} and mix until smooth.
+
+Fry in @butter{}. Flour the surface.`
+	if got != want {
+		t.Errorf("ConvertMarkdown() = %s, want %s", got, want)
+	}
+}
+
+func TestImportMarkdown(t *testing.T) {
+	got, err := ImportMarkdown(strings.NewReader(testMarkdownRecipe))
+	if err != nil {
+		t.Fatalf("ImportMarkdown() error = %v", err)
+	}
+	wantIngredients := []Ingredient{
+		{"Eggs", IngredientAmount{true, 2, "2", ""}, false, false, false, false},
+		{"milk", IngredientAmount{true, 1.5, "1.5", "cups"}, false, false, false, false},
+	}
+	if !reflect.DeepEqual(got.Steps[1].Ingredients, wantIngredients) {
+		t.Errorf("ImportMarkdown() ingredients = %#v, want %#v", got.Steps[1].Ingredients, wantIngredients)
+	}
+	if got.Metadata["servings"] != "4" {
+		t.Errorf("ImportMarkdown() servings = %q, want %q", got.Metadata["servings"], "4")
+	}
+	if _, err := ImportMarkdown(strings.NewReader("just some text")); err == nil {
+		t.Errorf("ImportMarkdown() expected error for text without recipe")
+	}
+}
@@ -0,0 +1,76 @@
+package cooklang
+
+import (
+	"slices"
+	"strings"
+)
+
+// SplitMetadataList splits a list metadata value: comma separated items
+// optionally enclosed in brackets ("vegan, quick" or "[vegan, quick]"). The
+// items are trimmed and the empty ones are dropped.
+func SplitMetadataList(value string) []string {
+	value = strings.TrimSpace(value)
+	if isMetadataList(value) {
+		value = value[1 : len(value)-1]
+	}
+	result := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// isMetadataList returns true for the values written with the list syntax
+// ([a, b])
+func isMetadataList(value string) bool {
+	value = strings.TrimSpace(value)
+	return len(value) >= 2 && value[0] == '[' && value[len(value)-1] == ']'
+}
+
+// MetadataKeys returns the keys of the metadata in the given order followed
+// by the keys missing from order, sorted. Keys of order which are not in the
+// metadata are skipped.
+func MetadataKeys(metadata Metadata, order []string) []string {
+	keys := make([]string, 0, len(metadata))
+	seen := make(map[string]bool, len(metadata))
+	for _, k := range order {
+		if _, ok := metadata[k]; ok && !seen[k] {
+			keys = append(keys, k)
+			seen[k] = true
+		}
+	}
+	extra := len(keys)
+	for k := range metadata {
+		if !seen[k] {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys[extra:])
+	return keys
+}
+
+// MetadataKeys returns the metadata keys in source order. Keys added after
+// parsing follow in sorted order.
+func (r *Recipe) MetadataKeys() []string {
+	return MetadataKeys(r.Metadata, r.MetadataOrder)
+}
+
+// MetadataKeys returns the metadata keys in source order. Keys added after
+// parsing follow in sorted order.
+func (r *RecipeV2) MetadataKeys() []string {
+	return MetadataKeys(r.Metadata, r.MetadataOrder)
+}
+
+// MetadataList returns the list value of the metadata key or nil when the
+// key has no list value (see ParseConfig.ListMetadataKeys)
+func (r *Recipe) MetadataList(key string) []string {
+	return r.MetadataLists[key]
+}
+
+// MetadataList returns the list value of the metadata key or nil when the
+// key has no list value (see ParseV2Config.ListMetadataKeys)
+func (r *RecipeV2) MetadataList(key string) []string {
+	return r.MetadataLists[key]
+}
@@ -0,0 +1,99 @@
+package cooklang
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMetadataKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata Metadata
+		order    []string
+		want     []string
+	}{
+		{"empty", Metadata{}, nil, []string{}},
+		{"source order", Metadata{"title": "Soup", "servings": "2", "author": "me"}, []string{"title", "servings", "author"}, []string{"title", "servings", "author"}},
+		{"added keys sorted", Metadata{"title": "Soup", "b": "2", "a": "1"}, []string{"title"}, []string{"title", "a", "b"}},
+		{"removed and repeated keys", Metadata{"a": "1", "b": "2"}, []string{"c", "b", "b"}, []string{"b", "a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MetadataKeys(tt.metadata, tt.order); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MetadataKeys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecipeStringMetadataOrder(t *testing.T) {
+	r, err := ParseString(">> title: Soup\n>> servings: 2\n>> author: me\n>> servings: 4\nBoil @water.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ">> title: Soup\n>> servings: 4\n>> author: me\n\nBoil water.\n"
+	for range 10 {
+		if got := r.String(); got != want {
+			t.Fatalf("String() = %q, want %q", got, want)
+		}
+	}
+	v2, err := NewParserV2(nil).ParseString(">> b: 1\n>> a: 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := v2.MetadataKeys(); !reflect.DeepEqual(got, []string{"b", "a"}) {
+		t.Errorf("MetadataKeys() = %v", got)
+	}
+}
+
+func TestSplitMetadataList(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{"vegan, quick", []string{"vegan", "quick"}},
+		{"[vegan, quick]", []string{"vegan", "quick"}},
+		{" [ vegan ,, quick , ] ", []string{"vegan", "quick"}},
+		{"vegan", []string{"vegan"}},
+		{"[]", []string{}},
+		{"", []string{}},
+	}
+	for _, tt := range tests {
+		if got := SplitMetadataList(tt.value); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitMetadataList(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseListMetadata(t *testing.T) {
+	recipe := `>> tags: vegan, quick
+>> authors: [Jane, John]
+>> title: Salt, pepper and soup
+>> course: [starter]
+>> course: main
+Boil @water.`
+	want := map[string][]string{
+		"tags":    {"vegan", "quick"},
+		"authors": {"Jane", "John"},
+	}
+	r, err := NewParser(&ParseConfig{ListMetadataKeys: []string{"tags"}}).ParseString(recipe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r.MetadataLists, want) {
+		t.Errorf("MetadataLists = %v, want %v", r.MetadataLists, want)
+	}
+	if got := r.Metadata["tags"]; got != "vegan, quick" {
+		t.Errorf("Metadata[tags] = %q, want the value as written", got)
+	}
+	if got := r.MetadataList("title"); got != nil {
+		t.Errorf("MetadataList(title) = %v, want nil", got)
+	}
+	v2, err := NewParserV2(&ParseV2Config{ListMetadataKeys: []string{"tags"}}).ParseString(recipe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(v2.MetadataLists, want) {
+		t.Errorf("v2 MetadataLists = %v, want %v", v2.MetadataLists, want)
+	}
+}
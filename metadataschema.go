@@ -0,0 +1,340 @@
+package cooklang
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrMetadataType is returned when a metadata value can not be
+	// converted to the type of its field
+	ErrMetadataType = errors.New("invalid metadata value")
+	// ErrMissingMetadata is returned when a required metadata key is
+	// missing
+	ErrMissingMetadata = errors.New("required metadata is missing")
+)
+
+// MetadataError describes a metadata value which could not be decoded
+type MetadataError struct {
+	Key   string
+	Value string
+	Err   error
+}
+
+func (e *MetadataError) Error() string {
+	return fmt.Sprintf("metadata %q: %v", e.Key, e.Err)
+}
+
+func (e *MetadataError) Unwrap() error {
+	return e.Err
+}
+
+// metadataDurationPart matches a number and its time unit ("1 hour", "30m")
+var metadataDurationPart = regexp.MustCompile(`(?i)(\d+(?:\.\d+)?)\s*([a-z]+)`)
+
+// ParseMetadataDuration parses the durations written in the metadata: Go
+// durations ("1h30m"), ISO 8601 durations ("PT1H30M") and numbers with time
+// units ("1 hour 30 minutes", "45 min")
+func ParseMetadataDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+	if d, err := ParseISO8601Duration(s); err == nil {
+		return d, nil
+	}
+	parts := metadataDurationPart.FindAllStringSubmatchIndex(s, -1)
+	if len(parts) == 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	var d time.Duration
+	end := 0
+	for _, m := range parts {
+		if strings.TrimSpace(s[end:m[0]]) != "" {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		unit, ok := timerUnits[strings.ToLower(s[m[4]:m[5]])]
+		if !ok {
+			return 0, fmt.Errorf("invalid duration %q: unknown unit %q", s, s[m[4]:m[5]])
+		}
+		f, _ := strconv.ParseFloat(s[m[2]:m[3]], 64)
+		d += time.Duration(f * float64(unit))
+		end = m[1]
+	}
+	if strings.TrimSpace(s[end:]) != "" {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
+
+var (
+	durationType        = reflect.TypeFor[time.Duration]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// metadataField is a struct field decoded from the metadata
+type metadataField struct {
+	index    []int
+	key      string
+	required bool
+}
+
+// metadataFields returns the decoded fields of the struct type. The key of a
+// field is set with the metadata tag (`metadata:"prep time"`), the lower case
+// field name by default. The required option (`metadata:"title,required"`)
+// marks the required keys and "-" skips the field.
+func metadataFields(t reflect.Type) []metadataField {
+	var fields []metadataField
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		tag, _ := f.Tag.Lookup("metadata")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields = append(fields, metadataField{f.Index, name, options == "required"})
+	}
+	return fields
+}
+
+// DecodeMetadata stores the metadata values in the fields of the struct v
+// points to. The fields are matched by their metadata tag (see
+// MetadataSchemaOf) and can be strings, numbers, booleans, durations (see
+// ParseMetadataDuration), string slices (see SplitMetadataList),
+// encoding.TextUnmarshaler or pointers to them, which are left nil for
+// missing keys. The keys without field are ignored. The returned error joins
+// a *MetadataError for every missing required key and value of the wrong
+// type.
+func DecodeMetadata(metadata Metadata, v any) error {
+	return decodeMetadata(metadata, nil, v)
+}
+
+// Decode stores the metadata in the struct v points to (see DecodeMetadata)
+func (m Metadata) Decode(v any) error {
+	return decodeMetadata(m, nil, v)
+}
+
+// DecodeMetadata stores the metadata in the struct v points to (see
+// DecodeMetadata). The list values of the metadata are used for the slice
+// fields.
+func (r *Recipe) DecodeMetadata(v any) error {
+	return decodeMetadata(r.Metadata, r.MetadataLists, v)
+}
+
+// DecodeMetadata stores the metadata in the struct v points to (see
+// DecodeMetadata)
+func (r *RecipeV2) DecodeMetadata(v any) error {
+	return decodeMetadata(r.Metadata, r.MetadataLists, v)
+}
+
+func decodeMetadata(metadata Metadata, lists map[string][]string, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("DecodeMetadata: expected a pointer to a struct, got %T", v)
+	}
+	rv = rv.Elem()
+	var errs []error
+	for _, f := range metadataFields(rv.Type()) {
+		value, ok := metadata[f.key]
+		if !ok {
+			if f.required {
+				errs = append(errs, &MetadataError{f.key, "", ErrMissingMetadata})
+			}
+			continue
+		}
+		field := rv.FieldByIndex(f.index)
+		if field.Kind() == reflect.Pointer && !field.Type().Implements(textUnmarshalerType) {
+			field.Set(reflect.New(field.Type().Elem()))
+			field = field.Elem()
+		}
+		if err := setMetadataValue(field, value, lists[f.key]); err != nil {
+			errs = append(errs, &MetadataError{f.key, value, err})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// setMetadataValue converts the value to the type of the field. list is the
+// list value of the key or nil.
+func setMetadataValue(field reflect.Value, value string, list []string) error {
+	if field.CanAddr() && field.Addr().Type().Implements(textUnmarshalerType) {
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+	if field.Kind() == reflect.Pointer && field.Type().Implements(textUnmarshalerType) {
+		field.Set(reflect.New(field.Type().Elem()))
+		return field.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+	typeError := func() error {
+		return fmt.Errorf("%w: %q is not a %s", ErrMetadataType, value, field.Type())
+	}
+	trimmed := strings.TrimSpace(value)
+	if field.Type() == durationType {
+		d, err := ParseMetadataDuration(trimmed)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrMetadataType, err)
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(trimmed)
+		if err != nil {
+			return typeError()
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(trimmed, 10, field.Type().Bits())
+		if err != nil {
+			return typeError()
+		}
+		field.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(trimmed, 10, field.Type().Bits())
+		if err != nil {
+			return typeError()
+		}
+		field.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(trimmed, field.Type().Bits())
+		if err != nil {
+			return typeError()
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("%w: unsupported field type %s", ErrMetadataType, field.Type())
+		}
+		if list == nil {
+			list = SplitMetadataList(value)
+		}
+		s := reflect.MakeSlice(field.Type(), len(list), len(list))
+		for i, item := range list {
+			s.Index(i).SetString(item)
+		}
+		field.Set(s)
+	default:
+		return fmt.Errorf("%w: unsupported field type %s", ErrMetadataType, field.Type())
+	}
+	return nil
+}
+
+// MetadataKind is the type of a metadata value
+type MetadataKind int
+
+// Metadata value types
+const (
+	MetadataKindText     MetadataKind = iota // any text
+	MetadataKindNumber                       // decimal number ("2.5")
+	MetadataKindInteger                      // whole number ("4")
+	MetadataKindBool                         // true or false
+	MetadataKindDuration                     // duration (see ParseMetadataDuration)
+	MetadataKindList                         // list (see SplitMetadataList)
+)
+
+func (k MetadataKind) String() string {
+	switch k {
+	case MetadataKindNumber:
+		return "number"
+	case MetadataKindInteger:
+		return "integer"
+	case MetadataKindBool:
+		return "boolean"
+	case MetadataKindDuration:
+		return "duration"
+	case MetadataKindList:
+		return "list"
+	}
+	return "text"
+}
+
+// MetadataKey declares a metadata key of a MetadataSchema
+type MetadataKey struct {
+	Key      string
+	Kind     MetadataKind
+	Required bool
+}
+
+// MetadataSchema declares the metadata keys of the recipes of a collection
+// and the types of their values
+type MetadataSchema []MetadataKey
+
+// MetadataSchemaOf returns the schema of the metadata decoded into the struct
+// v (see DecodeMetadata)
+func MetadataSchemaOf(v any) MetadataSchema {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	var schema MetadataSchema
+	for _, f := range metadataFields(t) {
+		ft := t.FieldByIndex(f.index).Type
+		if ft.Kind() == reflect.Pointer && !ft.Implements(textUnmarshalerType) {
+			ft = ft.Elem()
+		}
+		kind := MetadataKindText
+		switch {
+		case ft == durationType:
+			kind = MetadataKindDuration
+		case ft.Implements(textUnmarshalerType) || reflect.PointerTo(ft).Implements(textUnmarshalerType):
+		case ft.Kind() == reflect.Bool:
+			kind = MetadataKindBool
+		case ft.Kind() >= reflect.Int && ft.Kind() <= reflect.Uint64:
+			kind = MetadataKindInteger
+		case ft.Kind() == reflect.Float32 || ft.Kind() == reflect.Float64:
+			kind = MetadataKindNumber
+		case ft.Kind() == reflect.Slice:
+			kind = MetadataKindList
+		}
+		schema = append(schema, MetadataKey{f.key, kind, f.required})
+	}
+	return schema
+}
+
+// Validate checks the metadata against the schema: the required keys must be
+// present and not empty and the values must have the type of their key. The
+// keys which are not in the schema are not checked.
+func (s MetadataSchema) Validate(metadata Metadata) []ValidationError {
+	var result []ValidationError
+	for _, key := range s {
+		value, ok := metadata[key.Key]
+		if !ok || strings.TrimSpace(value) == "" {
+			if key.Required {
+				result = append(result, ValidationError{-1, key.Key, "required metadata is missing"})
+			}
+			continue
+		}
+		var err error
+		value = strings.TrimSpace(value)
+		switch key.Kind {
+		case MetadataKindNumber:
+			_, err = strconv.ParseFloat(value, 64)
+		case MetadataKindInteger:
+			_, err = strconv.ParseInt(value, 10, 64)
+		case MetadataKindBool:
+			_, err = strconv.ParseBool(value)
+		case MetadataKindDuration:
+			_, err = ParseMetadataDuration(value)
+		}
+		if err != nil {
+			result = append(result, ValidationError{-1, key.Key, fmt.Sprintf("%q is not a valid %s", value, key.Kind)})
+		}
+	}
+	return result
+}
@@ -0,0 +1,129 @@
+package cooklang
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// course is a TextUnmarshaler metadata value
+type course string
+
+func (c *course) UnmarshalText(b []byte) error {
+	switch string(b) {
+	case "starter", "main", "dessert":
+		*c = course(b)
+		return nil
+	}
+	return errors.New("unknown course")
+}
+
+type recipeInfo struct {
+	Title    string        `metadata:"title,required"`
+	Servings int           `metadata:"servings"`
+	PrepTime time.Duration `metadata:"prep time"`
+	Tags     []string
+	Vegan    *bool
+	Rating   *float64
+	Course   course
+	Ignored  string `metadata:"-"`
+}
+
+func TestRecipeDecodeMetadata(t *testing.T) {
+	r, err := NewParser(&ParseConfig{ListMetadataKeys: []string{"tags"}}).ParseString(">> title: Pancakes\n>> servings: 4\n>> prep time: 1 hour 30 minutes\n>> tags: breakfast, sweet\n>> vegan: false\n>> course: dessert\n>> ignored: x\nMix.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got recipeInfo
+	if err := r.DecodeMetadata(&got); err != nil {
+		t.Fatal(err)
+	}
+	vegan := false
+	want := recipeInfo{"Pancakes", 4, 90 * time.Minute, []string{"breakfast", "sweet"}, &vegan, nil, "dessert", ""}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeMetadata() = %+v, want %+v", got, want)
+	}
+}
+
+func TestMetadataDecode(t *testing.T) {
+	var got recipeInfo
+	m := Metadata{"title": "Pancakes", "servings": "4", "tags": "breakfast, sweet"}
+	if err := m.Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	want := recipeInfo{Title: "Pancakes", Servings: 4, Tags: []string{"breakfast", "sweet"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Metadata.Decode() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeMetadataErrors(t *testing.T) {
+	var info recipeInfo
+	err := DecodeMetadata(Metadata{"servings": "four", "course": "snack", "prep time": "PT10M"}, &info)
+	var me *MetadataError
+	if !errors.As(err, &me) {
+		t.Fatalf("DecodeMetadata() error = %v, want a MetadataError", err)
+	}
+	for _, target := range []error{ErrMissingMetadata, ErrMetadataType} {
+		if !errors.Is(err, target) {
+			t.Errorf("DecodeMetadata() error = %v, want %v", err, target)
+		}
+	}
+	if info.PrepTime != 10*time.Minute {
+		t.Errorf("PrepTime = %v, want 10m", info.PrepTime)
+	}
+	if err := DecodeMetadata(Metadata{}, info); err == nil {
+		t.Errorf("DecodeMetadata(struct) error = nil, want an error")
+	}
+}
+
+func TestParseMetadataDuration(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"1h30m", 90 * time.Minute, false},
+		{"PT1H30M", 90 * time.Minute, false},
+		{"1 hour 30 minutes", 90 * time.Minute, false},
+		{"45 min", 45 * time.Minute, false},
+		{"2 days", 48 * time.Hour, false},
+		{"a while", 0, true},
+		{"10 parsecs", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMetadataDuration(tt.s)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseMetadataDuration(%q) = %v, %v, want %v", tt.s, got, err, tt.want)
+		}
+	}
+}
+
+func TestMetadataSchema(t *testing.T) {
+	schema := MetadataSchemaOf(&recipeInfo{})
+	want := MetadataSchema{
+		{"title", MetadataKindText, true},
+		{"servings", MetadataKindInteger, false},
+		{"prep time", MetadataKindDuration, false},
+		{"tags", MetadataKindList, false},
+		{"vegan", MetadataKindBool, false},
+		{"rating", MetadataKindNumber, false},
+		{"course", MetadataKindText, false},
+	}
+	if !reflect.DeepEqual(schema, want) {
+		t.Fatalf("MetadataSchemaOf() = %v, want %v", schema, want)
+	}
+	r, err := ParseString(">> servings: 4 people\n>> rating: 4.5\nMix.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := Validate(r, ValidationProfile{MetadataSchema: schema})
+	wantErrors := []ValidationError{
+		{-1, "title", "required metadata is missing"},
+		{-1, "servings", `"4 people" is not a valid integer`},
+	}
+	if !reflect.DeepEqual(got, wantErrors) {
+		t.Errorf("Validate() = %v, want %v", got, wantErrors)
+	}
+}
@@ -0,0 +1,38 @@
+package cooklang
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// foldLetters are the letters without decomposition folded by NormalizeName
+var foldLetters = strings.NewReplacer("ß", "ss", "æ", "ae", "œ", "oe", "ø", "o", "ł", "l", "đ", "d", "ð", "d", "þ", "th", "ı", "i")
+
+// NormalizeName returns the form in which ingredient names are compared: lower
+// case, without accents and with the white space collapsed ("Crème  Fraîche"
+// becomes "creme fraiche"). The ingredient lookups of the library (merging,
+// substitutions, classification, aisles) use it, so the names match however
+// they are written. It is a key rather than a display name.
+func NormalizeName(name string) string {
+	name = strings.ToLower(name)
+	var b strings.Builder
+	b.Grow(len(name))
+	space := false
+	for _, r := range norm.NFD.String(name) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case unicode.IsSpace(r):
+			space = b.Len() > 0
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	return norm.NFC.String(foldLetters.Replace(b.String()))
+}
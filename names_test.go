@@ -0,0 +1,23 @@
+package cooklang
+
+import "testing"
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Flour", "flour"},
+		{"  Crème \t Fraîche ", "creme fraiche"},
+		{"JALAPEÑO", "jalapeno"},
+		{"Weißwurst", "weisswurst"},
+		{"Smørrebrød", "smorrebrod"},
+		{"paprika (smoked)", "paprika (smoked)"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeName(tt.name); got != tt.want {
+			t.Errorf("NormalizeName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
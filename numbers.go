@@ -0,0 +1,192 @@
+package cooklang
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// NumberParser converts the textual quantities of a language ("three", "a
+// dozen") to numbers
+type NumberParser interface {
+	ParseNumber(s string) (float64, bool)
+}
+
+// NumberWordKind is the role of a word in a textual number
+type NumberWordKind int
+
+// Kinds of number words
+const (
+	NumberWordValue      NumberWordKind = iota // adds its value ("three", "twenty")
+	NumberWordMultiplier                       // multiplies the number before it ("dozen", "hundred")
+	NumberWordScale                            // multiplies and closes the group before it ("thousand")
+	NumberWordFraction                         // multiplies the number before it or adds its value ("two thirds", "one and a half")
+	NumberWordArticle                          // one, unless a number precedes ("a", "an")
+	NumberWordFiller                           // ignored ("and")
+)
+
+// NumberWord is a word of a textual number
+type NumberWord struct {
+	Kind  NumberWordKind
+	Value float64
+}
+
+// NumberWords is a NumberParser of a language defined by its words. Numbers
+// written with digits can be mixed with the words ("2 dozen"). The words are
+// separated by white space or hyphens ("twenty-one").
+type NumberWords map[string]NumberWord
+
+// ParseNumber converts the text to a number. It returns false when a word is
+// not known.
+func (w NumberWords) ParseNumber(s string) (float64, bool) {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return unicode.IsSpace(r) || r == '-'
+	})
+	var total, current float64
+	article := false  // an article without number after it
+	previous := false // the previous word is a value
+	found := false
+	for _, token := range words {
+		word, ok := w[token]
+		if !ok {
+			f, err := strconv.ParseFloat(token, 64)
+			if err != nil {
+				return 0, false
+			}
+			word = NumberWord{NumberWordValue, f}
+		}
+		isValue := false
+		switch word.Kind {
+		case NumberWordValue:
+			current += word.Value
+			article = false
+			isValue = true
+		case NumberWordArticle:
+			article = current == 0
+		case NumberWordMultiplier:
+			if current == 0 || article {
+				current = 1
+			}
+			current *= word.Value
+			article = false
+			isValue = true
+		case NumberWordScale:
+			if current == 0 || article {
+				current = 1
+			}
+			total += current * word.Value
+			current = 0
+			article = false
+		case NumberWordFraction:
+			if previous {
+				current *= word.Value
+			} else {
+				current += word.Value
+			}
+			article = false
+			isValue = true
+		}
+		found = found || (word.Kind != NumberWordArticle && word.Kind != NumberWordFiller)
+		previous = isValue
+	}
+	if !found {
+		return 0, false
+	}
+	return total + current, true
+}
+
+var (
+	numberLanguagesMu sync.RWMutex
+	numberLanguages   = map[string]NumberParser{
+		"en": englishNumbers,
+		"de": germanNumbers,
+	}
+)
+
+// RegisterNumberLanguage registers the number parser of the language,
+// replacing the existing one
+func RegisterNumberLanguage(language string, p NumberParser) {
+	numberLanguagesMu.Lock()
+	defer numberLanguagesMu.Unlock()
+	numberLanguages[strings.ToLower(language)] = p
+}
+
+// NumberLanguage returns the number parser of the language ("en"). English
+// and German are built in, other languages can be registered with
+// RegisterNumberLanguage. Regional languages ("en-GB") fall back to the
+// language.
+func NumberLanguage(language string) (NumberParser, bool) {
+	numberLanguagesMu.RLock()
+	defer numberLanguagesMu.RUnlock()
+	language = strings.ToLower(strings.ReplaceAll(language, "_", "-"))
+	if p, ok := numberLanguages[language]; ok {
+		return p, true
+	}
+	base, _, _ := strings.Cut(language, "-")
+	p, ok := numberLanguages[base]
+	return p, ok
+}
+
+// numberValues adds the value words of the list with the values 0..n, empty
+// words are skipped
+func numberValues(words NumberWords, values ...string) {
+	for i, word := range values {
+		if word != "" {
+			words[word] = NumberWord{NumberWordValue, float64(i)}
+		}
+	}
+}
+
+var englishNumbers = func() NumberWords {
+	w := NumberWords{
+		"twenty": {NumberWordValue, 20}, "thirty": {NumberWordValue, 30}, "forty": {NumberWordValue, 40},
+		"fifty": {NumberWordValue, 50}, "sixty": {NumberWordValue, 60}, "seventy": {NumberWordValue, 70},
+		"eighty": {NumberWordValue, 80}, "ninety": {NumberWordValue, 90},
+		"dozen": {NumberWordMultiplier, 12}, "dozens": {NumberWordMultiplier, 12},
+		"hundred": {NumberWordMultiplier, 100}, "thousand": {NumberWordScale, 1000},
+		"half": {NumberWordFraction, 0.5}, "halves": {NumberWordFraction, 0.5},
+		"third": {NumberWordFraction, 1.0 / 3}, "thirds": {NumberWordFraction, 1.0 / 3},
+		"quarter": {NumberWordFraction, 0.25}, "quarters": {NumberWordFraction, 0.25},
+		"a": {NumberWordArticle, 1}, "an": {NumberWordArticle, 1},
+		"and": {NumberWordFiller, 0}, "couple": {NumberWordValue, 2},
+	}
+	numberValues(w, "zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten",
+		"eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen")
+	return w
+}()
+
+var germanNumbers = func() NumberWords {
+	w := NumberWords{
+		"eins": {NumberWordValue, 1}, "zwanzig": {NumberWordValue, 20}, "dreißig": {NumberWordValue, 30},
+		"vierzig": {NumberWordValue, 40}, "fünfzig": {NumberWordValue, 50},
+		"dutzend": {NumberWordMultiplier, 12}, "hundert": {NumberWordMultiplier, 100}, "tausend": {NumberWordScale, 1000},
+		"halb": {NumberWordFraction, 0.5}, "halbe": {NumberWordFraction, 0.5}, "halben": {NumberWordFraction, 0.5},
+		"drittel": {NumberWordFraction, 1.0 / 3}, "viertel": {NumberWordFraction, 0.25},
+		"ein": {NumberWordArticle, 1}, "eine": {NumberWordArticle, 1}, "einen": {NumberWordArticle, 1},
+		"und": {NumberWordFiller, 0},
+	}
+	numberValues(w, "null", "", "zwei", "drei", "vier", "fünf", "sechs", "sieben", "acht", "neun", "zehn",
+		"elf", "zwölf")
+	return w
+}()
+
+// parseTextualAmount converts the textual quantity of the amount using p,
+// keeping the quantity as written in QuantityRaw
+func parseTextualAmount(p NumberParser, amount IngredientAmount) IngredientAmount {
+	if p == nil || amount.IsNumeric || amount.QuantityRaw == "" {
+		return amount
+	}
+	if f, ok := p.ParseNumber(amount.QuantityRaw); ok {
+		amount.Quantity = f
+		amount.IsNumeric = true
+	}
+	return amount
+}
+
+// parseTextualCookware converts the textual quantity of the cookware
+func parseTextualCookware(p NumberParser, c Cookware) Cookware {
+	amount := parseTextualAmount(p, IngredientAmount{IsNumeric: c.IsNumeric, Quantity: c.Quantity, QuantityRaw: c.QuantityRaw})
+	c.Quantity, c.IsNumeric = amount.Quantity, amount.IsNumeric
+	return c
+}
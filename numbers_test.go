@@ -0,0 +1,97 @@
+package cooklang
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestNumberWordsParseNumber(t *testing.T) {
+	en, _ := NumberLanguage("en")
+	de, _ := NumberLanguage("de-AT")
+	tests := []struct {
+		parser NumberParser
+		s      string
+		want   float64
+		wantOk bool
+	}{
+		{en, "three", 3, true},
+		{en, "Twenty-one", 21, true},
+		{en, "a dozen", 12, true},
+		{en, "two dozen", 24, true},
+		{en, "2 dozen", 24, true},
+		{en, "half", 0.5, true},
+		{en, "a half", 0.5, true},
+		{en, "one and a half", 1.5, true},
+		{en, "two and half", 2.5, true},
+		{en, "two thirds", 2.0 / 3, true},
+		{en, "a couple", 2, true},
+		{en, "three hundred", 300, true},
+		{en, "two thousand five hundred", 2500, true},
+		{en, "a", 0, false},
+		{en, "a pinch", 0, false},
+		{en, "some", 0, false},
+		{de, "drei", 3, true},
+		{de, "ein Dutzend", 12, true},
+		{de, "zwei und ein halb", 2.5, true},
+		{de, "three", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, ok := tt.parser.ParseNumber(tt.s)
+			if ok != tt.wantOk || math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("ParseNumber(%q) = %v, %v, want %v, %v", tt.s, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestNumberLanguage(t *testing.T) {
+	if _, ok := NumberLanguage("xx"); ok {
+		t.Errorf("NumberLanguage(xx) found")
+	}
+	RegisterNumberLanguage("XX", NumberWords{"uno": {NumberWordValue, 1}})
+	p, ok := NumberLanguage("xx_YY")
+	if !ok {
+		t.Fatalf("NumberLanguage(xx_YY) not found")
+	}
+	if got, _ := p.ParseNumber("uno"); got != 1 {
+		t.Errorf("ParseNumber(uno) = %v, want 1", got)
+	}
+}
+
+func TestParseTextualNumbers(t *testing.T) {
+	en, _ := NumberLanguage("en")
+	src := "Beat @eggs{a dozen} with @salt{a pinch} in #bowls{two}."
+	r, err := NewParser(&ParseConfig{Numbers: en}).ParseString(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantIngredients := []Ingredient{
+		{Name: "eggs", Amount: IngredientAmount{IsNumeric: true, Quantity: 12, QuantityRaw: "a dozen"}},
+		{Name: "salt", Amount: IngredientAmount{QuantityRaw: "a pinch"}},
+	}
+	if !reflect.DeepEqual(r.Steps[0].Ingredients, wantIngredients) {
+		t.Errorf("Ingredients = %+v, want %+v", r.Steps[0].Ingredients, wantIngredients)
+	}
+	wantCookware := []Cookware{{IsNumeric: true, Name: "bowls", Quantity: 2, QuantityRaw: "two"}}
+	if !reflect.DeepEqual(r.Steps[0].Cookware, wantCookware) {
+		t.Errorf("Cookware = %+v, want %+v", r.Steps[0].Cookware, wantCookware)
+	}
+
+	plain, err := ParseString(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain.Steps[0].Ingredients[0].Amount.IsNumeric {
+		t.Errorf("textual quantity converted without Numbers")
+	}
+
+	v2, err := NewParserV2(&ParseV2Config{Numbers: en}).ParseString(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := v2.Steps[0][1].(IngredientV2).Quantity; got != 12 {
+		t.Errorf("IngredientV2.Quantity = %v, want 12", got)
+	}
+}
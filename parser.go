@@ -7,11 +7,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
-	"slices"
+	"regexp"
 	"strconv"
 	"strings"
-	"unicode/utf8"
+	"unicode"
 )
 
 const (
@@ -23,6 +24,10 @@ const (
 	prefixTimer            = '~'
 	prefixBlockComment     = '['
 	prefixInlineComment    = '-'
+	prefixHidden           = '-' // follows the ingredient prefix of the hidden ingredients (@-salt)
+	prefixReference        = '&' // follows the ingredient prefix of the references (@&flour)
+	prefixFixed            = '=' // starts the fixed quantities, which are not scaled (@salt{=1%tsp})
+	prefixOptional         = '?' // follows the item prefix of the optional ingredients and cookware (@?parsley, #?thermometer)
 
 	ItemTypeText       ItemType = "text"
 	ItemTypeComment    ItemType = "comment"
@@ -40,18 +45,41 @@ type ItemType string
 // CommentType defines what type is the comment
 type CommentType int
 
+// CommentMode defines what is left in the step text of the block and end of
+// line comments. The comments are always reported as comments (Step.Comments
+// and the Comment items of StepV2).
+type CommentMode int
+
+const (
+	// CommentsRemove removes the comments from the text (default)
+	CommentsRemove CommentMode = iota
+	// CommentsPlaceholder replaces every comment with the placeholder
+	CommentsPlaceholder
+	// CommentsPreserve keeps the comments in the text as written
+	// ("[- note -]", "-- note")
+	CommentsPreserve
+)
+
+// DefaultCommentPlaceholder replaces the comments in CommentsPlaceholder mode
+// when no placeholder is configured
+const DefaultCommentPlaceholder = "…"
+
 // Cookware represents a cookware item
 type Cookware struct {
 	IsNumeric   bool    // true if the amount is numeric
 	Name        string  // cookware name
 	Quantity    float64 // quantity of the cookware
 	QuantityRaw string  // quantity of the cookware as raw text
+	Note        string  `json:",omitempty" yaml:",omitempty"` // note written after the cookware: #pan{}(cast iron preferred)
+	Optional    bool    `json:",omitempty" yaml:",omitempty"` // the cookware is not required (#?thermometer)
 }
 
 type CookwareV2 struct {
 	Type     ItemType `json:"type"`
 	Name     string   `json:"name"`
 	Quantity float64  `json:"quantity"`
+	Note     string   `json:"note,omitempty"`
+	Optional bool     `json:"optional,omitempty"`
 }
 
 func (c Cookware) asCookwareV2() CookwareV2 {
@@ -59,9 +87,17 @@ func (c Cookware) asCookwareV2() CookwareV2 {
 		Type:     ItemTypeCookware,
 		Name:     c.Name,
 		Quantity: c.Quantity,
+		Note:     c.Note,
+		Optional: c.Optional,
 	}
 }
 
+// IsPresent reports whether the quantity of the cookware is written in the
+// recipe, as #pot and #pot{} have the default quantity 1
+func (c Cookware) IsPresent() bool {
+	return c.IsNumeric || c.QuantityRaw != ""
+}
+
 // IngredientAmount represents the amount required of an ingredient
 type IngredientAmount struct {
 	IsNumeric   bool    // true if the amount is numeric
@@ -70,40 +106,106 @@ type IngredientAmount struct {
 	Unit        string  // optional ingredient unit
 }
 
+// IsPresent reports whether the quantity is written in the recipe. The
+// ingredients without quantity (@salt, @salt{}) get a default quantity (see
+// EmptyAmountMode), which is not distinguishable from @salt{1} otherwise.
+func (a IngredientAmount) IsPresent() bool {
+	return a.IsNumeric || a.QuantityRaw != ""
+}
+
+// EmptyAmountMode defines the quantity of the ingredients without quantity.
+// The specification reads them as "some" of the ingredient.
+type EmptyAmountMode int
+
+const (
+	// EmptyAmountLegacy is the quantity 1 for @salt and 0 for @salt{} or
+	// @salt{%g}
+	EmptyAmountLegacy EmptyAmountMode = iota
+	// EmptyAmountDefault is the DefaultQuantity of the parser configuration
+	// for all ingredients without quantity. Textual quantities ("a pinch")
+	// are not changed.
+	EmptyAmountDefault
+)
+
+// emptyAmount returns the amount with the default quantity of the mode when
+// the quantity is missing
+func emptyAmount(mode EmptyAmountMode, defaultQuantity float64, amount IngredientAmount) IngredientAmount {
+	if mode == EmptyAmountDefault && !amount.IsPresent() {
+		amount.Quantity = defaultQuantity
+	}
+	return amount
+}
+
 // Ingredient represents a recipe ingredient
 type Ingredient struct {
-	Name   string           // name of the ingredient
-	Amount IngredientAmount // optional ingredient amount (default: 1)
+	Name      string           // name of the ingredient
+	Amount    IngredientAmount // optional ingredient amount (default: 1)
+	Reference bool             `json:",omitempty" yaml:",omitempty"` // refers to an earlier mention, not added to the totals (see ReferenceMode)
+	Hidden    bool             `json:",omitempty" yaml:",omitempty"` // listed with the ingredients but left out of the directions (@-salt)
+	Fixed     bool             `json:",omitempty" yaml:",omitempty"` // the quantity is not scaled (@salt{=1%tsp})
+	Optional  bool             `json:",omitempty" yaml:",omitempty"` // the ingredient can be left out (@?parsley)
 }
 
 type IngredientV2 struct {
-	Type     ItemType `json:"type"`
-	Name     string   `json:"name"`
-	Quantity float64  `json:"quantity"`
-	Units    string   `json:"units,omitempty"`
+	Type      ItemType `json:"type"`
+	Name      string   `json:"name"`
+	Quantity  float64  `json:"quantity"`
+	Units     string   `json:"units,omitempty"`
+	Reference bool     `json:"reference,omitempty"`
+	Hidden    bool     `json:"hidden,omitempty"`
+	Fixed     bool     `json:"fixed,omitempty"`
+	Optional  bool     `json:"optional,omitempty"`
 }
 
 func (i Ingredient) asIngredientV2() IngredientV2 {
 	return IngredientV2{
-		Type:     ItemTypeIngredient,
-		Name:     i.Name,
-		Quantity: i.Amount.Quantity,
-		Units:    i.Amount.Unit,
+		Type:      ItemTypeIngredient,
+		Name:      i.Name,
+		Quantity:  i.Amount.Quantity,
+		Units:     i.Amount.Unit,
+		Reference: i.Reference,
+		Hidden:    i.Hidden,
+		Fixed:     i.Fixed,
+		Optional:  i.Optional,
 	}
 }
 
-// Timer represents a time duration
+// Timer represents a time duration. Single word timers (~rest) have a name
+// but no duration: zero Duration and empty Unit.
 type Timer struct {
 	Name     string  // name of the timer
 	Duration float64 // duration of the timer
 	Unit     string  // time unit of the duration
 }
 
+// HasDuration returns false for the timers without duration (~rest)
+func (t Timer) HasDuration() bool {
+	return t.Duration != 0 || t.Unit != ""
+}
+
+// TimerV2 represents a timer item. Timers without duration are encoded with
+// empty string quantity.
 type TimerV2 struct {
 	Type     ItemType `json:"type"`
 	Name     string   `json:"name,omitempty"`
 	Quantity float64  `json:"quantity"`
-	Unit     string   `json:"units"`
+	Unit     string   `json:"units,omitempty"`
+}
+
+// HasDuration returns false for the timers without duration (~rest)
+func (t TimerV2) HasDuration() bool {
+	return t.Quantity != 0 || t.Unit != ""
+}
+
+func (t TimerV2) MarshalJSON() ([]byte, error) {
+	type timerV2 TimerV2
+	if t.HasDuration() {
+		return json.Marshal(timerV2(t))
+	}
+	return json.Marshal(struct {
+		timerV2
+		Quantity string `json:"quantity"`
+	}{timerV2(t), ""})
 }
 
 func (t Timer) asTimerV2() TimerV2 {
@@ -115,12 +217,23 @@ func (t Timer) asTimerV2() TimerV2 {
 	}
 }
 
+func (t TimerV2) asTimer() Timer {
+	return Timer{Name: t.Name, Duration: t.Quantity, Unit: t.Unit}
+}
+
 // Comment represents comment text
 type Comment struct {
 	Type  CommentType
 	Value string
 }
 
+// StepComment is a comment of a step with its position
+type StepComment struct {
+	Type   CommentType // type of the comment
+	Value  string      // comment text
+	Offset int         // byte offset in the step directions where the comment was
+}
+
 type Text struct {
 	Value string
 }
@@ -155,24 +268,168 @@ func newText(v string) Text {
 
 // Step represents a recipe step
 type Step struct {
-	Directions  string       // step directions as plain text
-	Timers      []Timer      // list of timers in the step
-	Ingredients []Ingredient // list of ingredients used in the step
-	Cookware    []Cookware   // list of cookware used in the step
-	Comments    []string     // list of comments
+	Directions    string         // step directions as plain text
+	Timers        []Timer        // list of timers in the step
+	Ingredients   []Ingredient   // list of ingredients used in the step
+	Cookware      []Cookware     // list of cookware used in the step
+	Comments      []string       // list of comments
+	Temperatures  []Temperature  `json:",omitempty" yaml:",omitempty"` // temperatures found in the directions
+	Links         []Link         `json:",omitempty" yaml:",omitempty"` // links found in the directions (see ParseConfig.DetectLinks)
+	TypedComments []StepComment  `json:",omitempty" yaml:",omitempty"` // comments with type and position (see ParseConfig.KeepCommentPositions)
+	Image         string         `json:",omitempty" yaml:",omitempty"` // optional step image
+	Attributes    map[string]any `json:",omitempty" yaml:",omitempty"` // optional arbitrary step attributes
 }
 
 // Metadata contains key value map of metadata
-type Metadata = map[string]string
+type Metadata map[string]string
 
 // Recipe contains a cooklang defined recipe
 type Recipe struct {
-	Steps    []Step   // list of steps for the recipe
-	Metadata Metadata // metadata of the recipe
+	Steps    []Step        // list of steps for the recipe
+	Metadata Metadata      // metadata of the recipe
+	Images   *RecipeImages `json:",omitempty"` // optional recipe and step images
+	// MetadataOrder lists the metadata keys in source order (see MetadataKeys)
+	MetadataOrder []string `json:"-" yaml:"-"`
+	// MetadataLists contains the list values of the metadata (see
+	// ParseConfig.ListMetadataKeys)
+	MetadataLists map[string][]string `json:",omitempty" yaml:",omitempty"`
+}
+
+// ParseConfig contains the parser configuration
+type ParseConfig struct {
+	Limits Limits // limits applied to the parsed input
+	Strict bool   // return an ItemError for malformed items instead of keeping them as text
+	// KeepCommentPositions stores the comments of every step with their type
+	// and offset in Step.TypedComments
+	KeepCommentPositions bool
+	// ListMetadataKeys are the metadata keys with comma separated list values
+	// (>> tags: vegan, quick). The values written with the list syntax
+	// (>> tags: [vegan, quick]) are lists for every key. The lists are stored
+	// in Recipe.MetadataLists, Recipe.Metadata keeps the value as written.
+	ListMetadataKeys []string
+	// StripListMarkers removes the list markers ("1.", "2)", "-", "*") the
+	// step lines start with, so the directions can be renumbered
+	StripListMarkers bool
+	// Comments defines what is left of the block and end of line comments
+	// in the directions. CommentPlaceholder is used in CommentsPlaceholder
+	// mode, DefaultCommentPlaceholder when empty.
+	Comments           CommentMode
+	CommentPlaceholder string
+	// Logger receives the debug events of the parser (items, malformed
+	// items kept as text, comments, metadata) to diagnose how a recipe is
+	// parsed. Nil disables logging.
+	Logger *slog.Logger
+	// SpecVersion selects the revision of the cooklang specification, the
+	// latest one when empty
+	SpecVersion SpecVersion
+	// EmptyAmountMode and DefaultQuantity define the quantity of the
+	// ingredients without quantity. The zero values keep the legacy
+	// defaults, EmptyAmountDefault with a zero DefaultQuantity reads them as
+	// "some" (see IngredientAmount.IsPresent).
+	EmptyAmountMode EmptyAmountMode
+	DefaultQuantity float64
+	// StrictMetadata reports the metadata keys which are not in
+	// AllowedMetadataKeys (StandardMetadataKeys when empty, compared case
+	// insensitively) as warnings or errors, suggesting the allowed key a
+	// key is likely a typo of
+	StrictMetadata      StrictMetadataMode
+	AllowedMetadataKeys []string
+	// Numbers converts the textual quantities of the ingredients and
+	// cookware ("three", "a dozen") to numbers, so they can be scaled and
+	// summed. QuantityRaw keeps the quantity as written. Nil keeps them
+	// textual (see NumberLanguage).
+	Numbers NumberParser
+	// References reads the repeated ingredient mentions as references to
+	// the first one, which are not added to the totals (see ReferenceMode)
+	References ReferenceMode
+	// VerbatimSpans reads the text in backticks as plain text, so the
+	// prefixes in it are not items ("use the `#2 @home` setting"). The
+	// backticks are removed from the text.
+	VerbatimSpans bool
+	// VerbatimPatterns are the patterns of the text read as plain text as
+	// written, such as model numbers
+	VerbatimPatterns []*regexp.Regexp
+	// DetectLinks reads the http and https URLs of the steps as links
+	// stored in Step.Links, so the prefixes in them ("#section") are not
+	// items. The URLs are kept in the directions.
+	DetectLinks bool
+	// DetectTemperatures fills Step.Temperatures with the temperatures
+	// (180°C) found in the directions
+	DetectTemperatures bool
+	// InheritDirMetadata adds the metadata of the config.cook and
+	// _meta.yml files of the directories to the recipes parsed with
+	// ParseDir and ParseFS. The metadata of the recipe wins over the
+	// directory metadata, _meta.yml over config.cook and the directories
+	// over their parents. The config.cook files are not parsed as recipes.
+	InheritDirMetadata bool
+	// Includes adds the recipes listed in the include metadata
+	// (>> include: ./base.cook) to the recipe (see IncludeMode)
+	Includes IncludeMode
+}
+
+// Parser parses cooklang recipes using the provided configuration
+type Parser struct {
+	config *ParseConfig
 }
 
 type ParseV2Config struct {
 	IgnoreTypes []ItemType
+	Limits      Limits // limits applied to the parsed input
+	Strict      bool   // return an ItemError for malformed items instead of keeping them as text
+	// DetectTemperatures splits the temperatures (180°C) from the text items
+	// as TemperatureV2 items
+	DetectTemperatures bool
+	// CustomPrefixes registers additional single byte item prefixes emitted
+	// as CustomItem. The characters of the built-in syntax (@ # ~ [ - { })
+	// can not be registered.
+	CustomPrefixes map[byte]CustomExtractor
+	// ListMetadataKeys are the metadata keys with list values stored in
+	// RecipeV2.MetadataLists (see ParseConfig.ListMetadataKeys)
+	ListMetadataKeys []string
+	// StripListMarkers removes the list markers of the step lines (see
+	// ParseConfig.StripListMarkers)
+	StripListMarkers bool
+	// Comments defines what is left of the comments in the text items. The
+	// placeholder or the comment as written is a text item preceding the
+	// Comment item (see ParseConfig.Comments).
+	Comments           CommentMode
+	CommentPlaceholder string
+	// Logger receives the debug events of the parser (see ParseConfig.Logger)
+	Logger *slog.Logger
+	// SpecVersion selects the revision of the cooklang specification (see
+	// ParseConfig.SpecVersion)
+	SpecVersion SpecVersion
+	// EmptyAmountMode and DefaultQuantity define the quantity of the
+	// ingredients without quantity (see ParseConfig.EmptyAmountMode)
+	EmptyAmountMode EmptyAmountMode
+	DefaultQuantity float64
+	// Numbers converts the textual quantities to numbers (see
+	// ParseConfig.Numbers)
+	Numbers NumberParser
+	// StrictMetadata reports the metadata keys which are not in
+	// AllowedMetadataKeys as errors (see ParseConfig.StrictMetadata). The
+	// v2 parser has no warnings, so StrictMetadataWarn accepts them.
+	StrictMetadata      StrictMetadataMode
+	AllowedMetadataKeys []string
+	// FrontMatter reads a YAML (---) or TOML (+++, Hugo style) front matter
+	// block at the start of the recipe as metadata, detected by the
+	// delimiter. The keys of nested tables are joined with dots
+	// ("source.name") and the arrays are list values (see
+	// RecipeV2.MetadataList).
+	FrontMatter bool
+	// References reads the repeated ingredient mentions as references (see
+	// ParseConfig.References)
+	References ReferenceMode
+	// VerbatimSpans and VerbatimPatterns define the text which is not
+	// tokenized (see ParseConfig.VerbatimSpans)
+	VerbatimSpans    bool
+	VerbatimPatterns []*regexp.Regexp
+	// DetectLinks splits the URLs from the text items as LinkV2 items (see
+	// ParseConfig.DetectLinks)
+	DetectLinks bool
+	// Includes adds the recipes listed in the include metadata to the
+	// recipe (see IncludeMode)
+	Includes IncludeMode
 }
 
 type StepV2 []any
@@ -181,6 +438,11 @@ type StepV2 []any
 type RecipeV2 struct {
 	Steps    []StepV2 `json:"steps"`    // list of steps for the recipe
 	Metadata Metadata `json:"metadata"` // metadata of the recipe
+	// MetadataOrder lists the metadata keys in source order (see MetadataKeys)
+	MetadataOrder []string `json:"-" yaml:"-"`
+	// MetadataLists contains the list values of the metadata (see
+	// ParseV2Config.ListMetadataKeys)
+	MetadataLists map[string][]string `json:"metadataLists,omitempty"`
 }
 
 type ParserV2 struct {
@@ -189,8 +451,8 @@ type ParserV2 struct {
 
 func (r Recipe) String() string {
 	var sb strings.Builder
-	for k, v := range r.Metadata {
-		sb.WriteString(fmt.Sprintf("%s %s: %s\n", metadataLinePrefix, k, v))
+	for _, k := range r.MetadataKeys() {
+		sb.WriteString(fmt.Sprintf("%s %s: %s\n", metadataLinePrefix, k, r.Metadata[k]))
 	}
 	if len(r.Metadata) > 0 {
 		sb.WriteString("\n")
@@ -205,14 +467,28 @@ func (r Recipe) String() string {
 	return sb.String()
 }
 
+// NewParser creates a new parser with the provided configuration. Nil config
+// uses the defaults.
+func NewParser(config *ParseConfig) *Parser {
+	if config == nil {
+		config = &ParseConfig{}
+	}
+	return &Parser{config}
+}
+
 // ParseFile parses a cooklang recipe file and returns the recipe or an error
 func ParseFile(fileName string) (*Recipe, error) {
+	return NewParser(nil).ParseFile(fileName)
+}
+
+// ParseFile parses a cooklang recipe file and returns the recipe or an error
+func (p *Parser) ParseFile(fileName string) (*Recipe, error) {
 	f, err := os.Open(fileName)
 	if err != nil {
 		return nil, err
 	}
 	defer f.Close()
-	return ParseStream(bufio.NewReader(f))
+	return p.parseStream(bufio.NewReader(f), nil, fileIncludeSource(nil, fileName))
 }
 
 func (p *ParserV2) ParseFile(fileName string) (*RecipeV2, error) {
@@ -221,15 +497,20 @@ func (p *ParserV2) ParseFile(fileName string) (*RecipeV2, error) {
 		return nil, err
 	}
 	defer f.Close()
-	return p.ParseStream(bufio.NewReader(f))
+	return p.parseStream(bufio.NewReader(f), fileIncludeSource(nil, fileName))
 }
 
 // ParseString parses a cooklang recipe string and returns the recipe or an error
 func ParseString(s string) (*Recipe, error) {
+	return NewParser(nil).ParseString(s)
+}
+
+// ParseString parses a cooklang recipe string and returns the recipe or an error
+func (p *Parser) ParseString(s string) (*Recipe, error) {
 	if s == "" {
 		return nil, fmt.Errorf("recipe string must not be empty")
 	}
-	return ParseStream(strings.NewReader(s))
+	return p.ParseStream(strings.NewReader(s))
 }
 
 func (p *ParserV2) ParseString(s string) (*RecipeV2, error) {
@@ -239,104 +520,71 @@ func (p *ParserV2) ParseString(s string) (*RecipeV2, error) {
 	return p.ParseStream(strings.NewReader(s))
 }
 
+// NewParserV2 creates a new v2 parser with the provided configuration. Nil
+// config uses the defaults.
 func NewParserV2(config *ParseV2Config) *ParserV2 {
+	if config == nil {
+		config = &ParseV2Config{}
+	}
 	return &ParserV2{config}
 }
 
 // ParseStream parses a cooklang recipe text stream and returns the recipe or an error
 func ParseStream(s io.Reader) (*Recipe, error) {
-	scanner := bufio.NewScanner(s)
-	recipe := Recipe{
-		make([]Step, 0),
-		make(map[string]string),
-	}
-	var line string
-	lineNumber := 0
-	for scanner.Scan() {
-		lineNumber++
-		line = scanner.Text()
-
-		if strings.TrimSpace(line) != "" {
-			err := parseLine(line, &recipe)
-			if err != nil {
-				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
-			}
-		}
-	}
-	return &recipe, nil
+	return NewParser(nil).ParseStream(s)
 }
 
 // ParseStream parses a cooklang recipe text stream and returns the recipe or an error
-func (p *ParserV2) ParseStream(s io.Reader) (*RecipeV2, error) {
-	scanner := bufio.NewScanner(s)
-	recipe := RecipeV2{
-		make([]StepV2, 0),
-		make(map[string]string),
-	}
-	var line string
-	lineNumber := 0
-	for scanner.Scan() {
-		lineNumber++
-		line = scanner.Text()
-
-		if strings.TrimSpace(line) != "" {
-			err := p.parseLine(line, &recipe)
-			if err != nil {
-				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
-			}
-		}
+func (p *Parser) ParseStream(s io.Reader) (*Recipe, error) {
+	return p.parseStream(s, nil, includeSource{})
+}
+
+// documentConfig returns the document configuration of the parser
+func (p *Parser) documentConfig(source includeSource) (documentConfig, error) {
+	features, err := p.config.SpecVersion.features()
+	if err != nil {
+		return documentConfig{}, err
 	}
-	return &recipe, nil
+	return documentConfig{features: features, limits: p.config.Limits, strict: p.config.Strict, listKeys: p.config.ListMetadataKeys, stripListMarkers: p.config.StripListMarkers,
+		comments: p.config.Comments, commentPlaceholder: p.config.CommentPlaceholder, logger: p.config.Logger,
+		strictMetadata: p.config.StrictMetadata, allowedMetadataKeys: p.config.AllowedMetadataKeys, references: p.config.References,
+		verbatimSpans: p.config.VerbatimSpans, verbatimPatterns: p.config.VerbatimPatterns, links: p.config.DetectLinks,
+		includes: p.config.Includes, includeSource: source}, nil
 }
 
-func parseLine(line string, recipe *Recipe) error {
-	if strings.HasPrefix(line, commentsLinePrefix) {
-		commentLine, err := parseSingleLineComment(line)
-		if err != nil {
-			return err
-		}
-		recipe.Steps = append(recipe.Steps, Step{
-			Comments: []string{commentLine},
-		})
-	} else if strings.HasPrefix(line, metadataLinePrefix) {
-		key, value, err := parseMetadata(line)
-		if err != nil {
-			return err
-		}
-		recipe.Metadata[key] = value
-	} else {
-		step, err := parseRecipeLine(line)
-		if err != nil {
-			return err
-		}
-		recipe.Steps = append(recipe.Steps, *step)
+func (p *Parser) parseStream(s io.Reader, warnings *warningList, source includeSource) (*Recipe, error) {
+	config, err := p.documentConfig(source)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	doc, err := parseDocument(s, config, warnings)
+	if err != nil {
+		return nil, err
+	}
+	defer doc.release()
+	return doc.recipe(p.config), nil
 }
 
-func (p *ParserV2) parseLine(line string, recipe *RecipeV2) error {
-	if strings.HasPrefix(line, commentsLinePrefix) {
-		commentLine, err := parseSingleLineComment(line)
-		if err != nil {
-			return err
-		}
-		if !slices.Contains(p.config.IgnoreTypes, ItemTypeComment) {
-			recipe.Steps = append(recipe.Steps, StepV2{Comment{CommentTypeLine, commentLine}})
-		}
-	} else if strings.HasPrefix(line, metadataLinePrefix) {
-		key, value, err := parseMetadata(line)
-		if err != nil {
-			return err
-		}
-		recipe.Metadata[key] = value
-	} else {
-		step, err := p.parseRecipeLine(line)
-		if err != nil {
-			return err
-		}
-		recipe.Steps = append(recipe.Steps, *step)
+// ParseStream parses a cooklang recipe text stream and returns the recipe or an error
+func (p *ParserV2) ParseStream(s io.Reader) (*RecipeV2, error) {
+	return p.parseStream(s, includeSource{})
+}
+
+func (p *ParserV2) parseStream(s io.Reader, source includeSource) (*RecipeV2, error) {
+	features, err := p.config.SpecVersion.features()
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	doc, err := parseDocument(s, documentConfig{features: features, limits: p.config.Limits, strict: p.config.Strict, custom: p.config.CustomPrefixes, listKeys: p.config.ListMetadataKeys, stripListMarkers: p.config.StripListMarkers,
+		comments: p.config.Comments, commentPlaceholder: p.config.CommentPlaceholder, logger: p.config.Logger, frontMatter: p.config.FrontMatter,
+		strictMetadata: p.config.StrictMetadata, allowedMetadataKeys: p.config.AllowedMetadataKeys, references: p.config.References,
+		verbatimSpans: p.config.VerbatimSpans, verbatimPatterns: p.config.VerbatimPatterns, links: p.config.DetectLinks,
+		includes: p.config.Includes, includeSource: source}, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer doc.release()
+	return doc.recipeV2(p.config), nil
 }
 
 func parseSingleLineComment(line string) (string, error) {
@@ -352,236 +600,100 @@ func parseMetadata(line string) (string, string, error) {
 	return strings.TrimSpace(metadataLine[:index]), strings.TrimSpace(metadataLine[index+1:]), nil
 }
 
-func peek(s string) rune {
-	r, _ := utf8.DecodeRuneInString(s)
-	return r
+func newItemError(itemType ItemType, raw string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ItemError{Type: itemType, Raw: raw, Err: err}
 }
 
-func parseStepCB(line string, cb func(item any) (bool, error)) (string, error) {
-	skipIndex := -1
-	var directions strings.Builder
-	var err error
-	var skipNext int
-	var ingredient *Ingredient
-	var cookware *Cookware
-	var timer *Timer
-	var comment string
-	var buffer strings.Builder
-	for index, ch := range line {
-		if skipIndex > index {
-			continue
-		}
-		if ch == prefixIngredient {
-			nextRune := peek(line[index+1:])
-			if nextRune != ' ' {
-				if buffer.Len() > 0 {
-					if stop, err := cb(newText(buffer.String())); err != nil || stop {
-						return directions.String(), err
-					}
-					buffer.Reset()
-				}
-				// ingredient ahead
-				ingredient, skipNext, err = getIngredient(line[index:])
-				if err != nil {
-					return directions.String(), err
-				}
-				skipIndex = index + skipNext
-				directions.WriteString((*ingredient).Name)
-				if stop, err := cb(*ingredient); err != nil || stop {
-					return directions.String(), err
-				}
-				continue
-
-			}
-		}
-		if ch == prefixCookware {
-			nextRune := peek(line[index+1:])
-			if nextRune != ' ' {
-				if buffer.Len() > 0 {
-					if stop, err := cb(newText(buffer.String())); err != nil || stop {
-						return directions.String(), err
-					}
-					buffer.Reset()
-				}
-				// Cookware ahead
-				cookware, skipNext, err = getCookware(line[index:])
-				if err != nil {
-					return directions.String(), err
-				}
-				skipIndex = index + skipNext
-				directions.WriteString((*cookware).Name)
-				if stop, err := cb(*cookware); err != nil || stop {
-					return directions.String(), err
-				}
-				continue
-			}
-		}
-		if ch == prefixTimer {
-			nextRune := peek(line[index+1:])
-			if nextRune != ' ' {
-				if buffer.Len() > 0 {
-					if stop, err := cb(newText(buffer.String())); err != nil || stop {
-						return directions.String(), err
-					}
-					buffer.Reset()
-				}
-				//timer ahead
-				timer, skipNext, err = getTimer(line[index:])
-				if err != nil {
-					return directions.String(), err
-				}
-				skipIndex = index + skipNext
-				directions.WriteString(fmt.Sprintf("%v %s", (*timer).Duration, (*timer).Unit))
-				if stop, err := cb(*timer); err != nil || stop {
-					return directions.String(), err
-				}
-				continue
-			}
-		}
-		if ch == prefixBlockComment {
-			nextRune := peek(line[index+1:])
-			if nextRune == '-' {
-				if buffer.Len() > 0 {
-					if stop, err := cb(newText(buffer.String())); err != nil || stop {
-						return directions.String(), err
-					}
-					buffer.Reset()
-				}
-				// block comment ahead
-				comment, skipNext, err = getBlockComment(line[index:])
-				if err != nil {
-					return directions.String(), err
-				}
-				skipIndex = index + skipNext
-				if stop, err := cb(Comment{CommentTypeBlock, comment}); err != nil || stop {
-					return directions.String(), err
-				}
-				continue
-			}
-		}
-		if ch == prefixInlineComment {
-			nextRune := peek(line[index+1:])
-			if nextRune == prefixInlineComment {
-				if buffer.Len() > 0 {
-					if stop, err := cb(newText(buffer.String())); err != nil || stop {
-						return directions.String(), err
-					}
-					buffer.Reset()
-				}
-				// end-line comment ahead
-				comment = strings.TrimSpace(line[index+len(commentsLinePrefix):])
-				if err != nil {
-					return directions.String(), err
-				}
-				if stop, err := cb(Comment{CommentTypeEndLine, comment}); err != nil || stop {
-					return directions.String(), err
-				}
-				break
+func getCookware(line string, features specFeatures) (Cookware, int, error) {
+	endIndex := findNodeEndIndex(line)
+	if i := strings.IndexByte(line[:endIndex], '('); features.cookwareNotes && i > 1 && !strings.Contains(line[:endIndex], "{") && strings.IndexByte(line[i:], ')') != -1 {
+		// single word cookware followed by a note: #pan(cast iron)
+		endIndex = i
+	}
+	cookware, err := getCookwareFromRawString(line[1:endIndex])
+	if err == nil && features.optionalItems {
+		if name, optional := strings.CutPrefix(cookware.Name, string(prefixOptional)); optional {
+			cookware.Name, cookware.Optional = strings.TrimSpace(name), true
+			if cookware.Name == "" {
+				err = ErrEmptyItem
 			}
 		}
-		// raw string
-		buffer.WriteRune(ch)
-		directions.WriteRune(ch)
 	}
-	if buffer.Len() > 0 {
-		if stop, err := cb(newText(buffer.String())); err != nil || stop {
-			return directions.String(), err
+	if err == nil && features.cookwareNotes {
+		if note, n := itemNote(line[endIndex:]); n > 0 {
+			cookware.Note = note
+			endIndex += n
 		}
-		buffer.Reset()
 	}
-	return strings.TrimSpace(directions.String()), nil
+	return cookware, endIndex, newItemError(ItemTypeCookware, line[:endIndex], err)
 }
 
-func parseRecipeLine(line string) (*Step, error) {
-	step := Step{
-		Timers:      make([]Timer, 0),
-		Ingredients: make([]Ingredient, 0),
-		Cookware:    make([]Cookware, 0),
+// itemNote returns the note in parentheses at the start of s and its length
+// with the parentheses, zero when s does not start with a note
+func itemNote(s string) (string, int) {
+	if !strings.HasPrefix(s, "(") {
+		return "", 0
 	}
-	var err error
-	step.Directions, err = parseStepCB(line, func(item any) (bool, error) {
-		switch v := item.(type) {
-		case Timer:
-			step.Timers = append(step.Timers, v)
-		case Ingredient:
-			step.Ingredients = append(step.Ingredients, v)
-		case Cookware:
-			step.Cookware = append(step.Cookware, v)
-		case Text:
-			//
-		case Comment:
-			step.Comments = append(step.Comments, v.Value)
-		default:
-			return true, fmt.Errorf("unknown type %T", v)
-		}
-		return false, nil
-	})
-	if err != nil {
-		return nil, err
+	end := strings.IndexByte(s, ')')
+	if end == -1 {
+		return "", 0
 	}
-	return &step, nil
+	return strings.TrimSpace(s[1:end]), end + 1
 }
 
-func (p *ParserV2) parseRecipeLine(line string) (*StepV2, error) {
-	step := StepV2{}
-	var err error
-	_, err = parseStepCB(line, func(item any) (bool, error) {
-		switch v := item.(type) {
-		case Timer:
-			if !slices.Contains(p.config.IgnoreTypes, ItemTypeTimer) {
-				step = append(step, v.asTimerV2())
-			}
-		case Ingredient:
-			if !slices.Contains(p.config.IgnoreTypes, ItemTypeIngredient) {
-				step = append(step, v.asIngredientV2())
-			}
-		case Cookware:
-			if !slices.Contains(p.config.IgnoreTypes, ItemTypeCookware) {
-				step = append(step, v.asCookwareV2())
-			}
-		case Text:
-			if !slices.Contains(p.config.IgnoreTypes, ItemTypeText) {
-				step = append(step, v.asTextV2())
-			}
-		case Comment:
-			if !slices.Contains(p.config.IgnoreTypes, ItemTypeComment) {
-				step = append(step, v)
-			}
+func getIngredient(line string, features specFeatures, references bool) (Ingredient, int, error) {
+	endIndex := findNodeEndIndex(line)
+	ingredient, err := getIngredientFromRawString(line[1:endIndex], features)
+	if err == nil {
+		ingredient, err = ingredientModifiers(ingredient, features, references)
+	}
+	return ingredient, endIndex, newItemError(ItemTypeIngredient, line[:endIndex], err)
+}
+
+// ingredientModifiers removes the modifiers from the start of the ingredient
+// name: - hides the ingredient from the directions, ? marks an optional
+// ingredient and & marks a reference when references are enabled. The
+// modifiers missing from the spec version are part of the name.
+func ingredientModifiers(ingredient Ingredient, features specFeatures, references bool) (Ingredient, error) {
+	name := ingredient.Name
+loop:
+	for name != "" {
+		switch {
+		case name[0] == prefixHidden && features.hiddenItems && !ingredient.Hidden:
+			ingredient.Hidden = true
+		case name[0] == prefixReference && references && !ingredient.Reference:
+			ingredient.Reference = true
+		case name[0] == prefixOptional && features.optionalItems && !ingredient.Optional:
+			ingredient.Optional = true
 		default:
-			return true, fmt.Errorf("unknown type %T", v)
+			break loop
 		}
-		return false, nil
-	})
-	if err != nil {
-		return nil, err
+		name = name[1:]
 	}
-	return &step, nil
-}
-
-func getCookware(line string) (*Cookware, int, error) {
-	endIndex := findNodeEndIndex(line)
-	Cookware, err := getCookwareFromRawString(line[1:endIndex])
-	return Cookware, endIndex, err
-}
-
-func getIngredient(line string) (*Ingredient, int, error) {
-	endIndex := findNodeEndIndex(line)
-	ingredient, err := getIngredientFromRawString(line[1:endIndex])
-	return ingredient, endIndex, err
+	if name == ingredient.Name {
+		return ingredient, nil
+	}
+	if ingredient.Name = strings.TrimSpace(name); ingredient.Name == "" {
+		return Ingredient{}, ErrEmptyItem
+	}
+	return ingredient, nil
 }
 
-func getTimer(line string) (*Timer, int, error) {
+func getTimer(line string) (Timer, int, error) {
 	endIndex := findNodeEndIndex(line)
 	timer, err := getTimerFromRawString(line[1:endIndex])
-	return timer, endIndex, err
+	return timer, endIndex, newItemError(ItemTypeTimer, line[:endIndex], err)
 }
 
 func getBlockComment(s string) (string, int, error) {
-	index := strings.Index(s, "-]")
+	index := strings.Index(s[2:], "-]")
 	if index == -1 {
-		return "", 0, fmt.Errorf("invalid block comment")
+		return "", 0, fmt.Errorf("invalid block comment: %w", ErrUnterminatedComment)
 	}
-	return strings.TrimSpace(s[2:index]), index + 2, nil
+	return strings.TrimSpace(s[2 : index+2]), index + 4, nil
 }
 
 func getFloat(s string) (bool, float64, error) {
@@ -610,45 +722,81 @@ func getFloat(s string) (bool, float64, error) {
 	return true, float64(numerator) / float64(denominator), nil
 }
 
+// findNodeEndIndex returns the end index of the item at the beginning of
+// line. Items with amount end after the closing brace and single word items
+// end at the first Unicode white space. The item ends before another item
+// starts. The line is iterated by runes so multi-byte names are kept whole.
 func findNodeEndIndex(line string) int {
-	endIndex := -1
-
+	openIndex := -1
+	depth := 0 // nesting of the template expressions ({{servings}}) in the amount
 	for index, ch := range line {
 		if index == 0 {
 			continue
 		}
-		if (ch == prefixCookware || ch == prefixIngredient || ch == prefixTimer || ch == prefixBlockComment) && endIndex == -1 {
+		if ch == prefixCookware || ch == prefixIngredient || ch == prefixTimer || ch == prefixBlockComment {
 			break
 		}
-		if ch == '}' {
-			endIndex = index + 1
-			break
+		if ch == '{' {
+			if openIndex == -1 {
+				openIndex = index
+			} else {
+				depth++
+			}
+		}
+		if ch == '}' && openIndex != -1 {
+			if depth == 0 {
+				return index + 1
+			}
+			depth--
 		}
 	}
+	endIndex := strings.IndexFunc(line, unicode.IsSpace)
 	if endIndex == -1 {
-		endIndex = strings.Index(line, " ")
-		if endIndex == -1 {
-			endIndex = len(line)
-		}
+		endIndex = len(line)
 	}
 	return endIndex
 }
 
-func getIngredientFromRawString(s string) (*Ingredient, error) {
+// splitAmount splits raw item text to name and amount. hasAmount is false
+// for single word items.
+func splitAmount(s string) (name string, amount string, hasAmount bool, err error) {
 	index := strings.Index(s, "{")
 	if index == -1 {
-		return &Ingredient{Name: s, Amount: IngredientAmount{Quantity: 1}}, nil
+		return s, "", false, nil
 	}
-	amount, err := getAmount(s[index+1:len(s)-1], 0)
-	if err != nil {
-		return nil, err
+	if !strings.HasSuffix(s, "}") {
+		return "", "", false, ErrUnterminatedAmount
 	}
-	return &Ingredient{Name: s[:index], Amount: *amount}, nil
+	return s[:index], s[index+1 : len(s)-1], true, nil
 }
 
-func getAmount(s string, defaultValue float64) (*IngredientAmount, error) {
+func getIngredientFromRawString(s string, features specFeatures) (Ingredient, error) {
+	name, rawAmount, hasAmount, err := splitAmount(s)
+	if err != nil {
+		return Ingredient{}, err
+	}
+	if !hasAmount {
+		if name == "" {
+			return Ingredient{}, ErrEmptyItem
+		}
+		return Ingredient{Name: name, Amount: IngredientAmount{Quantity: 1}}, nil
+	}
+	if strings.TrimSpace(name) == "" {
+		return Ingredient{}, ErrEmptyItem
+	}
+	rawAmount, fixed := strings.TrimSpace(rawAmount), false
+	if features.fixedQuantities {
+		rawAmount, fixed = strings.CutPrefix(rawAmount, string(prefixFixed))
+	}
+	amount, err := getAmount(rawAmount, 0)
+	if err != nil {
+		return Ingredient{}, err
+	}
+	return Ingredient{Name: name, Amount: amount, Fixed: fixed}, nil
+}
+func getAmount(s string, defaultValue float64) (IngredientAmount, error) {
 	if s == "" {
-		return &IngredientAmount{Quantity: defaultValue, QuantityRaw: "", IsNumeric: false}, nil
+		return IngredientAmount{Quantity: defaultValue, QuantityRaw: "", IsNumeric: false}, nil
 	}
 	index := strings.Index(s, "%")
 	if index == -1 {
@@ -656,44 +804,58 @@ func getAmount(s string, defaultValue float64) (*IngredientAmount, error) {
 		if !isNumeric {
 			f = defaultValue
 		}
-		return &IngredientAmount{Quantity: f, QuantityRaw: strings.TrimSpace(s), IsNumeric: isNumeric}, nil
+		return IngredientAmount{Quantity: f, QuantityRaw: strings.TrimSpace(s), IsNumeric: isNumeric}, nil
 	}
 	isNumeric, f, _ := getFloat(s[:index])
 	if !isNumeric {
 		f = defaultValue
 	}
-	return &IngredientAmount{Quantity: f, QuantityRaw: strings.TrimSpace(s[:index]), Unit: strings.TrimSpace(s[index+1:]), IsNumeric: isNumeric}, nil
+	return IngredientAmount{Quantity: f, QuantityRaw: strings.TrimSpace(s[:index]), Unit: strings.TrimSpace(s[index+1:]), IsNumeric: isNumeric}, nil
 }
 
-func getCookwareFromRawString(s string) (*Cookware, error) {
-	index := strings.Index(s, "{")
-	if index == -1 {
-		return &Cookware{Name: s, Quantity: 1}, nil
+func getCookwareFromRawString(s string) (Cookware, error) {
+	name, rawAmount, hasAmount, err := splitAmount(s)
+	if err != nil {
+		return Cookware{}, err
 	}
-	amount, err := getAmount(s[index+1:len(s)-1], 1)
+	if !hasAmount {
+		if name == "" {
+			return Cookware{}, ErrEmptyItem
+		}
+		return Cookware{Name: name, Quantity: 1}, nil
+	}
+	if strings.TrimSpace(name) == "" {
+		return Cookware{}, ErrEmptyItem
+	}
+	amount, err := getAmount(rawAmount, 1)
 	if err != nil {
-		return nil, err
+		return Cookware{}, err
 	}
-	return &Cookware{Name: s[:index], Quantity: amount.Quantity, IsNumeric: amount.IsNumeric, QuantityRaw: amount.QuantityRaw}, nil
+	return Cookware{Name: name, Quantity: amount.Quantity, IsNumeric: amount.IsNumeric, QuantityRaw: amount.QuantityRaw}, nil
 }
 
-func getTimerFromRawString(s string) (*Timer, error) {
-	name := ""
-	index := strings.Index(s, "{")
-	if index > -1 {
-		name = strings.TrimSpace(s[:index])
-		s = s[index+1:]
+func getTimerFromRawString(s string) (Timer, error) {
+	name, rawAmount, hasAmount, err := splitAmount(s)
+	if err != nil {
+		return Timer{}, err
 	}
-	index = strings.Index(s, "%")
-	if index == -1 {
-		return &Timer{Name: s, Duration: 0, Unit: ""}, nil
+	if !hasAmount {
+		if name == "" {
+			return Timer{}, ErrEmptyItem
+		}
+		return Timer{Name: name}, nil
 	}
-	isNumeric, f, err := getFloat(s[:index])
-	if err != nil {
-		return nil, err
+	name = strings.TrimSpace(name)
+	rawDuration, unit, _ := strings.Cut(rawAmount, "%")
+	if strings.TrimSpace(rawDuration) == "" {
+		if name == "" {
+			return Timer{}, ErrEmptyItem
+		}
+		return Timer{Name: name, Unit: strings.TrimSpace(unit)}, nil
 	}
-	if !isNumeric {
-		return &Timer{Name: name, Duration: 0, Unit: s[index+1 : len(s)-1]}, nil
+	_, f, err := getFloat(rawDuration)
+	if err != nil {
+		return Timer{}, fmt.Errorf("%w: %q", ErrInvalidQuantity, strings.TrimSpace(rawDuration))
 	}
-	return &Timer{Name: name, Duration: f, Unit: s[index+1 : len(s)-1]}, nil
+	return Timer{Name: name, Duration: f, Unit: strings.TrimSpace(unit)}, nil
 }
@@ -1,8 +1,13 @@
 package cooklang
 
 import (
+	"encoding/json"
+	"errors"
+	"math/rand"
 	"reflect"
 	"testing"
+	"unicode"
+	"unicode/utf8"
 )
 
 func TestParseString(t *testing.T) {
@@ -68,6 +73,7 @@ func TestParseString(t *testing.T) {
 				Metadata: Metadata{
 					"key": "value",
 				},
+				MetadataOrder: []string{"key"},
 			},
 			false,
 		},
@@ -217,7 +223,57 @@ Put in an #oven for ~{4%minutes}.`,
 						Cookware:    []Cookware{{Name: "oven", Quantity: 1, IsNumeric: false, QuantityRaw: ""}},
 					},
 				},
-				Metadata: Metadata{"servings": "6"},
+				Metadata:      Metadata{"servings": "6"},
+				MetadataOrder: []string{"servings"},
+			},
+			false,
+		},
+		{
+			"Parses single word timers",
+			"Let it ~rest after plating and ~10 more",
+			&Recipe{
+				Steps: []Step{
+					{
+						Directions:  "Let it rest after plating and 10 more",
+						Timers:      []Timer{{Name: "rest"}, {Name: "10"}},
+						Ingredients: []Ingredient{},
+						Cookware:    []Cookware{},
+					},
+				},
+				Metadata: make(Metadata),
+			},
+			false,
+		},
+		{
+			"Parses CRLF line endings",
+			">> servings: 2\r\n\r\nAdd @salt\r\n",
+			&Recipe{
+				Steps: []Step{
+					{
+						Directions:  "Add salt",
+						Timers:      []Timer{},
+						Ingredients: []Ingredient{{Name: "salt", Amount: IngredientAmount{Quantity: 1}}},
+						Cookware:    []Cookware{},
+					},
+				},
+				Metadata:      Metadata{"servings": "2"},
+				MetadataOrder: []string{"servings"},
+			},
+			false,
+		},
+		{
+			"Unicode white space ends single word items",
+			"Add @chilli\u2009then put in #pot\u00a0and @\u2009 ~\u3000",
+			&Recipe{
+				Steps: []Step{
+					{
+						Directions:  "Add chilli\u2009then put in pot\u00a0and @\u2009 ~",
+						Timers:      []Timer{},
+						Ingredients: []Ingredient{{Name: "chilli", Amount: IngredientAmount{Quantity: 1}}},
+						Cookware:    []Cookware{{Name: "pot", Quantity: 1}},
+					},
+				},
+				Metadata: make(Metadata),
 			},
 			false,
 		},
@@ -296,6 +352,18 @@ func Test_findIngredient(t *testing.T) {
 			"word1{1%kg}",
 			12,
 		},
+		{
+			"ignores closing brace without opening one",
+			"@salt and pepper}",
+			"salt",
+			5,
+		},
+		{
+			"unterminated amount ends at the first space",
+			"@flour{200%g and more",
+			"flour{200%g",
+			12,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -318,7 +386,7 @@ func Test_getTimer(t *testing.T) {
 	tests := []struct {
 		name    string
 		args    args
-		want    *Timer
+		want    Timer
 		wantErr bool
 	}{
 		{
@@ -326,7 +394,7 @@ func Test_getTimer(t *testing.T) {
 			args{
 				"~potato{42%minutes}",
 			},
-			&Timer{
+			Timer{
 				"potato",
 				42,
 				"minutes",
@@ -338,13 +406,41 @@ func Test_getTimer(t *testing.T) {
 			args{
 				"~{42%minutes}",
 			},
-			&Timer{
+			Timer{
 				"",
 				42,
 				"minutes",
 			},
 			false,
 		},
+		{
+			"Gets timer without unit",
+			args{
+				"~{5}",
+			},
+			Timer{
+				"",
+				5,
+				"",
+			},
+			false,
+		},
+		{
+			"Fails on empty timer",
+			args{
+				"~{}",
+			},
+			Timer{},
+			true,
+		},
+		{
+			"Fails on non numeric duration",
+			args{
+				"~{few%minutes}",
+			},
+			Timer{},
+			true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -359,3 +455,204 @@ func Test_getTimer(t *testing.T) {
 		})
 	}
 }
+
+const benchmarkRecipe = `>> servings: 6
+
+Make 6 pizza balls using @tipo zero flour{820%g}, @water{533%ml}, @salt{24.6%g} and @fresh yeast{1.6%g}. Put in a #fridge for ~{2%days}.
+
+Set #oven to max temperature and heat #pizza stone{} for about ~{40%minutes}.
+
+Make some tomato sauce with @chopped tomato{3%cans} and @garlic{3%cloves} and @dried oregano{3%tbsp}. Put on a #pan and leave for ~{15%minutes} occasionally stirring.
+
+Make pizzas putting some tomato sauce with #spoon on top of flattened dough. Add @fresh basil{18%leaves}, @parma ham{3%packs} and @mozzarella{3%packs}. [- optional -]
+
+Put in an #oven for ~{4%minutes}. -- enjoy`
+
+func BenchmarkParseString(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseString(benchmarkRecipe); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParserV2ParseString(b *testing.B) {
+	p := NewParserV2(&ParseV2Config{})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := p.ParseString(benchmarkRecipe); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestParseStringItemErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		recipe  string
+		want    ItemError
+		wantErr error
+	}{
+		{
+			"Unterminated ingredient amount",
+			"Add @flour{200%g",
+			ItemError{ItemTypeIngredient, "@flour{200%g", 4, ErrUnterminatedAmount},
+			ErrUnterminatedAmount,
+		},
+		{
+			"Unterminated cookware amount",
+			"Use #pan{",
+			ItemError{ItemTypeCookware, "#pan{", 4, ErrUnterminatedAmount},
+			ErrUnterminatedAmount,
+		},
+		{
+			"Empty timer",
+			"Wait ~{}",
+			ItemError{ItemTypeTimer, "~{}", 5, ErrEmptyItem},
+			ErrEmptyItem,
+		},
+		{
+			"Ingredient without name",
+			"Add @{2%g}",
+			ItemError{ItemTypeIngredient, "@{2%g}", 4, ErrEmptyItem},
+			ErrEmptyItem,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewParser(&ParseConfig{Strict: true}).ParseString(tt.recipe)
+			var itemErr *ItemError
+			if !errors.As(err, &itemErr) {
+				t.Fatalf("ParseString() error = %v, want ItemError", err)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseString() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(*itemErr, tt.want) {
+				t.Errorf("ParseString() error = %#v, want %#v", *itemErr, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseStringDowngradeToText(t *testing.T) {
+	tests := []struct {
+		name   string
+		recipe string
+		want   string
+	}{
+		{"Unterminated ingredient amount", "Add @flour{200%g", "Add @flour{200%g"},
+		{"Unterminated cookware amount", "Use #pan{ now", "Use #pan{ now"},
+		{"Empty timer", "Wait ~{} please", "Wait ~{} please"},
+		{"Ingredient without name", "Add @{2%g} of it", "Add @{2%g} of it"},
+		{"Prefix at end of line", "Recipe #", "Recipe #"},
+		{"Timer prefix at end of line", "Wait ~", "Wait ~"},
+		{"Malformed before valid item", "Add @{} and @salt", "Add @{} and salt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseString(tt.recipe)
+			if err != nil {
+				t.Fatalf("ParseString() error = %v", err)
+			}
+			if got.Steps[0].Directions != tt.want {
+				t.Errorf("ParseString() directions = %q, want %q", got.Steps[0].Directions, tt.want)
+			}
+			if tt.name != "Malformed before valid item" && len(got.Steps[0].Ingredients)+len(got.Steps[0].Cookware)+len(got.Steps[0].Timers) != 0 {
+				t.Errorf("ParseString() step = %#v, want no items", got.Steps[0])
+			}
+		})
+	}
+}
+
+// randomName returns a string of random non ASCII runes from the table
+func randomName(rng *rand.Rand, table *unicode.RangeTable, length int) string {
+	var runes []rune
+	for len(runes) < length {
+		var lo, hi, stride uint32
+		if len(table.R32) > 0 && rng.Intn(4) == 0 {
+			r := table.R32[rng.Intn(len(table.R32))]
+			lo, hi, stride = r.Lo, r.Hi, r.Stride
+		} else {
+			r := table.R16[rng.Intn(len(table.R16))]
+			lo, hi, stride = uint32(r.Lo), uint32(r.Hi), uint32(r.Stride)
+		}
+		ch := rune(lo + uint32(rng.Intn(int((hi-lo)/stride)+1))*stride)
+		if ch >= utf8.RuneSelf {
+			runes = append(runes, ch)
+		}
+	}
+	return string(runes)
+}
+
+func TestParseStringUnicodeCategories(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	separators := []string{" ", "\t", "\u00a0", "\u1680", "\u2009", "\u202f", "\u3000"}
+	categories := map[string]*unicode.RangeTable{
+		"Letter":       unicode.Letter,
+		"Number":       unicode.Number,
+		"Mark":         unicode.Mark,
+		"Symbol other": unicode.So,
+		"Punctuation":  unicode.Punct,
+	}
+	for name, table := range categories {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 200; i++ {
+				item := randomName(rng, table, 1+rng.Intn(5))
+				separator := separators[rng.Intn(len(separators))]
+				r, err := ParseString("Add @" + item + separator + "and #" + item + "{}")
+				if err != nil {
+					t.Fatalf("ParseString(%q) error = %v", item, err)
+				}
+				step := r.Steps[0]
+				if len(step.Ingredients) != 1 || step.Ingredients[0].Name != item {
+					t.Fatalf("ParseString() ingredients = %v, want %q", step.Ingredients, item)
+				}
+				if len(step.Cookware) != 1 || step.Cookware[0].Name != item {
+					t.Fatalf("ParseString() cookware = %v, want %q", step.Cookware, item)
+				}
+				if want := "Add " + item + separator + "and " + item; step.Directions != want {
+					t.Fatalf("ParseString() directions = %q, want %q", step.Directions, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseStringEmoji(t *testing.T) {
+	r, err := ParseString("Add some @🧂 and @🌶️{2}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Ingredient{
+		{Name: "🧂", Amount: IngredientAmount{Quantity: 1}},
+		{Name: "🌶️", Amount: IngredientAmount{true, 2, "2", ""}},
+	}
+	if !reflect.DeepEqual(r.Steps[0].Ingredients, want) {
+		t.Errorf("ParseString() ingredients = %v, want %v", r.Steps[0].Ingredients, want)
+	}
+}
+
+func TestTimerV2_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		timer TimerV2
+		want  string
+	}{
+		{"With duration", TimerV2{ItemTypeTimer, "", 10, "minutes"}, `{"type":"timer","quantity":10,"units":"minutes"}`},
+		{"Without unit", TimerV2{ItemTypeTimer, "", 5, ""}, `{"type":"timer","quantity":5}`},
+		{"Without duration", TimerV2{ItemTypeTimer, "rest", 0, ""}, `{"type":"timer","name":"rest","quantity":""}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.timer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("TimerV2.MarshalJSON() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package cooklang
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownReference is returned in strict mode for the references to
+// ingredients which are not mentioned before
+var ErrUnknownReference = errors.New("reference to an unknown ingredient")
+
+// ReferenceMode defines how the ingredients mentioned more than once are
+// read. A reference is marked with Ingredient.Reference and has the amount of
+// the first mention when it has no amount of its own, so the shopping lists
+// and the ingredient totals count the ingredient once.
+type ReferenceMode int
+
+const (
+	// ReferencesOff reads every mention as a separate ingredient, the &
+	// is part of the name (default)
+	ReferencesOff ReferenceMode = iota
+	// ReferencesExplicit reads @&flour as a reference to the earlier
+	// @flour{200%g}
+	ReferencesExplicit
+	// ReferencesImplicit also reads the mentions without amount of an
+	// earlier ingredient (@flour) as references
+	ReferencesImplicit
+)
+
+// resolveReference marks the references of the ingredient mentioned before
+// and copies the amount of the first mention to the references without one.
+// The other ingredients are recorded as the first mention of their name.
+func (d *document) resolveReference(t *tokenizer, config documentConfig, ingredient *Ingredient) error {
+	key := NormalizeName(ingredient.Name)
+	declared, ok := d.ingredients[key]
+	hasAmount := ingredient.Amount.IsPresent() || ingredient.Amount.Unit != ""
+	switch {
+	case ingredient.Reference && !ok:
+		if config.strict {
+			return fmt.Errorf("%w: %q", ErrUnknownReference, ingredient.Name)
+		}
+		t.warnings.add(WarningUnknownReference, t.start, "ingredient %q is not mentioned before", ingredient.Name)
+		ingredient.Reference = false
+	case !ingredient.Reference && ok && !hasAmount && config.references == ReferencesImplicit:
+		ingredient.Reference = true
+	}
+	if ingredient.Reference {
+		if !hasAmount {
+			ingredient.Amount = declared
+		}
+		return nil
+	}
+	if !ok {
+		if d.ingredients == nil {
+			d.ingredients = make(map[string]IngredientAmount)
+		}
+		d.ingredients[key] = ingredient.Amount
+	}
+	return nil
+}
+
+// appendDeclared appends the ingredients which are not references
+func appendDeclared(list []Ingredient, ingredients []Ingredient) []Ingredient {
+	for _, i := range ingredients {
+		if !i.Reference {
+			list = append(list, i)
+		}
+	}
+	return list
+}
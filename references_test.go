@@ -0,0 +1,115 @@
+package cooklang
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseReferences(t *testing.T) {
+	src := "Mix @flour{200%g} and @water{100%ml}.\nKnead the @&flour with @&water{50%ml} and @flour{}."
+	grams := IngredientAmount{true, 200, "200", "g"}
+	tests := []struct {
+		name       string
+		mode       ReferenceMode
+		step       []Ingredient
+		total      []Ingredient
+		directions string
+	}{
+		{
+			"Off",
+			ReferencesOff,
+			[]Ingredient{
+				{"&flour", IngredientAmount{false, 1, "", ""}, false, false, false, false},
+				{"&water", IngredientAmount{true, 50, "50", "ml"}, false, false, false, false},
+				{"flour", IngredientAmount{}, false, false, false, false},
+			},
+			nil,
+			"Knead the &flour with &water and flour.",
+		},
+		{
+			"Explicit",
+			ReferencesExplicit,
+			[]Ingredient{
+				{"flour", grams, true, false, false, false},
+				{"water", IngredientAmount{true, 50, "50", "ml"}, true, false, false, false},
+				{"flour", IngredientAmount{}, false, false, false, false},
+			},
+			[]Ingredient{
+				{"flour", grams, false, false, false, false},
+				{"water", IngredientAmount{true, 100, "100", "ml"}, false, false, false, false},
+				{"flour", IngredientAmount{}, false, false, false, false},
+			},
+			"Knead the flour with water and flour.",
+		},
+		{
+			"Implicit",
+			ReferencesImplicit,
+			[]Ingredient{
+				{"flour", grams, true, false, false, false},
+				{"water", IngredientAmount{true, 50, "50", "ml"}, true, false, false, false},
+				{"flour", grams, true, false, false, false},
+			},
+			[]Ingredient{
+				{"flour", grams, false, false, false, false},
+				{"water", IngredientAmount{true, 100, "100", "ml"}, false, false, false, false},
+			},
+			"Knead the flour with water and flour.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewParser(&ParseConfig{References: tt.mode}).ParseString(src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := r.Steps[1].Ingredients; !reflect.DeepEqual(got, tt.step) {
+				t.Errorf("step ingredients = %v, want %v", got, tt.step)
+			}
+			if tt.total != nil {
+				if got := r.AllIngredients(nil); !reflect.DeepEqual(got, tt.total) {
+					t.Errorf("AllIngredients() = %v, want %v", got, tt.total)
+				}
+			}
+			if got := r.Steps[1].Directions; got != tt.directions {
+				t.Errorf("directions = %q, want %q", got, tt.directions)
+			}
+		})
+	}
+}
+
+func TestParseUnknownReference(t *testing.T) {
+	src := "Season with @&Salt{}."
+	r, warnings, err := NewParser(&ParseConfig{References: ReferencesExplicit}).ParseStringWithWarnings(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Warning{{WarningUnknownReference, 1, 12, `ingredient "Salt" is not mentioned before`}}
+	if !reflect.DeepEqual(warnings, want) {
+		t.Errorf("warnings = %v, want %v", warnings, want)
+	}
+	if i := r.Steps[0].Ingredients[0]; i.Name != "Salt" || i.Reference {
+		t.Errorf("ingredient = %v, want a salt declaration", i)
+	}
+
+	_, err = NewParser(&ParseConfig{References: ReferencesExplicit, Strict: true}).ParseString(src)
+	if !errors.Is(err, ErrUnknownReference) {
+		t.Errorf("ParseString() strict error = %v, want ErrUnknownReference", err)
+	}
+
+	_, err = NewParser(&ParseConfig{References: ReferencesExplicit, Strict: true}).ParseString("Add @&{2}.")
+	if !errors.Is(err, ErrEmptyItem) {
+		t.Errorf("ParseString() empty reference error = %v, want ErrEmptyItem", err)
+	}
+}
+
+func TestParseV2References(t *testing.T) {
+	r, err := NewParserV2(&ParseV2Config{References: ReferencesExplicit}).ParseString("Melt @Crème fraîche{100%ml}.\nStir in the @&creme fraiche{}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := StepV2{TextV2{"text", "Stir in the "}, IngredientV2{"ingredient", "creme fraiche", 100, "ml", true, false, false, false}, TextV2{"text", "."}}
+	if !reflect.DeepEqual(r.Steps[1], want) {
+		t.Errorf("step = %v, want %v", r.Steps[1], want)
+	}
+}
@@ -0,0 +1,243 @@
+package render
+
+import (
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+const htmlTemplate = `<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+{{- range .Meta}}
+<meta {{if .Property}}property="{{.Property}}"{{else}}name="{{.Name}}"{{end}} content="{{.Content}}">
+{{- end}}
+</head>
+<body>
+<article class="recipe">
+{{- if .Title}}
+<h1>{{.Title}}</h1>
+{{- end}}
+{{- if .Cover}}
+<img class="recipe-image" src="{{.Cover}}" alt="{{.Title}}">
+{{- end}}
+{{- if .Metadata}}
+<dl class="metadata">
+{{- range .Metadata}}
+<dt>{{.Key}}</dt><dd>{{.Value}}</dd>
+{{- end}}
+</dl>
+{{- end}}
+{{- if .Ingredients}}
+<h2>{{.Labels.Ingredients}}</h2>
+<ul class="ingredients">
+{{- range .Ingredients}}
+<li>{{if .Amount}}<span class="amount">{{.Amount}}</span> {{end}}{{.Name}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .Allergens}}
+<p class="allergens">{{.Labels.Allergens}}: {{range $i, $a := .Allergens}}{{if $i}}, {{end}}<span title="{{$a.Ingredients}}">{{$a.Name}}</span>{{end}}</p>
+{{- end}}
+{{- if .Cookware}}
+<h2>{{.Labels.Cookware}}</h2>
+<ul class="cookware">
+{{- range .Cookware}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .Steps}}
+<h2>{{.Labels.Steps}}</h2>
+<ol class="steps">
+{{- range .Steps}}
+<li{{with .Source}} data-source-line="{{.Line}}" data-source-column="{{.Column}}"{{end}}{{range .Attributes}} data-{{.Key}}="{{.Value}}"{{end}}>
+<p>{{.Directions}}</p>
+{{- if .Image}}
+<img class="step-image" src="{{.Image}}" alt="{{$.Labels.Step}} {{.Number}}">
+{{- end}}
+</li>
+{{- end}}
+</ol>
+{{- end}}
+</article>
+</body>
+</html>
+`
+
+var recipeTemplate = template.Must(template.New("recipe").Parse(htmlTemplate))
+
+type htmlKeyValue struct {
+	Key   string
+	Value string
+}
+
+type htmlIngredient struct {
+	Name   string
+	Amount string
+}
+
+type htmlStep struct {
+	Number     int
+	Directions template.HTML
+	Image      string
+	Attributes []htmlKeyValue
+	Source     *cooklang.SourcePosition
+}
+
+// htmlMeta is a meta tag with either property (OpenGraph) or name
+// (Twitter)
+type htmlMeta struct {
+	Property string
+	Name     string
+	Content  string
+}
+
+type htmlAllergen struct {
+	Name        string
+	Ingredients string
+}
+
+type htmlLabels struct {
+	Ingredients string
+	Cookware    string
+	Steps       string
+	Step        string
+	Allergens   string
+}
+
+type htmlRecipe struct {
+	Lang        string
+	Labels      htmlLabels
+	Title       string
+	Meta        []htmlMeta
+	Cover       string
+	Metadata    []htmlKeyValue
+	Ingredients []htmlIngredient
+	Allergens   []htmlAllergen
+	Cookware    []string
+	Steps       []htmlStep
+}
+
+// HTML renders the recipe as a HTML document. The allergens of the
+// ingredients follow the ingredient list. The head contains the OpenGraph
+// and Twitter card tags of the title, the description (the description
+// metadata or the first step) and the cover image. The URLs in the
+// directions are links.
+func HTML(w io.Writer, r *cooklang.Recipe, opts *Options) error {
+	r = opts.scaled(r)
+	data := htmlRecipe{
+		Lang: opts.locale(),
+		Labels: htmlLabels{
+			Ingredients: opts.message(MsgIngredients),
+			Cookware:    opts.message(MsgCookware),
+			Steps:       opts.message(MsgSteps),
+			Step:        opts.message(MsgStep),
+			Allergens:   opts.message(MsgAllergens),
+		},
+		Title: r.Metadata[metadataTitle],
+	}
+	if r.Images != nil {
+		data.Cover = r.Images.Cover
+	}
+	data.Meta = socialMeta(r, opts)
+	for _, k := range r.MetadataKeys() {
+		if k != metadataTitle {
+			data.Metadata = append(data.Metadata, htmlKeyValue{k, r.Metadata[k]})
+		}
+	}
+	for _, ingredient := range collectIngredients(r) {
+		data.Ingredients = append(data.Ingredients, htmlIngredient{ingredient.Name, FormatAmount(ingredient.Amount, opts)})
+	}
+	for _, a := range opts.allergens().Allergens(r) {
+		name := Message(opts.locale(), AllergenMessageID(string(a.Name)), string(a.Name))
+		data.Allergens = append(data.Allergens, htmlAllergen{name, strings.Join(a.Ingredients, ", ")})
+	}
+	for _, c := range collectCookware(r) {
+		data.Cookware = append(data.Cookware, formatCookware(c))
+	}
+	for i, step := range r.Steps {
+		if step.IsComment() {
+			continue
+		}
+		s := htmlStep{
+			Source:     opts.stepSource(i),
+			Number:     len(data.Steps) + 1,
+			Directions: template.HTML(formatLinks(opts.localizeDirections(step), template.HTMLEscapeString, htmlLink)),
+			Image:      step.Image,
+		}
+		for _, k := range sortedKeys(step.Attributes) {
+			s.Attributes = append(s.Attributes, htmlKeyValue{k, formatAttribute(step.Attributes[k])})
+		}
+		data.Steps = append(data.Steps, s)
+	}
+	return recipeTemplate.Execute(w, data)
+}
+
+// maxDescriptionLength is the length the descriptions of the social media
+// previews are cut to
+const maxDescriptionLength = 200
+
+// socialMeta returns the OpenGraph and Twitter card tags of the recipe
+func socialMeta(r *cooklang.Recipe, opts *Options) []htmlMeta {
+	title := r.Metadata[metadataTitle]
+	description := r.Metadata[metadataDescription]
+	if description == "" {
+		for _, step := range r.Steps {
+			if step.Directions != "" {
+				description = step.Directions
+				break
+			}
+		}
+	}
+	description = truncate(strings.Join(strings.Fields(description), " "), maxDescriptionLength)
+	var image string
+	if r.Images != nil {
+		image = opts.absoluteURL(r.Images.Cover)
+	}
+	var meta []htmlMeta
+	add := func(property, name, content string) {
+		if content != "" {
+			meta = append(meta, htmlMeta{property, name, content})
+		}
+	}
+	add("og:type", "", "article")
+	add("og:title", "", title)
+	add("og:description", "", description)
+	add("og:image", "", image)
+	if opts != nil {
+		add("og:url", "", opts.URL)
+	}
+	card := "summary"
+	if image != "" {
+		card = "summary_large_image"
+	}
+	add("", "twitter:card", card)
+	add("", "twitter:title", title)
+	add("", "twitter:description", description)
+	add("", "twitter:image", image)
+	return meta
+}
+
+// truncate cuts s to at most n runes at a word boundary, adding an ellipsis
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	cut := string(runes[:n-1])
+	if i := strings.LastIndexByte(cut, ' '); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:") + "…"
+}
+
+// htmlLink returns the escaped link to the URL
+func htmlLink(url string) string {
+	url = template.HTMLEscapeString(url)
+	return `<a href="` + url + `">` + url + `</a>`
+}
@@ -0,0 +1,108 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+func TestHTML(t *testing.T) {
+	r := parseTestRecipe(t)
+	r.AttachImages(cooklang.RecipeImages{Steps: map[int]string{0: "Pizza.0.jpg"}})
+	r.Steps[0].Attributes = map[string]any{"difficulty": "<easy>"}
+	var b strings.Builder
+	if err := HTML(&b, r, nil); err != nil {
+		t.Fatalf("HTML() error = %v", err)
+	}
+	got := b.String()
+	for _, want := range []string{
+		"<h1>Pizza</h1>",
+		"<dt>servings</dt><dd>2</dd>",
+		`<li><span class="amount">200 g</span> flour</li>`,
+		"<li>oven</li>",
+		`<p class="allergens">Allergens: <span title="flour">gluten</span>, <span title="flour">wheat</span>`,
+		`<li data-difficulty="&lt;easy&gt;">`,
+		`<img class="step-image" src="Pizza.0.jpg" alt="Step 1">`,
+		"<p>Bake in the oven for 10 minutes.</p>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("HTML() missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestHTMLSocialMeta(t *testing.T) {
+	r := parseTestRecipe(t)
+	r.AttachImages(cooklang.RecipeImages{Cover: "Pizza.jpg"})
+	var b strings.Builder
+	if err := HTML(&b, r, &Options{URL: "https://example.com/recipes/Pizza.html"}); err != nil {
+		t.Fatalf("HTML() error = %v", err)
+	}
+	got := b.String()
+	for _, want := range []string{
+		`<meta property="og:title" content="Pizza">`,
+		`<meta property="og:description" content="Mix flour and water in a bowl.">`,
+		`<meta property="og:image" content="https://example.com/recipes/Pizza.jpg">`,
+		`<meta property="og:url" content="https://example.com/recipes/Pizza.html">`,
+		`<meta name="twitter:card" content="summary_large_image">`,
+		`<meta name="twitter:description" content="Mix flour and water in a bowl.">`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("HTML() missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestHTMLLinks(t *testing.T) {
+	r, err := cooklang.NewParser(&cooklang.ParseConfig{DetectLinks: true}).ParseString("Shape as in https://example.com/bread?a=1&b=2#shaping, <gently>.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := HTML(&b, r, nil); err != nil {
+		t.Fatalf("HTML() error = %v", err)
+	}
+	want := `<p>Shape as in <a href="https://example.com/bread?a=1&amp;b=2#shaping">https://example.com/bread?a=1&amp;b=2#shaping</a>, &lt;gently&gt;.</p>`
+	if got := b.String(); !strings.Contains(got, want) {
+		t.Errorf("HTML() missing %q in:\n%s", want, got)
+	}
+}
+
+func TestHTMLSourceMap(t *testing.T) {
+	r := parseTestRecipe(t)
+	m, err := cooklang.NewSourceMap(testRecipe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := HTML(&b, r, &Options{SourceMap: m}); err != nil {
+		t.Fatalf("HTML() error = %v", err)
+	}
+	got := b.String()
+	for _, want := range []string{
+		`<li data-source-line="4" data-source-column="1">`,
+		`<li data-source-line="7" data-source-column="1">`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("HTML() missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{"Mix the flour.", 20, "Mix the flour."},
+		{"Mix the flour, then knead the dough.", 18, "Mix the flour…"},
+		{"Knead", 3, "Kn…"},
+	}
+	for _, tt := range tests {
+		if got := truncate(tt.s, tt.n); got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package render
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+// DefaultLocale is the locale used when none is set or the requested one is
+// not registered
+const DefaultLocale = "en"
+
+// Message IDs used by the renderers
+const (
+	MsgMetadata    = "metadata"
+	MsgIngredients = "ingredients"
+	MsgCookware    = "cookware"
+	MsgSteps       = "steps"
+	MsgStep        = "step"
+	MsgAllergens   = "allergens"
+)
+
+// TimerUnitMessageID returns the message ID used for translating a timer unit
+func TimerUnitMessageID(unit string) string {
+	return "timer.unit." + strings.ToLower(unit)
+}
+
+// AllergenMessageID returns the message ID used for translating an allergen
+// name (see the classify package)
+func AllergenMessageID(name string) string {
+	return "allergen." + strings.ToLower(name)
+}
+
+// Catalog maps message IDs to localized messages
+type Catalog map[string]string
+
+var (
+	catalogsMu sync.RWMutex
+	catalogs   = map[string]Catalog{
+		"en": {
+			MsgMetadata:    "Metadata",
+			MsgIngredients: "Ingredients",
+			MsgCookware:    "Cookware",
+			MsgSteps:       "Steps",
+			MsgStep:        "Step",
+			MsgAllergens:   "Allergens",
+		},
+		"de": {
+			MsgMetadata:                      "Metadaten",
+			MsgIngredients:                   "Zutaten",
+			MsgCookware:                      "Küchengeräte",
+			MsgSteps:                         "Zubereitung",
+			MsgStep:                          "Schritt",
+			MsgAllergens:                     "Allergene",
+			AllergenMessageID("gluten"):      "Gluten",
+			AllergenMessageID("wheat"):       "Weizen",
+			AllergenMessageID("crustaceans"): "Krebstiere",
+			AllergenMessageID("eggs"):        "Eier",
+			AllergenMessageID("fish"):        "Fisch",
+			AllergenMessageID("peanuts"):     "Erdnüsse",
+			AllergenMessageID("soybeans"):    "Soja",
+			AllergenMessageID("milk"):        "Milch",
+			AllergenMessageID("tree nuts"):   "Schalenfrüchte",
+			AllergenMessageID("celery"):      "Sellerie",
+			AllergenMessageID("mustard"):     "Senf",
+			AllergenMessageID("sesame"):      "Sesam",
+			AllergenMessageID("sulphites"):   "Sulfite",
+			AllergenMessageID("lupin"):       "Lupinen",
+			AllergenMessageID("molluscs"):    "Weichtiere",
+			TimerUnitMessageID("second"):     "Sekunde",
+			TimerUnitMessageID("seconds"):    "Sekunden",
+			TimerUnitMessageID("minute"):     "Minute",
+			TimerUnitMessageID("minutes"):    "Minuten",
+			TimerUnitMessageID("hour"):       "Stunde",
+			TimerUnitMessageID("hours"):      "Stunden",
+			TimerUnitMessageID("day"):        "Tag",
+			TimerUnitMessageID("days"):       "Tage",
+		},
+		"es": {
+			MsgMetadata:                      "Metadatos",
+			MsgIngredients:                   "Ingredientes",
+			MsgCookware:                      "Utensilios",
+			MsgSteps:                         "Pasos",
+			MsgStep:                          "Paso",
+			MsgAllergens:                     "Alérgenos",
+			AllergenMessageID("gluten"):      "gluten",
+			AllergenMessageID("wheat"):       "trigo",
+			AllergenMessageID("crustaceans"): "crustáceos",
+			AllergenMessageID("eggs"):        "huevos",
+			AllergenMessageID("fish"):        "pescado",
+			AllergenMessageID("peanuts"):     "cacahuetes",
+			AllergenMessageID("soybeans"):    "soja",
+			AllergenMessageID("milk"):        "leche",
+			AllergenMessageID("tree nuts"):   "frutos de cáscara",
+			AllergenMessageID("celery"):      "apio",
+			AllergenMessageID("mustard"):     "mostaza",
+			AllergenMessageID("sesame"):      "sésamo",
+			AllergenMessageID("sulphites"):   "sulfitos",
+			AllergenMessageID("lupin"):       "altramuces",
+			AllergenMessageID("molluscs"):    "moluscos",
+			TimerUnitMessageID("second"):     "segundo",
+			TimerUnitMessageID("seconds"):    "segundos",
+			TimerUnitMessageID("minute"):     "minuto",
+			TimerUnitMessageID("minutes"):    "minutos",
+			TimerUnitMessageID("hour"):       "hora",
+			TimerUnitMessageID("hours"):      "horas",
+			TimerUnitMessageID("day"):        "día",
+			TimerUnitMessageID("days"):       "días",
+		},
+	}
+)
+
+// RegisterCatalog adds the messages to the catalog of the locale, creating
+// the catalog if it does not exist. Existing messages are overwritten.
+func RegisterCatalog(locale string, messages Catalog) {
+	catalogsMu.Lock()
+	defer catalogsMu.Unlock()
+	locale = strings.ToLower(locale)
+	c, ok := catalogs[locale]
+	if !ok {
+		c = make(Catalog, len(messages))
+		catalogs[locale] = c
+	}
+	for id, message := range messages {
+		c[id] = message
+	}
+}
+
+// Message returns the localized message for the locale. Regional locales
+// ("de-AT") fall back to the language ("de"), then to DefaultLocale and
+// finally the fallback is returned.
+func Message(locale, id, fallback string) string {
+	catalogsMu.RLock()
+	defer catalogsMu.RUnlock()
+	locale = strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+	language, _, _ := strings.Cut(locale, "-")
+	for _, l := range []string{locale, language, DefaultLocale} {
+		if message, ok := catalogs[l][id]; ok {
+			return message
+		}
+	}
+	return fallback
+}
+
+func (o *Options) locale() string {
+	if o == nil || o.Locale == "" {
+		return DefaultLocale
+	}
+	return o.Locale
+}
+
+func (o *Options) message(id string) string {
+	return Message(o.locale(), id, id)
+}
+
+func (o *Options) localizeDirections(step cooklang.Step) string {
+	unit := ""
+	if o != nil && len(step.Temperatures) > 0 {
+		unit = o.TemperatureUnit
+	}
+	return localizeDirections(o.locale(), unit, step)
+}
+
+// LocalizeDirections returns the step directions with the timer units
+// replaced by their localized names
+func LocalizeDirections(locale string, step cooklang.Step) string {
+	return localizeDirections(locale, "", step)
+}
+
+// localizeDirections assembles the step directions from the step items, so
+// the names of the other items are never changed: the timer units are
+// localized and the temperatures in the text are converted to the
+// temperature unit when it is set. The item positions are recovered from the
+// directions as by cooklang.Recipe.ToV2.
+func localizeDirections(locale, temperatureUnit string, step cooklang.Step) string {
+	r := &cooklang.Recipe{Steps: []cooklang.Step{step}}
+	items := r.ToV2().Steps[0]
+	var b strings.Builder
+	for _, item := range items {
+		switch v := item.(type) {
+		case cooklang.TextV2:
+			if temperatureUnit != "" {
+				b.WriteString(cooklang.ConvertTemperatures(v.Value, temperatureUnit))
+			} else {
+				b.WriteString(v.Value)
+			}
+		case cooklang.TimerV2:
+			if !v.HasDuration() {
+				b.WriteString(v.Name)
+				continue
+			}
+			b.WriteString(strconv.FormatFloat(v.Quantity, 'g', -1, 64))
+			if v.Unit != "" {
+				b.WriteString(" " + Message(locale, TimerUnitMessageID(v.Unit), v.Unit))
+			}
+		default:
+			b.WriteString(cooklang.StepV2{item}.Directions())
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,71 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+func TestMessage(t *testing.T) {
+	tests := []struct {
+		name   string
+		locale string
+		id     string
+		want   string
+	}{
+		{"English", "en", MsgIngredients, "Ingredients"},
+		{"German", "de", MsgIngredients, "Zutaten"},
+		{"Regional locale", "es_MX", MsgSteps, "Pasos"},
+		{"Unknown locale", "xx", MsgCookware, "Cookware"},
+		{"Unknown message", "de", "unknown", "fallback"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Message(tt.locale, tt.id, "fallback"); got != tt.want {
+				t.Errorf("Message() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterCatalog(t *testing.T) {
+	RegisterCatalog("bg", Catalog{MsgIngredients: "Съставки", TimerUnitMessageID("minutes"): "минути"})
+	r, err := cooklang.ParseString("Bake @potato for ~{10%minutes}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := Markdown(&b, r, &Options{Locale: "bg"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"## Съставки", "## Steps", "1. Bake potato for 10 минути."} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("Markdown() missing %q in:\n%s", want, b.String())
+		}
+	}
+}
+
+func TestLocalizeDirections(t *testing.T) {
+	tests := []struct {
+		name string
+		unit string
+		src  string
+		want string
+	}{
+		{"Timer units", "", "Add the @10 minutes rice{}, cook ~{10%minutes} and ~rest.", "Add the 10 minutes rice, cook 10 Minuten and rest."},
+		{"Temperatures", "F", "Heat the #180°C pan{} to 180°C for ~{2%minutes}.", "Heat the 180°C pan to 356°F for 2 Minuten."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := cooklang.NewParser(&cooklang.ParseConfig{DetectTemperatures: true}).ParseString(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			opts := &Options{Locale: "de", TemperatureUnit: tt.unit}
+			if got := opts.localizeDirections(r.Steps[0]); got != tt.want {
+				t.Errorf("localizeDirections() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
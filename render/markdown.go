@@ -0,0 +1,100 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+// Markdown renders the recipe as a Markdown document. The URLs in the
+// directions are written as autolinks.
+func Markdown(w io.Writer, r *cooklang.Recipe, opts *Options) error {
+	s, _ := markdown(r, opts)
+	_, err := io.WriteString(w, s)
+	return err
+}
+
+// MarkdownSourceMap renders the recipe like Markdown and returns the source
+// positions of the rendered steps (see Options.SourceMap) keyed by their one
+// based line number in the output
+func MarkdownSourceMap(w io.Writer, r *cooklang.Recipe, opts *Options) (map[int]cooklang.SourcePosition, error) {
+	s, lines := markdown(r, opts)
+	_, err := io.WriteString(w, s)
+	return lines, err
+}
+
+// markdown returns the Markdown document and the source positions of its
+// step lines
+func markdown(r *cooklang.Recipe, opts *Options) (string, map[int]cooklang.SourcePosition) {
+	r = opts.scaled(r)
+	lines := make(map[int]cooklang.SourcePosition)
+	var b strings.Builder
+	title := r.Metadata[metadataTitle]
+	if title != "" {
+		fmt.Fprintf(&b, "# %s\n\n", title)
+	}
+	if r.Images != nil && r.Images.Cover != "" {
+		fmt.Fprintf(&b, "![%s](<%s>)\n\n", title, r.Images.Cover)
+	}
+	metadata := 0
+	for _, k := range r.MetadataKeys() {
+		if k == metadataTitle {
+			continue
+		}
+		fmt.Fprintf(&b, "- **%s**: %s\n", k, r.Metadata[k])
+		metadata++
+	}
+	if metadata > 0 {
+		b.WriteString("\n")
+	}
+	if ingredients := collectIngredients(r); len(ingredients) > 0 {
+		fmt.Fprintf(&b, "## %s\n\n", opts.message(MsgIngredients))
+		for _, ingredient := range ingredients {
+			if amount := FormatAmount(ingredient.Amount, opts); amount != "" {
+				fmt.Fprintf(&b, "- %s %s\n", amount, ingredient.Name)
+			} else {
+				fmt.Fprintf(&b, "- %s\n", ingredient.Name)
+			}
+		}
+		b.WriteString("\n")
+	}
+	if cookware := collectCookware(r); len(cookware) > 0 {
+		fmt.Fprintf(&b, "## %s\n\n", opts.message(MsgCookware))
+		for _, c := range cookware {
+			fmt.Fprintf(&b, "- %s\n", formatCookware(c))
+		}
+		b.WriteString("\n")
+	}
+	number := 0
+	for i, step := range r.Steps {
+		if step.IsComment() {
+			continue
+		}
+		if number == 0 {
+			fmt.Fprintf(&b, "## %s\n\n", opts.message(MsgSteps))
+		}
+		number++
+		if source := opts.stepSource(i); source != nil {
+			lines[strings.Count(b.String(), "\n")+1] = *source
+		}
+		fmt.Fprintf(&b, "%d. %s\n", number, formatLinks(opts.localizeDirections(step), markdownText, markdownLink))
+		if step.Image != "" {
+			fmt.Fprintf(&b, "\n   ![%s %d](<%s>)\n\n", opts.message(MsgStep), number, step.Image)
+		}
+		for _, k := range sortedKeys(step.Attributes) {
+			fmt.Fprintf(&b, "   - %s: %s\n", k, formatAttribute(step.Attributes[k]))
+		}
+	}
+	return b.String(), lines
+}
+
+func markdownText(s string) string {
+	return s
+}
+
+// markdownLink returns the URL as an autolink
+func markdownLink(url string) string {
+	return "<" + url + ">"
+}
@@ -0,0 +1,144 @@
+package render
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+const testRecipe = `>> title: Pizza
+>> servings: 2
+
+Mix @flour{200%g} and @water{1/2%cup} in a #bowl{}.
+
+-- rest
+Bake in the #oven for ~{10%minutes}.`
+
+func parseTestRecipe(t *testing.T) *cooklang.Recipe {
+	t.Helper()
+	r, err := cooklang.ParseString(testRecipe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestMarkdown(t *testing.T) {
+	r := parseTestRecipe(t)
+	r.AttachImages(cooklang.RecipeImages{Cover: "Pizza.jpg", Steps: map[int]string{1: "Pizza.1.jpg"}})
+	r.Steps[0].Attributes = map[string]any{"difficulty": "easy"}
+	var b strings.Builder
+	if err := Markdown(&b, r, nil); err != nil {
+		t.Fatalf("Markdown() error = %v", err)
+	}
+	want := `# Pizza
+
+![Pizza](<Pizza.jpg>)
+
+- **servings**: 2
+
+## Ingredients
+
+- 200 g flour
+- 0.5 cup water
+
+## Cookware
+
+- bowl
+- oven
+
+## Steps
+
+1. Mix flour and water in a bowl.
+   - difficulty: easy
+2. Bake in the oven for 10 minutes.
+
+   ![Step 2](<Pizza.1.jpg>)
+
+`
+	if got := b.String(); got != want {
+		t.Errorf("Markdown() = %q, want %q", got, want)
+	}
+}
+
+func TestMarkdownTemperatureUnit(t *testing.T) {
+	r, err := cooklang.NewParser(&cooklang.ParseConfig{DetectTemperatures: true}).ParseString("Bake at 180°C, then at 200°C or 400F.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := Markdown(&b, r, &Options{TemperatureUnit: "F"}); err != nil {
+		t.Fatalf("Markdown() error = %v", err)
+	}
+	want := "## Steps\n\n1. Bake at 356°F, then at 392°F or 400F.\n"
+	if got := b.String(); got != want {
+		t.Errorf("Markdown() = %q, want %q", got, want)
+	}
+}
+
+func TestMarkdownLinks(t *testing.T) {
+	r, err := cooklang.NewParser(&cooklang.ParseConfig{DetectLinks: true}).ParseString("Shape the @dough{} (see https://example.com/bread#shaping).")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := Markdown(&b, r, nil); err != nil {
+		t.Fatalf("Markdown() error = %v", err)
+	}
+	want := "1. Shape the dough (see <https://example.com/bread#shaping>).\n"
+	if got := b.String(); !strings.HasSuffix(got, want) {
+		t.Errorf("Markdown() = %q, want suffix %q", got, want)
+	}
+}
+
+func TestMarkdownSourceMap(t *testing.T) {
+	r := parseTestRecipe(t)
+	m, err := cooklang.NewSourceMap(testRecipe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	got, err := MarkdownSourceMap(&b, r, &Options{SourceMap: m})
+	if err != nil {
+		t.Fatalf("MarkdownSourceMap() error = %v", err)
+	}
+	want := map[int]cooklang.SourcePosition{17: {Line: 4, Column: 1}, 18: {Line: 7, Column: 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MarkdownSourceMap() = %v, want %v", got, want)
+	}
+	if lines := strings.Split(b.String(), "\n"); lines[16] != "1. Mix flour and water in a bowl." {
+		t.Errorf("MarkdownSourceMap() line 17 = %q", lines[16])
+	}
+}
+
+func TestMarkdownScale(t *testing.T) {
+	r, err := cooklang.ParseString(">> servings: 2\nMix @flour{200%g} and @salt{a pinch}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := Markdown(&b, r, &Options{Scale: 1.5}); err != nil {
+		t.Fatalf("Markdown() error = %v", err)
+	}
+	want := "- **servings**: 3\n\n## Ingredients\n\n- 300 g flour\n- a pinch salt\n\n## Steps\n\n1. Mix flour and salt.\n"
+	if got := b.String(); got != want {
+		t.Errorf("Markdown() = %q, want %q", got, want)
+	}
+}
+
+func TestMarkdownDualUnits(t *testing.T) {
+	r, err := cooklang.ParseString("Mix @flour{820%g}, @milk{2%cups}, @eggs{2} and @salt{a pinch}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := Markdown(&b, r, &Options{DualUnits: true}); err != nil {
+		t.Fatalf("Markdown() error = %v", err)
+	}
+	want := "## Ingredients\n\n- 820 g (1.8 lb) flour\n- 2 cups (473.2 ml) milk\n- 2 eggs\n- a pinch salt\n\n## Steps\n\n1. Mix flour, milk, eggs and salt.\n"
+	if got := b.String(); got != want {
+		t.Errorf("Markdown() = %q, want %q", got, want)
+	}
+}
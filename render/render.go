@@ -0,0 +1,178 @@
+// Package render renders parsed cooklang recipes in human readable formats
+package render
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/classify"
+	"github.com/aquilax/cooklang-go/units"
+)
+
+const (
+	metadataTitle       = "title"
+	metadataDescription = "description"
+)
+
+// Options contains the rendering options
+type Options struct {
+	Locale          string // locale of the rendered labels and timer units (default: DefaultLocale)
+	TemperatureUnit string // convert the temperatures in the directions (see cooklang.ParseConfig.DetectTemperatures) to units.Celsius or units.Fahrenheit
+	// DualUnits adds the ingredient amounts converted to the other
+	// measurement system ("820 g (1.8 lb)")
+	DualUnits bool
+	// Scale multiplies the numeric ingredient quantities and the servings
+	// (see cooklang.Scale). Zero keeps the recipe as written.
+	Scale float64
+	// Rounding rounds the scaled quantities, nil uses
+	// cooklang.DefaultRoundingTable and an empty table keeps the exact
+	// quantities
+	Rounding cooklang.RoundingTable
+	// Allergens is the dictionary of the allergens listed in the HTML
+	// output, nil uses classify.DefaultAllergens
+	Allergens classify.AllergenDictionary
+	// URL is the address of the rendered HTML page for the og:url tag. The
+	// relative image paths are resolved against it.
+	URL string
+	// SourceMap links the rendered steps to the recipe source: the HTML
+	// steps get data-source-line and data-source-column attributes and
+	// MarkdownSourceMap returns the source of the Markdown lines
+	SourceMap *cooklang.SourceMap
+}
+
+// absoluteURL resolves the reference against the URL option. Without the
+// option or for invalid URLs the reference is returned as it is.
+func (o *Options) absoluteURL(ref string) string {
+	if o == nil || o.URL == "" || ref == "" {
+		return ref
+	}
+	base, err := url.Parse(o.URL)
+	if err != nil {
+		return ref
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return base.ResolveReference(u).String()
+}
+
+// stepSource returns the source position of the step with the index of
+// Recipe.Steps
+func (o *Options) stepSource(index int) *cooklang.SourcePosition {
+	if o == nil {
+		return nil
+	}
+	if s, ok := o.SourceMap.Step(index); ok {
+		return &s.SourcePosition
+	}
+	return nil
+}
+
+func (o *Options) allergens() classify.AllergenDictionary {
+	if o == nil || o.Allergens == nil {
+		return classify.DefaultAllergens
+	}
+	return o.Allergens
+}
+
+// scaled returns the recipe scaled by the Scale option
+func (o *Options) scaled(r *cooklang.Recipe) *cooklang.Recipe {
+	if o == nil || o.Scale <= 0 || o.Scale == 1 {
+		return r
+	}
+	rounding := o.Rounding
+	if rounding == nil {
+		rounding = cooklang.DefaultRoundingTable
+	}
+	return cooklang.ScaleRounded(r, o.Scale, rounding)
+}
+
+func formatFloat(num float64, precision int) string {
+	fs := fmt.Sprintf("%%.%df", precision)
+	s := fmt.Sprintf(fs, num)
+	return strings.TrimRight(strings.TrimRight(s, "0"), ".")
+}
+
+// FormatAmount returns the quantity and unit of the amount. With the
+// DualUnits option the numeric amounts of the known units are followed by
+// the amount in the other measurement system.
+func FormatAmount(amount cooklang.IngredientAmount, opts *Options) string {
+	quantity := amount.QuantityRaw
+	if amount.IsNumeric {
+		quantity = formatFloat(amount.Quantity, 2)
+	}
+	result := strings.TrimSpace(quantity + " " + amount.Unit)
+	if opts == nil || !opts.DualUnits || !amount.IsNumeric {
+		return result
+	}
+	u, ok := units.Lookup(amount.Unit)
+	if !ok {
+		return result
+	}
+	system := units.Imperial
+	if u.System == units.Imperial {
+		system = units.Metric
+	}
+	value, unit, err := units.ConvertToSystem(amount.Quantity, amount.Unit, system)
+	if err != nil {
+		return result
+	}
+	converted := formatFloat(value, 1)
+	if converted == "0" {
+		converted = formatFloat(value, 2)
+	}
+	return fmt.Sprintf("%s (%s %s)", result, converted, unit)
+}
+
+func formatCookware(c cooklang.Cookware) string {
+	if c.IsNumeric && c.Quantity != 1 {
+		return formatFloat(c.Quantity, 2) + " " + c.Name
+	}
+	if !c.IsNumeric && c.QuantityRaw != "" {
+		return c.QuantityRaw + " " + c.Name
+	}
+	return c.Name
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func formatAttribute(v any) string {
+	return fmt.Sprint(v)
+}
+
+func collectIngredients(r *cooklang.Recipe) []cooklang.Ingredient {
+	return r.AllIngredients(nil)
+}
+
+func collectCookware(r *cooklang.Recipe) []cooklang.Cookware {
+	var result []cooklang.Cookware
+	for i := range r.Steps {
+		result = append(result, r.Steps[i].Cookware...)
+	}
+	return result
+}
+
+// formatLinks returns the directions with the URLs (see cooklang.FindLinks)
+// formatted by link and the text between them by text
+func formatLinks(directions string, text, link func(string) string) string {
+	var b strings.Builder
+	for _, url := range cooklang.FindLinks(directions) {
+		i := strings.Index(directions, url)
+		b.WriteString(text(directions[:i]))
+		b.WriteString(link(url))
+		directions = directions[i+len(url):]
+	}
+	b.WriteString(text(directions))
+	return b.String()
+}
@@ -0,0 +1,46 @@
+package render
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+// SitemapURL is a page of the sitemap
+type SitemapURL struct {
+	Loc     string    // absolute URL of the page
+	LastMod time.Time // time of the last change, zero omits it
+}
+
+type xmlSitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type xmlSitemap struct {
+	XMLName xml.Name        `xml:"urlset"`
+	XMLNS   string          `xml:"xmlns,attr"`
+	URLs    []xmlSitemapURL `xml:"url"`
+}
+
+// Sitemap writes the sitemap.xml (https://www.sitemaps.org) of the pages
+func Sitemap(w io.Writer, urls []SitemapURL) error {
+	sitemap := xmlSitemap{XMLNS: "http://www.sitemaps.org/schemas/sitemap/0.9"}
+	for _, u := range urls {
+		s := xmlSitemapURL{Loc: u.Loc}
+		if !u.LastMod.IsZero() {
+			s.LastMod = u.LastMod.UTC().Format("2006-01-02")
+		}
+		sitemap.URLs = append(sitemap.URLs, s)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(sitemap); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
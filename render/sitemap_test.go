@@ -0,0 +1,32 @@
+package render
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSitemap(t *testing.T) {
+	var b strings.Builder
+	err := Sitemap(&b, []SitemapURL{
+		{"https://example.com/pizza.html", time.Date(2024, 3, 1, 23, 0, 0, 0, time.UTC)},
+		{"https://example.com/soups/tomato%20soup.html", time.Time{}},
+	})
+	if err != nil {
+		t.Fatalf("Sitemap() error = %v", err)
+	}
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url>
+    <loc>https://example.com/pizza.html</loc>
+    <lastmod>2024-03-01</lastmod>
+  </url>
+  <url>
+    <loc>https://example.com/soups/tomato%20soup.html</loc>
+  </url>
+</urlset>
+`
+	if got := b.String(); got != want {
+		t.Errorf("Sitemap() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,87 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/graph"
+)
+
+var mermaidText = strings.NewReplacer(":", " ", ";", " ", "#", " ", "\n", " ")
+
+// Timeline renders the recipe timers as a Mermaid Gantt chart. Every step
+// starts when the steps it depends on (see graph.Build) are finished, or
+// after the previous step when it has no dependencies, so the steps which
+// can run in parallel overlap in the chart. The timers of a step run one
+// after another. Steps without timers take no time and are not drawn.
+func Timeline(w io.Writer, r *cooklang.Recipe, opts *Options) error {
+	var b strings.Builder
+	b.WriteString("gantt\n")
+	if title := r.Metadata[metadataTitle]; title != "" {
+		fmt.Fprintf(&b, "    title %s\n", mermaidText.Replace(title))
+	}
+	b.WriteString("    dateFormat X\n    axisFormat %H:%M\n")
+
+	g := graph.Build(r)
+	steps := make(map[string]int)
+	for _, n := range g.Nodes {
+		if n.Step != -1 {
+			steps[n.ID] = n.Step
+		}
+	}
+	depends := make(map[int][]int)
+	for _, e := range g.Edges {
+		// recipe references take no time in this recipe
+		if from, ok := steps[e.From]; ok {
+			depends[steps[e.To]] = append(depends[steps[e.To]], from)
+		}
+	}
+	finish := make(map[int]time.Duration)
+	previous := -1
+	number := 0
+	for i, step := range r.Steps {
+		if step.IsComment() {
+			continue
+		}
+		number++
+		var start time.Duration
+		if deps, ok := depends[i]; ok {
+			for _, d := range deps {
+				start = max(start, finish[d])
+			}
+		} else if previous != -1 {
+			start = finish[previous]
+		}
+		end := start
+		section := false
+		for j, timer := range step.Timers {
+			d, ok := timer.ToDuration()
+			if !ok {
+				continue
+			}
+			if !section {
+				fmt.Fprintf(&b, "    section %d. %s\n", number, mermaidText.Replace(opts.localizeDirections(step)))
+				section = true
+			}
+			fmt.Fprintf(&b, "    %s :s%dt%d, %d, %ds\n", mermaidText.Replace(timerLabel(timer, opts)), i, j, int64(end/time.Second), int64(d/time.Second))
+			end += d
+		}
+		finish[i] = end
+		previous = i
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func timerLabel(timer cooklang.Timer, opts *Options) string {
+	unit := Message(opts.locale(), TimerUnitMessageID(timer.Unit), timer.Unit)
+	label := strings.TrimSpace(strconv.FormatFloat(timer.Duration, 'g', -1, 64) + " " + unit)
+	if timer.Name != "" {
+		return timer.Name + " (" + label + ")"
+	}
+	return label
+}
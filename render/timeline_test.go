@@ -0,0 +1,39 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+func TestTimeline(t *testing.T) {
+	r, err := cooklang.ParseString(`>> title: Eggs: Benedict
+Boil @water in a #pot{} for ~{10%minutes}.
+-- meanwhile
+Toast @bread in the #oven{} for ~toast{3%minutes}.
+Poach @eggs{2} in the #pot{} for ~{3%minutes} and rest ~{30%seconds}.
+Serve.`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := Timeline(&b, r, &Options{Locale: "de"}); err != nil {
+		t.Fatalf("Timeline() error = %v", err)
+	}
+	want := `gantt
+    title Eggs  Benedict
+    dateFormat X
+    axisFormat %H:%M
+    section 1. Boil water in a pot for 10 Minuten.
+    10 Minuten :s0t0, 0, 600s
+    section 2. Toast bread in the oven for 3 Minuten.
+    toast (3 Minuten) :s2t0, 600, 180s
+    section 3. Poach eggs in the pot for 3 Minuten and rest 30 Sekunden.
+    3 Minuten :s3t0, 600, 180s
+    30 Sekunden :s3t1, 780, 30s
+`
+	if got := b.String(); got != want {
+		t.Errorf("Timeline() = %s, want %s", got, want)
+	}
+}
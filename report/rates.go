@@ -0,0 +1,91 @@
+package report
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/ingredients"
+	"github.com/aquilax/cooklang-go/units"
+)
+
+// ErrInvalidRate is returned by LoadRates for the malformed lines
+var ErrInvalidRate = errors.New("invalid rate")
+
+// Rate is the value (price, calories) of a quantity of an ingredient
+type Rate struct {
+	Quantity float64
+	Unit     string // empty for the counted ingredients
+	Value    float64
+}
+
+// Rates maps the ingredient names to their rates, used for the prices and
+// the calories of the ingredients
+type Rates map[string]Rate
+
+// LoadRates reads the rates written one per line as "name: quantity [unit] =
+// value":
+//
+//	# prices
+//	flour: 1 kg = 1.20
+//	eggs: 12 = 3.50
+//
+// Empty lines and lines starting with # are skipped.
+func LoadRates(r io.Reader) (Rates, error) {
+	rates := make(Rates)
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, rest, ok := strings.Cut(line, ":")
+		amount, value, ok2 := strings.Cut(rest, "=")
+		fields := strings.Fields(amount)
+		if !ok || !ok2 || strings.TrimSpace(name) == "" || len(fields) == 0 {
+			return nil, fmt.Errorf("%w: line %d: %q", ErrInvalidRate, n, line)
+		}
+		var rate Rate
+		var err error
+		if rate.Quantity, err = strconv.ParseFloat(fields[0], 64); err != nil || rate.Quantity <= 0 {
+			return nil, fmt.Errorf("%w: line %d: quantity %q", ErrInvalidRate, n, fields[0])
+		}
+		rate.Unit = strings.Join(fields[1:], " ")
+		if rate.Value, err = strconv.ParseFloat(strings.TrimSpace(value), 64); err != nil {
+			return nil, fmt.Errorf("%w: line %d: value %q", ErrInvalidRate, n, strings.TrimSpace(value))
+		}
+		rates[rateKey(name)] = rate
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+// rateKey is the lower case singular name of the ingredient
+func rateKey(name string) string {
+	return ingredients.Singular(cooklang.NormalizeName(name))
+}
+
+// Value returns the value of the ingredient amount. It returns false for the
+// ingredients without rate, textual quantities and units which can not be
+// converted to the unit of the rate.
+func (r Rates) Value(i cooklang.Ingredient) (float64, bool) {
+	rate, ok := r[rateKey(i.Name)]
+	if !ok || !i.Amount.IsNumeric {
+		return 0, false
+	}
+	quantity := i.Amount.Quantity
+	if !strings.EqualFold(strings.TrimSpace(i.Amount.Unit), rate.Unit) {
+		converted, err := units.Convert(quantity, i.Amount.Unit, rate.Unit)
+		if err != nil {
+			return 0, false
+		}
+		quantity = converted
+	}
+	return quantity / rate.Quantity * rate.Value, true
+}
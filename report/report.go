@@ -0,0 +1,246 @@
+// Package report summarizes meal plans: the cost and calories of every day
+// and the shopping list of the week
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/export"
+	"github.com/aquilax/cooklang-go/ingredients"
+	"github.com/aquilax/cooklang-go/render"
+	"github.com/aquilax/cooklang-go/shoppinglist"
+)
+
+// Options are the data of the reports
+type Options struct {
+	Prices   Rates  // prices of the ingredients
+	Calories Rates  // calories (kcal) of the ingredients
+	Currency string // written before the prices ("€")
+	Merge    ingredients.MergeOptions
+}
+
+// Day is the summary of the meals of a day
+type Day struct {
+	Date  time.Time // midnight of the day in the location of the meals
+	Meals []string  // names of the meals
+	Cost  float64
+	// Calories is the sum of the calories of a serving of every meal (see
+	// cooklang.Servings), the recipes without servings are one serving
+	Calories float64
+}
+
+// Item is a shopping list item and its price
+type Item struct {
+	cooklang.Ingredient
+	Cost   float64
+	Priced bool // false for the items without price
+}
+
+// Report is the summary of a meal plan
+type Report struct {
+	Name         string
+	Days         []Day
+	Cost         float64 // total cost of the meals
+	ShoppingList []Item
+	Unpriced     []string // ingredients without price
+	Uncounted    []string // ingredients without calories
+	currency     string
+}
+
+// mealName returns the name of the meal, the recipe title by default
+func mealName(meal export.Meal) string {
+	if meal.Name != "" {
+		return meal.Name
+	}
+	if title := meal.Recipe.Metadata[export.MetadataTitle]; title != "" {
+		return title
+	}
+	return "Meal"
+}
+
+// New returns the report of the meal plan. The days are sorted by date.
+func New(plan export.MealPlan, opts Options) *Report {
+	report := &Report{Name: plan.Name, currency: opts.Currency}
+	days := make(map[time.Time]int)
+	unpriced := make(map[string]bool)
+	uncounted := make(map[string]bool)
+	var recipes []*cooklang.Recipe
+	for _, meal := range plan.Meals {
+		y, m, d := meal.Time.Date()
+		date := time.Date(y, m, d, 0, 0, 0, 0, meal.Time.Location())
+		i, ok := days[date]
+		if !ok {
+			i = len(report.Days)
+			days[date] = i
+			report.Days = append(report.Days, Day{Date: date})
+		}
+		day := &report.Days[i]
+		day.Meals = append(day.Meals, mealName(meal))
+		servings, err := cooklang.Servings(meal.Recipe)
+		if err != nil {
+			servings = 1
+		}
+		for _, ingredient := range meal.Recipe.AllIngredients(nil) {
+			if cost, ok := opts.Prices.Value(ingredient); ok {
+				day.Cost += cost
+			} else {
+				unpriced[ingredient.Name] = true
+			}
+			if kcal, ok := opts.Calories.Value(ingredient); ok {
+				day.Calories += kcal / servings
+			} else {
+				uncounted[ingredient.Name] = true
+			}
+		}
+		recipes = append(recipes, meal.Recipe)
+	}
+	slices.SortStableFunc(report.Days, func(a, b Day) int {
+		return a.Date.Compare(b.Date)
+	})
+	for _, day := range report.Days {
+		report.Cost += day.Cost
+	}
+	for _, ingredient := range shoppinglist.New(opts.Merge, recipes...) {
+		cost, ok := opts.Prices.Value(ingredient)
+		report.ShoppingList = append(report.ShoppingList, Item{ingredient, cost, ok})
+	}
+	report.Unpriced = sortedKeys(unpriced)
+	report.Uncounted = sortedKeys(uncounted)
+	return report
+}
+
+func sortedKeys(m map[string]bool) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// title returns the heading of the report
+func (r *Report) title() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return "Meal plan"
+}
+
+// price formats the price with the currency
+func (r *Report) price(f float64) string {
+	return r.currency + strconv.FormatFloat(f, 'f', 2, 64)
+}
+
+func formatCalories(f float64) string {
+	return strconv.FormatFloat(f, 'f', 0, 64) + " kcal"
+}
+
+// formatDay formats the date of the day ("Mon 2026-10-12")
+func formatDay(t time.Time) string {
+	return t.Format("Mon 2006-01-02")
+}
+
+// itemText returns the amount and the name of the shopping list item
+func itemText(i Item) string {
+	return strings.TrimSpace(render.FormatAmount(i.Amount, nil) + " " + i.Name)
+}
+
+// Markdown writes the report as Markdown
+func (r *Report) Markdown(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", r.title())
+	b.WriteString("| Day | Meals | Cost | Calories |\n|---|---|---:|---:|\n")
+	for _, day := range r.Days {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", formatDay(day.Date), strings.Join(day.Meals, ", "), r.price(day.Cost), formatCalories(day.Calories))
+	}
+	fmt.Fprintf(&b, "\n**Total cost:** %s\n", r.price(r.Cost))
+	if len(r.ShoppingList) > 0 {
+		b.WriteString("\n## Shopping list\n\n")
+		for _, item := range r.ShoppingList {
+			if item.Priced {
+				fmt.Fprintf(&b, "- [ ] %s (%s)\n", itemText(item), r.price(item.Cost))
+			} else {
+				fmt.Fprintf(&b, "- [ ] %s\n", itemText(item))
+			}
+		}
+	}
+	if len(r.Unpriced) > 0 {
+		fmt.Fprintf(&b, "\nWithout price: %s\n", strings.Join(r.Unpriced, ", "))
+	}
+	if len(r.Uncounted) > 0 {
+		fmt.Fprintf(&b, "\nWithout calories: %s\n", strings.Join(r.Uncounted, ", "))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+const htmlReport = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table class="days">
+<tr><th>Day</th><th>Meals</th><th>Cost</th><th>Calories</th></tr>
+{{- range .Days}}
+<tr><td>{{.Date}}</td><td>{{.Meals}}</td><td>{{.Cost}}</td><td>{{.Calories}}</td></tr>
+{{- end}}
+</table>
+<p class="total">Total cost: {{.Cost}}</p>
+{{- if .Items}}
+<h2>Shopping list</h2>
+<ul class="shopping-list">
+{{- range .Items}}
+<li>{{.Text}}{{with .Cost}} <span class="cost">{{.}}</span>{{end}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- with .Unpriced}}
+<p class="unpriced">Without price: {{.}}</p>
+{{- end}}
+{{- with .Uncounted}}
+<p class="uncounted">Without calories: {{.}}</p>
+{{- end}}
+</body>
+</html>
+`
+
+var reportTemplate = template.Must(template.New("report").Parse(htmlReport))
+
+// htmlDay and htmlItem are the formatted values of the HTML template
+type htmlDay struct{ Date, Meals, Cost, Calories string }
+type htmlItem struct{ Text, Cost string }
+
+// HTML writes the report as a HTML page
+func (r *Report) HTML(w io.Writer) error {
+	data := struct {
+		Title, Cost, Unpriced, Uncounted string
+		Days                             []htmlDay
+		Items                            []htmlItem
+	}{
+		Title:     r.title(),
+		Cost:      r.price(r.Cost),
+		Unpriced:  strings.Join(r.Unpriced, ", "),
+		Uncounted: strings.Join(r.Uncounted, ", "),
+	}
+	for _, day := range r.Days {
+		data.Days = append(data.Days, htmlDay{formatDay(day.Date), strings.Join(day.Meals, ", "), r.price(day.Cost), formatCalories(day.Calories)})
+	}
+	for _, item := range r.ShoppingList {
+		i := htmlItem{Text: itemText(item)}
+		if item.Priced {
+			i.Cost = r.price(item.Cost)
+		}
+		data.Items = append(data.Items, i)
+	}
+	return reportTemplate.Execute(w, data)
+}
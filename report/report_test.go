@@ -0,0 +1,132 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/export"
+)
+
+func TestLoadRates(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Rates
+		wantErr error
+	}{
+		{
+			"rates",
+			"# prices\nflour: 1 kg = 1.20\n\nEggs: 12 = 3.60\n",
+			Rates{"flour": {1, "kg", 1.2}, "egg": {12, "", 3.6}},
+			nil,
+		},
+		{"missing value", "flour: 1 kg\n", nil, ErrInvalidRate},
+		{"invalid quantity", "flour: kg = 1\n", nil, ErrInvalidRate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadRates(strings.NewReader(tt.input))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("LoadRates() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadRates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRatesValue(t *testing.T) {
+	rates := Rates{"flour": {1, "kg", 1.2}, "egg": {12, "", 3.6}}
+	tests := []struct {
+		ingredient cooklang.Ingredient
+		want       float64
+		wantOk     bool
+	}{
+		{cooklang.Ingredient{Name: "flour", Amount: cooklang.IngredientAmount{IsNumeric: true, Quantity: 500, Unit: "g"}}, 0.6, true},
+		{cooklang.Ingredient{Name: "eggs", Amount: cooklang.IngredientAmount{IsNumeric: true, Quantity: 2}}, 0.6, true},
+		{cooklang.Ingredient{Name: "flour", Amount: cooklang.IngredientAmount{IsNumeric: true, Quantity: 1, Unit: "cup"}}, 0, false},
+		{cooklang.Ingredient{Name: "salt", Amount: cooklang.IngredientAmount{QuantityRaw: "a pinch"}}, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := rates.Value(tt.ingredient)
+		if ok != tt.wantOk || (got-tt.want) > 1e-9 || (tt.want-got) > 1e-9 {
+			t.Errorf("Value(%v) = %v, %v, want %v, %v", tt.ingredient, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
+
+func testReport(t *testing.T) *Report {
+	t.Helper()
+	parse := func(s string) *cooklang.Recipe {
+		r, err := cooklang.ParseString(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+	pancakes := parse(">> title: Pancakes\n>> servings: 2\nMix @flour{200%g} with @eggs{2} and @salt{a pinch}.")
+	bread := parse(">> title: Bread\nKnead @flour{500%g}.")
+	day := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	plan := export.MealPlan{Name: "Week 42", Meals: []export.Meal{
+		{Recipe: bread, Time: day.Add(24*time.Hour + 19*time.Hour)},
+		{Recipe: pancakes, Time: day.Add(8 * time.Hour)},
+	}}
+	return New(plan, Options{
+		Prices:   Rates{"flour": {1, "kg", 1.2}, "egg": {12, "", 3.6}},
+		Calories: Rates{"flour": {100, "g", 364}, "egg": {1, "", 70}},
+		Currency: "€",
+	})
+}
+
+func TestNew(t *testing.T) {
+	r := testReport(t)
+	var b bytes.Buffer
+	if err := r.Markdown(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `# Week 42
+
+| Day | Meals | Cost | Calories |
+|---|---|---:|---:|
+| Mon 2026-10-12 | Pancakes | €0.84 | 434 kcal |
+| Tue 2026-10-13 | Bread | €0.60 | 1820 kcal |
+
+**Total cost:** €1.44
+
+## Shopping list
+
+- [ ] 2 egg (€0.60)
+- [ ] 700 g flour (€0.84)
+- [ ] a pinch salt
+
+Without price: salt
+
+Without calories: salt
+`
+	if got := b.String(); got != want {
+		t.Errorf("Markdown() = %q, want %q", got, want)
+	}
+}
+
+func TestReportHTML(t *testing.T) {
+	var b bytes.Buffer
+	if err := testReport(t).HTML(&b); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"<h1>Week 42</h1>",
+		"<tr><td>Mon 2026-10-12</td><td>Pancakes</td><td>€0.84</td><td>434 kcal</td></tr>",
+		`<li>700 g flour <span class="cost">€0.84</span></li>`,
+		`<p class="unpriced">Without price: salt</p>`,
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("HTML() does not contain %q:\n%s", want, b.String())
+		}
+	}
+}
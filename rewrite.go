@@ -0,0 +1,231 @@
+package cooklang
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+var (
+	// ErrInvalidName is returned when a new item name can not be written as
+	// cooklang markup
+	ErrInvalidName = errors.New("invalid name")
+	// ErrInvalidStep is returned when a step source is not a single step
+	ErrInvalidStep = errors.New("invalid step source")
+)
+
+// RewriteIngredient renames the ingredient oldName (case insensitive) to
+// newName in the recipe source. Only the ingredient names are changed, the
+// rest of the source including the amounts is kept as written. The
+// punctuation the parser keeps at the end of single word names is ignored
+// when matching and kept in place (@scallion, stays @onion,). Single word
+// ingredients renamed to a multi-word name get an empty amount (@scallion
+// becomes @green onion{}).
+func RewriteIngredient(src, oldName, newName string) (string, error) {
+	if err := checkItemName(newName); err != nil {
+		return "", err
+	}
+	oldName = strings.TrimSpace(oldName)
+	return rewriteNodes(src, func(n *CSTNode) {
+		ingredient, ok := n.Item.(Ingredient)
+		if !ok {
+			return
+		}
+		name, rest := ingredient.Name, n.Raw[1+len(ingredient.Name):]
+		if rest == "" {
+			name = strings.TrimRightFunc(name, unicode.IsPunct)
+			rest = ingredient.Name[len(name):]
+			if strings.ContainsFunc(newName, unicode.IsSpace) {
+				rest = "{}" + rest
+			}
+		}
+		if strings.EqualFold(strings.TrimSpace(name), oldName) {
+			n.Raw = n.Raw[:1] + newName + rest
+		}
+	})
+}
+
+// RewriteIngredientDir renames the ingredient in all recipe files of the dir
+// tree (see RewriteIngredient). The changed files are written in place.
+// Returns the paths of the changed files and the joined errors of the files
+// that could not be rewritten.
+func RewriteIngredientDir(dir, oldName, newName string) ([]string, error) {
+	if err := checkItemName(newName); err != nil {
+		return nil, err
+	}
+	return rewriteDir(dir, func(src string) (string, error) {
+		return RewriteIngredient(src, oldName, newName)
+	}, writeFile)
+}
+
+// ReplaceUnits replaces the units of the ingredient and timer amounts
+// ({250%grams} or ~{1%hours}) found in mapping (exact match) with the mapped
+// units. The rest of the source is kept as written.
+func ReplaceUnits(src string, mapping map[string]string) (string, error) {
+	for _, unit := range mapping {
+		if strings.ContainsAny(unit, "{}\n\r") {
+			return "", fmt.Errorf("%w: unit %q", ErrInvalidName, unit)
+		}
+	}
+	return rewriteNodes(src, func(n *CSTNode) {
+		if n.Type != ItemTypeIngredient && n.Type != ItemTypeTimer {
+			return
+		}
+		open := strings.Index(n.Raw, "{")
+		if open == -1 {
+			return
+		}
+		separator := strings.Index(n.Raw[open:], "%")
+		if separator == -1 {
+			return
+		}
+		start, end := open+separator+1, len(n.Raw)-1
+		unit := strings.TrimSpace(n.Raw[start:end])
+		if replacement, ok := mapping[unit]; ok && unit != "" {
+			index := start + strings.Index(n.Raw[start:end], unit)
+			n.Raw = n.Raw[:index] + replacement + n.Raw[index+len(unit):]
+		}
+	})
+}
+
+// RewriteUnits replaces the units in all recipe files of the dir tree (see
+// ReplaceUnits). The changed files are written in place. Returns the paths
+// of the changed files and the joined errors of the files that could not be
+// rewritten.
+func RewriteUnits(dir string, mapping map[string]string) ([]string, error) {
+	return rewriteDir(dir, func(src string) (string, error) {
+		return ReplaceUnits(src, mapping)
+	}, writeFile)
+}
+
+// RewriteUnitsDiff is a dry run of RewriteUnits: the files are not changed,
+// the changes are written to w as an unified diff instead. Returns the paths
+// of the files which would be changed.
+func RewriteUnitsDiff(w io.Writer, dir string, mapping map[string]string) ([]string, error) {
+	return rewriteDir(dir, func(src string) (string, error) {
+		return ReplaceUnits(src, mapping)
+	}, func(path, before, after string) error {
+		return writeDiff(w, path, before, after)
+	})
+}
+
+// ReplaceStepSource replaces the source of the step with the index of
+// Recipe.Steps (see NewSourceMap) with newStepSource. The step is the text of
+// its line without the line terminator, the rest of the source is kept byte
+// for byte. Returns ErrStepIndex for the indexes out of range and
+// ErrInvalidStep when newStepSource is not a single step line or line
+// comment.
+func ReplaceStepSource(src string, stepIndex int, newStepSource string) (string, error) {
+	replacement, err := ParseCST(newStepSource)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidStep, err)
+	}
+	if steps := cstSteps(replacement.Nodes); len(steps) != 1 || steps[0] != [2]int{0, len(replacement.Nodes)} {
+		return "", fmt.Errorf("%w: %q", ErrInvalidStep, newStepSource)
+	}
+	cst, err := ParseCST(src)
+	if err != nil {
+		return "", err
+	}
+	steps := cstSteps(cst.Nodes)
+	if stepIndex < 0 || stepIndex >= len(steps) {
+		return "", fmt.Errorf("%w: %d", ErrStepIndex, stepIndex)
+	}
+	first, last := cst.Nodes[steps[stepIndex][0]], cst.Nodes[steps[stepIndex][1]-1]
+	return src[:first.Offset] + newStepSource + src[last.Offset+len(last.Raw):], nil
+}
+
+// checkItemName returns an error for the names which would not be parsed
+// back as a single item name
+func checkItemName(name string) error {
+	if strings.TrimSpace(name) == "" || name != strings.TrimSpace(name) || strings.ContainsAny(name, "@#~[{}%\n\r") || strings.Contains(name, commentsLinePrefix) {
+		return fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+	return nil
+}
+
+// rewriteNodes calls rewrite for every node of the source concrete syntax
+// tree and returns the resulting source
+func rewriteNodes(src string, rewrite func(n *CSTNode)) (string, error) {
+	cst, err := ParseCST(src)
+	if err != nil {
+		return "", err
+	}
+	for i := range cst.Nodes {
+		rewrite(&cst.Nodes[i])
+	}
+	return cst.String(), nil
+}
+
+// rewriteDir applies rewrite to all recipe files in the dir tree and calls
+// apply for the changed ones
+func rewriteDir(dir string, rewrite func(src string) (string, error), apply func(path, before, after string) error) ([]string, error) {
+	var changed []string
+	var errs []error
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			return nil
+		}
+		if d.IsDir() || filepath.Ext(path) != RecipeFileExtension {
+			return nil
+		}
+		ok, err := rewriteFile(path, rewrite, apply)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+		} else if ok {
+			changed = append(changed, path)
+		}
+		return nil
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return changed, errors.Join(errs...)
+}
+
+// rewriteFile rewrites the file and calls apply with the result. Returns
+// false when the file was not changed.
+func rewriteFile(path string, rewrite func(src string) (string, error), apply func(path, before, after string) error) (bool, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	result, err := rewrite(string(b))
+	if err != nil || result == string(b) {
+		return false, err
+	}
+	return true, apply(path, string(b), result)
+}
+
+// writeFile replaces the file content keeping its permissions
+func writeFile(path, _, after string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(after), info.Mode().Perm())
+}
+
+// writeDiff writes the changed lines as an unified diff without context.
+// The rewrites keep the number of lines, so every changed line is a hunk.
+func writeDiff(w io.Writer, path, before, after string) error {
+	if _, err := fmt.Fprintf(w, "--- %s\n+++ %s\n", path, path); err != nil {
+		return err
+	}
+	oldLines, newLines := strings.Split(before, "\n"), strings.Split(after, "\n")
+	for i := range min(len(oldLines), len(newLines)) {
+		if oldLines[i] == newLines[i] {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "@@ -%d +%d @@\n-%s\n+%s\n", i+1, i+1, oldLines[i], newLines[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
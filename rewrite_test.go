@@ -0,0 +1,185 @@
+package cooklang
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRewriteIngredient(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		old, new string
+		want     string
+	}{
+		{
+			"Single word",
+			"Chop @scallion  and @Scallion{2}.\n-- @scallion in comments stays\nAdd @scallions.",
+			"scallion", "onion",
+			"Chop @onion  and @onion{2}.\n-- @scallion in comments stays\nAdd @scallions.",
+		},
+		{
+			"Multi-word name",
+			"Chop @scallion, add @scallion{ 2 %pcs}\r\n",
+			"scallion", "green onion",
+			"Chop @green onion{}, add @green onion{ 2 %pcs}\r\n",
+		},
+		{
+			"Multi-word old name",
+			"Add @green onion{1} and #pot.",
+			"green onion", "scallion",
+			"Add @scallion{1} and #pot.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RewriteIngredient(tt.src, tt.old, tt.new)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("RewriteIngredient() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRewriteIngredientInvalidName(t *testing.T) {
+	for _, name := range []string{"", " onion", "on{ion}", "a@b", "a -- b"} {
+		if _, err := RewriteIngredient("Add @salt.", "salt", name); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("RewriteIngredient(%q) error = %v, want ErrInvalidName", name, err)
+		}
+	}
+}
+
+func TestRewriteIngredientDir(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"soup.cook":          "Add @scallion{1}.",
+		"salads/salad.cook":  "Slice @Scallion.",
+		"salads/other.cook":  "Slice @onion.",
+		"salads/broken.cook": ">> no separator",
+		"notes.txt":          "@scallion",
+	})
+	changed, err := RewriteIngredientDir(dir, "scallion", "green onion")
+	if err == nil {
+		t.Error("RewriteIngredientDir() error = nil, want error for broken.cook")
+	}
+	want := []string{filepath.Join(dir, "salads", "salad.cook"), filepath.Join(dir, "soup.cook")}
+	if !reflect.DeepEqual(changed, want) {
+		t.Errorf("RewriteIngredientDir() = %v, want %v", changed, want)
+	}
+	for name, content := range map[string]string{
+		"soup.cook":         "Add @green onion{1}.",
+		"salads/salad.cook": "Slice @green onion{}.",
+		"salads/other.cook": "Slice @onion.",
+		"notes.txt":         "@scallion",
+	} {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != content {
+			t.Errorf("%s = %q, want %q", name, b, content)
+		}
+	}
+}
+
+func TestReplaceUnits(t *testing.T) {
+	mapping := map[string]string{"grams": "g", "minutes": "min", "Tbsp": "tbsp"}
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			"Ingredients and timers",
+			"Add @flour{250%grams} and @sugar{1 % Tbsp }, bake ~{10%minutes}.",
+			"Add @flour{250%g} and @sugar{1 % tbsp }, bake ~{10%min}.",
+		},
+		{
+			"Other text untouched",
+			">> note: 5 grams\n-- grams\n#pan{2%grams} @salt{grams} @pepper{1%kilograms} grams [- {1%grams} -]",
+			">> note: 5 grams\n-- grams\n#pan{2%grams} @salt{grams} @pepper{1%kilograms} grams [- {1%grams} -]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReplaceUnits(tt.src, mapping)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("ReplaceUnits() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRewriteUnits(t *testing.T) {
+	files := map[string]string{
+		"soup.cook":  "Add @water{1%liters}.\nSimmer.\nAdd @salt{5%grams}.",
+		"salad.cook": "Add @oil{1%tbsp}.",
+	}
+	mapping := map[string]string{"grams": "g", "liters": "l"}
+
+	dir := writeTestFiles(t, files)
+	var diff strings.Builder
+	changed, err := RewriteUnitsDiff(&diff, dir, mapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	soup := filepath.Join(dir, "soup.cook")
+	if !reflect.DeepEqual(changed, []string{soup}) {
+		t.Errorf("RewriteUnitsDiff() = %v, want %v", changed, []string{soup})
+	}
+	wantDiff := "--- " + soup + "\n+++ " + soup + "\n" +
+		"@@ -1 +1 @@\n-Add @water{1%liters}.\n+Add @water{1%l}.\n" +
+		"@@ -3 +3 @@\n-Add @salt{5%grams}.\n+Add @salt{5%g}.\n"
+	if diff.String() != wantDiff {
+		t.Errorf("RewriteUnitsDiff() diff = %q, want %q", diff.String(), wantDiff)
+	}
+	if b, _ := os.ReadFile(soup); string(b) != files["soup.cook"] {
+		t.Errorf("RewriteUnitsDiff() changed %s", soup)
+	}
+
+	changed, err = RewriteUnits(dir, mapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(changed, []string{soup}) {
+		t.Errorf("RewriteUnits() = %v, want %v", changed, []string{soup})
+	}
+	if b, _ := os.ReadFile(soup); string(b) != "Add @water{1%l}.\nSimmer.\nAdd @salt{5%g}." {
+		t.Errorf("RewriteUnits() %s = %q", soup, b)
+	}
+}
+
+func TestReplaceStepSource(t *testing.T) {
+	src := ">> title: Soup\r\n\r\nBoil @water{1%l}.  \r\n-- taste it\r\n  Add @salt [- a\r\nlot -] and serve.\r\n"
+	tests := []struct {
+		index   int
+		step    string
+		want    string
+		wantErr error
+	}{
+		{0, "Boil @stock{1%l}.", ">> title: Soup\r\n\r\nBoil @stock{1%l}.\r\n-- taste it\r\n  Add @salt [- a\r\nlot -] and serve.\r\n", nil},
+		{1, "Taste @soup{}.", ">> title: Soup\r\n\r\nBoil @water{1%l}.  \r\nTaste @soup{}.\r\n  Add @salt [- a\r\nlot -] and serve.\r\n", nil},
+		{2, "Serve [- hot\nor cold -].", ">> title: Soup\r\n\r\nBoil @water{1%l}.  \r\n-- taste it\r\nServe [- hot\nor cold -].\r\n", nil},
+		{3, "Serve.", "", ErrStepIndex},
+		{-1, "Serve.", "", ErrStepIndex},
+		{0, "Boil.\nServe.", "", ErrInvalidStep},
+		{0, ">> servings: 2", "", ErrInvalidStep},
+		{0, "", "", ErrInvalidStep},
+		{0, "Boil [- forever.", "", ErrInvalidStep},
+	}
+	for _, tt := range tests {
+		got, err := ReplaceStepSource(src, tt.index, tt.step)
+		if !errors.Is(err, tt.wantErr) || got != tt.want {
+			t.Errorf("ReplaceStepSource(%d, %q) = %q, %v, want %q, %v", tt.index, tt.step, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package cooklang
+
+import (
+	"math"
+	"strings"
+
+	"github.com/aquilax/cooklang-go/units"
+)
+
+// RoundingTable maps the units to the increments the scaled quantities are
+// rounded to. Units are matched case insensitive as written and then by
+// their canonical units package name. Quantities without unit use the ""
+// entry.
+type RoundingTable map[string]float64
+
+// DefaultRoundingTable rounds the scaled quantities to practical kitchen
+// increments
+var DefaultRoundingTable = RoundingTable{
+	"tsp":  0.25,
+	"tbsp": 0.5,
+	"cup":  0.25,
+	"g":    5,
+	"kg":   0.05,
+	"ml":   5,
+	"l":    0.05,
+	"oz":   0.5,
+	"lb":   0.25,
+}
+
+// increment returns the rounding increment of the unit
+func (t RoundingTable) increment(unit string) (float64, bool) {
+	unit = strings.ToLower(strings.TrimSpace(unit))
+	if step, ok := t[unit]; ok {
+		return step, true
+	}
+	if u, ok := units.Lookup(unit); ok {
+		step, ok := t[u.Name]
+		return step, ok
+	}
+	return 0, false
+}
+
+// Round rounds the numeric amount to the nearest increment of its unit.
+// Amounts of units without increment, textual amounts and amounts which
+// would be rounded to zero are returned as they are.
+func (t RoundingTable) Round(amount IngredientAmount) IngredientAmount {
+	if !amount.IsNumeric {
+		return amount
+	}
+	step, ok := t.increment(amount.Unit)
+	if !ok || step <= 0 {
+		return amount
+	}
+	rounded := math.Round(amount.Quantity/step) * step
+	if rounded == 0 {
+		return amount
+	}
+	amount.Quantity = rounded
+	amount.QuantityRaw = formatScaled(rounded)
+	return amount
+}
+
+// ScaleRounded scales the recipe like Scale and rounds the scaled ingredient
+// quantities with the table. A nil table keeps the exact quantities.
+func ScaleRounded(r *Recipe, factor float64, table RoundingTable) *Recipe {
+	scaled, _ := ScaleWithReport(r, factor, ScaleOptions{Rounding: table})
+	return scaled
+}
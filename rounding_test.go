@@ -0,0 +1,64 @@
+package cooklang
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRoundingTableRound(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount IngredientAmount
+		want   IngredientAmount
+	}{
+		{"teaspoons", IngredientAmount{true, 1.3333, "1.333", "tsp"}, IngredientAmount{true, 1.25, "1.25", "tsp"}},
+		{"unit alias", IngredientAmount{true, 1.4, "1.4", "teaspoons"}, IngredientAmount{true, 1.5, "1.5", "teaspoons"}},
+		{"grams", IngredientAmount{true, 333.333, "333.333", "g"}, IngredientAmount{true, 335, "335", "g"}},
+		{"rounded to zero", IngredientAmount{true, 2, "2", "g"}, IngredientAmount{true, 2, "2", "g"}},
+		{"no increment", IngredientAmount{true, 1.333, "1.333", "cans"}, IngredientAmount{true, 1.333, "1.333", "cans"}},
+		{"textual", IngredientAmount{false, 0, "a pinch", "g"}, IngredientAmount{false, 0, "a pinch", "g"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DefaultRoundingTable.Round(tt.amount); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Round() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScaleRounded(t *testing.T) {
+	r, err := ParseString(">> servings: 3\nMix @flour{500%g}, @sugar{2%tsp} and @eggs{2}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		table RoundingTable
+		want  []Ingredient
+	}{
+		{"default", DefaultRoundingTable, []Ingredient{
+			{"flour", IngredientAmount{true, 335, "335", "g"}, false, false, false, false},
+			{"sugar", IngredientAmount{true, 1.25, "1.25", "tsp"}, false, false, false, false},
+			{"eggs", IngredientAmount{true, 4.0 / 3, "1.333", ""}, false, false, false, false},
+		}},
+		{"custom", RoundingTable{"": 1, "g": 100}, []Ingredient{
+			{"flour", IngredientAmount{true, 300, "300", "g"}, false, false, false, false},
+			{"sugar", IngredientAmount{true, 4.0 / 3, "1.333", "tsp"}, false, false, false, false},
+			{"eggs", IngredientAmount{true, 1, "1", ""}, false, false, false, false},
+		}},
+		{"exact", nil, []Ingredient{
+			{"flour", IngredientAmount{true, 1000.0 / 3, "333.333", "g"}, false, false, false, false},
+			{"sugar", IngredientAmount{true, 4.0 / 3, "1.333", "tsp"}, false, false, false, false},
+			{"eggs", IngredientAmount{true, 4.0 / 3, "1.333", ""}, false, false, false, false},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ScaleRounded(r, 2.0/3, tt.table)
+			if !reflect.DeepEqual(got.Steps[0].Ingredients, tt.want) {
+				t.Errorf("ScaleRounded() = %+v, want %+v", got.Steps[0].Ingredients, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package cooklang
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// RoundTrip parses the recipe and serializes it back to cooklang markup in a
+// normalized form: one line per metadata entry, comment or step, a blank line
+// between the steps, items written in their shortest form (@salt, @olive
+// oil{}, #pot, ~{10%minutes}), surrounding white space removed from the
+// amounts and comments, and the text kept as written. Metadata keeps the
+// source order.
+//
+// RoundTrip guarantees that parsing its result gives the same Recipe as
+// parsing src, and that RoundTrip of its result returns the result unchanged.
+// Sources with white space only lines are serialized to the empty string.
+// Parse errors are returned as by ParseString.
+func RoundTrip(src string) (string, error) {
+	if src == "" {
+		return "", fmt.Errorf("recipe string must not be empty")
+	}
+	var limits Limits
+	scanner := limits.newScanner(strings.NewReader(src), nil)
+	t := tokenizer{features: latestFeatures}
+	var b strings.Builder
+	previous := ""
+	lineNumber := 0
+	comments := commentLines{multiline: t.features.multilineComments}
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		if err := limits.checkLine(line); err != nil {
+			return "", fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		line, stepLine, ok := comments.add(line, lineNumber)
+		if !ok || strings.TrimSpace(line) == "" {
+			continue
+		}
+		kind := ""
+		var out string
+		switch {
+		case strings.HasPrefix(line, commentsLinePrefix):
+			comment, _ := parseSingleLineComment(line)
+			out = joinNonEmpty(commentsLinePrefix, comment)
+		case strings.HasPrefix(line, metadataLinePrefix):
+			key, value, err := parseMetadata(line)
+			if err != nil {
+				return "", fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			kind = metadataLinePrefix
+			out = joinNonEmpty(metadataLinePrefix+" "+key+metadataValueSeparator, value)
+		default:
+			var err error
+			if out, err = formatStepLine(&t, line); err != nil {
+				return "", fmt.Errorf("line %d: %w", stepLine, err)
+			}
+		}
+		if b.Len() > 0 {
+			if kind == "" || previous == "" {
+				// steps and comments are separated by a blank line
+				b.WriteString("\n")
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString(out)
+		previous = kind
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("line %d: %w", lineNumber+1, limits.scannerError(err))
+	}
+	if line := comments.open(); line != 0 {
+		return "", fmt.Errorf("line %d: %w", line, ErrUnterminatedComment)
+	}
+	if b.Len() > 0 {
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
+
+// formatStepLine returns the normalized markup of a step line
+func formatStepLine(t *tokenizer, line string) (string, error) {
+	var parts []string
+	var bare []int // indexes of the parts written without braces
+	// indexes of the cookware written with a note and without braces
+	// (#pan(cast iron)) and their form with braces
+	var notes []int
+	var braced []string
+	write := func(part string, isBare bool) {
+		if isBare {
+			bare = append(bare, len(parts))
+		}
+		parts = append(parts, part)
+	}
+	_, err := t.tokenize(line, func(item any) (bool, error) {
+		switch v := item.(type) {
+		case Text:
+			write(v.Value, false)
+		case Ingredient:
+			isBare := v.Amount.Quantity == 1 && v.Amount.QuantityRaw == "" && v.Amount.Unit == ""
+			name := v.Name
+			if v.Hidden {
+				name = string(prefixHidden) + name
+			}
+			if v.Optional {
+				name = string(prefixOptional) + name
+			}
+			quantity := v.Amount.QuantityRaw
+			if v.Fixed {
+				quantity = string(prefixFixed) + quantity
+			}
+			write(formatItem(prefixIngredient, name, isBare && !v.Fixed, quantity, v.Amount.Unit))
+		case Cookware:
+			name := v.Name
+			if v.Optional {
+				name = string(prefixOptional) + name
+			}
+			isBare := v.Quantity == 1 && v.QuantityRaw == ""
+			item, bare := formatItem(prefixCookware, name, isBare && v.Note == "", v.QuantityRaw, "")
+			if v.Note != "" {
+				item += "(" + v.Note + ")"
+				if isBare && !strings.ContainsAny(name, "{(") {
+					if noted, ok := formatItem(prefixCookware, name, true, "", ""); ok {
+						notes = append(notes, len(parts))
+						braced = append(braced, item)
+						item = noted + "(" + v.Note + ")"
+					}
+				}
+			}
+			write(item, bare)
+		case Timer:
+			duration := ""
+			if v.HasDuration() || v.Name == "" {
+				// ~{0} has neither name nor duration
+				duration = strconv.FormatFloat(v.Duration, 'f', -1, 64)
+			}
+			write(formatItem(prefixTimer, v.Name, !v.HasDuration(), duration, v.Unit))
+		case Comment:
+			if v.Type == CommentTypeBlock {
+				write("[- "+v.Value+" -]", false)
+			} else {
+				write(joinNonEmpty(commentsLinePrefix, v.Value), false)
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	for i, j := len(bare)-1, len(notes)-1; i >= 0 || j >= 0; {
+		if j < 0 || (i >= 0 && bare[i] > notes[j]) {
+			// the braces are needed when the text after a single word item
+			// would be read as part of it ("#pot{}." or "#pot{} {lid}")
+			n := bare[i]
+			if findNodeEndIndex(strings.Join(parts[n:], "")) != len(parts[n]) {
+				parts[n] += "{}"
+			}
+			i--
+			continue
+		}
+		// the names which parse only without braces (#a~b(note)) keep the
+		// bare form unless the text after it changes the item
+		n := notes[j]
+		if _, end, err := getCookware(strings.Join(parts[n:], ""), latestFeatures); err != nil || end != len(parts[n]) {
+			parts[n] = braced[j]
+		}
+		j--
+	}
+	out := strings.TrimRightFunc(strings.Join(parts, ""), unicode.IsSpace)
+	if trimmed := strings.TrimLeftFunc(out, unicode.IsSpace); !strings.HasPrefix(trimmed, commentsLinePrefix) && !strings.HasPrefix(trimmed, metadataLinePrefix) {
+		// the leading white space is kept only where it prevents the line
+		// from becoming a comment or metadata
+		out = trimmed
+	}
+	return out, nil
+}
+
+// formatItem returns the item markup. Single word names without amount are
+// written without braces, in which case the returned bool is true.
+func formatItem(prefix byte, name string, bare bool, quantity, unit string) (string, bool) {
+	if bare && name != "" && !strings.ContainsFunc(name, unicode.IsSpace) {
+		return string(prefix) + name, true
+	}
+	if unit != "" {
+		quantity += "%" + unit
+	}
+	return string(prefix) + name + "{" + quantity + "}", false
+}
+
+func joinNonEmpty(prefix, value string) string {
+	if value == "" {
+		return prefix
+	}
+	return prefix + " " + value
+}
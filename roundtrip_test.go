@@ -0,0 +1,105 @@
+package cooklang
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// checkRoundTrip verifies the RoundTrip guarantee for src
+func checkRoundTrip(t *testing.T, src string) string {
+	t.Helper()
+	want, err := ParseString(src)
+	if err != nil {
+		if _, rtErr := RoundTrip(src); rtErr == nil {
+			t.Fatalf("RoundTrip(%q) error = nil, want %v", src, err)
+		}
+		return ""
+	}
+	out, err := RoundTrip(src)
+	if err != nil {
+		t.Fatalf("RoundTrip(%q) error = %v", src, err)
+	}
+	if out == "" {
+		if len(want.Steps) > 0 || len(want.Metadata) > 0 {
+			t.Errorf("RoundTrip(%q) = \"\", want markup", src)
+		}
+		return out
+	}
+	got, err := ParseString(out)
+	if err != nil {
+		t.Fatalf("ParseString(%q) error = %v", out, err)
+	}
+	// NaN quantities (0/0) are never DeepEqual
+	if !reflect.DeepEqual(got, want) && fmt.Sprintf("%#v", *got) != fmt.Sprintf("%#v", *want) {
+		t.Errorf("ParseString(RoundTrip(%q)) = %#v, want %#v", src, *got, *want)
+	}
+	if again, _ := RoundTrip(out); again != out {
+		t.Errorf("RoundTrip(%q) = %q, want it unchanged", out, again)
+	}
+	return out
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"Blank", " \n\t\n", ""},
+		{
+			"Normalized",
+			">>servings:2\n>> title : Soup\n\n\n  Add @salt and @olive oil{ 2 % tbsp } to the #pot{} now\n--  note\nSimmer ~{ 1/2 %hour}[-covered-] -- stir",
+			">> servings: 2\n>> title: Soup\n\nAdd @salt and @olive oil{2%tbsp} to the #pot now\n\n-- note\n\nSimmer ~{0.5%hour}[- covered -] -- stir\n",
+		},
+		{"Quantities", "@water{} @eggs{two} #pan{2} ~rest{}", "@water{} @eggs{two} #pan{2} ~rest\n"},
+		{"Braces kept before text", "Heat the #pan{}-- hot\nWait ~rest{}ing", "Heat the #pan{}-- hot\n\nWait ~rest{}ing\n"},
+		{"Leading space before comment", "  -- not a line comment", "  -- not a line comment\n"},
+		{"Malformed item kept as text", "Add @ salt and @x{", "Add @ salt and @x{\n"},
+		{"Fixed quantities", "Add @salt{= 1 % tsp} and @pepper{=}", "Add @salt{=1%tsp} and @pepper{=}\n"},
+		{"Cookware notes", "Use a #pan( cast iron ) or #? wok{2}(large)", "Use a #pan(cast iron) or #?wok{2}(large)\n"},
+		{"Cookware notes with bare names", "#a~b(c) and #pan{}(cast iron) {lid}", "#a~b(c) and #pan{}(cast iron) {lid}\n"},
+		{"Hidden ingredients", "Season@-salt{} the @- olive oil{1%tbsp}", "Season@-salt{} the @-olive oil{1%tbsp}\n"},
+		{"Optional ingredients", "Top with @? parsley{} and @?-chili{1}", "Top with @?parsley{} and @?-chili{1}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkRoundTrip(t, tt.src); got != tt.want {
+				t.Errorf("RoundTrip() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundTripCanonical(t *testing.T) {
+	b, err := os.ReadFile("spec/canonical.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var specs struct {
+		Tests map[string]struct {
+			Source string `json:"source"`
+		} `json:"tests"`
+	}
+	if err := json.Unmarshal(b, &specs); err != nil {
+		t.Fatal(err)
+	}
+	for name, tc := range specs.Tests {
+		t.Run(name, func(t *testing.T) {
+			checkRoundTrip(t, tc.Source)
+		})
+	}
+	t.Run("benchmarkRecipe", func(t *testing.T) {
+		checkRoundTrip(t, benchmarkRecipe)
+	})
+}
+
+func TestRoundTripError(t *testing.T) {
+	for _, src := range []string{"", "Mix [- unterminated", ">> no separator"} {
+		if _, err := RoundTrip(src); err == nil {
+			t.Errorf("RoundTrip(%q) error = nil, want error", src)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Package rpc contains the protobuf schema of the recipe model and a gRPC
+// service which parses and renders recipes, so the parser can be used as a
+// sidecar by non-Go services
+package rpc
+
+//go:generate buf generate
+
+import (
+	"github.com/aquilax/cooklang-go"
+)
+
+// ToProto converts the recipe to its protobuf message. Step attributes are
+// not part of the schema and are dropped.
+func ToProto(r *cooklang.Recipe) *Recipe {
+	result := &Recipe{Metadata: make(map[string]string, len(r.Metadata)), MetadataOrder: r.MetadataOrder}
+	for k, v := range r.Metadata {
+		result.Metadata[k] = v
+	}
+	for _, s := range r.Steps {
+		step := &Step{Directions: s.Directions, Comments: s.Comments, Image: s.Image}
+		for _, t := range s.Timers {
+			step.Timers = append(step.Timers, &Timer{Name: t.Name, Duration: t.Duration, Unit: t.Unit})
+		}
+		for _, i := range s.Ingredients {
+			step.Ingredients = append(step.Ingredients, ingredientToProto(i))
+		}
+		for _, c := range s.Cookware {
+			step.Cookware = append(step.Cookware, &Cookware{Name: c.Name, IsNumeric: c.IsNumeric, Quantity: c.Quantity, QuantityRaw: c.QuantityRaw})
+		}
+		result.Steps = append(result.Steps, step)
+	}
+	return result
+}
+
+func ingredientToProto(i cooklang.Ingredient) *Ingredient {
+	return &Ingredient{
+		Name: i.Name,
+		Amount: &Amount{
+			IsNumeric:   i.Amount.IsNumeric,
+			Quantity:    i.Amount.Quantity,
+			QuantityRaw: i.Amount.QuantityRaw,
+			Unit:        i.Amount.Unit,
+		},
+	}
+}
+
+// FromProto converts the protobuf message to a recipe
+func FromProto(r *Recipe) *cooklang.Recipe {
+	result := &cooklang.Recipe{
+		Steps:         make([]cooklang.Step, 0, len(r.GetSteps())),
+		Metadata:      make(cooklang.Metadata, len(r.GetMetadata())),
+		MetadataOrder: r.GetMetadataOrder(),
+	}
+	for k, v := range r.GetMetadata() {
+		result.Metadata[k] = v
+	}
+	for _, s := range r.GetSteps() {
+		step := cooklang.Step{
+			Directions:  s.GetDirections(),
+			Timers:      make([]cooklang.Timer, 0, len(s.GetTimers())),
+			Ingredients: make([]cooklang.Ingredient, 0, len(s.GetIngredients())),
+			Cookware:    make([]cooklang.Cookware, 0, len(s.GetCookware())),
+			Comments:    s.GetComments(),
+			Image:       s.GetImage(),
+		}
+		for _, t := range s.GetTimers() {
+			step.Timers = append(step.Timers, cooklang.Timer{Name: t.GetName(), Duration: t.GetDuration(), Unit: t.GetUnit()})
+		}
+		for _, i := range s.GetIngredients() {
+			step.Ingredients = append(step.Ingredients, ingredientFromProto(i))
+		}
+		for _, c := range s.GetCookware() {
+			step.Cookware = append(step.Cookware, cooklang.Cookware{Name: c.GetName(), IsNumeric: c.GetIsNumeric(), Quantity: c.GetQuantity(), QuantityRaw: c.GetQuantityRaw()})
+		}
+		result.Steps = append(result.Steps, step)
+	}
+	return result
+}
+
+func ingredientFromProto(i *Ingredient) cooklang.Ingredient {
+	return cooklang.Ingredient{
+		Name: i.GetName(),
+		Amount: cooklang.IngredientAmount{
+			IsNumeric:   i.GetAmount().GetIsNumeric(),
+			Quantity:    i.GetAmount().GetQuantity(),
+			QuantityRaw: i.GetAmount().GetQuantityRaw(),
+			Unit:        i.GetAmount().GetUnit(),
+		},
+	}
+}
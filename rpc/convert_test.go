@@ -0,0 +1,20 @@
+package rpc
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+func TestProtoRoundTrip(t *testing.T) {
+	r, err := cooklang.ParseString(`>> servings: 2
+Put @bacon strips{2} and @salt in a #frying pan{} on the #stove{two}.
+Fry for ~{5%minutes} and ~rest.`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := FromProto(ToProto(r)); !reflect.DeepEqual(got, r) {
+		t.Errorf("FromProto(ToProto()) = %#v, want %#v", got, r)
+	}
+}
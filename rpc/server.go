@@ -0,0 +1,80 @@
+package rpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/ingredients"
+	"github.com/aquilax/cooklang-go/render"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements CooklangServiceServer
+type Server struct {
+	UnimplementedCooklangServiceServer
+	parser *cooklang.Parser
+}
+
+// NewServer creates a new service using the parser configuration. Nil config
+// uses the defaults.
+func NewServer(config *cooklang.ParseConfig) *Server {
+	return &Server{parser: cooklang.NewParser(config)}
+}
+
+func (s *Server) parse(source string) (*cooklang.Recipe, error) {
+	r, err := s.parser.ParseString(source)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return r, nil
+}
+
+// Parse parses the recipe source
+func (s *Server) Parse(ctx context.Context, req *ParseRequest) (*ParseResponse, error) {
+	r, err := s.parse(req.GetSource())
+	if err != nil {
+		return nil, err
+	}
+	return &ParseResponse{Recipe: ToProto(r)}, nil
+}
+
+// Render renders the recipe source as Markdown or HTML
+func (s *Server) Render(ctx context.Context, req *RenderRequest) (*RenderResponse, error) {
+	r, err := s.parse(req.GetSource())
+	if err != nil {
+		return nil, err
+	}
+	opts := &render.Options{Locale: req.GetLocale()}
+	var b strings.Builder
+	switch req.GetFormat() {
+	case RenderFormat_RENDER_FORMAT_UNSPECIFIED, RenderFormat_RENDER_FORMAT_MARKDOWN:
+		err = render.Markdown(&b, r, opts)
+	case RenderFormat_RENDER_FORMAT_HTML:
+		err = render.HTML(&b, r, opts)
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown format %v", req.GetFormat())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &RenderResponse{Content: b.String()}, nil
+}
+
+// ShoppingList returns the merged ingredients of all recipes
+func (s *Server) ShoppingList(ctx context.Context, req *ShoppingListRequest) (*ShoppingListResponse, error) {
+	var list []cooklang.Ingredient
+	for _, source := range req.GetSources() {
+		r, err := s.parse(source)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, r.AllIngredients(nil)...)
+	}
+	resp := &ShoppingListResponse{}
+	for _, i := range ingredients.Merge(list, ingredients.MergeOptions{ConvertUnits: true}) {
+		resp.Ingredients = append(resp.Ingredients, ingredientToProto(i))
+	}
+	return resp, nil
+}
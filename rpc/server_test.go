@@ -0,0 +1,69 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestClient(t *testing.T) CooklangServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	RegisterCooklangServiceServer(srv, NewServer(nil))
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return NewCooklangServiceClient(conn)
+}
+
+func TestServer(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	parsed, err := client.Parse(ctx, &ParseRequest{Source: "Add @flour{200%g}."})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := parsed.GetRecipe().GetSteps()[0].GetIngredients()[0].GetName(); got != "flour" {
+		t.Errorf("Parse() ingredient = %q, want %q", got, "flour")
+	}
+
+	_, err = client.Parse(ctx, &ParseRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Parse() error = %v, want InvalidArgument", err)
+	}
+
+	rendered, err := client.Render(ctx, &RenderRequest{Source: "Add @flour{200%g}.", Format: RenderFormat_RENDER_FORMAT_HTML, Locale: "de"})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !strings.Contains(rendered.GetContent(), `<html lang="de">`) {
+		t.Errorf("Render() = %s, want HTML document", rendered.GetContent())
+	}
+
+	list, err := client.ShoppingList(ctx, &ShoppingListRequest{Sources: []string{"Add @flour{200%g}.", "Add @flour{1%kg} and @salt{}."}})
+	if err != nil {
+		t.Fatalf("ShoppingList() error = %v", err)
+	}
+	var names []string
+	for _, i := range list.GetIngredients() {
+		names = append(names, i.GetName())
+	}
+	if got := strings.Join(names, ","); got != "flour,salt" {
+		t.Errorf("ShoppingList() = %s, want flour,salt", got)
+	}
+}
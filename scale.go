@@ -0,0 +1,146 @@
+package cooklang
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// MetadataServings is the metadata key of the number of servings
+const MetadataServings = "servings"
+
+// Scale returns a copy of the recipe with the numeric ingredient quantities
+// multiplied by factor and rounded with DefaultRoundingTable (see
+// ScaleRounded). Textual quantities ("a pinch"), fixed quantities
+// (@salt{=1%tsp}) and the cookware are kept as they are. A numeric servings
+// metadata value is scaled too.
+func Scale(r *Recipe, factor float64) *Recipe {
+	return ScaleRounded(r, factor, DefaultRoundingTable)
+}
+
+// ScaleOptions configures ScaleWithReport
+type ScaleOptions struct {
+	// Rounding rounds the scaled quantities, nil keeps the exact quantities
+	Rounding RoundingTable
+	// Fixed are the names of the ingredients which are not scaled besides
+	// the fixed quantities of the recipe ("salt", "yeast"), compared with
+	// NormalizeName
+	Fixed []string
+}
+
+// ScaleReport describes the scaling of a recipe
+type ScaleReport struct {
+	Factor float64
+	// Fixed are the ingredients with numeric quantity which were not
+	// scaled, in step order
+	Fixed []Ingredient
+}
+
+// ScaleWithReport scales the recipe like Scale with the options and reports
+// the ingredients which were kept as they are
+func ScaleWithReport(r *Recipe, factor float64, opts ScaleOptions) (*Recipe, *ScaleReport) {
+	fixed := make(map[string]bool, len(opts.Fixed))
+	for _, name := range opts.Fixed {
+		fixed[NormalizeName(name)] = true
+	}
+	scaled := r.clone()
+	if servings, err := Servings(r); err == nil {
+		scaled.Metadata[MetadataServings] = formatScaled(servings * factor)
+	}
+	report := &ScaleReport{Factor: factor}
+	for i := range scaled.Steps {
+		step := &scaled.Steps[i]
+		if step.Ingredients == nil {
+			continue
+		}
+		step.Ingredients = slices.Clone(step.Ingredients)
+		for j := range step.Ingredients {
+			ingredient := &step.Ingredients[j]
+			if !ingredient.Amount.IsNumeric {
+				continue
+			}
+			if ingredient.Fixed || fixed[NormalizeName(ingredient.Name)] {
+				report.Fixed = append(report.Fixed, *ingredient)
+				continue
+			}
+			ingredient.Amount = scaleAmount(ingredient.Amount, factor)
+			if opts.Rounding != nil && factor != 1 {
+				ingredient.Amount = opts.Rounding.Round(ingredient.Amount)
+			}
+		}
+	}
+	return scaled, report
+}
+
+// scaleAmount returns the numeric amount multiplied by factor
+func scaleAmount(amount IngredientAmount, factor float64) IngredientAmount {
+	amount.Quantity *= factor
+	amount.QuantityRaw = formatScaled(amount.Quantity)
+	return amount
+}
+
+// ScaledIngredients returns a copy of the step ingredients with the numeric
+// quantities multiplied by factor (see Scale). The step is not changed.
+func (s Step) ScaledIngredients(factor float64) []Ingredient {
+	if s.Ingredients == nil {
+		return nil
+	}
+	ingredients := make([]Ingredient, len(s.Ingredients))
+	for i, ingredient := range s.Ingredients {
+		if ingredient.Amount.IsNumeric && !ingredient.Fixed {
+			ingredient.Amount = scaleAmount(ingredient.Amount, factor)
+		}
+		ingredients[i] = ingredient
+	}
+	return ingredients
+}
+
+// Servings returns the number of servings of the recipe from the leading
+// number of the servings metadata ("4" or "4 people")
+func Servings(r *Recipe) (float64, error) {
+	fields := strings.Fields(r.Metadata[MetadataServings])
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("recipe has no %s metadata", MetadataServings)
+	}
+	servings, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil || !isPositiveFinite(servings) {
+		return 0, fmt.Errorf("invalid %s metadata %q", MetadataServings, r.Metadata[MetadataServings])
+	}
+	return servings, nil
+}
+
+// ScaleFactor returns the scaling factor of the recipe for the scale
+// specification: a multiplier ("2x" or "0.5x") or the wanted number of
+// servings ("6"), which requires the servings metadata.
+func ScaleFactor(r *Recipe, spec string) (float64, error) {
+	spec = strings.TrimSpace(spec)
+	if multiplier, ok := strings.CutSuffix(strings.ToLower(spec), "x"); ok {
+		factor, err := strconv.ParseFloat(multiplier, 64)
+		if err != nil || !isPositiveFinite(factor) {
+			return 0, fmt.Errorf("invalid scale %q", spec)
+		}
+		return factor, nil
+	}
+	wanted, err := strconv.ParseFloat(spec, 64)
+	if err != nil || !isPositiveFinite(wanted) {
+		return 0, fmt.Errorf("invalid servings %q", spec)
+	}
+	servings, err := Servings(r)
+	if err != nil {
+		return 0, err
+	}
+	return wanted / servings, nil
+}
+
+// isPositiveFinite returns true for the numbers greater than zero which are
+// neither NaN nor infinite
+func isPositiveFinite(f float64) bool {
+	return f > 0 && !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+// formatScaled formats the scaled quantity with at most three decimals
+func formatScaled(f float64) string {
+	return strconv.FormatFloat(math.Round(f*1000)/1000, 'f', -1, 64)
+}
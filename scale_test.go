@@ -0,0 +1,114 @@
+package cooklang
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScale(t *testing.T) {
+	r, err := ParseString(">> servings: 4 people\nMix @flour{200%g}, @eggs{3}, @salt{a pinch} and @water in a #bowl{2}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := Scale(r, 1.5)
+	want := []Ingredient{
+		{"flour", IngredientAmount{true, 300, "300", "g"}, false, false, false, false},
+		{"eggs", IngredientAmount{true, 4.5, "4.5", ""}, false, false, false, false},
+		{"salt", IngredientAmount{false, 0, "a pinch", ""}, false, false, false, false},
+		{"water", IngredientAmount{false, 1, "", ""}, false, false, false, false},
+	}
+	if !reflect.DeepEqual(got.Steps[0].Ingredients, want) {
+		t.Errorf("Scale() ingredients = %+v, want %+v", got.Steps[0].Ingredients, want)
+	}
+	if got.Metadata[MetadataServings] != "6" {
+		t.Errorf("Scale() servings = %q, want %q", got.Metadata[MetadataServings], "6")
+	}
+	if !reflect.DeepEqual(got.Steps[0].Cookware, r.Steps[0].Cookware) {
+		t.Errorf("Scale() cookware = %+v, want %+v", got.Steps[0].Cookware, r.Steps[0].Cookware)
+	}
+	if r.Steps[0].Ingredients[0].Amount.Quantity != 200 || r.Metadata[MetadataServings] != "4 people" {
+		t.Error("Scale() modified the original recipe")
+	}
+}
+
+func TestScaleFactor(t *testing.T) {
+	tests := []struct {
+		metadata string
+		spec     string
+		want     float64
+		wantErr  bool
+	}{
+		{"", "2x", 2, false},
+		{"", "0.5X", 0.5, false},
+		{"4", "6", 1.5, false},
+		{"2 people", "1", 0.5, false},
+		{"", "6", 0, true},
+		{"a few", "6", 0, true},
+		{"4", "0", 0, true},
+		{"4", "twice", 0, true},
+		{"", "-2x", 0, true},
+		{"", "nanx", 0, true},
+		{"", "infx", 0, true},
+		{"4", "nan", 0, true},
+		{"4", "inf", 0, true},
+		{"inf", "6", 0, true},
+	}
+	for _, tt := range tests {
+		r := &Recipe{Metadata: Metadata{}}
+		if tt.metadata != "" {
+			r.Metadata[MetadataServings] = tt.metadata
+		}
+		got, err := ScaleFactor(r, tt.spec)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ScaleFactor(%q, %q) = %v, %v, want %v", tt.metadata, tt.spec, got, err, tt.want)
+		}
+	}
+}
+
+func TestStepScaledIngredients(t *testing.T) {
+	r, err := ParseString("Mix @flour{200%g}, @salt{a pinch} and @water.\nServe.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Ingredient{
+		{"flour", IngredientAmount{true, 400, "400", "g"}, false, false, false, false},
+		{"salt", IngredientAmount{false, 0, "a pinch", ""}, false, false, false, false},
+		{"water.", IngredientAmount{false, 1, "", ""}, false, false, false, false},
+	}
+	if got := r.Steps[0].ScaledIngredients(2); !reflect.DeepEqual(got, want) {
+		t.Errorf("ScaledIngredients() = %+v, want %+v", got, want)
+	}
+	if r.Steps[0].Ingredients[0].Amount.Quantity != 200 {
+		t.Error("ScaledIngredients() modified the step")
+	}
+	if got := r.Steps[1].ScaledIngredients(2); len(got) != 0 {
+		t.Errorf("ScaledIngredients() = %+v, want no ingredients", got)
+	}
+}
+
+func TestScaleWithReport(t *testing.T) {
+	r, err := ParseString(">> servings: 2\nMix @flour{500%g}, @salt{=1%tsp}, @Yeast{7%g} and @water{300%ml}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, report := ScaleWithReport(r, 3, ScaleOptions{Fixed: []string{"yeast"}})
+	want := []Ingredient{
+		{Name: "flour", Amount: IngredientAmount{true, 1500, "1500", "g"}},
+		{Name: "salt", Amount: IngredientAmount{true, 1, "1", "tsp"}, Fixed: true},
+		{Name: "Yeast", Amount: IngredientAmount{true, 7, "7", "g"}},
+		{Name: "water", Amount: IngredientAmount{true, 900, "900", "ml"}},
+	}
+	if !reflect.DeepEqual(got.Steps[0].Ingredients, want) {
+		t.Errorf("ScaleWithReport() ingredients = %+v, want %+v", got.Steps[0].Ingredients, want)
+	}
+	wantReport := &ScaleReport{Factor: 3, Fixed: []Ingredient{want[1], want[2]}}
+	if !reflect.DeepEqual(report, wantReport) {
+		t.Errorf("ScaleWithReport() report = %+v, want %+v", report, wantReport)
+	}
+	if scaled := Scale(r, 3); scaled.Steps[0].Ingredients[1].Amount.Quantity != 1 {
+		t.Errorf("Scale() scaled the fixed quantity: %+v", scaled.Steps[0].Ingredients[1])
+	}
+	if r.Steps[0].Ingredients[0].Amount.Quantity != 500 {
+		t.Error("ScaleWithReport() modified the original recipe")
+	}
+}
@@ -0,0 +1,83 @@
+package cooklang
+
+import (
+	"strings"
+	"time"
+)
+
+// DefaultActiveTime is the hands-on time of a step used when
+// ScheduleOptions.ActiveTime is not set
+const DefaultActiveTime = 2 * time.Minute
+
+// ScheduleOptions contains the cooking schedule options
+type ScheduleOptions struct {
+	ActiveTime time.Duration // hands-on time of every step (default: DefaultActiveTime)
+	Finish     time.Time     // optional target finish time
+}
+
+// ScheduledStep is a step in the cooking schedule. The times are offsets
+// from the start of the schedule.
+type ScheduledStep struct {
+	Step       int           // zero based step index
+	Start      time.Duration // start of the hands-on work
+	TimerStart time.Duration // start of the step timers
+	End        time.Duration // end of the step
+}
+
+// CookingSchedule is a suggested cooking timeline
+type CookingSchedule struct {
+	Start time.Time       // start time when a target finish time was set
+	Total time.Duration   // total time from the start to the end of the last step
+	Steps []ScheduledStep // scheduled steps in recipe order
+}
+
+// Schedule computes a cooking timeline for the recipe. The steps are started
+// in recipe order by a single cook: the hands-on part of a step starts once
+// the hands-on part of the previous step is done and the cookware the step
+// uses (case insensitive) has been released by the previous steps. The steps
+// without ingredients and cookware ("Serve.") work on the results of the
+// previous steps, so they start once all the previous steps have ended.
+// Timers run unattended after the hands-on part, so the next steps can be
+// prepared in parallel. The timers of a step run one after another. Nil
+// options use the defaults.
+func Schedule(r *Recipe, opts *ScheduleOptions) CookingSchedule {
+	if opts == nil {
+		opts = &ScheduleOptions{}
+	}
+	active := opts.ActiveTime
+	if active <= 0 {
+		active = DefaultActiveTime
+	}
+	var schedule CookingSchedule
+	var cook time.Duration                     // time the cook is free
+	released := make(map[string]time.Duration) // cookware name -> time it is free
+	for i, step := range r.Steps {
+		if step.IsComment() {
+			continue
+		}
+		start := cook
+		if len(step.Ingredients) == 0 && len(step.Cookware) == 0 {
+			start = max(start, schedule.Total)
+		}
+		for _, c := range step.Cookware {
+			start = max(start, released[strings.ToLower(c.Name)])
+		}
+		s := ScheduledStep{Step: i, Start: start, TimerStart: start + active}
+		s.End = s.TimerStart
+		for _, timer := range step.Timers {
+			if d, ok := timer.ToDuration(); ok {
+				s.End += d
+			}
+		}
+		for _, c := range step.Cookware {
+			released[strings.ToLower(c.Name)] = s.End
+		}
+		cook = s.TimerStart
+		schedule.Total = max(schedule.Total, s.End)
+		schedule.Steps = append(schedule.Steps, s)
+	}
+	if !opts.Finish.IsZero() {
+		schedule.Start = opts.Finish.Add(-schedule.Total)
+	}
+	return schedule
+}
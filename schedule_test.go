@@ -0,0 +1,60 @@
+package cooklang
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSchedule(t *testing.T) {
+	recipe := `Boil @water in a #pot{} for ~{10%minutes}.
+-- meanwhile
+Toast @bread in the #oven{} for ~{3%minutes}.
+Poach @eggs{2} in the #Pot{} for ~{3%minutes} and rest ~{30%seconds}.
+Serve.`
+	finish := time.Date(2024, 5, 1, 19, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		opts *ScheduleOptions
+		want CookingSchedule
+	}{
+		{
+			"Defaults",
+			nil,
+			CookingSchedule{
+				Total: 19*time.Minute + 30*time.Second,
+				Steps: []ScheduledStep{
+					{0, 0, 2 * time.Minute, 12 * time.Minute},
+					{2, 2 * time.Minute, 4 * time.Minute, 7 * time.Minute},
+					{3, 12 * time.Minute, 14 * time.Minute, 17*time.Minute + 30*time.Second},
+					{4, 17*time.Minute + 30*time.Second, 19*time.Minute + 30*time.Second, 19*time.Minute + 30*time.Second},
+				},
+			},
+		},
+		{
+			"Target finish",
+			&ScheduleOptions{ActiveTime: time.Minute, Finish: finish},
+			CookingSchedule{
+				Start: finish.Add(-(16*time.Minute + 30*time.Second)),
+				Total: 16*time.Minute + 30*time.Second,
+				Steps: []ScheduledStep{
+					{0, 0, time.Minute, 11 * time.Minute},
+					{2, time.Minute, 2 * time.Minute, 5 * time.Minute},
+					{3, 11 * time.Minute, 12 * time.Minute, 15*time.Minute + 30*time.Second},
+					{4, 15*time.Minute + 30*time.Second, 16*time.Minute + 30*time.Second, 16*time.Minute + 30*time.Second},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := ParseString(recipe)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := Schedule(r, tt.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Schedule() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
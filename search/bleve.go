@@ -0,0 +1,122 @@
+//go:build bleve
+
+package search
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+
+	"github.com/aquilax/cooklang-go"
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/keyword"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search/query"
+)
+
+// defaultSize is the number of hits of the queries without Size
+const defaultSize = 10
+
+// facetSize is the maximum number of terms of a facet
+const facetSize = 20
+
+// bleveIndex is an Index stored by Bleve
+type bleveIndex struct {
+	index bleve.Index
+}
+
+// NewBleveIndex opens the Bleve index at path, creating it when it does not
+// exist, or creates an in memory index when the path is empty. The title and
+// steps fields are analyzed as English text, the other fields are indexed
+// as whole terms for filtering and faceting.
+func NewBleveIndex(path string) (Index, error) {
+	if path == "" {
+		index, err := bleve.NewMemOnly(indexMapping())
+		if err != nil {
+			return nil, err
+		}
+		return &bleveIndex{index}, nil
+	}
+	index, err := bleve.Open(path)
+	if errors.Is(err, bleve.ErrorIndexPathDoesNotExist) {
+		index, err = bleve.New(path, indexMapping())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &bleveIndex{index}, nil
+}
+
+// indexMapping returns the mapping of RecipeDocument
+func indexMapping() mapping.IndexMapping {
+	text := bleve.NewTextFieldMapping()
+	text.Analyzer = "en"
+	term := bleve.NewTextFieldMapping()
+	term.Analyzer = keyword.Name
+	term.IncludeInAll = true
+	document := bleve.NewDocumentStaticMapping()
+	document.AddFieldMappingsAt(FieldTitle, text)
+	document.AddFieldMappingsAt(FieldSteps, text)
+	for _, field := range []string{FieldTags, FieldIngredients, FieldCookware, FieldTimeBucket} {
+		document.AddFieldMappingsAt(field, term)
+	}
+	document.AddFieldMappingsAt(FieldTotalMinutes, bleve.NewNumericFieldMapping())
+	m := bleve.NewIndexMapping()
+	m.DefaultMapping = document
+	m.DefaultAnalyzer = "en"
+	return m
+}
+
+func (b *bleveIndex) Add(id string, r *cooklang.Recipe) error {
+	return b.index.Index(id, Document(r))
+}
+
+func (b *bleveIndex) Delete(id string) error {
+	return b.index.Delete(id)
+}
+
+func (b *bleveIndex) Search(q Query) (*Result, error) {
+	var text query.Query = bleve.NewMatchAllQuery()
+	if q.Text != "" {
+		text = bleve.NewQueryStringQuery(q.Text)
+	}
+	queries := []query.Query{text}
+	for _, f := range q.Filters {
+		term := bleve.NewTermQuery(f.Value)
+		term.SetField(f.Field)
+		queries = append(queries, term)
+	}
+	size := q.Size
+	if size == 0 {
+		size = defaultSize
+	}
+	req := bleve.NewSearchRequestOptions(bleve.NewConjunctionQuery(queries...), size, 0, false)
+	for _, field := range q.Facets {
+		req.AddFacet(field, bleve.NewFacetRequest(field, facetSize))
+	}
+	res, err := b.index.Search(req)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{Total: res.Total, Hits: make([]Hit, 0, len(res.Hits)), Facets: make(map[string][]FacetTerm, len(res.Facets))}
+	for _, hit := range res.Hits {
+		result.Hits = append(result.Hits, Hit{hit.ID, hit.Score})
+	}
+	for field, facet := range res.Facets {
+		terms := []FacetTerm{}
+		if facet.Terms != nil {
+			for _, t := range facet.Terms.Terms() {
+				terms = append(terms, FacetTerm{t.Term, t.Count})
+			}
+		}
+		slices.SortStableFunc(terms, func(a, b FacetTerm) int {
+			return cmp.Or(b.Count-a.Count, cmp.Compare(a.Term, b.Term))
+		})
+		result.Facets[field] = terms
+	}
+	return result, nil
+}
+
+func (b *bleveIndex) Close() error {
+	return b.index.Close()
+}
@@ -0,0 +1,9 @@
+//go:build !bleve
+
+package search
+
+// NewBleveIndex is not available without the bleve build tag, which adds
+// the Bleve dependency
+func NewBleveIndex(path string) (Index, error) {
+	return nil, ErrIndexDisabled
+}
@@ -0,0 +1,122 @@
+//go:build bleve
+
+package search
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+func TestBleveIndex(t *testing.T) {
+	index, err := NewBleveIndex("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer index.Close()
+	sources := map[string]string{
+		"soup":     ">> title: Tomato Soup\n>> tags: vegan, quick\n\nSimmer the @tomatoes{400%g} with @garlic{} for ~{20%minutes}.",
+		"pasta":    ">> title: Garlic Pasta\n>> tags: quick\n\nBoil the @pasta{200%g} for ~{10%minutes} and toss with @garlic{}.",
+		"stew":     ">> title: Beef Stew\n\nBraise the @beef{1%kg} with @tomatoes{} for ~{3%hours}.",
+		"obsolete": ">> title: Old Stew\n\nBoil the @beef{}.",
+	}
+	for id, src := range sources {
+		r, err := cooklang.ParseString(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := index.Add(id, r); err != nil {
+			t.Fatalf("Add(%q) error = %v", id, err)
+		}
+	}
+	if err := index.Delete("obsolete"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		query  Query
+		hits   []string
+		facets map[string][]FacetTerm
+	}{
+		{
+			"Text",
+			Query{Text: "braised"},
+			[]string{"stew"},
+			map[string][]FacetTerm{},
+		},
+		{
+			"Ingredient",
+			Query{Filters: []Filter{{FieldIngredients, "garlic"}}, Facets: []string{FieldTags}},
+			[]string{"pasta", "soup"},
+			map[string][]FacetTerm{FieldTags: {{"quick", 2}, {"vegan", 1}}},
+		},
+		{
+			"Filters",
+			Query{Text: "tomatoes", Filters: []Filter{{FieldTags, "vegan"}, {FieldTimeBucket, Time15To30}}},
+			[]string{"soup"},
+			map[string][]FacetTerm{},
+		},
+		{
+			"Facets",
+			Query{Facets: []string{FieldTimeBucket}},
+			[]string{"pasta", "soup", "stew"},
+			map[string][]FacetTerm{FieldTimeBucket: {{Time15To30, 1}, {TimeOver120, 1}, {TimeUnder15, 1}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := index.Search(tt.query)
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			if got.Total != uint64(len(tt.hits)) {
+				t.Errorf("Search() total = %d, want %d", got.Total, len(tt.hits))
+			}
+			hits := make(map[string]bool)
+			for _, hit := range got.Hits {
+				hits[hit.ID] = true
+			}
+			for _, id := range tt.hits {
+				if !hits[id] {
+					t.Errorf("Search() hits = %v, want %v", got.Hits, tt.hits)
+				}
+			}
+			if !reflect.DeepEqual(got.Facets, tt.facets) {
+				t.Errorf("Search() facets = %v, want %v", got.Facets, tt.facets)
+			}
+		})
+	}
+}
+
+func TestBleveIndexPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recipes.bleve")
+	index, err := NewBleveIndex(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := cooklang.ParseString("Toast the @bread{}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := index.Add("toast", r); err != nil {
+		t.Fatal(err)
+	}
+	if err := index.Close(); err != nil {
+		t.Fatal(err)
+	}
+	index, err = NewBleveIndex(path)
+	if err != nil {
+		t.Fatalf("NewBleveIndex() reopen error = %v", err)
+	}
+	defer index.Close()
+	got, err := index.Search(Query{Text: "bread"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Hit{{"toast", got.Hits[0].Score}}; !reflect.DeepEqual(got.Hits, want) {
+		t.Errorf("Search() hits = %v, want %v", got.Hits, want)
+	}
+}
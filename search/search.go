@@ -0,0 +1,155 @@
+// Package search flattens recipes into documents for full text search
+// engines like Elasticsearch or Bleve. The Bleve backed Index is built with
+// the bleve build tag, which adds the Bleve dependency.
+package search
+
+import (
+	"errors"
+	"time"
+
+	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/export"
+)
+
+// Fields of RecipeDocument, used in the Query filters and facets
+const (
+	FieldTitle        = "title"
+	FieldTags         = "tags"
+	FieldIngredients  = "ingredients"
+	FieldCookware     = "cookware"
+	FieldSteps        = "steps"
+	FieldTotalMinutes = "total_minutes"
+	FieldTimeBucket   = "time_bucket"
+)
+
+// Time buckets of the recipe total time
+const (
+	TimeUnder15 = "under 15m"
+	Time15To30  = "15-30m"
+	Time30To60  = "30-60m"
+	Time60To120 = "1-2h"
+	TimeOver120 = "over 2h"
+)
+
+// timeBuckets are the upper bounds (exclusive) of the time buckets
+var timeBuckets = []struct {
+	limit  time.Duration
+	bucket string
+}{
+	{15 * time.Minute, TimeUnder15},
+	{30 * time.Minute, Time15To30},
+	{time.Hour, Time30To60},
+	{2 * time.Hour, Time60To120},
+}
+
+// ErrIndexDisabled is returned by NewBleveIndex without the bleve build tag
+var ErrIndexDisabled = errors.New("search index is not supported, build with -tags bleve")
+
+// RecipeDocument is the search friendly form of a recipe. The JSON field
+// names are the Field constants.
+type RecipeDocument struct {
+	Title        string   `json:"title"`
+	Tags         []string `json:"tags"`
+	Ingredients  []string `json:"ingredients"` // distinct normalized names (see cooklang.NormalizeName)
+	Cookware     []string `json:"cookware"`    // distinct normalized names
+	Steps        []string `json:"steps"`       // directions of the steps
+	TotalMinutes int      `json:"total_minutes,omitempty"`
+	TimeBucket   string   `json:"time_bucket,omitempty"` // Time… constant, empty when the time is unknown
+}
+
+// Document returns the search document of the recipe. The total time is
+// the sum of the prep and cook times (see export.MealTimes).
+func Document(r *cooklang.Recipe) RecipeDocument {
+	d := RecipeDocument{
+		Title:       r.Metadata[export.MetadataTitle],
+		Tags:        []string{},
+		Ingredients: []string{},
+		Cookware:    []string{},
+		Steps:       []string{},
+	}
+	if list := r.MetadataList(export.MetadataTags); list != nil {
+		d.Tags = append(d.Tags, list...)
+	} else if r.Metadata[export.MetadataTags] != "" {
+		d.Tags = cooklang.SplitMetadataList(r.Metadata[export.MetadataTags])
+	}
+	ingredients := make(map[string]bool)
+	cookware := make(map[string]bool)
+	for _, step := range r.Steps {
+		for _, i := range step.Ingredients {
+			if name := cooklang.NormalizeName(i.Name); !ingredients[name] {
+				ingredients[name] = true
+				d.Ingredients = append(d.Ingredients, name)
+			}
+		}
+		for _, c := range step.Cookware {
+			if name := cooklang.NormalizeName(c.Name); !cookware[name] {
+				cookware[name] = true
+				d.Cookware = append(d.Cookware, name)
+			}
+		}
+		if step.Directions != "" {
+			d.Steps = append(d.Steps, step.Directions)
+		}
+	}
+	prep, cook := export.MealTimes(r)
+	if total := prep + cook; total > 0 {
+		d.TotalMinutes = int((total + time.Minute - 1) / time.Minute)
+		d.TimeBucket = TimeBucket(total)
+	}
+	return d
+}
+
+// TimeBucket returns the time bucket of the duration
+func TimeBucket(d time.Duration) string {
+	for _, b := range timeBuckets {
+		if d < b.limit {
+			return b.bucket
+		}
+	}
+	return TimeOver120
+}
+
+// Query is a search of an Index
+type Query struct {
+	Text    string   // free text searched in all the fields, empty matches all the documents
+	Filters []Filter // required field values
+	Facets  []string // fields to count the values of
+	Size    int      // maximum number of hits, 10 when zero
+}
+
+// Filter requires the documents to have the value in the field, like
+// {FieldTags, "vegan"} or {FieldTimeBucket, TimeUnder15}
+type Filter struct {
+	Field string
+	Value string
+}
+
+// Hit is a document found by a Query
+type Hit struct {
+	ID    string
+	Score float64
+}
+
+// FacetTerm is the number of the found documents with a field value
+type FacetTerm struct {
+	Term  string
+	Count int
+}
+
+// Result is the result of a Query
+type Result struct {
+	Total  uint64                 // number of the matching documents
+	Hits   []Hit                  // best matches first
+	Facets map[string][]FacetTerm // most frequent terms first, keyed by field
+}
+
+// Index is a full text index of recipes
+type Index interface {
+	// Add indexes the recipe under the id, replacing the recipe indexed
+	// under the same id
+	Add(id string, r *cooklang.Recipe) error
+	// Delete removes the recipe from the index
+	Delete(id string) error
+	Search(q Query) (*Result, error)
+	Close() error
+}
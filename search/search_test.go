@@ -0,0 +1,77 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+func TestDocument(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   RecipeDocument
+	}{
+		{
+			"Recipe",
+			">> title: Tomato Soup\n>> tags: vegan, quick\n\nFry the @Onion{1} in a #pot{}.\n-- or a pan\nAdd the @tomatoes{400%g} and @onion{} and simmer for ~{20%minutes}.\n",
+			RecipeDocument{
+				Title:        "Tomato Soup",
+				Tags:         []string{"vegan", "quick"},
+				Ingredients:  []string{"onion", "tomatoes"},
+				Cookware:     []string{"pot"},
+				Steps:        []string{"Fry the Onion in a pot.", "Add the tomatoes and onion and simmer for 20 minutes."},
+				TotalMinutes: 20,
+				TimeBucket:   Time15To30,
+			},
+		},
+		{
+			"Metadata time",
+			">> prep time: 10m\n>> cook time: 1h\n\nBake the @bread{}.",
+			RecipeDocument{
+				Tags:         []string{},
+				Ingredients:  []string{"bread"},
+				Cookware:     []string{},
+				Steps:        []string{"Bake the bread."},
+				TotalMinutes: 70,
+				TimeBucket:   Time60To120,
+			},
+		},
+		{
+			"No items",
+			"-- serve cold\nServe.",
+			RecipeDocument{Tags: []string{}, Ingredients: []string{}, Cookware: []string{}, Steps: []string{"Serve."}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := cooklang.ParseString(tt.source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := Document(r); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Document() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeBucket(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{time.Minute, TimeUnder15},
+		{15 * time.Minute, Time15To30},
+		{59 * time.Minute, Time30To60},
+		{time.Hour, Time60To120},
+		{2 * time.Hour, TimeOver120},
+	}
+	for _, tt := range tests {
+		if got := TimeBucket(tt.d); got != tt.want {
+			t.Errorf("TimeBucket(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
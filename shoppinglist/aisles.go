@@ -0,0 +1,99 @@
+package shoppinglist
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/ingredients"
+)
+
+// Aisles maps the normalized ingredient names (see cooklang.NormalizeName)
+// to the shop sections (aisles) they are found in
+type Aisles map[string]string
+
+// LoadAisles reads the aisle configuration of the cooklang tools
+// (aisle.conf). The sections are in brackets and followed by their
+// ingredients, one per line with the synonyms separated by "|":
+//
+//	[dairy]
+//	milk
+//	yogurt | yoghurt
+//
+// Empty lines and lines starting with # are skipped.
+func LoadAisles(r io.Reader) (Aisles, error) {
+	aisles := make(Aisles)
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	section := ""
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: unterminated section", lineNumber)
+			}
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		if section == "" {
+			return nil, fmt.Errorf("line %d: ingredient outside of a section", lineNumber)
+		}
+		for _, name := range strings.Split(line, "|") {
+			if name = cooklang.NormalizeName(name); name != "" {
+				aisles[name] = section
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return aisles, nil
+}
+
+// Section returns the section of the ingredient, trying the singular form
+// of the name when it is not found. Unknown ingredients have no section.
+func (a Aisles) Section(name string) string {
+	name = cooklang.NormalizeName(name)
+	if section, ok := a[name]; ok {
+		return section
+	}
+	return a[ingredients.Singular(name)]
+}
+
+// Section is a shop section and its part of a shopping list
+type Section struct {
+	Name  string // empty for the ingredients without section
+	Items List
+}
+
+// Sections groups the list by the aisles in the order of the first item of
+// every section. The ingredients without section come last.
+func (l List) Sections(aisles Aisles) []Section {
+	var sections []Section
+	index := make(map[string]int)
+	var other List
+	for _, ingredient := range l {
+		name := aisles.Section(ingredient.Name)
+		if name == "" {
+			other = append(other, ingredient)
+			continue
+		}
+		i, ok := index[name]
+		if !ok {
+			i = len(sections)
+			index[name] = i
+			sections = append(sections, Section{Name: name})
+		}
+		sections[i].Items = append(sections[i].Items, ingredient)
+	}
+	if len(other) > 0 {
+		sections = append(sections, Section{Items: other})
+	}
+	return sections
+}
@@ -0,0 +1,58 @@
+package shoppinglist
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testAisles = `# shop layout
+[Baking]
+flour
+[Dairy]
+milk | whole milk
+egg
+`
+
+func TestLoadAisles(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Aisles
+		wantErr bool
+	}{
+		{"Aisles", testAisles, Aisles{"flour": "Baking", "milk": "Dairy", "whole milk": "Dairy", "egg": "Dairy"}, false},
+		{"Outside of section", "flour", nil, true},
+		{"Unterminated section", "[Baking\nflour", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadAisles(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadAisles() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadAisles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestList_Sections(t *testing.T) {
+	aisles, err := LoadAisles(strings.NewReader(testAisles))
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := List{{Name: "Eggs"}, {Name: "salt"}, {Name: "flour"}, {Name: "whole milk"}}
+	want := []Section{
+		{"Dairy", List{{Name: "Eggs"}, {Name: "whole milk"}}},
+		{"Baking", List{{Name: "flour"}}},
+		{"", List{{Name: "salt"}}},
+	}
+	if got := list.Sections(aisles); !reflect.DeepEqual(got, want) {
+		t.Errorf("Sections() = %v, want %v", got, want)
+	}
+	if got := (List{{Name: "salt"}}).Sections(nil); !reflect.DeepEqual(got, []Section{{"", List{{Name: "salt"}}}}) {
+		t.Errorf("Sections(nil) = %v", got)
+	}
+}
@@ -0,0 +1,104 @@
+package shoppinglist
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/ingredients"
+)
+
+// ErrUnknownGrouping is returned by NewGroups for the unsupported groupings
+var ErrUnknownGrouping = errors.New("unknown shopping list grouping")
+
+// Grouping selects the groups of a shopping list
+type Grouping string
+
+// Groupings of the shopping lists
+const (
+	GroupNone      Grouping = ""        // one group with all items
+	GroupByRecipe  Grouping = "recipe"  // a group per recipe
+	GroupBySection Grouping = "section" // a group per recipe section (cooklang.AttributeSection), across the recipes
+)
+
+// Source is a recipe of a shopping list with the name used by GroupByRecipe
+type Source struct {
+	Name   string
+	Recipe *cooklang.Recipe
+}
+
+// Group is a part of a grouped shopping list
+type Group struct {
+	Name  string `json:"name"` // recipe or section name, empty for the items without section
+	Items List   `json:"items"`
+}
+
+// NewGroups returns the shopping list of the recipes grouped by grouping, in
+// the order of the first recipe or step of every group. The ingredients of
+// every group are merged by opts.
+func NewGroups(opts ingredients.MergeOptions, grouping Grouping, sources ...Source) ([]Group, error) {
+	var groups []Group
+	index := make(map[string]int)
+	add := func(name string, items []cooklang.Ingredient) {
+		i, ok := index[name]
+		if !ok {
+			i = len(groups)
+			index[name] = i
+			groups = append(groups, Group{Name: name})
+		}
+		groups[i].Items = append(groups[i].Items, items...)
+	}
+	for _, source := range sources {
+		switch grouping {
+		case GroupNone:
+			add("", source.Recipe.AllIngredients(nil))
+		case GroupByRecipe:
+			add(source.Name, source.Recipe.AllIngredients(nil))
+		case GroupBySection:
+			for _, section := range source.Recipe.IngredientsBySection(nil) {
+				add(section.Name, section.Ingredients)
+			}
+		default:
+			return nil, fmt.Errorf("%w: %q", ErrUnknownGrouping, grouping)
+		}
+	}
+	for i := range groups {
+		groups[i].Items = List(opts.Merge(groups[i].Items))
+	}
+	return groups, nil
+}
+
+// ExportGroups returns the grouped list in the Text or Markdown format with
+// the group names as headings
+func ExportGroups(groups []Group, format Format) (string, error) {
+	var b strings.Builder
+	switch format {
+	case Text:
+		for i, g := range groups {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			if g.Name != "" {
+				b.WriteString(g.Name + ":\n")
+			}
+			for _, item := range g.Items.Items() {
+				b.WriteString(item + "\n")
+			}
+		}
+	case Markdown:
+		b.WriteString("# Shopping list\n")
+		for _, g := range groups {
+			b.WriteString("\n")
+			if g.Name != "" {
+				b.WriteString("## " + g.Name + "\n\n")
+			}
+			for _, item := range g.Items.Items() {
+				b.WriteString("- [ ] " + item + "\n")
+			}
+		}
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,55 @@
+package shoppinglist
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/ingredients"
+)
+
+func testSources(t *testing.T) []Source {
+	t.Helper()
+	pizza, err := cooklang.ParseString("Mix @flour{200%g} and @water{100%ml}.\n\nSpread @tomatoes{200%g}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pizza.Steps[0].Attributes = map[string]any{cooklang.AttributeSection: "Dough"}
+	pizza.Steps[1].Attributes = map[string]any{cooklang.AttributeSection: "Sauce"}
+	bread, err := cooklang.ParseString("Knead @flour{300%g} with @water{200%ml}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bread.Steps[0].Attributes = map[string]any{cooklang.AttributeSection: "Dough"}
+	return []Source{{"Pizza", pizza}, {"Bread", bread}}
+}
+
+func TestNewGroups(t *testing.T) {
+	tests := []struct {
+		grouping Grouping
+		format   Format
+		want     string
+		wantErr  error
+	}{
+		{GroupNone, Text, "500 g flour\n200 g tomato\n300 ml water\n", nil},
+		{GroupByRecipe, Text, "Pizza:\n200 g flour\n200 g tomato\n100 ml water\n\nBread:\n300 g flour\n200 ml water\n", nil},
+		{GroupBySection, Markdown, "# Shopping list\n\n## Dough\n\n- [ ] 500 g flour\n- [ ] 300 ml water\n\n## Sauce\n\n- [ ] 200 g tomato\n", nil},
+		{"aisle", Text, "", ErrUnknownGrouping},
+		{GroupNone, URL, "", ErrUnknownFormat},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.grouping)+"/"+string(tt.format), func(t *testing.T) {
+			groups, err := NewGroups(ingredients.MergeOptions{}, tt.grouping, testSources(t)...)
+			if err == nil {
+				var got string
+				got, err = ExportGroups(groups, tt.format)
+				if got != tt.want {
+					t.Errorf("ExportGroups() = %q, want %q", got, tt.want)
+				}
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package shoppinglist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/render"
+)
+
+// Item is a shopping list item pushed to a shopping app
+type Item struct {
+	ID      string `json:"id,omitempty"` // ID of the item in the app, empty for new items
+	Name    string `json:"name"`
+	Amount  string `json:"amount,omitempty"`
+	Section string `json:"section,omitempty"`
+}
+
+// Pusher pushes shopping lists to a shopping app (Bring!, AnyList, ...)
+type Pusher interface {
+	// Push adds the items of the list to the app or updates the items
+	// pushed before
+	Push(ctx context.Context, list List) error
+}
+
+// PushItems returns the list as items of the shopping apps, grouped by the
+// aisles
+func (l List) PushItems(aisles Aisles) []Item {
+	var items []Item
+	for _, section := range l.Sections(aisles) {
+		for _, ingredient := range section.Items {
+			items = append(items, Item{
+				Name:    ingredient.Name,
+				Amount:  render.FormatAmount(ingredient.Amount, nil),
+				Section: section.Name,
+			})
+		}
+	}
+	return items
+}
+
+// DefaultRetries is the number of retries of HTTPPusher requests when
+// HTTPPusher.Retries is not set
+const DefaultRetries = 3
+
+// HTTPPusher is the skeleton of the pushers to HTTP APIs. New items are
+// created with POST to URL and the items pushed before are updated with PUT
+// to URL/{id}. The integrations of the apps fill in Encode and DecodeID for
+// their API. Failed update requests are retried on network errors, 429 and
+// 5xx responses, the create requests only with RetryCreates. It is safe for
+// concurrent use.
+type HTTPPusher struct {
+	URL     string
+	Client  *http.Client  // nil uses http.DefaultClient
+	Header  http.Header   // added to every request (Authorization, ...)
+	Aisles  Aisles        // sections of the items
+	Retries int           // retries of a failed request (default: DefaultRetries)
+	Backoff time.Duration // wait before the first retry, doubled after every retry (default: 500ms)
+	// RetryCreates retries the failed create requests too. The app may
+	// have created the item of a request which timed out or failed, so the
+	// retries can create duplicate items.
+	RetryCreates bool
+	// Encode returns the request body and its content type, nil sends the
+	// item as JSON
+	Encode func(Item) ([]byte, string, error)
+	// DecodeID returns the ID of the item from the response of a create
+	// request, nil reads the id property of a JSON object
+	DecodeID func(body []byte) (string, error)
+
+	mu  sync.Mutex
+	ids map[string]string // item key to ID
+}
+
+// errRetry marks the responses which are retried
+var errRetry = errors.New("temporary failure")
+
+// itemKey is the key of the item in the ID mapping
+func itemKey(name string) string {
+	return cooklang.NormalizeName(name)
+}
+
+// IDs returns a copy of the mapping of the lower case item names to the IDs
+// of the app
+func (p *HTTPPusher) IDs() map[string]string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ids := make(map[string]string, len(p.ids))
+	for k, v := range p.ids {
+		ids[k] = v
+	}
+	return ids
+}
+
+// SetIDs restores the ID mapping of a previous session, so the items are
+// updated instead of created again
+func (p *HTTPPusher) SetIDs(ids map[string]string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ids = make(map[string]string, len(ids))
+	for k, v := range ids {
+		p.ids[itemKey(k)] = v
+	}
+}
+
+// Push sends every item of the list. All items are tried, the returned
+// error joins the errors of the failed ones.
+func (p *HTTPPusher) Push(ctx context.Context, list List) error {
+	var errs []error
+	for _, item := range list.PushItems(p.Aisles) {
+		key := itemKey(item.Name)
+		p.mu.Lock()
+		item.ID = p.ids[key]
+		p.mu.Unlock()
+		id, err := p.pushItem(ctx, item)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", item.Name, err))
+			continue
+		}
+		if id != "" {
+			p.mu.Lock()
+			if p.ids == nil {
+				p.ids = make(map[string]string)
+			}
+			p.ids[key] = id
+			p.mu.Unlock()
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// pushItem sends the item with retries and returns its ID
+func (p *HTTPPusher) pushItem(ctx context.Context, item Item) (string, error) {
+	encode := p.Encode
+	if encode == nil {
+		encode = encodeJSONItem
+	}
+	body, contentType, err := encode(item)
+	if err != nil {
+		return "", err
+	}
+	method, target := http.MethodPost, p.URL
+	if item.ID != "" {
+		method, target = http.MethodPut, strings.TrimSuffix(p.URL, "/")+"/"+url.PathEscape(item.ID)
+	}
+	retries := p.Retries
+	if retries <= 0 {
+		retries = DefaultRetries
+	}
+	if method == http.MethodPost && !p.RetryCreates {
+		retries = 0
+	}
+	backoff := p.Backoff
+	if backoff <= 0 {
+		backoff = 500 * time.Millisecond
+	}
+	for attempt := 0; ; attempt++ {
+		var response []byte
+		response, err = p.do(ctx, method, target, body, contentType)
+		if err == nil {
+			if item.ID != "" {
+				return item.ID, nil
+			}
+			decode := p.DecodeID
+			if decode == nil {
+				decode = decodeJSONID
+			}
+			return decode(response)
+		}
+		if !errors.Is(err, errRetry) || attempt == retries {
+			return "", err
+		}
+		select {
+		case <-time.After(backoff << attempt):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+}
+
+// do sends the request and returns the response body of the successful
+// responses. Network errors and the responses worth retrying wrap errRetry.
+func (p *HTTPPusher) do(ctx context.Context, method, target string, body []byte, contentType string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, values := range p.Header {
+		for _, v := range values {
+			req.Header.Add(k, v)
+		}
+	}
+	req.Header.Set("Content-Type", contentType)
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("%w: %w", errRetry, err)
+	}
+	defer resp.Body.Close()
+	response, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errRetry, err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return nil, fmt.Errorf("%w: %s", errRetry, resp.Status)
+	case resp.StatusCode >= 300:
+		return nil, fmt.Errorf("unexpected response: %s", resp.Status)
+	}
+	return response, nil
+}
+
+func encodeJSONItem(item Item) ([]byte, string, error) {
+	body, err := json.Marshal(item)
+	return body, "application/json", err
+}
+
+func decodeJSONID(body []byte) (string, error) {
+	var response struct {
+		ID json.RawMessage `json:"id"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", fmt.Errorf("invalid response: %w", err)
+	}
+	var id string
+	if json.Unmarshal(response.ID, &id) == nil {
+		return id, nil
+	}
+	var n json.Number
+	if json.Unmarshal(response.ID, &n) == nil {
+		return n.String(), nil
+	}
+	return "", fmt.Errorf("invalid response: missing id")
+}
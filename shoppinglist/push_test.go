@@ -0,0 +1,116 @@
+package shoppinglist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testApp is a shopping app API failing the first request of every item
+type testApp struct {
+	mu       sync.Mutex
+	requests []string
+	failed   map[string]bool
+	items    map[string]Item
+}
+
+func (a *testApp) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var item Item
+	if err := json.NewDecoder(req.Body).Decode(&item); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	a.requests = append(a.requests, req.Method+" "+req.URL.EscapedPath())
+	if req.Header.Get("Authorization") != "Bearer token" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if !a.failed[item.Name] {
+		a.failed[item.Name] = true
+		http.Error(w, "busy", http.StatusServiceUnavailable)
+		return
+	}
+	id := strings.TrimPrefix(req.URL.Path, "/items/")
+	if req.Method == http.MethodPost {
+		id = fmt.Sprint(len(a.items) + 1)
+	}
+	a.items[id] = item
+	fmt.Fprintf(w, `{"id": %s}`, id)
+}
+
+func TestHTTPPusher(t *testing.T) {
+	app := &testApp{failed: make(map[string]bool), items: make(map[string]Item)}
+	server := httptest.NewServer(app)
+	defer server.Close()
+	p := &HTTPPusher{
+		URL:          server.URL + "/items",
+		Header:       http.Header{"Authorization": {"Bearer token"}},
+		Aisles:       Aisles{"flour": "Baking"},
+		Backoff:      1,
+		RetryCreates: true,
+	}
+	list := List{{Name: "flour"}, {Name: "Milk"}}
+	if err := p.Push(context.Background(), list); err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+	if want := map[string]string{"flour": "1", "milk": "2"}; !reflect.DeepEqual(p.IDs(), want) {
+		t.Errorf("IDs() = %v, want %v", p.IDs(), want)
+	}
+	app.failed = map[string]bool{"flour": true, "Milk": true}
+	if err := p.Push(context.Background(), list); err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+	wantRequests := []string{
+		"POST /items", "POST /items", "POST /items", "POST /items",
+		"PUT /items/1", "PUT /items/2",
+	}
+	if !reflect.DeepEqual(app.requests, wantRequests) {
+		t.Errorf("requests = %v, want %v", app.requests, wantRequests)
+	}
+	wantItems := map[string]Item{
+		"1": {Name: "flour", Section: "Baking", ID: "1"},
+		"2": {Name: "Milk", ID: "2"},
+	}
+	if !reflect.DeepEqual(app.items, wantItems) {
+		t.Errorf("items = %v, want %v", app.items, wantItems)
+	}
+}
+
+func TestHTTPPusherErrors(t *testing.T) {
+	app := &testApp{failed: make(map[string]bool), items: make(map[string]Item)}
+	server := httptest.NewServer(app)
+	defer server.Close()
+	p := &HTTPPusher{URL: server.URL + "/items", Retries: 1, Backoff: 1}
+	p.SetIDs(map[string]string{"Flour": "7"})
+	err := p.Push(context.Background(), List{{Name: "flour"}, {Name: "milk"}})
+	if err == nil || !strings.Contains(err.Error(), "flour: unexpected response: 401 Unauthorized") || !strings.Contains(err.Error(), "milk: ") {
+		t.Errorf("Push() error = %v", err)
+	}
+	if want := []string{"PUT /items/7", "POST /items"}; !reflect.DeepEqual(app.requests, want) {
+		t.Errorf("requests = %v, want %v", app.requests, want)
+	}
+}
+
+func TestHTTPPusherCreateRetries(t *testing.T) {
+	app := &testApp{failed: make(map[string]bool), items: make(map[string]Item)}
+	server := httptest.NewServer(app)
+	defer server.Close()
+	p := &HTTPPusher{URL: server.URL + "/items", Header: http.Header{"Authorization": {"Bearer token"}}, Backoff: 1}
+	p.SetIDs(map[string]string{"flour": "a/b c"})
+	err := p.Push(context.Background(), List{{Name: "flour"}, {Name: "milk"}})
+	if err == nil || !strings.Contains(err.Error(), "milk: ") || strings.Contains(err.Error(), "flour: ") {
+		t.Errorf("Push() error = %v", err)
+	}
+	// the update is retried, the create is not
+	if want := []string{"PUT /items/a%2Fb%20c", "PUT /items/a%2Fb%20c", "POST /items"}; !reflect.DeepEqual(app.requests, want) {
+		t.Errorf("requests = %v, want %v", app.requests, want)
+	}
+}
@@ -0,0 +1,81 @@
+// Package shoppinglist builds the shopping lists of recipes and exports them
+// in formats which can be sent to phones
+package shoppinglist
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/ingredients"
+	"github.com/aquilax/cooklang-go/render"
+)
+
+// ErrUnknownFormat is returned by Export for the unsupported formats
+var ErrUnknownFormat = errors.New("unknown shopping list format")
+
+// Format is an export format of the shopping lists
+type Format string
+
+// Export formats
+const (
+	Text     Format = "text"     // one item per line ("200 g flour")
+	Markdown Format = "markdown" // Markdown task list ("- [ ] 200 g flour")
+	URL      Format = "url"      // URLPrefix followed by the items as query parameters
+)
+
+// URLPrefix starts the URLs of the URL format. The items are added as item
+// query parameters, so the URL can be encoded in a QR code and opened by an
+// app registered for the prefix.
+var URLPrefix = "cooklang:shopping-list?"
+
+// List is a shopping list
+type List []cooklang.Ingredient
+
+// New returns the shopping list of the recipes with the ingredients merged
+// by opts
+func New(opts ingredients.MergeOptions, recipes ...*cooklang.Recipe) List {
+	var list []cooklang.Ingredient
+	for _, r := range recipes {
+		list = append(list, r.AllIngredients(nil)...)
+	}
+	return List(opts.Merge(list))
+}
+
+// Items returns the items as text: the amount followed by the name
+func (l List) Items() []string {
+	items := make([]string, len(l))
+	for i, ingredient := range l {
+		items[i] = strings.TrimSpace(render.FormatAmount(ingredient.Amount, nil) + " " + ingredient.Name)
+	}
+	return items
+}
+
+// Export returns the list in the format
+func (l List) Export(format Format) (string, error) {
+	var b strings.Builder
+	switch format {
+	case Text:
+		for _, item := range l.Items() {
+			b.WriteString(item + "\n")
+		}
+	case Markdown:
+		b.WriteString("# Shopping list\n\n")
+		for _, item := range l.Items() {
+			b.WriteString("- [ ] " + item + "\n")
+		}
+	case URL:
+		b.WriteString(URLPrefix)
+		for i, item := range l.Items() {
+			if i > 0 {
+				b.WriteString("&")
+			}
+			b.WriteString("item=" + url.QueryEscape(item))
+		}
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,58 @@
+package shoppinglist
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/ingredients"
+)
+
+func testList(t *testing.T) List {
+	t.Helper()
+	var recipes []*cooklang.Recipe
+	for _, source := range []string{
+		"Mix @flour{200%g} with @milk{1/2%cup} and @salt{a pinch}.",
+		"Knead @flour{100%g} & @eggs{2}.",
+	} {
+		r, err := cooklang.ParseString(source)
+		if err != nil {
+			t.Fatal(err)
+		}
+		recipes = append(recipes, r)
+	}
+	return New(ingredients.MergeOptions{}, recipes...)
+}
+
+func TestList_Items(t *testing.T) {
+	want := []string{"2 egg", "300 g flour", "0.5 cup milk", "a pinch salt"}
+	if got := testList(t).Items(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Items() = %v, want %v", got, want)
+	}
+}
+
+func TestList_Export(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  Format
+		want    string
+		wantErr error
+	}{
+		{"Text", Text, "2 egg\n300 g flour\n0.5 cup milk\na pinch salt\n", nil},
+		{"Markdown", Markdown, "# Shopping list\n\n- [ ] 2 egg\n- [ ] 300 g flour\n- [ ] 0.5 cup milk\n- [ ] a pinch salt\n", nil},
+		{"URL", URL, "cooklang:shopping-list?item=2+egg&item=300+g+flour&item=0.5+cup+milk&item=a+pinch+salt", nil},
+		{"Unknown", "pdf", "", ErrUnknownFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := testList(t).Export(tt.format)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Export() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Export() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
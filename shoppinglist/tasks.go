@@ -0,0 +1,104 @@
+package shoppinglist
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+
+	"github.com/aquilax/cooklang-go/render"
+)
+
+// Task is an item of the shopping list as a task of a task app: the
+// ingredient name is the title and the amount is in the notes
+type Task struct {
+	Title   string `json:"title"`
+	Notes   string `json:"notes,omitempty"`
+	Section string `json:"section,omitempty"`
+}
+
+// Tasks returns the items as tasks grouped by the aisles (see Sections)
+func (l List) Tasks(aisles Aisles) []Task {
+	var tasks []Task
+	for _, item := range l.PushItems(aisles) {
+		tasks = append(tasks, Task{item.Name, item.Amount, item.Section})
+	}
+	return tasks
+}
+
+// todoistColumns are the columns of the Todoist CSV template
+var todoistColumns = []string{"TYPE", "CONTENT", "DESCRIPTION", "PRIORITY", "INDENT", "AUTHOR", "RESPONSIBLE", "DATE", "DATE_LANG", "TIMEZONE"}
+
+// WriteTodoistCSV writes the list as Todoist CSV template, which can be
+// imported into a project. The aisles become sections and the amounts are
+// the task descriptions. The items without section come first, as the
+// tasks after a section row belong to the section.
+func (l List) WriteTodoistCSV(w io.Writer, aisles Aisles) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(todoistColumns); err != nil {
+		return err
+	}
+	row := func(kind, content, description, priority, indent string) error {
+		return cw.Write([]string{kind, content, description, priority, indent, "", "", "", "", ""})
+	}
+	sections := l.Sections(aisles)
+	if n := len(sections); n > 0 && sections[n-1].Name == "" {
+		sections = append([]Section{sections[n-1]}, sections[:n-1]...)
+	}
+	for _, section := range sections {
+		if section.Name != "" {
+			if err := row("section", section.Name, "", "", ""); err != nil {
+				return err
+			}
+		}
+		for _, ingredient := range section.Items {
+			if err := row("task", ingredient.Name, render.FormatAmount(ingredient.Amount, nil), "4", "1"); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// anyDoTask is an Any.do task, the aisle is the category
+type anyDoTask struct {
+	Title    string `json:"title"`
+	Note     string `json:"note,omitempty"`
+	Category string `json:"category,omitempty"`
+}
+
+// WriteAnyDoJSON writes the list as JSON array of Any.do tasks with the
+// aisles as categories and the amounts in the notes
+func (l List) WriteAnyDoJSON(w io.Writer, aisles Aisles) error {
+	tasks := make([]anyDoTask, 0, len(l))
+	for _, t := range l.Tasks(aisles) {
+		tasks = append(tasks, anyDoTask{t.Title, t.Notes, t.Section})
+	}
+	return writeJSON(w, tasks)
+}
+
+// remindersList is the payload of the Apple Reminders import, which is read
+// by a Shortcuts automation ("Get Dictionary from Input" and "Add New
+// Reminder" for every item)
+type remindersList struct {
+	List  string `json:"list"`
+	Items []Task `json:"items"`
+}
+
+// WriteRemindersJSON writes the list as JSON for an Apple Shortcuts
+// automation adding the items to the Reminders list with the name. The
+// aisles are the sections of the reminders.
+func (l List) WriteRemindersJSON(w io.Writer, name string, aisles Aisles) error {
+	items := l.Tasks(aisles)
+	if items == nil {
+		items = make([]Task, 0)
+	}
+	return writeJSON(w, remindersList{name, items})
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
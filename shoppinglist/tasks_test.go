@@ -0,0 +1,80 @@
+package shoppinglist
+
+import (
+	"strings"
+	"testing"
+)
+
+func testTaskList(t *testing.T) (List, Aisles) {
+	t.Helper()
+	aisles, err := LoadAisles(strings.NewReader(testAisles))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testList(t), aisles
+}
+
+func TestList_WriteTodoistCSV(t *testing.T) {
+	list, aisles := testTaskList(t)
+	var b strings.Builder
+	if err := list.WriteTodoistCSV(&b, aisles); err != nil {
+		t.Fatalf("WriteTodoistCSV() error = %v", err)
+	}
+	want := `TYPE,CONTENT,DESCRIPTION,PRIORITY,INDENT,AUTHOR,RESPONSIBLE,DATE,DATE_LANG,TIMEZONE
+task,salt,a pinch,4,1,,,,,
+section,Dairy,,,,,,,,
+task,egg,2,4,1,,,,,
+task,milk,0.5 cup,4,1,,,,,
+section,Baking,,,,,,,,
+task,flour,300 g,4,1,,,,,
+`
+	if got := b.String(); got != want {
+		t.Errorf("WriteTodoistCSV() = %s, want %s", got, want)
+	}
+}
+
+func TestList_WriteAnyDoJSON(t *testing.T) {
+	list, aisles := testTaskList(t)
+	var b strings.Builder
+	if err := list[2:].WriteAnyDoJSON(&b, aisles); err != nil {
+		t.Fatalf("WriteAnyDoJSON() error = %v", err)
+	}
+	want := `[
+  {
+    "title": "milk",
+    "note": "0.5 cup",
+    "category": "Dairy"
+  },
+  {
+    "title": "salt",
+    "note": "a pinch"
+  }
+]
+`
+	if got := b.String(); got != want {
+		t.Errorf("WriteAnyDoJSON() = %s, want %s", got, want)
+	}
+}
+
+func TestList_WriteRemindersJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		list List
+		want string
+	}{
+		{"Empty", List{}, "{\n  \"list\": \"Groceries\",\n  \"items\": []\n}\n"},
+		{"Items", List{{Name: "flour"}}, "{\n  \"list\": \"Groceries\",\n  \"items\": [\n    {\n      \"title\": \"flour\",\n      \"section\": \"Baking\"\n    }\n  ]\n}\n"},
+	}
+	_, aisles := testTaskList(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			if err := tt.list.WriteRemindersJSON(&b, "Groceries", aisles); err != nil {
+				t.Fatalf("WriteRemindersJSON() error = %v", err)
+			}
+			if got := b.String(); got != tt.want {
+				t.Errorf("WriteRemindersJSON() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
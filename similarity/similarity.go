@@ -0,0 +1,112 @@
+// Package similarity compares recipes to find the duplicates of recipe
+// collections, like the copies of a recipe in merged recipe folders
+package similarity
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/ingredients"
+)
+
+// DuplicateThreshold is the Score from which FindDuplicates reports the
+// recipes as duplicates
+const DuplicateThreshold = 0.8
+
+// shingleSize is the number of words of the direction shingles
+const shingleSize = 3
+
+// features are the compared parts of a recipe
+type features struct {
+	ingredients map[string]bool // lower case singular names
+	shingles    map[string]bool // word sequences of the directions
+}
+
+func newFeatures(r *cooklang.Recipe) features {
+	f := features{ingredients: make(map[string]bool), shingles: make(map[string]bool)}
+	var words []string
+	for _, step := range r.Steps {
+		for _, i := range step.Ingredients {
+			f.ingredients[ingredients.Singular(cooklang.NormalizeName(i.Name))] = true
+		}
+		words = append(words, strings.FieldsFunc(strings.ToLower(step.Directions), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})...)
+	}
+	if len(words) > 0 && len(words) < shingleSize {
+		f.shingles[strings.Join(words, " ")] = true
+	}
+	for i := 0; i+shingleSize <= len(words); i++ {
+		f.shingles[strings.Join(words[i:i+shingleSize], " ")] = true
+	}
+	return f
+}
+
+// jaccard returns the size of the intersection of the sets divided by the
+// size of their union, 1 for two empty sets
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	common := 0
+	for k := range a {
+		if b[k] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(a)+len(b)-common)
+}
+
+func (f features) score(other features) float64 {
+	return (jaccard(f.ingredients, other.ingredients) + jaccard(f.shingles, other.shingles)) / 2
+}
+
+// Score returns the similarity of the recipes from 0 (nothing in common) to
+// 1 (the same ingredients and directions). It is the mean of the Jaccard
+// indexes of the ingredient names and of the three word sequences of the
+// directions, so reworded copies score lower than copies with changed
+// amounts.
+func Score(a, b *cooklang.Recipe) float64 {
+	return newFeatures(a).score(newFeatures(b))
+}
+
+// Duplicate is a pair of similar recipes of a collection
+type Duplicate struct {
+	A, B  string // keys of the recipes in the collection, A < B
+	Score float64
+}
+
+// FindDuplicates returns the pairs of recipes of the collection (see
+// cooklang.ParseDir) with a Score of at least DuplicateThreshold
+func FindDuplicates(collection map[string]*cooklang.Recipe) []Duplicate {
+	return FindSimilar(collection, DuplicateThreshold)
+}
+
+// FindSimilar returns the pairs of recipes of the collection with a Score of
+// at least threshold, the most similar first
+func FindSimilar(collection map[string]*cooklang.Recipe, threshold float64) []Duplicate {
+	keys := make([]string, 0, len(collection))
+	for k := range collection {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	all := make([]features, len(keys))
+	for i, k := range keys {
+		all[i] = newFeatures(collection[k])
+	}
+	var result []Duplicate
+	for i := range keys {
+		for j := i + 1; j < len(keys); j++ {
+			if score := all[i].score(all[j]); score >= threshold {
+				result = append(result, Duplicate{keys[i], keys[j], score})
+			}
+		}
+	}
+	slices.SortStableFunc(result, func(a, b Duplicate) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	return result
+}
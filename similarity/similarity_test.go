@@ -0,0 +1,55 @@
+package similarity
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+func parse(t *testing.T, s string) *cooklang.Recipe {
+	t.Helper()
+	r, err := cooklang.ParseString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestScore(t *testing.T) {
+	pancakes := "Mix @flour{200%g} with @milk{300%ml} and @eggs{2}.\n\nFry in a #pan for ~{2%minutes}."
+	tests := []struct {
+		name string
+		a, b string
+		want float64
+	}{
+		{"same", pancakes, pancakes, 1},
+		{"other amounts", pancakes, "Mix @flour{250%g} with @milk{1%cup} and @eggs{3}.\n\nFry in a #pan for ~{2%minutes}.", 1},
+		{"nothing in common", pancakes, "Chop @onions{2} finely.", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Score(parse(t, tt.a), parse(t, tt.b)); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Score() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	collection := map[string]*cooklang.Recipe{
+		"b/pancakes.cook":   parse(t, "Mix @flour{250%g} with @milk{300%ml} and @eggs{2}.\n\nFry in a #pan."),
+		"a/pancakes.cook":   parse(t, "Mix @flour{200%g} with @milk{300%ml} and @eggs{2}.\n\nFry in a #pan."),
+		"a/crepes.cook":     parse(t, "Mix @flour{100%g} with @milk{300%ml}, @eggs{2} and @butter{20%g}.\n\nFry thinly in a #pan."),
+		"a/onion-soup.cook": parse(t, "Chop @onions{4} and simmer in @stock{1%l}."),
+	}
+	got := FindDuplicates(collection)
+	want := []Duplicate{{"a/pancakes.cook", "b/pancakes.cook", 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindDuplicates() = %v, want %v", got, want)
+	}
+	if got := FindSimilar(collection, 0.3); len(got) != 3 || got[0].Score != 1 {
+		t.Errorf("FindSimilar(0.3) = %v, want the 3 pairs of pancakes and crepes", got)
+	}
+}
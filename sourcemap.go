@@ -0,0 +1,93 @@
+package cooklang
+
+import "strings"
+
+// SourcePosition is a position in the recipe source
+type SourcePosition struct {
+	Line   int // one based line number
+	Column int // one based byte column
+}
+
+// ItemSource is the source position of a step item
+type ItemSource struct {
+	Type ItemType // ItemTypeIngredient, ItemTypeCookware or ItemTypeTimer
+	SourcePosition
+}
+
+// StepSource is the source position of a step and its items
+type StepSource struct {
+	SourcePosition
+	Items []ItemSource // items of the step in source order
+}
+
+// SourceMap links the steps of a recipe and their items back to the source
+// the recipe was parsed from, so the rendered output can point to the
+// source lines (see render.Options.SourceMap)
+type SourceMap struct {
+	Steps []StepSource // source of the steps indexed like Recipe.Steps
+}
+
+// Step returns the source of the step with the index of Recipe.Steps
+func (m *SourceMap) Step(index int) (StepSource, bool) {
+	if m == nil || index < 0 || index >= len(m.Steps) {
+		return StepSource{}, false
+	}
+	return m.Steps[index], true
+}
+
+// NewSourceMap returns the source map of the recipe source parsed with the
+// default configuration. Errors are reported as by ParseCST.
+func NewSourceMap(src string) (*SourceMap, error) {
+	cst, err := ParseCST(src)
+	if err != nil {
+		return nil, err
+	}
+	var m SourceMap
+	// offset of the line of the node next, updated by position
+	lineStart, next := 0, 0
+	position := func(i int) SourcePosition {
+		for ; next < i; next++ {
+			n := cst.Nodes[next]
+			if last := strings.LastIndexByte(n.Raw, '\n'); last != -1 {
+				lineStart = n.Offset + last + 1
+			}
+		}
+		return SourcePosition{cst.Nodes[i].Line, cst.Nodes[i].Offset - lineStart + 1}
+	}
+	for _, span := range cstSteps(cst.Nodes) {
+		step := StepSource{SourcePosition: position(span[0])}
+		for i := span[0]; i < span[1]; i++ {
+			switch t := cst.Nodes[i].Type; t {
+			case ItemTypeIngredient, ItemTypeCookware, ItemTypeTimer:
+				step.Items = append(step.Items, ItemSource{t, position(i)})
+			}
+		}
+		m.Steps = append(m.Steps, step)
+	}
+	return &m, nil
+}
+
+// cstSteps returns the node index ranges of the steps of the tree: the step
+// lines and the line comments
+func cstSteps(nodes []CSTNode) [][2]int {
+	var steps [][2]int
+	inStep := false
+	for i, n := range nodes {
+		switch {
+		case n.Type == ItemTypeNewline:
+			inStep = false
+		case n.Type == ItemTypeMetadata, !inStep && isBlankLine(nodes, i):
+		case inStep:
+			steps[len(steps)-1][1] = i + 1
+		default:
+			steps = append(steps, [2]int{i, i + 1})
+			inStep = true
+		}
+	}
+	return steps
+}
+
+// isBlankLine reports whether the node is a blank line
+func isBlankLine(nodes []CSTNode, i int) bool {
+	return nodes[i].Type == ItemTypeText && strings.TrimSpace(nodes[i].Raw) == "" && (i+1 == len(nodes) || nodes[i+1].Type == ItemTypeNewline)
+}
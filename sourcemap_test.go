@@ -0,0 +1,32 @@
+package cooklang
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewSourceMap(t *testing.T) {
+	src := ">> title: Soup\n\nBoil @water{1%l} in a #pot.\n-- taste it\n  Add @salt [- a\nlot -] for ~{5%minutes}.\n"
+	r, err := ParseString(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewSourceMap(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &SourceMap{Steps: []StepSource{
+		{SourcePosition{3, 1}, []ItemSource{{ItemTypeIngredient, SourcePosition{3, 6}}, {ItemTypeCookware, SourcePosition{3, 23}}}},
+		{SourcePosition{4, 1}, nil},
+		{SourcePosition{5, 1}, []ItemSource{{ItemTypeIngredient, SourcePosition{5, 7}}, {ItemTypeTimer, SourcePosition{6, 12}}}},
+	}}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("NewSourceMap() = %+v, want %+v", m, want)
+	}
+	if len(m.Steps) != len(r.Steps) {
+		t.Errorf("NewSourceMap() has %d steps, want %d like the recipe", len(m.Steps), len(r.Steps))
+	}
+	if _, ok := m.Step(3); ok {
+		t.Error("Step(3) found a step out of range")
+	}
+}
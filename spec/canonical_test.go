@@ -1,113 +1,75 @@
-package canonical_test
+package spec_test
 
 import (
-	"encoding/json"
-	"io"
+	"bufio"
+	"flag"
 	"os"
-	"slices"
+	"strings"
 	"testing"
 
 	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/spec"
 	"github.com/stretchr/testify/assert"
 )
 
-type Result struct {
-	Steps [][]struct {
-		Type     string      `json:"type"`
-		Value    string      `json:"value,omitempty"`
-		Name     string      `json:"name,omitempty"`
-		Quantity interface{} `json:"quantity,omitempty"`
-		Units    string      `json:"units,omitempty"`
-	} `json:"steps"`
-	Metadata interface{} `json:"metadata"`
-}
-
-type TestCase struct {
-	Source string `json:"source"`
-	Result Result `json:"result"`
-}
+// failuresFileName lists the canonical cases known to fail, one per line.
+// It is rewritten by "go test ./spec -update".
+const failuresFileName = "failures.txt"
 
-type SpecTests struct {
-	Version int                 `json:"version"`
-	Tests   map[string]TestCase `json:"tests"`
-}
+var update = flag.Bool("update", false, "rewrite "+failuresFileName+" with the failing cases")
 
-const specFileName = "canonical.json"
-
-func loadSpecs(fileName string) (*SpecTests, error) {
-	var err error
-	var jsonFile *os.File
-	jsonFile, err = os.Open(fileName)
+func loadFailures(t *testing.T) map[string]bool {
+	t.Helper()
+	f, err := os.Open(failuresFileName)
 	if err != nil {
-		return nil, err
+		t.Fatal(err)
 	}
-	defer jsonFile.Close()
-
-	b, _ := io.ReadAll(jsonFile)
-
-	var result *SpecTests
-	err = json.Unmarshal(b, &result)
-	if err != nil {
-		return nil, err
+	defer f.Close()
+	failures := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if name := strings.TrimSpace(scanner.Text()); name != "" && !strings.HasPrefix(name, "#") {
+			failures[name] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
 	}
-	return result, nil
+	return failures
 }
 
 func TestCanonical(t *testing.T) {
-	specs, err := loadSpecs(specFileName)
+	tests, err := spec.Canonical()
 	if err != nil {
-		panic(err)
+		t.Fatal(err)
 	}
-	skipCases := []string{}
-	skipResultChecks := []string{
-		"testQuantityAsText",
-		"testSingleWordCookwareWithUnicodePunctuation",
-		"testSingleWordCookwareWithPunctuation",
-		"testIngredientNoUnits",
-		"testEquipmentQuantityMultipleWords",
-		"testIngredientWithEmoji",
-		"testSingleWordIngredientWithUnicodePunctuation",
-		"testMutipleIngredientsWithoutStopper",
-		"testTimerWithUnicodeWhitespace",
-		"testIngredientWithoutStopper",
-		"testSingleWordIngredientWithPunctuation",
-		"testSingleWordTimer",
-		"testSingleWordTimerWithUnicodePunctuation",
-		"testInvalidSingleWordIngredient",
-		"testInvalidMultiWordIngredient",
-		"testMultiWordIngredientNoAmount",
-		"testEquipmentQuantityOneWord",
-		"testQuantityDigitalString",
-		"testCookwareWithUnicodeWhitespace",
-		"testFractionsLike",
-		"testIngredientWithUnicodeWhitespace",
-		"testInvalidMultiWordTimer",
-		"testSingleWordTimerWithPunctuation",
-		"testIngredientMultipleWordsWithLeadingNumber",
-		"testIngredientNoUnitsNotOnlyString",
-		"testInvalidMultiWordCookware",
-	}
-	for name, spec := range (*specs).Tests {
-		name := name
-		spec := spec
-		t.Run(name, func(t *testing.T) {
-			assert := assert.New(t)
-			t.Parallel()
-			if slices.Contains(skipCases, name) {
-				t.Skip(name)
+	parser := cooklang.NewParserV2(&cooklang.ParseV2Config{IgnoreTypes: []cooklang.ItemType{cooklang.ItemTypeComment}})
+	results := spec.Run(tests, parser)
+	if *update {
+		var b strings.Builder
+		b.WriteString("# canonical cases known to fail, generated by go test ./spec -update\n")
+		for _, r := range results {
+			if !r.Passed {
+				b.WriteString(r.Name + "\n")
 			}
-			parserV2 := cooklang.NewParserV2(&cooklang.ParseV2Config{IgnoreTypes: []cooklang.ItemType{cooklang.ItemTypeComment}})
-
-			r, err := parserV2.ParseString(spec.Source)
-			assert.NoError(err)
-
-			if !slices.Contains(skipResultChecks, name) {
-				gotJson, err := json.Marshal(r)
-				assert.NoError(err)
-				expectJson, err := json.Marshal(spec.Result)
-				assert.NoError(err)
-
-				assert.JSONEq(string(expectJson), string(gotJson))
+		}
+		if err := os.WriteFile(failuresFileName, []byte(b.String()), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	failures := loadFailures(t)
+	for _, r := range results {
+		t.Run(r.Name, func(t *testing.T) {
+			switch {
+			case failures[r.Name] && r.Passed:
+				t.Errorf("%s passes now, remove it from %s (go test ./spec -update)", r.Name, failuresFileName)
+			case failures[r.Name]:
+				t.Skip("known failure")
+			case r.Err != nil:
+				t.Error(r.Err)
+			case !r.Passed:
+				assert.JSONEq(t, r.Want, r.Got)
 			}
 		})
 	}
@@ -0,0 +1,52 @@
+// Command fetch downloads the canonical tests of the cooklang specification
+// and writes them as JSON, the format loaded by the spec package:
+//
+//	go run ./internal/fetch -o canonical.json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+const canonicalURL = "https://raw.githubusercontent.com/cooklang/spec/main/tests/canonical.yaml"
+
+func main() {
+	url := flag.String("url", canonicalURL, "URL of the canonical tests in YAML")
+	output := flag.String("o", "canonical.json", "output file")
+	flag.Parse()
+	if err := fetch(*url, *output); err != nil {
+		fmt.Fprintln(os.Stderr, "fetch:", err)
+		os.Exit(1)
+	}
+}
+
+func fetch(url, output string) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var tests any
+	if err := yaml.Unmarshal(data, &tests); err != nil {
+		return fmt.Errorf("%s: %w", url, err)
+	}
+	result, err := json.MarshalIndent(tests, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(output, append(result, '\n'), 0o644)
+}
@@ -0,0 +1,153 @@
+// Package spec loads the canonical tests of the cooklang specification
+// (https://github.com/cooklang/spec) and runs them against the parser.
+// canonical.json is a copy of the upstream tests, refreshed with
+// "go generate ./spec".
+package spec
+
+//go:generate go run ./internal/fetch -o canonical.json
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+//go:embed canonical.json
+var canonical []byte
+
+// Item is an expected step item
+type Item struct {
+	Type     string `json:"type"`
+	Value    string `json:"value,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Quantity any    `json:"quantity,omitempty"`
+	Units    string `json:"units,omitempty"`
+}
+
+// Result is the expected parse result of a test case
+type Result struct {
+	Steps    [][]Item `json:"steps"`
+	Metadata any      `json:"metadata"`
+}
+
+// TestCase is a canonical test case
+type TestCase struct {
+	Source string `json:"source"`
+	Result Result `json:"result"`
+}
+
+// Tests are the canonical tests by name
+type Tests struct {
+	Version int                 `json:"version"`
+	Tests   map[string]TestCase `json:"tests"`
+}
+
+// Load reads the canonical tests in the JSON format
+func Load(r io.Reader) (*Tests, error) {
+	var tests Tests
+	if err := json.NewDecoder(r).Decode(&tests); err != nil {
+		return nil, err
+	}
+	return &tests, nil
+}
+
+// LoadFile reads the canonical tests from the JSON file
+func LoadFile(name string) (*Tests, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Load(f)
+}
+
+// Canonical returns the bundled copy of the canonical tests
+func Canonical() (*Tests, error) {
+	var tests Tests
+	if err := json.Unmarshal(canonical, &tests); err != nil {
+		return nil, err
+	}
+	return &tests, nil
+}
+
+// CaseResult is the outcome of a test case
+type CaseResult struct {
+	Name   string
+	Passed bool
+	Err    error  // parse error
+	Got    string // JSON of the parsed recipe
+	Want   string // JSON of the expected result
+}
+
+// Run runs the test cases in parallel and returns their results sorted by
+// name. The comments are not part of the canonical results, so the parser
+// should ignore them (ParseV2Config.IgnoreTypes).
+func Run(tests *Tests, parser *cooklang.ParserV2) []CaseResult {
+	names := make([]string, 0, len(tests.Tests))
+	for name := range tests.Tests {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	results := make([]CaseResult, len(names))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range runtime.GOMAXPROCS(0) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = runCase(names[i], tests.Tests[names[i]], parser)
+			}
+		}()
+	}
+	for i := range names {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+func runCase(name string, tc TestCase, parser *cooklang.ParserV2) CaseResult {
+	result := CaseResult{Name: name}
+	want, err := json.Marshal(tc.Result)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Want = string(want)
+	r, err := parser.ParseString(tc.Source)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	got, err := json.Marshal(r)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Got = string(got)
+	result.Passed, result.Err = jsonEqual(got, want)
+	return result
+}
+
+// jsonEqual compares the JSON documents ignoring the formatting and the
+// order of the object keys
+func jsonEqual(a, b []byte) (bool, error) {
+	var va, vb any
+	if err := json.Unmarshal(a, &va); err != nil {
+		return false, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if err := json.Unmarshal(b, &vb); err != nil {
+		return false, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return reflect.DeepEqual(va, vb), nil
+}
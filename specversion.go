@@ -0,0 +1,56 @@
+package cooklang
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownSpecVersion is returned when the configured spec version is not
+// supported
+var ErrUnknownSpecVersion = errors.New("unknown spec version")
+
+// SpecVersion selects the revision of the cooklang specification the parser
+// follows, so old recipe collections keep parsing as they were written.
+// The empty version is SpecVersionLatest.
+type SpecVersion string
+
+const (
+	// SpecVersion2021 is the original specification: timers are always
+	// written with braces (~{10%minutes}, ~eggs{3%minutes}), so a single
+	// word like ~rest is plain text
+	SpecVersion2021 SpecVersion = "2021"
+	// SpecVersion2024 adds the single word timers without duration (~rest),
+	// the hidden (@-salt) and optional (@?parsley, #?thermometer) items,
+	// the fixed quantities (@salt{=1%tsp}), the cookware notes
+	// (#pan(cast iron)) and the block comments spanning several lines
+	// ("[-" without "-]" on the same line, which is text in 2021)
+	SpecVersion2024 SpecVersion = "2024"
+	// SpecVersionLatest is the latest supported revision
+	SpecVersionLatest SpecVersion = "latest"
+)
+
+// specFeatures are the syntax features toggled by the spec version
+type specFeatures struct {
+	bareTimers      bool // single word timers without braces (~rest)
+	hiddenItems     bool // ingredients left out of the directions (@-salt)
+	optionalItems   bool // optional ingredients and cookware (@?parsley, #?thermometer)
+	fixedQuantities bool // quantities not changed by scaling (@salt{=1%tsp})
+	cookwareNotes   bool // cookware notes (#pan(cast iron))
+	// block comments spanning several lines, "[-" is text when the line
+	// does not close it otherwise
+	multilineComments bool
+}
+
+// latestFeatures are the syntax features of SpecVersionLatest
+var latestFeatures = specFeatures{bareTimers: true, hiddenItems: true, optionalItems: true, fixedQuantities: true, cookwareNotes: true, multilineComments: true}
+
+// features returns the syntax features of the spec version
+func (v SpecVersion) features() (specFeatures, error) {
+	switch v {
+	case SpecVersion2021:
+		return specFeatures{}, nil
+	case SpecVersion2024, SpecVersionLatest, "":
+		return latestFeatures, nil
+	}
+	return specFeatures{}, fmt.Errorf("%w: %q", ErrUnknownSpecVersion, string(v))
+}
@@ -0,0 +1,122 @@
+package cooklang
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseConfigSpecVersion(t *testing.T) {
+	recipe := "Let it ~rest{} or ~rest then boil ~eggs{3%minutes}."
+	tests := []struct {
+		version SpecVersion
+		want    []Timer
+		wantErr error
+	}{
+		{"", []Timer{{"rest", 0, ""}, {"rest", 0, ""}, {"eggs", 3, "minutes"}}, nil},
+		{SpecVersionLatest, []Timer{{"rest", 0, ""}, {"rest", 0, ""}, {"eggs", 3, "minutes"}}, nil},
+		{SpecVersion2024, []Timer{{"rest", 0, ""}, {"rest", 0, ""}, {"eggs", 3, "minutes"}}, nil},
+		{SpecVersion2021, []Timer{{"rest", 0, ""}, {"eggs", 3, "minutes"}}, nil},
+		{"1999", nil, ErrUnknownSpecVersion},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.version), func(t *testing.T) {
+			r, err := NewParser(&ParseConfig{SpecVersion: tt.version}).ParseString(recipe)
+			_, errV2 := NewParserV2(&ParseV2Config{SpecVersion: tt.version}).ParseString(recipe)
+			if !errors.Is(err, tt.wantErr) || !errors.Is(errV2, tt.wantErr) {
+				t.Fatalf("error = %v, v2 error = %v, want %v", err, errV2, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(r.Steps[0].Timers, tt.want) {
+				t.Errorf("Timers = %v, want %v", r.Steps[0].Timers, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseConfigSpecVersion2021Items(t *testing.T) {
+	recipe := "Add @-salt{1%tsp}, @?parsley and @pepper{=2%g} to the #?pot{} and #pan{}(cast iron)."
+	tests := []struct {
+		version         SpecVersion
+		wantIngredients []Ingredient
+		wantCookware    []Cookware
+	}{
+		{
+			SpecVersionLatest,
+			[]Ingredient{
+				{Name: "salt", Amount: IngredientAmount{true, 1, "1", "tsp"}, Hidden: true},
+				{Name: "parsley", Amount: IngredientAmount{Quantity: 1}, Optional: true},
+				{Name: "pepper", Amount: IngredientAmount{true, 2, "2", "g"}, Fixed: true},
+			},
+			[]Cookware{{Name: "pot", Quantity: 1, Optional: true}, {Name: "pan", Quantity: 1, Note: "cast iron"}},
+		},
+		{
+			SpecVersion2021,
+			[]Ingredient{
+				{Name: "-salt", Amount: IngredientAmount{true, 1, "1", "tsp"}},
+				{Name: "?parsley", Amount: IngredientAmount{Quantity: 1}},
+				{Name: "pepper", Amount: IngredientAmount{false, 0, "=2", "g"}},
+			},
+			[]Cookware{{Name: "?pot", Quantity: 1}, {Name: "pan", Quantity: 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.version), func(t *testing.T) {
+			r, err := NewParser(&ParseConfig{SpecVersion: tt.version}).ParseString(recipe)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(r.Steps[0].Ingredients, tt.wantIngredients) {
+				t.Errorf("Ingredients = %#v, want %#v", r.Steps[0].Ingredients, tt.wantIngredients)
+			}
+			if !reflect.DeepEqual(r.Steps[0].Cookware, tt.wantCookware) {
+				t.Errorf("Cookware = %#v, want %#v", r.Steps[0].Cookware, tt.wantCookware)
+			}
+		})
+	}
+}
+
+func TestParseConfigSpecVersion2021BlockComments(t *testing.T) {
+	recipe := "Mix [- sifted\nor not -] with @water{}.\nAdd [- a note -] @salt."
+	tests := []struct {
+		version        SpecVersion
+		wantDirections []string
+		wantComments   [][]string
+	}{
+		{SpecVersionLatest, []string{"Mix  with water.", "Add  salt."}, [][]string{{"sifted\nor not"}, {"a note"}}},
+		{SpecVersion2021, []string{"Mix [- sifted", "or not -] with water.", "Add  salt."}, [][]string{nil, nil, {"a note"}}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.version), func(t *testing.T) {
+			p := NewParser(&ParseConfig{SpecVersion: tt.version})
+			r, err := p.ParseString(recipe)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var directions []string
+			var comments [][]string
+			for _, step := range r.Steps {
+				directions = append(directions, step.Directions)
+				comments = append(comments, step.Comments)
+			}
+			if !reflect.DeepEqual(directions, tt.wantDirections) || !reflect.DeepEqual(comments, tt.wantComments) {
+				t.Errorf("Steps = %q, %q, want %q, %q", directions, comments, tt.wantDirections, tt.wantComments)
+			}
+			lines := 0
+			for e, err := range p.Tokenize(strings.NewReader(recipe)) {
+				if err != nil {
+					t.Fatalf("Tokenize() error = %v", err)
+				}
+				if e.Type == EventStepEnd {
+					lines++
+				}
+			}
+			if lines != len(tt.wantDirections) {
+				t.Errorf("Tokenize() steps = %d, want %d", lines, len(tt.wantDirections))
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package cooklang
+
+import (
+	"strings"
+	"time"
+)
+
+// ReadingWordsPerMinute is the reading speed used to estimate the reading
+// time of a recipe
+const ReadingWordsPerMinute = 200
+
+// RecipeStats contains summary counts of a recipe
+type RecipeStats struct {
+	Steps             int           // number of steps with directions
+	Ingredients       int           // number of ingredient items
+	UniqueIngredients int           // number of distinct ingredient names (case insensitive)
+	Cookware          int           // number of distinct cookware names (case insensitive)
+	Timers            int           // number of timer items
+	TotalTime         time.Duration // sum of all timer durations
+	LongestStep       int           // index of the step with the longest timer time or -1
+	LongestStepTime   time.Duration // timer time of the longest step
+	Words             int           // number of words in the directions
+	ReadingTime       time.Duration // estimated time to read the directions
+}
+
+// Stats returns the summary counts of the recipe. Timers with unknown time
+// units are counted but do not contribute to the times.
+func Stats(r *Recipe) RecipeStats {
+	stats := RecipeStats{LongestStep: -1}
+	ingredients := make(map[string]struct{})
+	cookware := make(map[string]struct{})
+	for i, step := range r.Steps {
+		if step.IsComment() {
+			continue
+		}
+		stats.Steps++
+		stats.Ingredients += len(step.Ingredients)
+		for _, ingredient := range step.Ingredients {
+			ingredients[strings.ToLower(ingredient.Name)] = struct{}{}
+		}
+		for _, c := range step.Cookware {
+			cookware[strings.ToLower(c.Name)] = struct{}{}
+		}
+		stats.Timers += len(step.Timers)
+		var stepTime time.Duration
+		for _, timer := range step.Timers {
+			if d, ok := timer.ToDuration(); ok {
+				stepTime += d
+			}
+		}
+		stats.TotalTime += stepTime
+		if stepTime > stats.LongestStepTime {
+			stats.LongestStep = i
+			stats.LongestStepTime = stepTime
+		}
+		stats.Words += len(strings.Fields(step.Directions))
+	}
+	stats.UniqueIngredients = len(ingredients)
+	stats.Cookware = len(cookware)
+	stats.ReadingTime = time.Duration(stats.Words) * time.Minute / ReadingWordsPerMinute
+	return stats
+}
@@ -0,0 +1,57 @@
+package cooklang
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	tests := []struct {
+		name   string
+		recipe string
+		want   RecipeStats
+	}{
+		{
+			"Empty recipe",
+			">> servings: 2",
+			RecipeStats{LongestStep: -1},
+		},
+		{
+			"Recipe",
+			`-- comment only step
+Put @bacon strips{2} and @Eggs{3} in a #frying pan{}.
+Fry for ~{5%minutes} and flip, fry for ~{30%sec} more.
+Add @eggs{1} and @bacon strips{1}, use the #Frying pan{} again.
+Rest ~{1.5%hours} in the #oven.`,
+			RecipeStats{
+				Steps:             4,
+				Ingredients:       4,
+				UniqueIngredients: 2,
+				Cookware:          2,
+				Timers:            3,
+				TotalTime:         95*time.Minute + 30*time.Second,
+				LongestStep:       4,
+				LongestStepTime:   90 * time.Minute,
+				Words:             36,
+				ReadingTime:       10800 * time.Millisecond,
+			},
+		},
+		{
+			"Unknown timer unit",
+			"Wait ~{2%moons} and ~rest.",
+			RecipeStats{Steps: 1, Timers: 2, LongestStep: -1, Words: 5, ReadingTime: 1500 * time.Millisecond},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := ParseString(tt.recipe)
+			if err != nil {
+				t.Fatalf("ParseString() error = %v", err)
+			}
+			if got := Stats(r); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Stats() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
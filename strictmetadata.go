@@ -0,0 +1,54 @@
+package cooklang
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrUnknownMetadataKey is returned in StrictMetadataError mode for the
+// metadata keys which are not allowed
+var ErrUnknownMetadataKey = errors.New("unknown metadata key")
+
+// StandardMetadataKeys are the metadata keys of the cooklang conventions,
+// the allowed keys of StrictMetadata when no keys are configured
+var StandardMetadataKeys = []string{
+	"title", "description", "introduction", "author", "source", "source.name", "source.url",
+	"servings", "serves", "yield", "course", "category", "cuisine", "diet", "tags", "difficulty",
+	"time", "duration", "prep time", "cook time", "time required", "image", "picture", "locale",
+}
+
+// StrictMetadataMode defines what happens with the metadata keys which are
+// not allowed, for curated collections
+type StrictMetadataMode int
+
+const (
+	StrictMetadataOff   StrictMetadataMode = iota // any key is accepted
+	StrictMetadataWarn                            // unknown keys are reported as WarningUnknownMetadata
+	StrictMetadataError                           // unknown keys fail the parse with ErrUnknownMetadataKey
+)
+
+// checkMetadataKey reports the key when it is not allowed, suggesting the
+// allowed key it is likely a typo of
+func (config documentConfig) checkMetadataKey(t *tokenizer, key string) error {
+	if config.strictMetadata == StrictMetadataOff {
+		return nil
+	}
+	allowed := config.allowedMetadataKeys
+	if len(allowed) == 0 {
+		allowed = StandardMetadataKeys
+	}
+	if slices.ContainsFunc(allowed, func(k string) bool { return strings.EqualFold(k, key) }) {
+		return nil
+	}
+	message := fmt.Sprintf("%q", key)
+	if suggestion, ok := closestWord(key, allowed); ok {
+		message += fmt.Sprintf(", did you mean %q?", suggestion)
+	}
+	if config.strictMetadata == StrictMetadataError {
+		return fmt.Errorf("%w: %s", ErrUnknownMetadataKey, message)
+	}
+	t.warnings.add(WarningUnknownMetadata, 0, "key %s", message)
+	return nil
+}
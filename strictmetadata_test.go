@@ -0,0 +1,32 @@
+package cooklang
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestStrictMetadata(t *testing.T) {
+	src := ">> title: Pancakes\n>> servigns: 4\n>> mood: happy\nMix @flour{200%g}."
+	_, warnings, err := NewParser(&ParseConfig{StrictMetadata: StrictMetadataWarn}).ParseStringWithWarnings(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Warning{
+		{WarningUnknownMetadata, 2, 0, `key "servigns", did you mean "servings"?`},
+		{WarningUnknownMetadata, 3, 0, `key "mood"`},
+	}
+	if !reflect.DeepEqual(warnings, want) {
+		t.Errorf("warnings = %v, want %v", warnings, want)
+	}
+
+	_, err = NewParser(&ParseConfig{StrictMetadata: StrictMetadataError}).ParseString(src)
+	if !errors.Is(err, ErrUnknownMetadataKey) || err.Error() != `line 2: unknown metadata key: "servigns", did you mean "servings"?` {
+		t.Errorf("ParseString() error = %v, want ErrUnknownMetadataKey", err)
+	}
+
+	config := &ParseV2Config{StrictMetadata: StrictMetadataError, AllowedMetadataKeys: []string{"Title", "Servigns", "mood"}}
+	if _, err := NewParserV2(config).ParseString(src); err != nil {
+		t.Errorf("ParseString() with allowed keys error = %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package cooklang
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/aquilax/cooklang-go/units"
+)
+
+// Substitution replaces an ingredient with other ingredients. The amounts of
+// With are for the Per amount of the replaced ingredient: buttermilk
+// replaced per 1 cup with 1 cup of milk and 1 tbsp of lemon juice. A
+// replacement without quantity takes the amount of the replaced ingredient,
+// which makes aliases (scallion for green onion).
+type Substitution struct {
+	Per  IngredientAmount
+	With []Ingredient
+}
+
+// factor returns the multiplier of the replacement amounts for the amount
+// of the replaced ingredient. Amounts which can not be related to Per
+// (textual quantities, incompatible units) keep the replacement amounts.
+func (s Substitution) factor(amount IngredientAmount) float64 {
+	if !amount.IsNumeric || s.Per.Quantity <= 0 {
+		return 1
+	}
+	quantity := amount.Quantity
+	if !sameUnit(amount.Unit, s.Per.Unit) {
+		converted, err := units.Convert(quantity, amount.Unit, s.Per.Unit)
+		if err != nil {
+			return 1
+		}
+		quantity = converted
+	}
+	return quantity / s.Per.Quantity
+}
+
+// sameUnit returns true for the same units written in singular or plural
+// ("clove" and "cloves")
+func sameUnit(a, b string) bool {
+	a, b = strings.ToLower(strings.TrimSpace(a)), strings.ToLower(strings.TrimSpace(b))
+	return a == b || a+"s" == b || b+"s" == a || a+"es" == b || b+"es" == a
+}
+
+// replace returns the replacements of the ingredient
+func (s Substitution) replace(ingredient Ingredient) []Ingredient {
+	factor := s.factor(ingredient.Amount)
+	result := make([]Ingredient, 0, len(s.With))
+	for _, with := range s.With {
+		switch {
+		case with.Amount.IsNumeric:
+			with.Amount.Quantity *= factor
+			with.Amount.QuantityRaw = formatScaled(with.Amount.Quantity)
+		case with.Amount.QuantityRaw == "" && with.Amount.Unit == "":
+			with.Amount = ingredient.Amount
+		}
+		result = append(result, with)
+	}
+	return result
+}
+
+// Substitute returns a copy of the recipe with the ingredients of the
+// substitutions replaced. The substitutions are keyed by the ingredient name,
+// matched case and accent insensitively (see NormalizeName). The first mention of a replaced ingredient in the step
+// directions is replaced with the names of its replacements.
+func (r *Recipe) Substitute(substitutions map[string]Substitution) *Recipe {
+	index := make(map[string]Substitution, len(substitutions))
+	for name, s := range substitutions {
+		index[NormalizeName(name)] = s
+	}
+	result := r.clone()
+	for i := range result.Steps {
+		step := &result.Steps[i]
+		if step.Ingredients == nil {
+			continue
+		}
+		ingredients := make([]Ingredient, 0, len(step.Ingredients))
+		offset := 0
+		for _, ingredient := range step.Ingredients {
+			s, ok := index[NormalizeName(ingredient.Name)]
+			if !ok || len(s.With) == 0 {
+				ingredients = append(ingredients, ingredient)
+				continue
+			}
+			replacements := s.replace(ingredient)
+			ingredients = append(ingredients, replacements...)
+			names := make([]string, len(replacements))
+			for j, with := range replacements {
+				names[j] = with.Name
+			}
+			text := joinNames(names)
+			if index := strings.Index(step.Directions[offset:], ingredient.Name); index != -1 {
+				index += offset
+				step.Directions = step.Directions[:index] + text + step.Directions[index+len(ingredient.Name):]
+				offset = index + len(text)
+			}
+		}
+		step.Ingredients = ingredients
+	}
+	return result
+}
+
+// joinNames joins the names as text ("milk, flour and lemon juice")
+func joinNames(names []string) string {
+	if len(names) < 2 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
+
+// clone returns a copy of the recipe with its own metadata and steps slice.
+// The step items are shared.
+func (r *Recipe) clone() *Recipe {
+	result := *r
+	result.Metadata = make(Metadata, len(r.Metadata))
+	for k, v := range r.Metadata {
+		result.Metadata[k] = v
+	}
+	result.MetadataOrder = slices.Clone(r.MetadataOrder)
+	result.MetadataLists = cloneLists(r.MetadataLists)
+	result.Steps = slices.Clone(r.Steps)
+	return &result
+}
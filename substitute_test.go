@@ -0,0 +1,61 @@
+package cooklang
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRecipe_Substitute(t *testing.T) {
+	r, err := ParseString(">> servings: 4\nWhisk @buttermilk{2%cups} with @eggs{2} and @salt{a pinch}.\nAdd @Scallion{3}, @garlic{2%cloves} and more @buttermilk{250%ml}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	table := map[string]Substitution{
+		"buttermilk": {
+			Per:  IngredientAmount{true, 1, "1", "cup"},
+			With: []Ingredient{{Name: "milk", Amount: IngredientAmount{true, 1, "1", "cup"}}, {Name: "lemon juice", Amount: IngredientAmount{true, 1, "1", "tbsp"}}},
+		},
+		"scallion": {With: []Ingredient{{Name: "green onion"}}},
+		"garlic": {
+			Per:  IngredientAmount{true, 1, "1", "clove"},
+			With: []Ingredient{{Name: "garlic powder", Amount: IngredientAmount{true, 0.125, "1/8", "tsp"}}},
+		},
+		"salt": {Per: IngredientAmount{true, 1, "1", "tsp"}, With: []Ingredient{{Name: "sea salt", Amount: IngredientAmount{true, 1, "1", "tsp"}}}},
+	}
+	got := r.Substitute(table)
+	want := [][]Ingredient{
+		{
+			{Name: "milk", Amount: IngredientAmount{true, 2, "2", "cup"}},
+			{Name: "lemon juice", Amount: IngredientAmount{true, 2, "2", "tbsp"}},
+			{Name: "eggs", Amount: IngredientAmount{true, 2, "2", ""}},
+			{Name: "sea salt", Amount: IngredientAmount{true, 1, "1", "tsp"}},
+		},
+		{
+			{Name: "green onion", Amount: IngredientAmount{true, 3, "3", ""}},
+			{Name: "garlic powder", Amount: IngredientAmount{true, 0.25, "0.25", "tsp"}},
+			{Name: "milk", Amount: IngredientAmount{true, 1.057, "1.057", "cup"}},
+			{Name: "lemon juice", Amount: IngredientAmount{true, 1.057, "1.057", "tbsp"}},
+		},
+	}
+	for i, step := range got.Steps {
+		for j := range step.Ingredients {
+			// compare the rounded quantities
+			step.Ingredients[j].Amount.Quantity = float64(int(step.Ingredients[j].Amount.Quantity*1000+0.5)) / 1000
+		}
+		if !reflect.DeepEqual(step.Ingredients, want[i]) {
+			t.Errorf("step %d ingredients = %v, want %v", i, step.Ingredients, want[i])
+		}
+	}
+	directions := []string{
+		"Whisk milk and lemon juice with eggs and sea salt.",
+		"Add green onion, garlic powder and more milk and lemon juice.",
+	}
+	for i, step := range got.Steps {
+		if step.Directions != directions[i] {
+			t.Errorf("step %d directions = %q, want %q", i, step.Directions, directions[i])
+		}
+	}
+	if r.Steps[0].Ingredients[0].Name != "buttermilk" || r.Steps[0].Directions == got.Steps[0].Directions {
+		t.Error("Substitute() changed the recipe")
+	}
+}
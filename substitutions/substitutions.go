@@ -0,0 +1,115 @@
+// Package substitutions loads ingredient substitution tables for
+// Recipe.Substitute, so recipes can be adapted to the diets and to the
+// ingredients at hand
+package substitutions
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/aquilax/cooklang-go"
+	"github.com/aquilax/cooklang-go/units"
+)
+
+//go:embed substitutions.txt
+var defaultTable string
+
+// Table maps the normalized ingredient names (see cooklang.NormalizeName) to
+// their substitutions
+type Table = map[string]cooklang.Substitution
+
+// Default returns the bundled table of common substitutions
+func Default() Table {
+	table, err := Load(strings.NewReader(defaultTable))
+	if err != nil {
+		panic(err)
+	}
+	return table
+}
+
+// Load reads a substitution table. Every line substitutes an ingredient
+// with one or more replacements joined by "+":
+//
+//	1 cup buttermilk = 1 cup milk + 1 tbsp lemon juice
+//	scallion = green onion
+//
+// The amounts are optional: a number (1.5 or 1/2) followed by an optional
+// unit. Replacements without amount take the amount of the replaced
+// ingredient. Empty lines and lines starting with # are skipped.
+func Load(r io.Reader) (Table, error) {
+	table := make(Table)
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		left, right, found := strings.Cut(line, "=")
+		if !found {
+			return nil, fmt.Errorf("line %d: missing =", lineNumber)
+		}
+		ingredient, err := parseIngredient(left)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		s := cooklang.Substitution{Per: ingredient.Amount}
+		for _, part := range strings.Split(right, "+") {
+			with, err := parseIngredient(part)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			s.With = append(s.With, with)
+		}
+		table[cooklang.NormalizeName(ingredient.Name)] = s
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+// parseIngredient parses "[quantity [unit]] name"
+func parseIngredient(s string) (cooklang.Ingredient, error) {
+	fields := strings.Fields(s)
+	var ingredient cooklang.Ingredient
+	if len(fields) > 0 {
+		if quantity, ok := parseQuantity(fields[0]); ok {
+			ingredient.Amount = cooklang.IngredientAmount{IsNumeric: true, Quantity: quantity, QuantityRaw: fields[0]}
+			fields = fields[1:]
+			if len(fields) > 1 && isUnit(fields[0]) {
+				ingredient.Amount.Unit = fields[0]
+				fields = fields[1:]
+			}
+		}
+	}
+	if len(fields) == 0 {
+		return cooklang.Ingredient{}, fmt.Errorf("missing ingredient name in %q", strings.TrimSpace(s))
+	}
+	ingredient.Name = strings.Join(fields, " ")
+	return ingredient, nil
+}
+
+// parseQuantity parses a decimal (1.5) or a fraction (1/2)
+func parseQuantity(s string) (float64, bool) {
+	if numerator, denominator, found := strings.Cut(s, "/"); found {
+		n, err1 := strconv.ParseFloat(numerator, 64)
+		d, err2 := strconv.ParseFloat(denominator, 64)
+		return n / d, err1 == nil && err2 == nil && d != 0
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	return f, err == nil
+}
+
+func isUnit(s string) bool {
+	if _, ok := units.Lookup(s); ok {
+		return true
+	}
+	return slices.Contains(cooklang.MarkdownUnits, strings.ToLower(s))
+}
@@ -0,0 +1,66 @@
+package substitutions
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Table
+		wantErr bool
+	}{
+		{
+			"Table",
+			"# comment\n\n1 cup Buttermilk = 1 cup milk + 1/2 tbsp lemon juice\nscallion = green onion\n2 eggs = 3 tbsp aquafaba",
+			Table{
+				"buttermilk": {
+					Per: cooklang.IngredientAmount{IsNumeric: true, Quantity: 1, QuantityRaw: "1", Unit: "cup"},
+					With: []cooklang.Ingredient{
+						{Name: "milk", Amount: cooklang.IngredientAmount{IsNumeric: true, Quantity: 1, QuantityRaw: "1", Unit: "cup"}},
+						{Name: "lemon juice", Amount: cooklang.IngredientAmount{IsNumeric: true, Quantity: 0.5, QuantityRaw: "1/2", Unit: "tbsp"}},
+					},
+				},
+				"scallion": {With: []cooklang.Ingredient{{Name: "green onion"}}},
+				"eggs": {
+					Per:  cooklang.IngredientAmount{IsNumeric: true, Quantity: 2, QuantityRaw: "2"},
+					With: []cooklang.Ingredient{{Name: "aquafaba", Amount: cooklang.IngredientAmount{IsNumeric: true, Quantity: 3, QuantityRaw: "3", Unit: "tbsp"}}},
+				},
+			},
+			false,
+		},
+		{"Missing separator", "buttermilk", nil, true},
+		{"Missing name", "1 = milk", nil, true},
+		{"Missing replacement", "buttermilk = milk + ", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Load() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefault(t *testing.T) {
+	r, err := cooklang.ParseString("Mix @buttermilk{2%cups} with @courgette{1}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := r.Substitute(Default())
+	if want := "Mix milk and lemon juice with zucchini."; got.Steps[0].Directions != want {
+		t.Errorf("Directions = %q, want %q", got.Steps[0].Directions, want)
+	}
+	if q := got.Steps[0].Ingredients[1].Amount.Quantity; q != 2 {
+		t.Errorf("lemon juice quantity = %v, want 2", q)
+	}
+}
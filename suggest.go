@@ -0,0 +1,118 @@
+package cooklang
+
+import (
+	"slices"
+	"strings"
+)
+
+// levenshtein returns the edit distance of the strings: the number of rune
+// insertions, deletions, substitutions and transpositions of adjacent runes
+// turning a into b (optimal string alignment distance)
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	rows := make([][]int, len(ra)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(rb)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			rows[i][j] = min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+	return rows[len(ra)][len(rb)]
+}
+
+// typoDistance is the largest edit distance of a typo of the word: one edit
+// for short words, up to a third of the length of longer ones
+func typoDistance(word string) int {
+	return max(1, len([]rune(word))/3)
+}
+
+// Suggest returns the words of the dictionary which name is likely a typo of
+// ("tomatoo" → "tomato"), the closest first. The words are compared case
+// insensitively by their Levenshtein distance, counting swapped letters
+// ("onoin") as a single edit. It returns nil when name is
+// in the dictionary.
+func Suggest(name string, dictionary []string) []string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	limit := typoDistance(name)
+	type candidate struct {
+		word     string
+		distance int
+	}
+	var candidates []candidate
+	for _, word := range dictionary {
+		d := levenshtein(name, strings.ToLower(strings.TrimSpace(word)))
+		if d == 0 {
+			return nil
+		}
+		if d <= limit {
+			candidates = append(candidates, candidate{word, d})
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		return a.distance - b.distance
+	})
+	var result []string
+	for _, c := range candidates {
+		result = append(result, c.word)
+	}
+	return result
+}
+
+// closestWord returns the first suggestion for name (see Suggest)
+func closestWord(name string, dictionary []string) (string, bool) {
+	if suggestions := Suggest(name, dictionary); len(suggestions) > 0 {
+		return suggestions[0], true
+	}
+	return "", false
+}
+
+// IngredientDictionary returns the ingredient names (lower case, sorted)
+// used in at least minRecipes recipes of the collection (see ParseDir), the
+// dictionary of ValidationProfile.IngredientDictionary. The names used in a
+// single recipe are the likely typos, so minRecipes is usually 2.
+func IngredientDictionary(collection map[string]*Recipe, minRecipes int) []string {
+	counts := make(map[string]int)
+	for _, r := range collection {
+		seen := make(map[string]bool)
+		for _, step := range r.Steps {
+			for _, i := range step.Ingredients {
+				name := strings.ToLower(strings.TrimSpace(i.Name))
+				if !seen[name] {
+					seen[name] = true
+					counts[name]++
+				}
+			}
+		}
+	}
+	var dictionary []string
+	for name, n := range counts {
+		if n >= minRecipes {
+			dictionary = append(dictionary, name)
+		}
+	}
+	slices.Sort(dictionary)
+	return dictionary
+}
+
+// isInflection reports whether the words only differ by a plural ending
+// ("tomato" and "tomatoes"), which is not a typo
+func isInflection(a, b string) bool {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	return b == a+"s" || b == a+"es" || (strings.HasSuffix(a, "y") && b == a[:len(a)-1]+"ies")
+}
@@ -0,0 +1,77 @@
+package cooklang
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClosestWord(t *testing.T) {
+	dictionary := []string{"servings", "tags", "title", "prep time"}
+	tests := []struct {
+		name   string
+		want   string
+		wantOk bool
+	}{
+		{"servigns", "servings", true},
+		{"Tittle", "title", true},
+		{"tag", "tags", true},
+		{"prep-time", "prep time", true},
+		{"title", "", false},
+		{"cuisine", "", false},
+	}
+	for _, tt := range tests {
+		if got, ok := closestWord(tt.name, dictionary); got != tt.want || ok != tt.wantOk {
+			t.Errorf("closestWord(%q) = %q, %v, want %q, %v", tt.name, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	dictionary := []string{"tomato", "potato", "onion", "garlic", "tomatillo"}
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"tomatoo", []string{"tomato"}},
+		{"Onoin", []string{"onion"}},
+		{"tomatilo", []string{"tomatillo", "tomato"}},
+		{"tomato", nil},
+		{"basil", nil},
+	}
+	for _, tt := range tests {
+		if got := Suggest(tt.name, dictionary); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Suggest(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidateIngredientDictionary(t *testing.T) {
+	collection := make(map[string]*Recipe)
+	for name, src := range map[string]string{
+		"salad": "Slice @tomatoes{2} and @onion{1}.",
+		"sauce": "Cook @tomatoes{4} with @onion{1} and @garlic{2%cloves}.",
+		"soup":  "Simmer @tomatos{3}, @onoin{1} and @garlic{1%clove}.",
+	} {
+		r, err := ParseString(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		collection[name] = r
+	}
+	dictionary := IngredientDictionary(collection, 2)
+	if want := []string{"garlic", "onion", "tomatoes"}; !reflect.DeepEqual(dictionary, want) {
+		t.Fatalf("IngredientDictionary() = %v, want %v", dictionary, want)
+	}
+	r, err := ParseString("Slice @tomato{1}, @tomatos{1} and @onoin{1}.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := Validate(r, ValidationProfile{IngredientDictionary: dictionary})
+	want := []ValidationError{
+		{0, "tomatos", `possible typo of "tomatoes"`},
+		{0, "onoin", `possible typo of "onion"`},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate() = %v, want %v", got, want)
+	}
+}
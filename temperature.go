@@ -0,0 +1,154 @@
+package cooklang
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/aquilax/cooklang-go/units"
+)
+
+// ItemTypeTemperature is the type of the temperature items
+const ItemTypeTemperature ItemType = "temperature"
+
+// temperaturePattern matches 180°C, 180 °F, 200℃ and 180 degrees Celsius.
+// The single letter units need the degree sign or word, "2 C" is usually
+// two cups.
+var temperaturePattern = regexp.MustCompile(`\b(\d+(?:[.,]\d+)?)\s?(?:°\s?([CcFf])\b|(℃)|(℉)|degrees?\s+(Celsius|Fahrenheit|C|F)\b)`)
+
+// Temperature is a temperature found in the step text
+type Temperature struct {
+	Value float64 // temperature value
+	Unit  string  // units.Celsius or units.Fahrenheit
+	Raw   string  // temperature as written in the text
+}
+
+// TemperatureV2 represents a temperature item
+type TemperatureV2 struct {
+	Type  ItemType `json:"type"`
+	Value float64  `json:"value"`
+	Unit  string   `json:"units"`
+}
+
+func (t Temperature) asTemperatureV2() TemperatureV2 {
+	return TemperatureV2{ItemTypeTemperature, t.Value, t.Unit}
+}
+
+// asTemperature returns the v1 temperature written as "180°C"
+func (t TemperatureV2) asTemperature() Temperature {
+	return Temperature{t.Value, t.Unit, strconv.FormatFloat(t.Value, 'f', -1, 64) + "°" + t.Unit}
+}
+
+// Celsius returns the temperature in degrees Celsius
+func (t Temperature) Celsius() float64 {
+	if t.Unit == units.Fahrenheit {
+		return units.FahrenheitToCelsius(t.Value)
+	}
+	return t.Value
+}
+
+// Fahrenheit returns the temperature in degrees Fahrenheit
+func (t Temperature) Fahrenheit() float64 {
+	if t.Unit == units.Celsius {
+		return units.CelsiusToFahrenheit(t.Value)
+	}
+	return t.Value
+}
+
+// Convert returns the temperature in the unit (units.Celsius or
+// units.Fahrenheit). The raw text is not changed.
+func (t Temperature) Convert(unit string) Temperature {
+	switch unit {
+	case units.Celsius:
+		return Temperature{t.Celsius(), units.Celsius, t.Raw}
+	case units.Fahrenheit:
+		return Temperature{t.Fahrenheit(), units.Fahrenheit, t.Raw}
+	}
+	return t
+}
+
+// String returns the temperature rounded to whole degrees (180°C)
+func (t Temperature) String() string {
+	return strconv.FormatFloat(math.Round(t.Value), 'f', -1, 64) + "°" + t.Unit
+}
+
+// temperatureMatch is a temperature with its byte offsets in the text
+type temperatureMatch struct {
+	Temperature
+	start, end int
+}
+
+// findTemperatures returns the temperatures in the text
+func findTemperatures(text string) []temperatureMatch {
+	if strings.IndexAny(text, "0123456789") == -1 {
+		return nil
+	}
+	var result []temperatureMatch
+	for _, m := range temperaturePattern.FindAllStringSubmatchIndex(text, -1) {
+		value, err := strconv.ParseFloat(strings.ReplaceAll(text[m[2]:m[3]], ",", "."), 64)
+		if err != nil {
+			continue
+		}
+		unit := units.Celsius
+		for group := 2; group <= 5; group++ {
+			start := m[group*2]
+			if start == -1 {
+				continue
+			}
+			switch u := text[start:m[group*2+1]]; {
+			case u == "℉", strings.EqualFold(u, "F"), u == "Fahrenheit":
+				unit = units.Fahrenheit
+			}
+		}
+		result = append(result, temperatureMatch{Temperature{value, unit, text[m[0]:m[1]]}, m[0], m[1]})
+	}
+	return result
+}
+
+// FindTemperatures returns the temperatures in the text
+func FindTemperatures(text string) []Temperature {
+	var result []Temperature
+	for _, m := range findTemperatures(text) {
+		result = append(result, m.Temperature)
+	}
+	return result
+}
+
+// ConvertTemperatures returns the text with the temperatures converted to
+// the unit (units.Celsius or units.Fahrenheit) and written as by
+// Temperature.String. The temperatures in the unit are kept as written.
+func ConvertTemperatures(text, unit string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range findTemperatures(text) {
+		if m.Unit == unit {
+			continue
+		}
+		b.WriteString(text[last:m.start])
+		b.WriteString(m.Convert(unit).String())
+		last = m.end
+	}
+	if last == 0 {
+		return text
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// splitTemperatures splits the text to text and temperature items
+func splitTemperatures(text string) []any {
+	var result []any
+	last := 0
+	for _, m := range findTemperatures(text) {
+		if m.start > last {
+			result = append(result, newText(text[last:m.start]))
+		}
+		result = append(result, m.Temperature)
+		last = m.end
+	}
+	if last < len(text) {
+		result = append(result, newText(text[last:]))
+	}
+	return result
+}
@@ -0,0 +1,89 @@
+package cooklang
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindTemperatures(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []Temperature
+	}{
+		{"No temperature", "Bake for a while", nil},
+		{"Celsius", "Heat oven up to 200°C", []Temperature{{200, "C", "200°C"}}},
+		{"Celsius sign", "Preheat the oven to 200℃/Fan 180°C.", []Temperature{{200, "C", "200℃"}, {180, "C", "180°C"}}},
+		{"Fahrenheit", "Bake at 350℉ or 375 °F", []Temperature{{350, "F", "350℉"}, {375, "F", "375 °F"}}},
+		{"Single letter units", "Add 2 C of water and heat to 350F or 180 degrees C", []Temperature{{180, "C", "180 degrees C"}}},
+		{"Words", "Keep at 62.5 degrees Celsius", []Temperature{{62.5, "C", "62.5 degrees Celsius"}}},
+		{"Decimal comma", "Heat to 82,5°c", []Temperature{{82.5, "C", "82,5°c"}}},
+		{"Not a temperature", "Add 2 Cups and 3 Feet of 5FF", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FindTemperatures(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindTemperatures() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemperature_Convert(t *testing.T) {
+	tests := []struct {
+		name string
+		t    Temperature
+		unit string
+		want string
+	}{
+		{"Celsius to Fahrenheit", Temperature{180, "C", "180°C"}, "F", "356°F"},
+		{"Fahrenheit to Celsius", Temperature{350, "F", "350F"}, "C", "177°C"},
+		{"Same unit", Temperature{350, "F", "350F"}, "F", "350°F"},
+		{"Unknown unit", Temperature{100, "C", "100C"}, "K", "100°C"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.t.Convert(tt.unit).String(); got != tt.want {
+				t.Errorf("Temperature.Convert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTemperatures(t *testing.T) {
+	recipe := "Bake the @dough{} at 220°C for ~{20%minutes}, then at 180°C."
+	r, err := ParseString(recipe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Steps[0].Temperatures != nil {
+		t.Errorf("ParseString() temperatures = %v, want nil", r.Steps[0].Temperatures)
+	}
+	r, err = NewParser(&ParseConfig{DetectTemperatures: true}).ParseString(recipe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Temperature{{220, "C", "220°C"}, {180, "C", "180°C"}}
+	if !reflect.DeepEqual(r.Steps[0].Temperatures, want) {
+		t.Errorf("ParseString() temperatures = %v, want %v", r.Steps[0].Temperatures, want)
+	}
+
+	v2, err := NewParserV2(&ParseV2Config{DetectTemperatures: true}).ParseString("Bake at 220°C.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantV2 := StepV2{TextV2{ItemTypeText, "Bake at "}, TemperatureV2{ItemTypeTemperature, 220, "C"}, TextV2{ItemTypeText, "."}}
+	if !reflect.DeepEqual(v2.Steps[0], wantV2) {
+		t.Errorf("ParserV2.ParseString() = %#v, want %#v", v2.Steps[0], wantV2)
+	}
+}
+
+func TestConvertTemperatures(t *testing.T) {
+	text := "Bake at 180°C, then at 400 °F or 200 degrees Celsius."
+	if got, want := ConvertTemperatures(text, "F"), "Bake at 356°F, then at 400 °F or 392°F."; got != want {
+		t.Errorf("ConvertTemperatures() = %q, want %q", got, want)
+	}
+	if got := ConvertTemperatures("Add 2 C of water", "F"); got != "Add 2 C of water" {
+		t.Errorf("ConvertTemperatures() = %q", got)
+	}
+}
@@ -0,0 +1,113 @@
+package cooklang
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+var timerUnits = map[string]time.Duration{
+	"s":       time.Second,
+	"sec":     time.Second,
+	"secs":    time.Second,
+	"second":  time.Second,
+	"seconds": time.Second,
+	"m":       time.Minute,
+	"min":     time.Minute,
+	"mins":    time.Minute,
+	"minute":  time.Minute,
+	"minutes": time.Minute,
+	"h":       time.Hour,
+	"hr":      time.Hour,
+	"hrs":     time.Hour,
+	"hour":    time.Hour,
+	"hours":   time.Hour,
+	"d":       24 * time.Hour,
+	"day":     24 * time.Hour,
+	"days":    24 * time.Hour,
+}
+
+// ToDuration returns the timer duration. ok is false when the timer has no
+// duration or the unit is not a known time unit.
+func (t Timer) ToDuration() (d time.Duration, ok bool) {
+	unit, ok := timerUnits[strings.ToLower(strings.TrimSpace(t.Unit))]
+	if !ok || !t.HasDuration() {
+		return 0, false
+	}
+	return time.Duration(math.Round(t.Duration * float64(unit))), true
+}
+
+// ISO8601 returns the timer duration as ISO 8601 duration ("PT20M") or an
+// empty string when the timer has no duration in a known time unit
+func (t Timer) ISO8601() string {
+	d, ok := t.ToDuration()
+	if !ok {
+		return ""
+	}
+	return FormatISO8601Duration(d)
+}
+
+// RecipeTimer is a timer with its position in the recipe
+type RecipeTimer struct {
+	Timer
+	Step  int // zero based index of the step
+	Index int // zero based index of the timer in the step
+}
+
+// RecipeTimers is the list of the timers of a recipe in recipe order
+type RecipeTimers []RecipeTimer
+
+// Timers returns all timers of the recipe with their positions
+func (r *Recipe) Timers() RecipeTimers {
+	var result RecipeTimers
+	for i, step := range r.Steps {
+		for j, timer := range step.Timers {
+			result = append(result, RecipeTimer{timer, i, j})
+		}
+	}
+	return result
+}
+
+// timerKey returns the normalized timer name the timers are matched by
+func timerKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Lookup returns the first timer with the name (case insensitive). Timers
+// without name can not be looked up.
+func (t RecipeTimers) Lookup(name string) (RecipeTimer, bool) {
+	key := timerKey(name)
+	if key == "" {
+		return RecipeTimer{}, false
+	}
+	for _, timer := range t {
+		if timerKey(timer.Name) == key {
+			return timer, true
+		}
+	}
+	return RecipeTimer{}, false
+}
+
+// Duplicates returns the names used by more than one timer (case
+// insensitive) as first written, in recipe order
+func (t RecipeTimers) Duplicates() []string {
+	var result []string
+	first := make(map[string]string)
+	reported := make(map[string]bool)
+	for _, timer := range t {
+		key := timerKey(timer.Name)
+		if key == "" {
+			continue
+		}
+		name, ok := first[key]
+		if !ok {
+			first[key] = strings.TrimSpace(timer.Name)
+			continue
+		}
+		if !reported[key] {
+			reported[key] = true
+			result = append(result, name)
+		}
+	}
+	return result
+}
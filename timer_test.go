@@ -0,0 +1,60 @@
+package cooklang
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRecipe_Timers(t *testing.T) {
+	r, err := ParseString("Knead ~dough{10%minutes} and rest ~{5%minutes}.\n\nBake ~Dough{30%minutes}, then ~cool{1%hour}.\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	timers := r.Timers()
+	want := RecipeTimers{
+		{Timer{"dough", 10, "minutes"}, 0, 0},
+		{Timer{"", 5, "minutes"}, 0, 1},
+		{Timer{"Dough", 30, "minutes"}, 1, 0},
+		{Timer{"cool", 1, "hour"}, 1, 1},
+	}
+	if !reflect.DeepEqual(timers, want) {
+		t.Fatalf("Timers() = %v, want %v", timers, want)
+	}
+	lookups := []struct {
+		name  string
+		want  RecipeTimer
+		found bool
+	}{
+		{"dough", want[0], true},
+		{" COOL ", want[3], true},
+		{"", RecipeTimer{}, false},
+		{"oven", RecipeTimer{}, false},
+	}
+	for _, tt := range lookups {
+		got, found := timers.Lookup(tt.name)
+		if found != tt.found || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Lookup(%q) = %v, %v, want %v, %v", tt.name, got, found, tt.want, tt.found)
+		}
+	}
+	if got, want := timers.Duplicates(), []string{"dough"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Duplicates() = %v, want %v", got, want)
+	}
+}
+
+func TestTimer_ISO8601(t *testing.T) {
+	tests := []struct {
+		timer Timer
+		want  string
+	}{
+		{Timer{"", 20, "minutes"}, "PT20M"},
+		{Timer{"", 1.5, "hours"}, "PT1H30M"},
+		{Timer{"", 2, "days"}, "P2D"},
+		{Timer{"rest", 0, ""}, ""},
+		{Timer{"", 3, "fortnights"}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.timer.ISO8601(); got != tt.want {
+			t.Errorf("%v.ISO8601() = %q, want %q", tt.timer, got, tt.want)
+		}
+	}
+}
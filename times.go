@@ -0,0 +1,105 @@
+package cooklang
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// Metadata keys of the recipe time
+const (
+	MetadataTime     = "time"
+	MetadataDuration = "duration"
+)
+
+// AttributePassive is the step attribute which marks the step timers as
+// passive (true) or active (false), overriding PassiveTimerWords
+const AttributePassive = "passive"
+
+// PassiveTimerWords are the word prefixes of the timer names and step
+// directions which mark the step timers as passive: unattended time like
+// resting in the fridge or fermenting. Timers measured in days are always
+// passive.
+var PassiveTimerWords = []string{
+	"rest", "fridge", "refrigerat", "chill", "freez", "ferment", "proof",
+	"rise", "marinat", "soak", "overnight", "cool",
+}
+
+// StepTimes contains the timer times of a step
+type StepTimes struct {
+	Active  time.Duration // time of the timers which need attention
+	Passive time.Duration // unattended time (see PassiveTimerWords)
+}
+
+// RecipeTimes contains the timer times of a recipe
+type RecipeTimes struct {
+	Steps   []StepTimes   // times of every step in recipe order
+	Active  time.Duration // sum of the active times
+	Passive time.Duration // sum of the passive times
+	Total   time.Duration // sum of all timers
+}
+
+// ComputeTimes sums the durations of the timers per step and for the whole
+// recipe, split into active and passive time. Timers with unknown time units
+// are skipped. When the recipe has neither the time nor the duration
+// metadata, the time metadata is set to the total ("1h30m").
+func (r *Recipe) ComputeTimes() RecipeTimes {
+	times := RecipeTimes{Steps: make([]StepTimes, len(r.Steps))}
+	for i, step := range r.Steps {
+		for _, timer := range step.Timers {
+			d, ok := timer.ToDuration()
+			if !ok {
+				continue
+			}
+			if isPassiveTimer(step, timer) {
+				times.Steps[i].Passive += d
+			} else {
+				times.Steps[i].Active += d
+			}
+		}
+		times.Active += times.Steps[i].Active
+		times.Passive += times.Steps[i].Passive
+	}
+	times.Total = times.Active + times.Passive
+	_, hasTime := r.Metadata[MetadataTime]
+	_, hasDuration := r.Metadata[MetadataDuration]
+	if times.Total > 0 && !hasTime && !hasDuration {
+		if r.Metadata == nil {
+			r.Metadata = make(Metadata)
+		}
+		r.Metadata[MetadataTime] = formatDuration(times.Total)
+		r.MetadataOrder = append(r.MetadataOrder, MetadataTime)
+	}
+	return times
+}
+
+// isPassiveTimer returns true for the unattended timers of the step
+func isPassiveTimer(step Step, timer Timer) bool {
+	if passive, ok := step.Attributes[AttributePassive].(bool); ok {
+		return passive
+	}
+	if unit := strings.ToLower(strings.TrimSpace(timer.Unit)); timerUnits[unit] >= 24*time.Hour {
+		return true
+	}
+	words := strings.FieldsFunc(strings.ToLower(timer.Name+" "+step.Directions), func(r rune) bool {
+		return !('a' <= r && r <= 'z')
+	})
+	return slices.ContainsFunc(words, func(word string) bool {
+		return slices.ContainsFunc(PassiveTimerWords, func(prefix string) bool {
+			return strings.HasPrefix(word, prefix)
+		})
+	})
+}
+
+// formatDuration formats d like time.Duration.String without the trailing
+// zero units ("1h30m" instead of "1h30m0s")
+func formatDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
@@ -0,0 +1,53 @@
+package cooklang
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRecipeComputeTimes(t *testing.T) {
+	r, err := ParseString(`Fry the onions for ~{10%minutes}.
+Let the dough ~rest{1%hour} in the #fridge.
+Ferment for ~{2%days}.
+Stir for ~{5%minutes} and bake for ~{20%minutes}.
+Wait ~{3%fortnights}.`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Steps[3].Attributes = map[string]any{AttributePassive: false}
+	got := r.ComputeTimes()
+	want := RecipeTimes{
+		Steps: []StepTimes{
+			{Active: 10 * time.Minute},
+			{Passive: time.Hour},
+			{Passive: 48 * time.Hour},
+			{Active: 25 * time.Minute},
+			{},
+		},
+		Active:  35 * time.Minute,
+		Passive: 49 * time.Hour,
+		Total:   49*time.Hour + 35*time.Minute,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ComputeTimes() = %+v, want %+v", got, want)
+	}
+	if r.Metadata[MetadataTime] != "49h35m" || !reflect.DeepEqual(r.MetadataKeys(), []string{MetadataTime}) {
+		t.Errorf("Metadata = %v, want the time metadata", r.Metadata)
+	}
+}
+
+func TestRecipeComputeTimesKeepsMetadata(t *testing.T) {
+	for _, key := range []string{MetadataTime, MetadataDuration} {
+		r, err := ParseString(">> " + key + ": 2 hours\nBake for ~{30%minutes}.")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := r.ComputeTimes().Total; got != 30*time.Minute {
+			t.Errorf("Total = %v, want 30m", got)
+		}
+		if len(r.Metadata) != 1 || r.Metadata[key] != "2 hours" {
+			t.Errorf("Metadata = %v, want it unchanged", r.Metadata)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package cooklang
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tokenizer splits recipe lines into items. The directions buffer is reused
+// between lines, so a single tokenizer should be used for the whole stream
+// and must not be shared between goroutines.
+type tokenizer struct {
+	directions []byte       // reusable buffer for the assembled step directions
+	strict     bool         // return malformed items as errors instead of text
+	warnings   *warningList // optional collector of the parse warnings
+	custom     map[byte]CustomExtractor
+	start, end int // byte range in the line of the item passed to the callback
+	// comments and commentPlaceholder define the text kept for the comments
+	comments           CommentMode
+	commentPlaceholder string
+	features           specFeatures // syntax features of the spec version
+	references         bool         // ingredients starting with & are references (@&flour)
+	logger             *slog.Logger // optional logger of the debug events
+	line               int          // line number of the logged events
+	// verbatimSpans and verbatimPatterns define the text which is never
+	// tokenized: the spans in backticks and the pattern matches
+	verbatimSpans    bool
+	verbatimPatterns []*regexp.Regexp
+	links            bool // the URLs are link items (https://example.com)
+}
+
+// debug logs a debug event of the line. The callers check t.logger first,
+// so no arguments are built when logging is off.
+func (t *tokenizer) debug(msg string, args ...any) {
+	t.logger.Debug(msg, append([]any{"line", t.line}, args...)...)
+}
+
+// tokenize walks the line and calls cb for every item found in it. Text
+// items are sub-slices of the line so no copies are made. Returns the step
+// directions as plain text.
+func (t *tokenizer) tokenize(line string, cb func(item any) (bool, error)) (string, error) {
+	t.directions = t.directions[:0]
+	textStart := 0
+	index := 0
+	var protected []protectedSpan
+	if len(t.verbatimPatterns) > 0 || t.links {
+		protected = t.protectedSpans(line)
+	}
+	for index < len(line) {
+		for len(protected) > 0 && protected[0].end <= index {
+			protected = protected[1:]
+		}
+		if len(protected) > 0 && index >= protected[0].start {
+			span := protected[0]
+			if span.link && index == span.start {
+				if stop, err := t.emitText(line, textStart, index, cb); err != nil || stop {
+					return string(t.directions), err
+				}
+				t.directions = append(t.directions, line[span.start:span.end]...)
+				t.start, t.end = span.start, span.end
+				if stop, err := cb(Link{line[span.start:span.end]}); err != nil || stop {
+					return string(t.directions), err
+				}
+				textStart = span.end
+			}
+			// the pattern matches are kept in the text
+			index = span.end
+			continue
+		}
+		ch := line[index]
+		var next byte
+		if index+1 < len(line) {
+			next = line[index+1]
+		}
+		switch {
+		case (ch == prefixIngredient || ch == prefixCookware || ch == prefixTimer || t.isCustom(ch)) && !startsWithSpace(line[index+1:]):
+			item, skipNext, err := t.getItem(ch, line[index:])
+			if err != nil {
+				var itemErr *ItemError
+				if !errors.As(err, &itemErr) {
+					return string(t.directions), err
+				}
+				itemErr.Offset = index
+				if t.strict && len(itemErr.Raw) > 1 {
+					return string(t.directions), err
+				}
+				// malformed items are kept as part of the surrounding text
+				if len(itemErr.Raw) > 1 {
+					t.warnings.add(WarningDowngradedItem, index, "%v", itemErr)
+				}
+				if t.logger != nil {
+					t.debug("malformed item kept as text", "raw", itemErr.Raw, "offset", index, "error", itemErr.Err)
+				}
+				index += len(itemErr.Raw)
+				continue
+			}
+			if ch == prefixTimer && !t.features.bareTimers && !strings.Contains(line[index:index+skipNext], "{") {
+				if t.logger != nil {
+					t.debug("timer without braces kept as text", "raw", line[index:index+skipNext], "offset", index)
+				}
+				index += skipNext
+				continue
+			}
+			if stop, err := t.emitText(line, textStart, index, cb); err != nil || stop {
+				return string(t.directions), err
+			}
+			if ingredient, ok := item.(Ingredient); ok {
+				t.warnings.checkUnit(ingredient, index)
+			}
+			t.appendItem(item)
+			t.start, t.end = index, index+skipNext
+			if t.logger != nil {
+				t.debug("item", "raw", line[t.start:t.end], "offset", index)
+			}
+			if stop, err := cb(item); err != nil || stop {
+				return string(t.directions), err
+			}
+			index += skipNext
+			textStart = index
+			continue
+		case ch == verbatimDelimiter && t.verbatimSpans && verbatimSpan(line[index:]) > 0:
+			// the span is emitted as text without the backticks
+			n := verbatimSpan(line[index:])
+			if stop, err := t.emitText(line, textStart, index, cb); err != nil || stop {
+				return string(t.directions), err
+			}
+			if stop, err := t.emitText(line, index+1, index+n-1, cb); err != nil || stop {
+				return string(t.directions), err
+			}
+			index += n
+			textStart = index
+			continue
+		case t.logger != nil && (ch == prefixIngredient || ch == prefixCookware || ch == prefixTimer):
+			t.debug("prefix followed by white space kept as text", "prefix", string(ch), "offset", index)
+		case ch == prefixBlockComment && next == '-' && (t.features.multilineComments || strings.Contains(line[index+2:], "-]")):
+			if stop, err := t.emitText(line, textStart, index, cb); err != nil || stop {
+				return string(t.directions), err
+			}
+			comment, skipNext, err := getBlockComment(line[index:])
+			if err != nil {
+				return string(t.directions), err
+			}
+			if stop, err := t.emitComment(line, index, index+skipNext, Comment{CommentTypeBlock, comment}, cb); err != nil || stop {
+				return string(t.directions), err
+			}
+			index += skipNext
+			textStart = index
+			continue
+		case ch == prefixInlineComment && next == prefixInlineComment:
+			if stop, err := t.emitText(line, textStart, index, cb); err != nil || stop {
+				return string(t.directions), err
+			}
+			comment := strings.TrimSpace(line[index+len(commentsLinePrefix):])
+			if stop, err := t.emitComment(line, index, len(line), Comment{CommentTypeEndLine, comment}, cb); err != nil || stop {
+				return string(t.directions), err
+			}
+			return strings.TrimSpace(string(t.directions)), nil
+		}
+		index++
+	}
+	if stop, err := t.emitText(line, textStart, len(line), cb); err != nil || stop {
+		return string(t.directions), err
+	}
+	return strings.TrimSpace(string(t.directions)), nil
+}
+
+// isCustom returns true for the registered custom prefixes which do not
+// clash with the built-in syntax
+func (t *tokenizer) isCustom(ch byte) bool {
+	if t.custom == nil {
+		return false
+	}
+	switch ch {
+	case prefixIngredient, prefixCookware, prefixTimer, prefixBlockComment, prefixInlineComment, '{', '}':
+		return false
+	}
+	return t.custom[ch] != nil
+}
+
+// getItem parses the item of the given prefix type starting at the
+// beginning of s
+func (t *tokenizer) getItem(prefix byte, s string) (any, int, error) {
+	switch prefix {
+	case prefixIngredient:
+		return getIngredient(s, t.features, t.references)
+	case prefixCookware:
+		return getCookware(s, t.features)
+	case prefixTimer:
+		return getTimer(s)
+	default:
+		return getCustomItem(s, t.custom[prefix])
+	}
+}
+
+// appendItem appends the directions representation of the item to the buffer.
+// The white space before the hidden ingredients is removed, so they leave no
+// gap in the directions.
+func (t *tokenizer) appendItem(item any) {
+	switch v := item.(type) {
+	case Ingredient:
+		if v.Hidden {
+			t.directions = bytes.TrimRightFunc(t.directions, unicode.IsSpace)
+		} else {
+			t.directions = append(t.directions, v.Name...)
+		}
+	case Cookware:
+		t.directions = append(t.directions, v.Name...)
+	case Timer:
+		if !v.HasDuration() {
+			t.directions = append(t.directions, v.Name...)
+			return
+		}
+		t.directions = strconv.AppendFloat(t.directions, v.Duration, 'g', -1, 64)
+		if v.Unit != "" {
+			t.directions = append(t.directions, ' ')
+			t.directions = append(t.directions, v.Unit...)
+		}
+	case CustomItem:
+		t.directions = append(t.directions, v.Prefix...)
+		t.directions = append(t.directions, v.Raw...)
+	case Link:
+		t.directions = append(t.directions, v.URL...)
+	}
+}
+
+func (t *tokenizer) emitText(line string, start, end int, cb func(item any) (bool, error)) (bool, error) {
+	if start == end {
+		return false, nil
+	}
+	t.directions = append(t.directions, line[start:end]...)
+	t.start, t.end = start, end
+	return cb(newText(line[start:end]))
+}
+
+// emitComment calls cb for the comment written in line[start:end]. The text
+// kept for the comment by the comment mode is emitted first as a text item.
+func (t *tokenizer) emitComment(line string, start, end int, comment Comment, cb func(item any) (bool, error)) (bool, error) {
+	var text string
+	switch t.comments {
+	case CommentsPlaceholder:
+		text = cmp.Or(t.commentPlaceholder, DefaultCommentPlaceholder)
+	case CommentsPreserve:
+		text = strings.TrimRightFunc(line[start:end], unicode.IsSpace)
+	}
+	t.start, t.end = start, end
+	if t.logger != nil {
+		t.debug("comment", "raw", line[start:end], "offset", start, "kept", text)
+	}
+	if text != "" {
+		t.directions = append(t.directions, text...)
+		if stop, err := cb(newText(text)); err != nil || stop {
+			return stop, err
+		}
+	}
+	return cb(comment)
+}
+
+// startsWithSpace returns true if s starts with an Unicode white space
+func startsWithSpace(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsSpace(r)
+}
@@ -0,0 +1,77 @@
+package cooklang
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_tokenizer_tokenize(t *testing.T) {
+	tests := []struct {
+		name       string
+		line       string
+		want       []any
+		directions string
+	}{
+		{
+			"Text only",
+			"Add a bit of chilli",
+			[]any{Text{"Add a bit of chilli"}},
+			"Add a bit of chilli",
+		},
+		{
+			"Mixed items",
+			"Put @salt{1%g} in #pot for ~{2%minutes} -- comment",
+			[]any{
+				Text{"Put "},
+				Ingredient{Name: "salt", Amount: IngredientAmount{true, 1, "1", "g"}},
+				Text{" in "},
+				Cookware{Name: "pot", Quantity: 1},
+				Text{" for "},
+				Timer{"", 2, "minutes"},
+				Text{" "},
+				Comment{CommentTypeEndLine, "comment"},
+			},
+			"Put salt in pot for 2 minutes",
+		},
+		{
+			"Prefix followed by space is text",
+			"1 @ 2 [- block -]",
+			[]any{
+				Text{"1 @ 2 "},
+				Comment{CommentTypeBlock, "block"},
+			},
+			"1 @ 2",
+		},
+	}
+	var tok tokenizer
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []any
+			directions, err := tok.tokenize(tt.line, func(item any) (bool, error) {
+				got = append(got, item)
+				return false, nil
+			})
+			if err != nil {
+				t.Fatalf("tokenize() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tokenize() got = %#v, want %#v", got, tt.want)
+			}
+			if directions != tt.directions {
+				t.Errorf("tokenize() directions = %q, want %q", directions, tt.directions)
+			}
+		})
+	}
+}
+
+func Benchmark_tokenizer_tokenize(b *testing.B) {
+	line := "Make 6 pizza balls using @tipo zero flour{820%g}, @water{533%ml}, @salt{24.6%g} and @fresh yeast{1.6%g}. Put in a #fridge for ~{2%days}."
+	var tok tokenizer
+	cb := func(item any) (bool, error) { return false, nil }
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := tok.tokenize(line, cb); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Package units converts quantities between the common cooking units
+package units
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Dimension is the physical dimension measured by a unit
+type Dimension int
+
+const (
+	Mass   Dimension = iota + 1 // base unit: gram
+	Volume                      // base unit: milliliter
+)
+
+// System is the measurement system of a unit
+type System int
+
+const (
+	Metric System = iota + 1
+	Imperial
+)
+
+// Unit describes a known unit
+type Unit struct {
+	Name      string    // canonical unit symbol
+	Dimension Dimension // measured dimension
+	System    System    // measurement system
+	Factor    float64   // size of the unit in base units of the dimension
+}
+
+var (
+	// ErrUnknownUnit is returned when the unit is not known
+	ErrUnknownUnit = errors.New("unknown unit")
+	// ErrIncompatibleUnits is returned when converting between different dimensions
+	ErrIncompatibleUnits = errors.New("incompatible units")
+)
+
+var knownUnits = []struct {
+	unit    Unit
+	aliases []string
+}{
+	{Unit{"mg", Mass, Metric, 0.001}, []string{"milligram", "milligrams"}},
+	{Unit{"g", Mass, Metric, 1}, []string{"gr", "gram", "grams", "gramme", "grammes"}},
+	{Unit{"kg", Mass, Metric, 1000}, []string{"kilo", "kilos", "kilogram", "kilograms"}},
+	{Unit{"oz", Mass, Imperial, 28.349523125}, []string{"ounce", "ounces"}},
+	{Unit{"lb", Mass, Imperial, 453.59237}, []string{"lbs", "pound", "pounds"}},
+	{Unit{"ml", Volume, Metric, 1}, []string{"milliliter", "milliliters", "millilitre", "millilitres"}},
+	{Unit{"cl", Volume, Metric, 10}, []string{"centiliter", "centiliters", "centilitre", "centilitres"}},
+	{Unit{"dl", Volume, Metric, 100}, []string{"deciliter", "deciliters", "decilitre", "decilitres"}},
+	{Unit{"l", Volume, Metric, 1000}, []string{"liter", "liters", "litre", "litres"}},
+	{Unit{"tsp", Volume, Imperial, 4.92892159375}, []string{"teaspoon", "teaspoons"}},
+	{Unit{"tbsp", Volume, Imperial, 14.78676478125}, []string{"tablespoon", "tablespoons"}},
+	{Unit{"fl oz", Volume, Imperial, 29.5735295625}, []string{"floz", "fluid ounce", "fluid ounces"}},
+	{Unit{"cup", Volume, Imperial, 236.5882365}, []string{"cups", "c"}},
+	{Unit{"pt", Volume, Imperial, 473.176473}, []string{"pint", "pints"}},
+	{Unit{"qt", Volume, Imperial, 946.352946}, []string{"quart", "quarts"}},
+	{Unit{"gal", Volume, Imperial, 3785.411784}, []string{"gallon", "gallons"}},
+}
+
+var unitsByName = func() map[string]Unit {
+	result := make(map[string]Unit)
+	for _, u := range knownUnits {
+		result[u.unit.Name] = u.unit
+		for _, alias := range u.aliases {
+			result[alias] = u.unit
+		}
+	}
+	return result
+}()
+
+// Lookup returns the unit for the name or alias (case insensitive)
+func Lookup(name string) (Unit, bool) {
+	u, ok := unitsByName[strings.ToLower(strings.TrimSpace(name))]
+	return u, ok
+}
+
+// Compatible returns true if quantities in the units a and b can be
+// converted to each other
+func Compatible(a, b string) bool {
+	ua, okA := Lookup(a)
+	ub, okB := Lookup(b)
+	return okA && okB && ua.Dimension == ub.Dimension
+}
+
+// Convert converts the value from one unit to another
+func Convert(value float64, from, to string) (float64, error) {
+	uf, ok := Lookup(from)
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownUnit, from)
+	}
+	ut, ok := Lookup(to)
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownUnit, to)
+	}
+	if uf.Dimension != ut.Dimension {
+		return 0, fmt.Errorf("%w: %q and %q", ErrIncompatibleUnits, from, to)
+	}
+	return value * uf.Factor / ut.Factor, nil
+}
+
+// displayUnits are the units used for the quantities converted to a
+// measurement system, from the smallest to the largest
+var displayUnits = map[System]map[Dimension][]string{
+	Metric:   {Mass: {"g", "kg"}, Volume: {"ml", "l"}},
+	Imperial: {Mass: {"oz", "lb"}, Volume: {"tsp", "tbsp", "cup"}},
+}
+
+// ConvertToSystem converts the value to the measurement system using the
+// largest common unit of the system in which the value is at least one
+// (820 g is 1.81 lb). Values already in the system are returned as they are.
+func ConvertToSystem(value float64, unit string, system System) (float64, string, error) {
+	u, ok := Lookup(unit)
+	if !ok {
+		return 0, "", fmt.Errorf("%w: %q", ErrUnknownUnit, unit)
+	}
+	if u.System == system {
+		return value, unit, nil
+	}
+	candidates := displayUnits[system][u.Dimension]
+	if len(candidates) == 0 {
+		return 0, "", fmt.Errorf("%w: %q and system %d", ErrIncompatibleUnits, unit, system)
+	}
+	base := value * u.Factor
+	result := candidates[0]
+	for _, name := range candidates[1:] {
+		if c := unitsByName[name]; base/c.Factor >= 1 {
+			result = name
+		}
+	}
+	return base / unitsByName[result].Factor, result, nil
+}
+
+// Temperature units
+const (
+	Celsius    = "C"
+	Fahrenheit = "F"
+)
+
+// CelsiusToFahrenheit converts the temperature from degrees Celsius to
+// degrees Fahrenheit
+func CelsiusToFahrenheit(c float64) float64 {
+	return c*9/5 + 32
+}
+
+// FahrenheitToCelsius converts the temperature from degrees Fahrenheit to
+// degrees Celsius
+func FahrenheitToCelsius(f float64) float64 {
+	return (f - 32) * 5 / 9
+}
@@ -0,0 +1,91 @@
+package units
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   float64
+		from    string
+		to      string
+		want    float64
+		wantErr error
+	}{
+		{"kg to g", 1.5, "kg", "g", 1500, nil},
+		{"aliases", 2, "Grams", "kilogram", 0.002, nil},
+		{"tbsp to tsp", 1, "tbsp", "tsp", 3, nil},
+		{"cups to ml", 1, "cup", "ml", 236.5882365, nil},
+		{"lb to oz", 1, "lb", "oz", 16, nil},
+		{"unknown unit", 1, "pinch", "g", 0, ErrUnknownUnit},
+		{"incompatible", 1, "g", "ml", 0, ErrIncompatibleUnits},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Convert(tt.value, tt.from, tt.to)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Convert() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Convert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertToSystem(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    float64
+		unit     string
+		system   System
+		want     float64
+		wantUnit string
+		wantErr  error
+	}{
+		{"g to lb", 820, "g", Imperial, 1.807790, "lb", nil},
+		{"g to oz", 100, "grams", Imperial, 3.527396, "oz", nil},
+		{"small mass", 5, "g", Imperial, 0.176370, "oz", nil},
+		{"ml to tbsp", 30, "ml", Imperial, 2.028842, "tbsp", nil},
+		{"l to cup", 1, "l", Imperial, 4.226753, "cup", nil},
+		{"cup to ml", 0.5, "cup", Metric, 118.294118, "ml", nil},
+		{"lb to kg", 3, "lb", Metric, 1.360777, "kg", nil},
+		{"same system", 2, "tsp", Imperial, 2, "tsp", nil},
+		{"unknown unit", 1, "pinch", Metric, 0, "", ErrUnknownUnit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, unit, err := ConvertToSystem(tt.value, tt.unit, tt.system)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ConvertToSystem() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if math.Abs(got-tt.want) > 1e-6 || unit != tt.wantUnit {
+				t.Errorf("ConvertToSystem() = %v %v, want %v %v", got, unit, tt.want, tt.wantUnit)
+			}
+		})
+	}
+}
+
+func TestCompatible(t *testing.T) {
+	if !Compatible("g", "lb") {
+		t.Errorf("Compatible(g, lb) = false, want true")
+	}
+	if Compatible("g", "cup") {
+		t.Errorf("Compatible(g, cup) = true, want false")
+	}
+	if Compatible("g", "pinch") {
+		t.Errorf("Compatible(g, pinch) = true, want false")
+	}
+}
+
+func TestTemperatureConversion(t *testing.T) {
+	if got := CelsiusToFahrenheit(100); got != 212 {
+		t.Errorf("CelsiusToFahrenheit(100) = %v, want 212", got)
+	}
+	if got := FahrenheitToCelsius(-40); got != -40 {
+		t.Errorf("FahrenheitToCelsius(-40) = %v, want -40", got)
+	}
+}
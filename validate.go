@@ -0,0 +1,90 @@
+package cooklang
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationProfile defines the rules a recipe must satisfy
+type ValidationProfile struct {
+	RequiredMetadata  []string       // metadata keys which must be present and non empty
+	AllowedUnits      []string       // allowed ingredient units (case insensitive), empty allows any unit
+	RequireDirections bool           // every step must have directions text
+	UniqueTimerNames  bool           // named timers must have distinct names (case insensitive)
+	MetadataSchema    MetadataSchema // types and required keys of the metadata
+	// IngredientDictionary flags the ingredient names which are likely
+	// typos of its names (see Suggest and IngredientDictionary)
+	IngredientDictionary []string
+}
+
+// ValidationError describes a single rule violation
+type ValidationError struct {
+	Step    int    // index of the step or -1 for recipe level errors
+	Field   string // metadata key or ingredient name the error refers to
+	Message string // human readable description of the error
+}
+
+func (e ValidationError) Error() string {
+	if e.Step < 0 {
+		return fmt.Sprintf("%s: %s", e.Field, e.Message)
+	}
+	if e.Field == "" {
+		return fmt.Sprintf("step %d: %s", e.Step+1, e.Message)
+	}
+	return fmt.Sprintf("step %d: %s: %s", e.Step+1, e.Field, e.Message)
+}
+
+// Validate checks the recipe against the profile and returns the list of
+// violations. Steps containing only comments are not checked.
+func Validate(r *Recipe, profile ValidationProfile) []ValidationError {
+	var result []ValidationError
+	for _, key := range profile.RequiredMetadata {
+		if strings.TrimSpace(r.Metadata[key]) == "" {
+			result = append(result, ValidationError{-1, key, "required metadata is missing"})
+		}
+	}
+	result = append(result, profile.MetadataSchema.Validate(r.Metadata)...)
+	if profile.UniqueTimerNames {
+		seen := make(map[string]bool)
+		for _, timer := range r.Timers() {
+			key := timerKey(timer.Name)
+			if key != "" && seen[key] {
+				result = append(result, ValidationError{timer.Step, timer.Name, "duplicate timer name"})
+			}
+			seen[key] = true
+		}
+	}
+	for i, step := range r.Steps {
+		if step.IsComment() {
+			continue
+		}
+		if profile.RequireDirections && strings.TrimSpace(step.Directions) == "" {
+			result = append(result, ValidationError{i, "", "step has no directions"})
+		}
+		if len(profile.IngredientDictionary) > 0 {
+			for _, ingredient := range step.Ingredients {
+				if suggestion, ok := closestWord(ingredient.Name, profile.IngredientDictionary); ok && !isInflection(ingredient.Name, suggestion) {
+					result = append(result, ValidationError{i, ingredient.Name, fmt.Sprintf("possible typo of %q", suggestion)})
+				}
+			}
+		}
+		if len(profile.AllowedUnits) == 0 {
+			continue
+		}
+		for _, ingredient := range step.Ingredients {
+			if ingredient.Amount.Unit != "" && !containsFold(profile.AllowedUnits, ingredient.Amount.Unit) {
+				result = append(result, ValidationError{i, ingredient.Name, fmt.Sprintf("unit %q is not allowed", ingredient.Amount.Unit)})
+			}
+		}
+	}
+	return result
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
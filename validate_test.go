@@ -0,0 +1,82 @@
+package cooklang
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	recipe := &Recipe{
+		Steps: []Step{
+			{Comments: []string{"just a comment"}},
+			{
+				Directions: "Mix flour and sugar",
+				Ingredients: []Ingredient{
+					{Name: "flour", Amount: IngredientAmount{true, 200, "200", "G"}},
+					{Name: "sugar", Amount: IngredientAmount{true, 1, "1", "cup"}},
+					{Name: "salt", Amount: IngredientAmount{Quantity: 1}},
+				},
+			},
+			{Directions: "  ", Comments: []string{"empty"}, Timers: []Timer{{"", 1, "minute"}, {"", 2, "minute"}}},
+			{Directions: "Bake", Timers: []Timer{{"Bake", 20, "minutes"}, {"bake ", 5, "minutes"}}},
+		},
+		Metadata: Metadata{"title": "Cake", "tags": " "},
+	}
+	tests := []struct {
+		name    string
+		profile ValidationProfile
+		want    []ValidationError
+	}{
+		{
+			"Empty profile",
+			ValidationProfile{},
+			nil,
+		},
+		{
+			"Required metadata",
+			ValidationProfile{RequiredMetadata: []string{"title", "servings", "tags"}},
+			[]ValidationError{
+				{-1, "servings", "required metadata is missing"},
+				{-1, "tags", "required metadata is missing"},
+			},
+		},
+		{
+			"Allowed units and directions",
+			ValidationProfile{AllowedUnits: []string{"g", "ml"}, RequireDirections: true},
+			[]ValidationError{
+				{1, "sugar", `unit "cup" is not allowed`},
+				{2, "", "step has no directions"},
+			},
+		},
+		{
+			"Unique timer names",
+			ValidationProfile{UniqueTimerNames: true},
+			[]ValidationError{
+				{3, "bake ", "duplicate timer name"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Validate(recipe, tt.profile); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidationError_Error(t *testing.T) {
+	tests := []struct {
+		err  ValidationError
+		want string
+	}{
+		{ValidationError{-1, "title", "required metadata is missing"}, "title: required metadata is missing"},
+		{ValidationError{0, "", "step has no directions"}, "step 1: step has no directions"},
+		{ValidationError{1, "sugar", `unit "cup" is not allowed`}, `step 2: sugar: unit "cup" is not allowed`},
+	}
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("ValidationError.Error() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package cooklang
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// verbatimDelimiter encloses the verbatim spans of the steps (`model #42`)
+const verbatimDelimiter = '`'
+
+// protectedSpan is a part of the line which is not tokenized, either a
+// verbatim pattern match or a link
+type protectedSpan struct {
+	start, end int
+	link       bool
+}
+
+// protectedSpans returns the verbatim pattern matches and the links of the
+// line sorted by their start
+func (t *tokenizer) protectedSpans(line string) []protectedSpan {
+	var spans []protectedSpan
+	for _, p := range t.verbatimPatterns {
+		for _, m := range p.FindAllStringIndex(line, -1) {
+			spans = append(spans, protectedSpan{m[0], m[1], false})
+		}
+	}
+	if t.links {
+		for _, m := range findLinks(line) {
+			spans = append(spans, protectedSpan{m[0], m[1], true})
+		}
+	}
+	slices.SortFunc(spans, func(a, b protectedSpan) int { return cmp.Compare(a.start, b.start) })
+	return spans
+}
+
+// verbatimSpan returns the length of the verbatim span at the start of s
+// with the delimiters, zero when s does not start with a closed span
+func verbatimSpan(s string) int {
+	if len(s) == 0 || s[0] != verbatimDelimiter {
+		return 0
+	}
+	end := strings.IndexByte(s[1:], verbatimDelimiter)
+	if end == -1 {
+		return 0
+	}
+	return end + 2
+}
@@ -0,0 +1,50 @@
+package cooklang
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestParseVerbatimSpans(t *testing.T) {
+	src := "Set the `#2 @home` program of the `Mixer` and add @flour{200%g}.\nKeep the `unclosed @salt{}."
+	r, err := NewParser(&ParseConfig{VerbatimSpans: true}).ParseString(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Set the #2 @home program of the Mixer and add flour."; r.Steps[0].Directions != want {
+		t.Errorf("directions = %q, want %q", r.Steps[0].Directions, want)
+	}
+	want := []Ingredient{{Name: "flour", Amount: IngredientAmount{true, 200, "200", "g"}}}
+	if !reflect.DeepEqual(r.Steps[0].Ingredients, want) || len(r.Steps[0].Cookware) != 0 {
+		t.Errorf("items = %+v %+v, want only %+v", r.Steps[0].Ingredients, r.Steps[0].Cookware, want)
+	}
+	if want := "Keep the `unclosed salt."; r.Steps[1].Directions != want {
+		t.Errorf("directions = %q, want %q", r.Steps[1].Directions, want)
+	}
+
+	r, err = ParseString(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Steps[0].Cookware) != 1 {
+		t.Errorf("cookware = %+v, want the spans tokenized by default", r.Steps[0].Cookware)
+	}
+}
+
+func TestParseVerbatimPatterns(t *testing.T) {
+	patterns := []*regexp.Regexp{regexp.MustCompile(`https?://\S+`), regexp.MustCompile(`KM-\d+#\w+`)}
+	src := "See https://example.com/@chef#bread--notes for the KM-20#b setting of the #mixer{}."
+	want := StepV2{
+		TextV2{"text", "See https://example.com/@chef#bread--notes for the KM-20#b setting of the "},
+		CookwareV2{Type: "cookware", Name: "mixer", Quantity: 1},
+		TextV2{"text", "."},
+	}
+	r, err := NewParserV2(&ParseV2Config{VerbatimPatterns: patterns}).ParseString(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r.Steps[0], want) {
+		t.Errorf("step = %+v, want %+v", r.Steps[0], want)
+	}
+}
@@ -0,0 +1,101 @@
+package cooklang
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aquilax/cooklang-go/units"
+)
+
+// WarningType defines the type of a parse warning
+type WarningType int
+
+const (
+	WarningDowngradedItem    WarningType = iota + 1 // malformed item kept as plain text
+	WarningUnknownUnit                              // ingredient unit not known to the units package
+	WarningDuplicateMetadata                        // metadata key defined more than once
+	WarningIgnoredLine                              // line which could not be parsed and was skipped
+	WarningUnknownMetadata                          // metadata key which is not allowed (see ParseConfig.StrictMetadata)
+	WarningUnknownReference                         // reference to an ingredient which is not mentioned before (see ReferenceMode)
+)
+
+func (t WarningType) String() string {
+	switch t {
+	case WarningDowngradedItem:
+		return "downgraded item"
+	case WarningUnknownUnit:
+		return "unknown unit"
+	case WarningDuplicateMetadata:
+		return "duplicate metadata"
+	case WarningIgnoredLine:
+		return "ignored line"
+	case WarningUnknownMetadata:
+		return "unknown metadata"
+	case WarningUnknownReference:
+		return "unknown reference"
+	}
+	return fmt.Sprintf("WarningType(%d)", int(t))
+}
+
+// Warning describes a non-fatal issue found while parsing a recipe
+type Warning struct {
+	Type    WarningType // type of the warning
+	Line    int         // one based line number
+	Offset  int         // byte offset in the line
+	Message string      // human readable description
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("line %d: %s: %s", w.Line, w.Type, w.Message)
+}
+
+// warningList collects the warnings of a single parse. A nil list discards
+// the warnings.
+type warningList struct {
+	line     int // current line number
+	warnings []Warning
+}
+
+func (w *warningList) add(t WarningType, offset int, format string, args ...any) {
+	if w == nil {
+		return
+	}
+	w.warnings = append(w.warnings, Warning{t, w.line, offset, fmt.Sprintf(format, args...)})
+}
+
+func (w *warningList) checkUnit(ingredient Ingredient, offset int) {
+	if w == nil || ingredient.Amount.Unit == "" {
+		return
+	}
+	if _, ok := units.Lookup(ingredient.Amount.Unit); !ok {
+		w.add(WarningUnknownUnit, offset, "unit %q of ingredient %q", ingredient.Amount.Unit, ingredient.Name)
+	}
+}
+
+// ParseStringWithWarnings parses a cooklang recipe string and returns the
+// recipe together with the non-fatal issues found in it
+func ParseStringWithWarnings(s string) (*Recipe, []Warning, error) {
+	return NewParser(nil).ParseStringWithWarnings(s)
+}
+
+// ParseStringWithWarnings parses a cooklang recipe string and returns the
+// recipe together with the non-fatal issues found in it
+func (p *Parser) ParseStringWithWarnings(s string) (*Recipe, []Warning, error) {
+	if s == "" {
+		return nil, nil, fmt.Errorf("recipe string must not be empty")
+	}
+	return p.ParseStreamWithWarnings(strings.NewReader(s))
+}
+
+// ParseStreamWithWarnings parses a cooklang recipe text stream and returns the
+// recipe together with the non-fatal issues found in it. Invalid metadata
+// lines are skipped with a warning instead of failing the parse.
+func (p *Parser) ParseStreamWithWarnings(s io.Reader) (*Recipe, []Warning, error) {
+	warnings := &warningList{}
+	recipe, err := p.parseStream(s, warnings, includeSource{})
+	if err != nil {
+		return nil, warnings.warnings, err
+	}
+	return recipe, warnings.warnings, nil
+}
@@ -0,0 +1,64 @@
+package cooklang
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseStringWithWarnings(t *testing.T) {
+	tests := []struct {
+		name   string
+		recipe string
+		want   []Warning
+	}{
+		{
+			"No warnings",
+			">> servings: 2\nAdd @flour{200%g} and @salt.",
+			nil,
+		},
+		{
+			"Downgraded item",
+			"Add @flour{200%g",
+			[]Warning{{WarningDowngradedItem, 1, 4, `invalid ingredient "@flour{200%g" at offset 4: unterminated amount`}},
+		},
+		{
+			"Unknown unit",
+			"Add @garlic{2%cloves}",
+			[]Warning{{WarningUnknownUnit, 1, 4, `unit "cloves" of ingredient "garlic"`}},
+		},
+		{
+			"Duplicate metadata",
+			">> servings: 2\n>> servings: 4",
+			[]Warning{{WarningDuplicateMetadata, 2, 0, `key "servings" is already defined`}},
+		},
+		{
+			"Ignored line",
+			"Boil ~{5%minutes}\n>> no separator",
+			[]Warning{{WarningIgnoredLine, 2, 0, "invalid metadata: no separator"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got, err := ParseStringWithWarnings(tt.recipe)
+			if err != nil {
+				t.Fatalf("ParseStringWithWarnings() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseStringWithWarnings() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseStringIgnoresWarnings(t *testing.T) {
+	if _, err := ParseString(">> no separator"); err == nil {
+		t.Errorf("ParseString() expected error for invalid metadata")
+	}
+	got, err := ParseString(">> servings: 2\n>> servings: 4")
+	if err != nil {
+		t.Fatalf("ParseString() error = %v", err)
+	}
+	if got.Metadata["servings"] != "4" {
+		t.Errorf("ParseString() servings = %q, want %q", got.Metadata["servings"], "4")
+	}
+}
@@ -0,0 +1,23 @@
+//go:build js && wasm
+
+// Command js exposes the parser to JavaScript as the global function
+// cooklangParse(source) which returns the parse result as a JSON string.
+// See index.html for a usage example.
+package main
+
+import (
+	"syscall/js"
+
+	"github.com/aquilax/cooklang-go/wasm"
+)
+
+func main() {
+	js.Global().Set("cooklangParse", js.FuncOf(func(this js.Value, args []js.Value) any {
+		if len(args) != 1 || args[0].Type() != js.TypeString {
+			return `{"error":"cooklangParse expects a single string argument"}`
+		}
+		return string(wasm.ParseJSON(args[0].String()))
+	}))
+	// keep the Go runtime alive for the callbacks
+	select {}
+}
@@ -0,0 +1,29 @@
+//go:build wasip1
+
+// Command wasip1 reads a recipe from stdin and writes the parse result as
+// JSON to stdout. It exits with status 1 when the recipe can not be parsed.
+//
+//	wasmtime cooklang-wasi.wasm < recipe.cook
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/aquilax/cooklang-go/wasm"
+)
+
+func main() {
+	source, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		os.Stderr.WriteString(err.Error() + "\n")
+		os.Exit(1)
+	}
+	output := wasm.ParseJSON(string(source))
+	os.Stdout.Write(append(output, '\n'))
+	var result wasm.Result
+	if json.Unmarshal(output, &result) != nil || result.Error != "" {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,35 @@
+// Package wasm contains the shared code of the WebAssembly builds of the
+// parser. The js subpackage exposes a JavaScript binding and the wasip1
+// subpackage a command line tool:
+//
+//	GOOS=js GOARCH=wasm go build -ldflags="-s -w" -o cooklang.wasm ./wasm/js
+//	GOOS=wasip1 GOARCH=wasm go build -ldflags="-s -w" -o cooklang-wasi.wasm ./wasm/wasip1
+package wasm
+
+import (
+	"encoding/json"
+
+	"github.com/aquilax/cooklang-go"
+)
+
+// Result is the JSON document returned by ParseJSON
+type Result struct {
+	Recipe *cooklang.Recipe `json:"recipe,omitempty"`
+	Error  string           `json:"error,omitempty"`
+}
+
+// ParseJSON parses the recipe source and returns the Result as JSON
+func ParseJSON(source string) []byte {
+	var result Result
+	r, err := cooklang.ParseString(source)
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		result.Recipe = r
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		b, _ = json.Marshal(Result{Error: err.Error()})
+	}
+	return b
+}
@@ -0,0 +1,29 @@
+package wasm
+
+import "testing"
+
+func TestParseJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			"Recipe",
+			"Add @salt{}.",
+			`{"recipe":{"Steps":[{"Directions":"Add salt.","Timers":[],"Ingredients":[{"Name":"salt","Amount":{"IsNumeric":false,"Quantity":0,"QuantityRaw":"","Unit":""}}],"Cookware":[],"Comments":null}],"Metadata":{}}}`,
+		},
+		{
+			"Error",
+			"",
+			`{"error":"recipe string must not be empty"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(ParseJSON(tt.source)); got != tt.want {
+				t.Errorf("ParseJSON() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}